@@ -17,6 +17,16 @@ func main() {
 			os.Exit(cli.RunStatus(os.Args[2:]))
 		case "sound":
 			os.Exit(cli.RunSound(os.Args[2:]))
+		case "report":
+			os.Exit(cli.RunReport(os.Args[2:]))
+		case "new":
+			os.Exit(cli.RunNew(os.Args[2:]))
+		case "agent":
+			os.Exit(cli.RunAgent(os.Args[2:]))
+		case "popup":
+			os.Exit(cli.RunPopup(os.Args[2:]))
+		case "state":
+			os.Exit(cli.RunState(os.Args[2:]))
 		}
 	}
 
@@ -1,6 +1,9 @@
 #!/bin/bash
 # tool-tracker.sh - Hook script for tracking tool usage in navi.
-# Called by Claude Code PostToolUse hooks to track tool counts and recent tools.
+# Called by Claude Code PreToolUse/PostToolUse/PostToolUseFailure hooks to track tool counts,
+# recent tools, and per-call timing. PreToolUse records a start time keyed by tool_use_id;
+# PostToolUse or PostToolUseFailure pairs it up and appends the call's duration and
+# success/error to a per-session log.
 # Receives JSON input via stdin with tool_name, tool_input, tool_response, tool_use_id.
 # Skips processing for teammate events (agent-level tool metrics are not tracked).
 
@@ -10,6 +13,25 @@ mkdir -p "$DIR"
 SESSION=$(tmux display-message -p '#{session_name}' 2>/dev/null || echo "unknown")
 SESSION_FILE="$DIR/$SESSION.json"
 
+# Tool call timing log (one JSON line per completed call)
+TOOL_CALLS_DIR="$DIR/tool-calls"
+PENDING_DIR="$TOOL_CALLS_DIR/pending/$SESSION"
+TOOL_CALLS_FILE="$TOOL_CALLS_DIR/$SESSION.jsonl"
+MAX_TOOL_CALL_LINES=5000
+# Start times of calls that never completed (crashes, interrupts) are dropped after this
+PENDING_MAX_AGE_MINUTES=1440
+
+# now_ms prints the current time in milliseconds, falling back to second precision
+# where date does not support %N (e.g. macOS).
+now_ms() {
+    local ms
+    ms=$(date +%s%3N 2>/dev/null)
+    case "$ms" in
+        ''|*N) echo $(( $(date +%s) * 1000 )) ;;
+        *) echo "$ms" ;;
+    esac
+}
+
 # Read stdin JSON
 # The hook receives JSON like: {"tool_name": "Read", "tool_input": {...}, ...}
 if command -v jq &> /dev/null; then
@@ -23,10 +45,15 @@ if command -v jq &> /dev/null; then
     fi
 
     TOOL_NAME=$(echo "$INPUT" | jq -r '.tool_name // ""')
+    HOOK_EVENT=$(echo "$INPUT" | jq -r '.hook_event_name // ""')
+    # Only characters safe for a file name are kept from the tool_use_id
+    TOOL_USE_ID=$(echo "$INPUT" | jq -r '.tool_use_id // ""' | tr -cd 'A-Za-z0-9_-')
 else
     # Fallback: try to extract tool_name with grep/sed
     INPUT=$(cat)
     TOOL_NAME=$(echo "$INPUT" | grep -o '"tool_name"[[:space:]]*:[[:space:]]*"[^"]*"' | sed 's/.*: *"\([^"]*\)".*/\1/')
+    HOOK_EVENT=$(echo "$INPUT" | grep -o '"hook_event_name"[[:space:]]*:[[:space:]]*"[^"]*"' | sed 's/.*: *"\([^"]*\)".*/\1/')
+    TOOL_USE_ID=""
 fi
 
 # Exit if we couldn't get a tool name
@@ -34,6 +61,67 @@ if [ -z "$TOOL_NAME" ] || [ "$TOOL_NAME" = "null" ]; then
     exit 0
 fi
 
+# ---- PreToolUse: record start time and stop ----
+# Counts are only updated once the tool completes (PostToolUse or PostToolUseFailure).
+if [ "$HOOK_EVENT" = "PreToolUse" ]; then
+    if [ -n "$TOOL_USE_ID" ]; then
+        mkdir -p "$PENDING_DIR"
+        now_ms > "$PENDING_DIR/$TOOL_USE_ID"
+    fi
+    # Drop stale start files of every session, then the directories they leave empty
+    find "$TOOL_CALLS_DIR/pending" -type f -mmin +"$PENDING_MAX_AGE_MINUTES" -delete 2>/dev/null
+    find "$TOOL_CALLS_DIR/pending" -mindepth 1 -type d -empty -delete 2>/dev/null
+    exit 0
+fi
+
+# ---- PostToolUse/PostToolUseFailure: pair with start time and log duration/outcome ----
+if [ -n "$TOOL_USE_ID" ] && [ -f "$PENDING_DIR/$TOOL_USE_ID" ] && command -v jq &> /dev/null; then
+    START_MS=$(cat "$PENDING_DIR/$TOOL_USE_ID" 2>/dev/null)
+    rm -f "$PENDING_DIR/$TOOL_USE_ID"
+    END_MS=$(now_ms)
+    DURATION_MS=0
+    if [ -n "$START_MS" ] && [ "$START_MS" -le "$END_MS" ] 2>/dev/null; then
+        DURATION_MS=$((END_MS - START_MS))
+    fi
+
+    # A call failed when the hook reports a failure event or tool_response carries an error marker
+    if [ "$HOOK_EVENT" = "PostToolUseFailure" ]; then
+        TOOL_ERROR=true
+    else
+        TOOL_ERROR=$(echo "$INPUT" | jq -r '
+            if (.tool_response | type) == "object" then
+                ((.tool_response.is_error // false) == true)
+                or (.tool_response.error != null)
+                or (.tool_response.success == false)
+                or ((.tool_response.interrupted // false) == true)
+            else false end' 2>/dev/null)
+        [ "$TOOL_ERROR" != "true" ] && TOOL_ERROR=false
+    fi
+
+    CALL_CWD=$(echo "$INPUT" | jq -r '.cwd // ""' 2>/dev/null)
+    [ -z "$CALL_CWD" ] && CALL_CWD=$(tmux display-message -p '#{pane_current_path}' 2>/dev/null || echo "")
+
+    mkdir -p "$TOOL_CALLS_DIR"
+    jq -nc \
+        --arg tool "$TOOL_NAME" \
+        --arg id "$TOOL_USE_ID" \
+        --arg session "$SESSION" \
+        --arg cwd "$CALL_CWD" \
+        --argjson ts "$((END_MS / 1000))" \
+        --argjson duration "$DURATION_MS" \
+        --argjson error "$TOOL_ERROR" \
+        '{tool: $tool, tool_use_id: $id, session: $session, cwd: $cwd, ts: $ts, duration_ms: $duration, error: $error}' \
+        >> "$TOOL_CALLS_FILE"
+
+    # Keep the log bounded
+    LINE_COUNT=$(wc -l < "$TOOL_CALLS_FILE" 2>/dev/null || echo 0)
+    if [ "$LINE_COUNT" -gt "$MAX_TOOL_CALL_LINES" ]; then
+        TMPFILE=$(mktemp "$TOOL_CALLS_DIR/.tmp.XXXXXX")
+        tail -n "$MAX_TOOL_CALL_LINES" "$TOOL_CALLS_FILE" > "$TMPFILE"
+        mv "$TMPFILE" "$TOOL_CALLS_FILE"
+    fi
+fi
+
 # Constants
 MAX_RECENT_TOOLS=10
 
@@ -0,0 +1,167 @@
+// Package agent implements the remote side of navi's push-based status
+// streaming. `navi agent` runs on a remote host, watches the session status
+// directory and writes change events as JSON lines to stdout, which the local
+// navi reads over a single long-lived SSH session channel.
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// ProtocolVersion identifies the event stream format. It is sent in the hello event.
+const ProtocolVersion = 1
+
+// Default timings for Run.
+const (
+	DefaultInterval  = 500 * time.Millisecond
+	DefaultHeartbeat = 10 * time.Second
+)
+
+// Event types
+const (
+	EventHello     = "hello"     // First event; carries Version
+	EventUpsert    = "upsert"    // A status file was created or changed; carries Name and Session
+	EventRemove    = "remove"    // A status file was deleted; carries Name
+	EventSync      = "sync"      // The initial snapshot is complete
+	EventHeartbeat = "heartbeat" // Sent when nothing changed for a heartbeat interval
+)
+
+// Event is one line of the agent's output stream.
+type Event struct {
+	Type    string        `json:"type"`
+	Version int           `json:"version,omitempty"`
+	Name    string        `json:"name,omitempty"` // Status file name without .json
+	Session *session.Info `json:"session,omitempty"`
+}
+
+// fileState is the change signature of one status file.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher detects changes in a status directory by comparing file modification
+// times and sizes between scans.
+type Watcher struct {
+	dir   string
+	files map[string]fileState
+}
+
+// NewWatcher creates a watcher for dir. The first Scan reports every status file.
+func NewWatcher(dir string) *Watcher {
+	return &Watcher{dir: dir, files: make(map[string]fileState)}
+}
+
+// Scan returns upsert events for new or changed status files and remove events
+// for deleted ones, in file name order. Files that fail to parse are retried on
+// the next scan. A missing directory is treated as empty.
+func (w *Watcher) Scan() []Event {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil
+	}
+
+	var events []Event
+	present := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		present[name] = true
+
+		state := fileState{modTime: info.ModTime(), size: info.Size()}
+		if prev, ok := w.files[name]; ok && prev == state {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(w.dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var s session.Info
+		if err := json.Unmarshal(data, &s); err != nil {
+			// Likely caught mid-write; leave unrecorded so the next scan retries
+			continue
+		}
+		w.files[name] = state
+		events = append(events, Event{Type: EventUpsert, Name: name, Session: &s})
+	}
+
+	var removed []string
+	for name := range w.files {
+		if !present[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		delete(w.files, name)
+		events = append(events, Event{Type: EventRemove, Name: name})
+	}
+
+	return events
+}
+
+// Run streams status changes in dir to w until ctx is cancelled or a write
+// fails (the reader went away). It writes a hello event, the initial snapshot
+// followed by a sync event, and then changes every interval, with a heartbeat
+// whenever nothing was written for the heartbeat duration.
+func Run(ctx context.Context, dir string, w io.Writer, interval, heartbeat time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if heartbeat <= 0 {
+		heartbeat = DefaultHeartbeat
+	}
+
+	enc := json.NewEncoder(w)
+	watcher := NewWatcher(dir)
+
+	if err := enc.Encode(Event{Type: EventHello, Version: ProtocolVersion}); err != nil {
+		return err
+	}
+	for _, ev := range watcher.Scan() {
+		if err := enc.Encode(ev); err != nil {
+			return err
+		}
+	}
+	if err := enc.Encode(Event{Type: EventSync}); err != nil {
+		return err
+	}
+	lastWrite := time.Now()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			events := watcher.Scan()
+			if len(events) == 0 && now.Sub(lastWrite) >= heartbeat {
+				events = []Event{{Type: EventHeartbeat}}
+			}
+			for _, ev := range events {
+				if err := enc.Encode(ev); err != nil {
+					return err
+				}
+				lastWrite = now
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeStatus(t *testing.T, dir, name, status string) {
+	t.Helper()
+	data := `{"tmux_session":"` + name + `","status":"` + status + `","timestamp":1}`
+	if err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func eventKinds(events []Event) []string {
+	var kinds []string
+	for _, ev := range events {
+		kinds = append(kinds, ev.Type+":"+ev.Name)
+	}
+	return kinds
+}
+
+func TestWatcherScan(t *testing.T) {
+	dir := t.TempDir()
+	writeStatus(t, dir, "web", "working")
+	writeStatus(t, dir, "api", "idle")
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWatcher(dir)
+	events := w.Scan()
+	if got := eventKinds(events); len(got) != 2 || got[0] != "upsert:api" || got[1] != "upsert:web" {
+		t.Fatalf("initial scan = %v", got)
+	}
+	if events[0].Session.Status != "idle" {
+		t.Errorf("api status = %q", events[0].Session.Status)
+	}
+
+	if got := w.Scan(); len(got) != 0 {
+		t.Errorf("unchanged scan = %v, want none", eventKinds(got))
+	}
+
+	// A half-written file is skipped and picked up once it parses
+	if err := os.WriteFile(filepath.Join(dir, "new.json"), []byte(`{"tmux_se`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Scan(); len(got) != 0 {
+		t.Errorf("scan with partial file = %v, want none", eventKinds(got))
+	}
+	writeStatus(t, dir, "new", "waiting")
+	writeStatus(t, dir, "web", "permission")
+	os.Remove(filepath.Join(dir, "api.json"))
+
+	got := eventKinds(w.Scan())
+	want := []string{"upsert:new", "upsert:web", "remove:api"}
+	if len(got) != len(want) {
+		t.Fatalf("scan = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("scan = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestWatcherMissingDir(t *testing.T) {
+	w := NewWatcher(filepath.Join(t.TempDir(), "missing"))
+	if got := w.Scan(); len(got) != 0 {
+		t.Errorf("scan of missing dir = %v, want none", eventKinds(got))
+	}
+}
+
+func TestRunStream(t *testing.T) {
+	dir := t.TempDir()
+	writeStatus(t, dir, "api", "working")
+
+	pr, pw := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(ctx, dir, pw, 10*time.Millisecond, 50*time.Millisecond)
+		pw.Close()
+	}()
+
+	lines := bufio.NewScanner(pr)
+	next := func() Event {
+		t.Helper()
+		if !lines.Scan() {
+			t.Fatalf("stream ended: %v", lines.Err())
+		}
+		var ev Event
+		if err := json.Unmarshal(lines.Bytes(), &ev); err != nil {
+			t.Fatalf("bad event %q: %v", lines.Text(), err)
+		}
+		return ev
+	}
+
+	if ev := next(); ev.Type != EventHello || ev.Version != ProtocolVersion {
+		t.Fatalf("first event = %+v, want hello", ev)
+	}
+	if ev := next(); ev.Type != EventUpsert || ev.Name != "api" {
+		t.Fatalf("snapshot event = %+v", ev)
+	}
+	if ev := next(); ev.Type != EventSync {
+		t.Fatalf("event after snapshot = %+v, want sync", ev)
+	}
+	if ev := next(); ev.Type != EventHeartbeat {
+		t.Fatalf("idle event = %+v, want heartbeat", ev)
+	}
+
+	os.Remove(filepath.Join(dir, "api.json"))
+	for {
+		ev := next()
+		if ev.Type == EventHeartbeat {
+			continue
+		}
+		if ev.Type != EventRemove || ev.Name != "api" {
+			t.Fatalf("event = %+v, want remove api", ev)
+		}
+		break
+	}
+
+	cancel()
+	go io.Copy(io.Discard, pr)
+	if err := <-done; err != nil {
+		t.Errorf("Run after cancel = %v", err)
+	}
+}
+
+func TestRunStopsWhenReaderGoes(t *testing.T) {
+	pr, pw := io.Pipe()
+	pr.Close()
+	err := Run(context.Background(), t.TempDir(), pw, 10*time.Millisecond, time.Second)
+	if err == nil {
+		t.Error("Run should fail once the reader is gone")
+	}
+}
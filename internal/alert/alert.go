@@ -0,0 +1,204 @@
+package alert
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stwalsh4118/navi/internal/metrics"
+	"github.com/stwalsh4118/navi/internal/resource"
+)
+
+// NotifyEvent is the notification event name used for resource alerts.
+const NotifyEvent = "alert"
+
+// Kind identifies which threshold was crossed.
+type Kind string
+
+// Threshold kinds
+const (
+	KindRSS       Kind = "rss"
+	KindCPU       Kind = "cpu"
+	KindProcesses Kind = "processes"
+)
+
+// offenderMinDepth is the shallowest depth an offender may have. Depth 0 is
+// the pane shell and depth 1 the agent it runs, which must never be killed;
+// only the agent's own subprocesses are candidates.
+const offenderMinDepth = 2
+
+// Breach describes a threshold crossed by a session's process tree.
+type Breach struct {
+	Kind     Kind
+	Label    string           // Short description for badges, e.g. "RSS 4.2G>4.0G"
+	Offender resource.Process // Agent subprocess most responsible; zero PID if none
+}
+
+// Result is the outcome of evaluating one session.
+type Result struct {
+	Active []Breach // All thresholds currently crossed
+	New    []Breach // Thresholds crossed since the previous evaluation
+}
+
+// Evaluator checks resource usage against thresholds and tracks per-session
+// state for sustained CPU and edge detection. It is safe for concurrent use.
+type Evaluator struct {
+	cfg *Config
+
+	mu       sync.Mutex
+	cpuSince map[string]time.Time     // When CPU first went over the limit
+	active   map[string]map[Kind]bool // Breaches active at the last evaluation
+}
+
+// NewEvaluator creates an evaluator for the given thresholds.
+func NewEvaluator(cfg *Config) *Evaluator {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	return &Evaluator{
+		cfg:      cfg,
+		cpuSince: make(map[string]time.Time),
+		active:   make(map[string]map[Kind]bool),
+	}
+}
+
+// Config returns the evaluator's thresholds.
+func (e *Evaluator) Config() *Config {
+	if e == nil {
+		return nil
+	}
+	return e.cfg
+}
+
+// Evaluate checks a session's usage against the configured thresholds.
+// A nil Evaluator or disabled config yields an empty Result.
+func (e *Evaluator) Evaluate(key string, usage resource.Usage, now time.Time) Result {
+	if e == nil || !e.cfg.Enabled() {
+		return Result{}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var result Result
+	if limit := e.cfg.MaxRSSBytes(); limit > 0 && usage.RSSBytes > limit {
+		result.Active = append(result.Active, Breach{
+			Kind:     KindRSS,
+			Label:    fmt.Sprintf("RSS %s>%s", metrics.FormatBytes(usage.RSSBytes), metrics.FormatBytes(limit)),
+			Offender: largestChild(usage.Processes, func(p resource.Process) float64 { return float64(p.RSSBytes) }),
+		})
+	}
+
+	if limit := e.cfg.MaxCPUPercent; limit > 0 && usage.CPUPercent > limit {
+		since, ok := e.cpuSince[key]
+		if !ok {
+			since = now
+			e.cpuSince[key] = now
+		}
+		sustained := now.Sub(since)
+		if sustained >= time.Duration(e.cfg.CPUSustainSeconds)*time.Second {
+			result.Active = append(result.Active, Breach{
+				Kind:     KindCPU,
+				Label:    fmt.Sprintf("CPU %.0f%% %s", usage.CPUPercent, metrics.FormatDuration(int64(sustained.Seconds()))),
+				Offender: largestChild(usage.Processes, func(p resource.Process) float64 { return p.CPUPercent }),
+			})
+		}
+	} else {
+		delete(e.cpuSince, key)
+	}
+
+	if limit := e.cfg.MaxProcesses; limit > 0 && usage.ProcessCount > limit {
+		result.Active = append(result.Active, Breach{
+			Kind:     KindProcesses,
+			Label:    fmt.Sprintf("%d procs>%d", usage.ProcessCount, limit),
+			Offender: largestSubtree(usage.Processes),
+		})
+	}
+
+	current := make(map[Kind]bool, len(result.Active))
+	for _, b := range result.Active {
+		current[b.Kind] = true
+		if !e.active[key][b.Kind] {
+			result.New = append(result.New, b)
+		}
+	}
+	if len(current) == 0 {
+		delete(e.active, key)
+	} else {
+		e.active[key] = current
+	}
+
+	return result
+}
+
+// Retain drops the state of sessions whose keys are not in keys, so sessions
+// that have gone away do not accumulate.
+func (e *Evaluator) Retain(keys map[string]bool) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for key := range e.cpuSince {
+		if !keys[key] {
+			delete(e.cpuSince, key)
+		}
+	}
+	for key := range e.active {
+		if !keys[key] {
+			delete(e.active, key)
+		}
+	}
+}
+
+// largestChild returns the agent subprocess with the highest score.
+func largestChild(procs []resource.Process, score func(resource.Process) float64) resource.Process {
+	var best resource.Process
+	bestScore := -1.0
+	for _, p := range procs {
+		if p.Depth < offenderMinDepth {
+			continue
+		}
+		if s := score(p); s > bestScore {
+			best, bestScore = p, s
+		}
+	}
+	return best
+}
+
+// largestSubtree returns the direct child of an agent with the most
+// descendants. procs must be in depth-first order as returned by resource.
+func largestSubtree(procs []resource.Process) resource.Process {
+	var best resource.Process
+	bestSize := 0
+	for i, p := range procs {
+		if p.Depth != offenderMinDepth {
+			continue
+		}
+		size := 1
+		for _, q := range procs[i+1:] {
+			if q.Depth <= p.Depth {
+				break
+			}
+			size++
+		}
+		if size > bestSize {
+			best, bestSize = p, size
+		}
+	}
+	return best
+}
+
+// Labels returns the badge labels for a set of breaches.
+func Labels(breaches []Breach) []string {
+	if len(breaches) == 0 {
+		return nil
+	}
+	labels := make([]string, len(breaches))
+	for i, b := range breaches {
+		labels[i] = b.Label
+	}
+	return labels
+}
@@ -0,0 +1,223 @@
+package alert
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stwalsh4118/navi/internal/resource"
+)
+
+func testUsage() resource.Usage {
+	return resource.Usage{
+		RSSBytes:     3 * 1024 * 1024 * 1024,
+		CPUPercent:   150,
+		ProcessCount: 6,
+		Processes: []resource.Process{
+			{PID: 1, Depth: 0, RSSBytes: 10, CPUPercent: 1},
+			{PID: 2, Depth: 1, RSSBytes: 400, CPUPercent: 10, Command: "claude"},
+			{PID: 3, Depth: 2, RSSBytes: 100, CPUPercent: 120, Command: "vitest --watch"},
+			{PID: 4, Depth: 3, RSSBytes: 2000, CPUPercent: 20},
+			{PID: 5, Depth: 3, RSSBytes: 20, CPUPercent: 5},
+			{PID: 6, Depth: 2, RSSBytes: 50, CPUPercent: 4},
+		},
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.yaml")
+	content := strings.Join([]string{
+		"max_rss_mb: 2048",
+		"max_cpu_percent: 90",
+		"cpu_sustain_seconds: 30",
+		"max_processes: 40",
+		"action: sigterm",
+	}, "\n")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	if cfg.MaxRSSBytes() != 2048*1024*1024 || cfg.MaxCPUPercent != 90 || cfg.CPUSustainSeconds != 30 || cfg.MaxProcesses != 40 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.Action != ActionSIGTERM {
+		t.Errorf("Action = %q, want %q", cfg.Action, ActionSIGTERM)
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	if cfg.Enabled() {
+		t.Error("expected thresholds disabled by default")
+	}
+	if cfg.Action != ActionNotify {
+		t.Errorf("Action = %q, want %q", cfg.Action, ActionNotify)
+	}
+}
+
+func TestLoadConfigUnknownActionFallsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.yaml")
+	if err := os.WriteFile(path, []byte("max_processes: 5\naction: explode\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	if cfg.Action != ActionNotify {
+		t.Errorf("Action = %q, want fallback %q", cfg.Action, ActionNotify)
+	}
+}
+
+func TestLoadConfigMalformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.yaml")
+	if err := os.WriteFile(path, []byte("max_processes: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestEvaluateRSSAndProcesses(t *testing.T) {
+	e := NewEvaluator(&Config{MaxRSSMB: 1024, MaxProcesses: 4})
+	result := e.Evaluate("api", testUsage(), time.Now())
+
+	if len(result.Active) != 2 || len(result.New) != 2 {
+		t.Fatalf("Active=%d New=%d, want 2/2", len(result.Active), len(result.New))
+	}
+	rss := result.Active[0]
+	if rss.Kind != KindRSS || rss.Offender.PID != 4 {
+		t.Errorf("RSS breach = %+v, want offender PID 4 (largest RSS subprocess)", rss)
+	}
+	if rss.Label != "RSS 3.0G>1.0G" {
+		t.Errorf("RSS label = %q", rss.Label)
+	}
+	procs := result.Active[1]
+	if procs.Kind != KindProcesses || procs.Offender.PID != 3 {
+		t.Errorf("process breach = %+v, want offender PID 3 (largest subtree)", procs)
+	}
+}
+
+func TestEvaluateNeverPicksAgent(t *testing.T) {
+	e := NewEvaluator(&Config{MaxRSSMB: 1024, MaxCPUPercent: 100, MaxProcesses: 2})
+	usage := resource.Usage{
+		RSSBytes:     3 * 1024 * 1024 * 1024,
+		CPUPercent:   150,
+		ProcessCount: 4,
+		Processes: []resource.Process{
+			{PID: 1, Depth: 0, RSSBytes: 10},
+			{PID: 2, Depth: 1, RSSBytes: 3000, CPUPercent: 140, Command: "claude"},
+			{PID: 3, Depth: 2, RSSBytes: 20, CPUPercent: 5, Command: "rg"},
+			{PID: 4, Depth: 1, RSSBytes: 2000, CPUPercent: 100, Command: "claude"},
+		},
+	}
+	result := e.Evaluate("api", usage, time.Now())
+	if len(result.Active) != 3 {
+		t.Fatalf("Active = %d, want 3", len(result.Active))
+	}
+	for _, b := range result.Active {
+		if b.Offender.PID != 3 {
+			t.Errorf("%s offender PID = %d, want the agent subprocess 3", b.Kind, b.Offender.PID)
+		}
+	}
+
+	// Without agent subprocesses there is nothing to kill
+	usage.Processes = usage.Processes[:2]
+	result = e.Evaluate("web", usage, time.Now())
+	for _, b := range result.Active {
+		if b.Offender.PID != 0 {
+			t.Errorf("%s offender = %+v, want none", b.Kind, b.Offender)
+		}
+	}
+}
+
+func TestEvaluatorRetain(t *testing.T) {
+	e := NewEvaluator(&Config{MaxCPUPercent: 100, CPUSustainSeconds: 60, MaxProcesses: 4})
+	now := time.Now()
+	e.Evaluate("api", testUsage(), now)
+	e.Evaluate("web", testUsage(), now)
+
+	e.Retain(map[string]bool{"api": true})
+	if _, ok := e.cpuSince["web"]; ok {
+		t.Error("Retain should drop the CPU timer of a removed session")
+	}
+	if _, ok := e.active["web"]; ok {
+		t.Error("Retain should drop the breaches of a removed session")
+	}
+	if _, ok := e.active["api"]; !ok {
+		t.Error("Retain should keep retained sessions")
+	}
+
+	var nilEval *Evaluator
+	nilEval.Retain(nil)
+}
+
+func TestEvaluateOnlyReportsNewOnce(t *testing.T) {
+	e := NewEvaluator(&Config{MaxProcesses: 4})
+	now := time.Now()
+
+	if got := e.Evaluate("api", testUsage(), now); len(got.New) != 1 {
+		t.Fatalf("first evaluation New = %d, want 1", len(got.New))
+	}
+	if got := e.Evaluate("api", testUsage(), now.Add(time.Second)); len(got.New) != 0 || len(got.Active) != 1 {
+		t.Errorf("second evaluation = %+v, want active but not new", got)
+	}
+
+	// Clearing the breach re-arms it
+	e.Evaluate("api", resource.Usage{ProcessCount: 1}, now.Add(2*time.Second))
+	if got := e.Evaluate("api", testUsage(), now.Add(3*time.Second)); len(got.New) != 1 {
+		t.Errorf("after clearing, New = %d, want 1", len(got.New))
+	}
+}
+
+func TestEvaluateCPUSustained(t *testing.T) {
+	e := NewEvaluator(&Config{MaxCPUPercent: 100, CPUSustainSeconds: 10})
+	now := time.Now()
+
+	if got := e.Evaluate("api", testUsage(), now); len(got.Active) != 0 {
+		t.Fatal("CPU breach should wait for the sustain period")
+	}
+	got := e.Evaluate("api", testUsage(), now.Add(10*time.Second))
+	if len(got.New) != 1 || got.New[0].Kind != KindCPU {
+		t.Fatalf("expected new CPU breach after sustain period, got %+v", got)
+	}
+	if got.New[0].Offender.PID != 3 {
+		t.Errorf("CPU offender PID = %d, want 3", got.New[0].Offender.PID)
+	}
+
+	// Dropping below the limit resets the sustain timer
+	e.Evaluate("api", resource.Usage{CPUPercent: 10}, now.Add(11*time.Second))
+	if got := e.Evaluate("api", testUsage(), now.Add(12*time.Second)); len(got.Active) != 0 {
+		t.Error("sustain timer should restart after CPU drops")
+	}
+}
+
+func TestEvaluateDisabled(t *testing.T) {
+	var nilEval *Evaluator
+	if got := nilEval.Evaluate("api", testUsage(), time.Now()); len(got.Active) != 0 {
+		t.Error("nil evaluator should report nothing")
+	}
+	if got := NewEvaluator(nil).Evaluate("api", testUsage(), time.Now()); len(got.Active) != 0 {
+		t.Error("default config should report nothing")
+	}
+}
+
+func TestLabels(t *testing.T) {
+	if Labels(nil) != nil {
+		t.Error("expected nil labels for no breaches")
+	}
+	got := Labels([]Breach{{Label: "a"}, {Label: "b"}})
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("Labels = %v", got)
+	}
+}
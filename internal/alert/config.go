@@ -0,0 +1,83 @@
+package alert
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/stwalsh4118/navi/internal/pathutil"
+)
+
+const (
+	// DefaultConfigPath is the default path for resource alert configuration.
+	DefaultConfigPath = "~/.config/navi/alerts.yaml"
+
+	// ActionNotify only flags the session and fires a notification.
+	ActionNotify = "notify"
+
+	// ActionSIGTERM additionally sends SIGTERM to the offending descendant process.
+	ActionSIGTERM = "sigterm"
+
+	bytesPerMiB = 1024 * 1024
+)
+
+// Config defines per-session resource thresholds loaded from alerts.yaml.
+// A zero threshold disables that check.
+type Config struct {
+	MaxRSSMB          int64   `yaml:"max_rss_mb"`
+	MaxCPUPercent     float64 `yaml:"max_cpu_percent"`
+	CPUSustainSeconds int     `yaml:"cpu_sustain_seconds"`
+	MaxProcesses      int     `yaml:"max_processes"`
+	Action            string  `yaml:"action"`
+}
+
+// DefaultConfig returns a configuration with all thresholds disabled.
+func DefaultConfig() *Config {
+	return &Config{Action: ActionNotify}
+}
+
+// Enabled reports whether any threshold is configured.
+func (c *Config) Enabled() bool {
+	return c != nil && (c.MaxRSSMB > 0 || c.MaxCPUPercent > 0 || c.MaxProcesses > 0)
+}
+
+// MaxRSSBytes returns the RSS threshold in bytes.
+func (c *Config) MaxRSSBytes() int64 {
+	return c.MaxRSSMB * bytesPerMiB
+}
+
+// LoadConfig reads and parses the alert configuration from YAML.
+// Missing files return defaults and no error.
+func LoadConfig(path string) (*Config, error) {
+	configPath := path
+	if configPath == "" {
+		configPath = DefaultConfigPath
+	}
+	configPath = pathutil.ExpandPath(configPath)
+
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return DefaultConfig(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := DefaultConfig()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse alert config: %w", err)
+	}
+
+	if cfg.Action != ActionNotify && cfg.Action != ActionSIGTERM {
+		if cfg.Action != "" {
+			fmt.Fprintf(os.Stderr, "Warning: unknown alert action %q, using %q\n", cfg.Action, ActionNotify)
+		}
+		cfg.Action = ActionNotify
+	}
+	if cfg.CPUSustainSeconds < 0 {
+		cfg.CPUSustainSeconds = 0
+	}
+
+	return cfg, nil
+}
@@ -83,6 +83,7 @@ func DefaultConfig() *Config {
 			"stopped":    false,
 			"done":       true,
 			"error":      true,
+			"alert":      true,
 		},
 		Files: make(map[string]string),
 		TTS: TTSConfig{
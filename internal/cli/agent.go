@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/stwalsh4118/navi/internal/agent"
+	"github.com/stwalsh4118/navi/internal/pathutil"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// RunAgent streams status file changes as JSON lines on stdout for a local
+// navi connected over SSH. It exits when stdin closes, on SIGINT/SIGTERM, or
+// when stdout can no longer be written.
+func RunAgent(args []string) int {
+	fs := flag.NewFlagSet("agent", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	dir := fs.String("dir", session.StatusDir, "session status directory to watch")
+	interval := fs.Duration("interval", agent.DefaultInterval, "how often to scan for changes")
+	heartbeat := fs.Duration("heartbeat", agent.DefaultHeartbeat, "idle time before a heartbeat is sent")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The SSH client closes stdin when it tears down the channel
+	go func() {
+		_, _ = io.Copy(io.Discard, os.Stdin)
+		stop()
+	}()
+
+	if err := agent.Run(ctx, pathutil.ExpandPath(*dir), os.Stdout, *interval, *heartbeat); err != nil {
+		fmt.Fprintf(os.Stderr, "agent stopped: %v\n", err)
+		return 1
+	}
+	return 0
+}
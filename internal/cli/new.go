@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/stwalsh4118/navi/internal/pathutil"
+	"github.com/stwalsh4118/navi/internal/remote"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+const defaultNewSessionName = "claude"
+
+// RunNew creates a tmux session running claude, locally or on a configured
+// remote, and seeds its status file.
+func RunNew(args []string) int {
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: navi new [--remote name] [--dir path] [--skip-permissions] [session-name]")
+		fs.PrintDefaults()
+	}
+
+	remoteName := fs.String("remote", "", "create the session on this remote from remotes.yaml")
+	dir := fs.String("dir", "", "working directory (default: current directory, or ~ on a remote)")
+	skipPerms := fs.Bool("skip-permissions", false, "start claude with --dangerously-skip-permissions")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 1
+	}
+
+	name := defaultNewSessionName
+	if fs.NArg() == 1 {
+		name = strings.TrimSpace(fs.Arg(0))
+	}
+	if !session.ValidName(name) {
+		fmt.Fprintf(os.Stderr, "invalid session name %q: must be non-empty without '.', ':' or shell metacharacters\n", name)
+		return 1
+	}
+
+	if *remoteName != "" {
+		return newRemoteSession(*remoteName, name, *dir, *skipPerms)
+	}
+
+	target := *dir
+	if target == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get current directory: %v\n", err)
+			return 1
+		}
+		target = cwd
+	}
+	target = pathutil.ExpandPath(target)
+	if info, err := os.Stat(target); err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "directory does not exist: %s\n", target)
+		return 1
+	}
+
+	if err := session.Create(name, target, *skipPerms, pathutil.ExpandPath(session.StatusDir)); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create session %q: %v\n", name, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stdout, "created %s in %s\n", name, target)
+	return 0
+}
+
+func newRemoteSession(remoteName, name, dir string, skipPerms bool) int {
+	remotes, err := remote.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load remotes config: %v\n", err)
+		return 1
+	}
+	rc := remote.GetByName(remotes, remoteName)
+	if rc == nil {
+		fmt.Fprintf(os.Stderr, "remote %q not found in %s\n", remoteName, remote.ConfigPath)
+		return 1
+	}
+
+	pool := remote.NewSSHPool(remotes)
+	defer pool.Close()
+
+	if err := remote.CreateSession(pool, remoteName, name, dir, skipPerms, rc.SessionsDir); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create session %q on %s: %v\n", name, remoteName, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stdout, "created %s on %s\n", name, remoteName)
+	return 0
+}
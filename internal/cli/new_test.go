@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunNewRejectsInvalidName(t *testing.T) {
+	if code := RunNew([]string{"bad.name"}); code != 1 {
+		t.Errorf("RunNew(bad.name) = %d, want 1", code)
+	}
+	if code := RunNew([]string{"x$(rm -rf ~)"}); code != 1 {
+		t.Errorf("RunNew with a shell metacharacter = %d, want 1", code)
+	}
+	if code := RunNew([]string{"a", "b"}); code != 1 {
+		t.Errorf("RunNew with two names = %d, want 1", code)
+	}
+}
+
+func TestRunNewMissingDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "missing")
+	if code := RunNew([]string{"--dir", dir, "api"}); code != 1 {
+		t.Errorf("RunNew with missing dir = %d, want 1", code)
+	}
+}
+
+func TestRunNewUnknownRemote(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configDir := filepath.Join(home, ".config", "navi")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := "remotes:\n  - name: devbox\n    host: devbox\n"
+	if err := os.WriteFile(filepath.Join(configDir, "remotes.yaml"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := RunNew([]string{"--remote", "nope", "api"}); code != 1 {
+		t.Errorf("RunNew with unknown remote = %d, want 1", code)
+	}
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/remote"
+	"github.com/stwalsh4118/navi/internal/session"
+	"github.com/stwalsh4118/navi/internal/tui"
+)
+
+// RunPopup shows the compact session switcher, meant to run inside
+// `tmux display-popup -E`, and switches to the chosen session.
+func RunPopup(args []string) int {
+	fs := flag.NewFlagSet("popup", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: navi popup")
+		fmt.Fprintln(os.Stderr, "  e.g. tmux bind-key s display-popup -E -w 80% -h 60% navi popup")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 1
+	}
+
+	// Remotes are optional; invalid entries are skipped as in the TUI
+	remotes, _, err := remote.LoadConfigFile(remote.ExpandConfigPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load remotes config: %v\n", err)
+		remotes = nil
+	}
+	var pool *remote.SSHPool
+	if len(remotes) > 0 {
+		pool = remote.NewSSHPool(remotes)
+		// The popup exits within seconds, so an agent stream would be
+		// dialed and torn down on every open; a single cat poll is cheaper
+		pool.DisableAgentStreams()
+		defer pool.Close()
+	}
+
+	final, err := tea.NewProgram(tui.NewPopupModel(pool, remotes), tea.WithAltScreen()).Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	s, ok := final.(tui.PopupModel).Chosen()
+	if !ok {
+		return 0
+	}
+
+	command, err := switchCommand(s, remotes, os.Getenv("TMUX") != "")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	c := exec.Command(command[0], command[1:]...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to switch to %s: %v\n", s.Label(), err)
+		return 1
+	}
+	return 0
+}
+
+// switchCommand returns the command that takes the user to s. Inside tmux,
+// local sessions are switched to with switch-client and remote sessions are
+// attached in a new window, since the popup closes once navi exits. Outside
+// tmux the session is attached in the current terminal.
+func switchCommand(s session.Info, remotes []remote.Config, inTmux bool) ([]string, error) {
+	if s.Remote == "" {
+		if inTmux {
+			return []string{"tmux", "switch-client", "-t", s.TmuxSession}, nil
+		}
+		return []string{"tmux", "attach-session", "-t", s.TmuxSession}, nil
+	}
+
+	rc := remote.GetByName(remotes, s.Remote)
+	if rc == nil {
+		return nil, fmt.Errorf("remote %q not found in %s", s.Remote, remote.ConfigPath)
+	}
+	attach := remote.BuildAttachCommand(rc, s.TmuxSession)
+	if inTmux {
+		return append([]string{"tmux", "new-window", "-n", s.Label()}, attach...), nil
+	}
+	return attach, nil
+}
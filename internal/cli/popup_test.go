@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stwalsh4118/navi/internal/remote"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+func TestSwitchCommand(t *testing.T) {
+	remotes := []remote.Config{{Name: "ctr", Transport: remote.TransportCommand, Command: "docker exec -i dev sh -c"}}
+	local := session.Info{TmuxSession: "api"}
+	remoteSession := session.Info{TmuxSession: "web", Remote: "ctr"}
+	attach := remote.BuildAttachCommand(&remotes[0], "web")
+
+	tests := []struct {
+		name   string
+		s      session.Info
+		inTmux bool
+		want   []string
+	}{
+		{"local in tmux switches the client", local, true, []string{"tmux", "switch-client", "-t", "api"}},
+		{"local outside tmux attaches", local, false, []string{"tmux", "attach-session", "-t", "api"}},
+		{"remote in tmux opens a window", remoteSession, true, append([]string{"tmux", "new-window", "-n", "web@ctr"}, attach...)},
+		{"remote outside tmux attaches", remoteSession, false, attach},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := switchCommand(tt.s, remotes, tt.inTmux)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("switchCommand = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := switchCommand(session.Info{TmuxSession: "x", Remote: "gone"}, remotes, true); err == nil {
+		t.Error("expected an error for an unknown remote")
+	}
+}
+
+func TestRunPopupRejectsArguments(t *testing.T) {
+	if code := RunPopup([]string{"extra"}); code != 1 {
+		t.Errorf("RunPopup(extra) = %d, want 1", code)
+	}
+}
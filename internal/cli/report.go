@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/stwalsh4118/navi/internal/metrics"
+	"github.com/stwalsh4118/navi/internal/pathutil"
+	"github.com/stwalsh4118/navi/internal/session"
+	"github.com/stwalsh4118/navi/internal/toolstats"
+)
+
+// reportMixTools are the tools whose share of calls is shown in the mix line.
+var reportMixTools = []string{"Bash", "Edit", "Read"}
+
+// reportGroup is one section of the tool report (a session or a project).
+type reportGroup struct {
+	Name    string            `json:"name"`
+	Summary toolstats.Summary `json:"summary"`
+}
+
+// RunReport executes the tool timing and failure report command.
+func RunReport(args []string) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	sessionName := fs.String("session", "", "report on a single session")
+	project := fs.String("project", "", "report on a single project directory")
+	limit := fs.Int("limit", 10, "maximum tools listed per group (0 for all)")
+	format := fs.String("format", "plain", "output format (plain|json)")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	if *format != "plain" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "invalid format: %s\n", *format)
+		return 1
+	}
+
+	statusDir := pathutil.ExpandPath(session.StatusDir)
+	var calls []toolstats.Call
+	var err error
+	if *sessionName != "" {
+		calls, err = toolstats.ReadSessionCalls(statusDir, *sessionName)
+	} else {
+		calls, err = toolstats.ReadAllCalls(statusDir)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed reading tool call logs: %v\n", err)
+		return 1
+	}
+
+	groups := buildReportGroups(calls, *sessionName, pathutil.ExpandPath(*project))
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(groups); err != nil {
+			fmt.Fprintf(os.Stderr, "failed encoding report: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(groups) == 0 {
+		fmt.Fprintln(os.Stdout, "no tool calls recorded")
+		return 0
+	}
+	writeReport(os.Stdout, groups, *limit)
+	return 0
+}
+
+// buildReportGroups summarizes calls for the requested scope. A session report
+// yields a single group; otherwise calls are grouped per project, busiest first.
+func buildReportGroups(calls []toolstats.Call, sessionName, project string) []reportGroup {
+	if project != "" {
+		calls = toolstats.FilterProject(calls, project)
+	}
+	if len(calls) == 0 {
+		return nil
+	}
+
+	if sessionName != "" {
+		return []reportGroup{{Name: "session " + sessionName, Summary: toolstats.Summarize(calls)}}
+	}
+	if project != "" {
+		return []reportGroup{{Name: project, Summary: toolstats.Summarize(calls)}}
+	}
+
+	var groups []reportGroup
+	for dir, projectCalls := range toolstats.GroupByProject(calls) {
+		groups = append(groups, reportGroup{Name: dir, Summary: toolstats.Summarize(projectCalls)})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Summary.Calls != groups[j].Summary.Calls {
+			return groups[i].Summary.Calls > groups[j].Summary.Calls
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// writeReport writes the plain-text report: a header per group followed by a
+// table of tools, slowest first.
+func writeReport(w io.Writer, groups []reportGroup, limit int) {
+	for i, g := range groups {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		s := g.Summary
+		fmt.Fprintf(w, "%s: %d calls, %.1f%% failed, total %s\n",
+			g.Name, s.Calls, s.FailureRate()*100, metrics.FormatMillis(s.TotalMs))
+
+		mix := make([]string, 0, len(reportMixTools))
+		for _, tool := range reportMixTools {
+			mix = append(mix, fmt.Sprintf("%s %.0f%%", tool, s.Share(tool)*100))
+		}
+		fmt.Fprintf(w, "  mix: %s\n", strings.Join(mix, ", "))
+
+		fmt.Fprintf(w, "  %-24s %6s %6s %8s %8s\n", "TOOL", "CALLS", "FAIL%", "AVG", "MAX")
+		tools := s.Tools
+		if limit > 0 && len(tools) > limit {
+			tools = tools[:limit]
+		}
+		for _, st := range tools {
+			fmt.Fprintf(w, "  %-24s %6d %5.1f%% %8s %8s\n",
+				st.Tool, st.Calls, st.FailureRate()*100,
+				metrics.FormatMillis(st.AvgMs()), metrics.FormatMillis(st.MaxMs))
+		}
+		if len(tools) < len(s.Tools) {
+			fmt.Fprintf(w, "  ... and %d more tools\n", len(s.Tools)-len(tools))
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stwalsh4118/navi/internal/session"
+	"github.com/stwalsh4118/navi/internal/toolstats"
+)
+
+func writeCallLog(t *testing.T, statusDir, sessionName string, calls []toolstats.Call) {
+	t.Helper()
+	dir := toolstats.LogDir(statusDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	for _, c := range calls {
+		line, err := json.Marshal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if err := os.WriteFile(filepath.Join(dir, sessionName+".jsonl"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setupReportStatusDir(t *testing.T) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	writeCallLog(t, tmpDir, "api", []toolstats.Call{
+		{Tool: "Bash", Session: "api", CWD: "/work/navi", DurationMs: 3000, Error: true},
+		{Tool: "Bash", Session: "api", CWD: "/work/navi", DurationMs: 1000},
+		{Tool: "Edit", Session: "api", CWD: "/work/navi", DurationMs: 100},
+	})
+	writeCallLog(t, tmpDir, "web", []toolstats.Call{
+		{Tool: "Read", Session: "web", CWD: "/work/site", DurationMs: 20},
+	})
+
+	origStatusDir := session.StatusDir
+	session.StatusDir = tmpDir
+	t.Cleanup(func() { session.StatusDir = origStatusDir })
+}
+
+func TestBuildReportGroupsPerProject(t *testing.T) {
+	calls := []toolstats.Call{
+		{Tool: "Read", CWD: "/b"},
+		{Tool: "Bash", CWD: "/a"},
+		{Tool: "Edit", CWD: "/a"},
+	}
+
+	groups := buildReportGroups(calls, "", "")
+	if len(groups) != 2 {
+		t.Fatalf("len(groups) = %d, want 2", len(groups))
+	}
+	if groups[0].Name != "/a" || groups[0].Summary.Calls != 2 {
+		t.Errorf("first group = %+v, want /a with 2 calls", groups[0])
+	}
+}
+
+func TestBuildReportGroupsEmpty(t *testing.T) {
+	if groups := buildReportGroups(nil, "", ""); groups != nil {
+		t.Errorf("groups = %v, want nil", groups)
+	}
+}
+
+func TestRunReportPlain(t *testing.T) {
+	setupReportStatusDir(t)
+
+	stdout, stderr := captureOutput(t, func() {
+		if code := RunReport(nil); code != 0 {
+			t.Fatalf("RunReport() code = %d, want 0", code)
+		}
+	})
+	if stderr != "" {
+		t.Fatalf("RunReport() stderr = %q, want empty", stderr)
+	}
+
+	for _, want := range []string{"/work/navi: 3 calls, 33.3% failed", "mix: Bash 67%, Edit 33%, Read 0%", "/work/site: 1 calls"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout)
+		}
+	}
+	if strings.Index(stdout, "  Bash ") > strings.Index(stdout, "  Edit ") {
+		t.Errorf("expected slowest tool first:\n%s", stdout)
+	}
+}
+
+func TestRunReportSessionJSON(t *testing.T) {
+	setupReportStatusDir(t)
+
+	stdout, _ := captureOutput(t, func() {
+		if code := RunReport([]string{"--session", "api", "--format", "json"}); code != 0 {
+			t.Fatalf("RunReport() code = %d, want 0", code)
+		}
+	})
+
+	var groups []reportGroup
+	if err := json.Unmarshal([]byte(stdout), &groups); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v\n%s", err, stdout)
+	}
+	if len(groups) != 1 || groups[0].Summary.Calls != 3 || groups[0].Summary.Errors != 1 {
+		t.Errorf("unexpected session report: %+v", groups)
+	}
+	if groups[0].Summary.Tools[0].Tool != "Bash" || groups[0].Summary.Tools[0].MaxMs != 3000 {
+		t.Errorf("slowest tool = %+v, want Bash with max 3000ms", groups[0].Summary.Tools[0])
+	}
+}
+
+func TestRunReportProjectFilter(t *testing.T) {
+	setupReportStatusDir(t)
+
+	stdout, _ := captureOutput(t, func() {
+		if code := RunReport([]string{"--project", "/work/site"}); code != 0 {
+			t.Fatalf("RunReport() code = %d, want 0", code)
+		}
+	})
+	if strings.Contains(stdout, "/work/navi") || !strings.Contains(stdout, "/work/site: 1 calls") {
+		t.Errorf("unexpected project report:\n%s", stdout)
+	}
+}
+
+func TestRunReportInvalidFormat(t *testing.T) {
+	_, stderr := captureOutput(t, func() {
+		if code := RunReport([]string{"--format", "xml"}); code != 1 {
+			t.Fatalf("RunReport(invalid) code = %d, want 1", code)
+		}
+	})
+	if !strings.Contains(stderr, "invalid format") {
+		t.Errorf("stderr = %q, want invalid format message", stderr)
+	}
+}
+
+func TestRunReportNoCalls(t *testing.T) {
+	origStatusDir := session.StatusDir
+	session.StatusDir = t.TempDir()
+	t.Cleanup(func() { session.StatusDir = origStatusDir })
+
+	stdout, _ := captureOutput(t, func() {
+		if code := RunReport(nil); code != 0 {
+			t.Fatalf("RunReport() code = %d, want 0", code)
+		}
+	})
+	if stdout != "no tool calls recorded\n" {
+		t.Errorf("stdout = %q", stdout)
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/stwalsh4118/navi/internal/pathutil"
+	"github.com/stwalsh4118/navi/internal/uistate"
+)
+
+// RunState handles the `navi state` subcommand, which shows or resets the
+// view settings the TUI restores on start.
+func RunState(args []string) int {
+	return runState(args, uistate.DefaultPath, os.Stdout)
+}
+
+func runState(args []string, path string, w io.Writer) int {
+	if len(args) != 1 {
+		printStateUsage()
+		return exitError
+	}
+
+	switch args[0] {
+	case "show":
+		s, err := uistate.Load(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading UI state: %v\n", err)
+			return exitError
+		}
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitError
+		}
+		fmt.Fprintf(w, "# %s\n%s\n", pathutil.ExpandPath(path), data)
+		return exitOK
+	case "reset":
+		if err := uistate.Reset(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error resetting UI state: %v\n", err)
+			return exitError
+		}
+		fmt.Fprintln(w, "UI state reset; navi starts with the default view next time.")
+		return exitOK
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown state subcommand: %q\n", args[0])
+		printStateUsage()
+		return exitError
+	}
+}
+
+func printStateUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: navi state <command>")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  show    Print the saved view settings")
+	fmt.Fprintln(os.Stderr, "  reset   Forget them so navi starts with the default view")
+}
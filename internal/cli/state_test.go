@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stwalsh4118/navi/internal/uistate"
+)
+
+func TestRunStateUsage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ui-state.json")
+	for _, args := range [][]string{nil, {"unknown"}, {"show", "extra"}} {
+		if code := runState(args, path, &bytes.Buffer{}); code != exitError {
+			t.Errorf("runState(%q) = %d, want %d", args, code, exitError)
+		}
+	}
+}
+
+func TestRunStateShowAndReset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ui-state.json")
+	if err := uistate.Save(path, uistate.State{Sort: "name", HideOffline: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if code := runState([]string{"show"}, path, &out); code != exitOK {
+		t.Fatalf("show exit code = %d", code)
+	}
+	if !strings.Contains(out.String(), `"sort": "name"`) || !strings.Contains(out.String(), path) {
+		t.Errorf("show output = %q", out.String())
+	}
+
+	if code := runState([]string{"reset"}, path, &bytes.Buffer{}); code != exitOK {
+		t.Fatalf("reset exit code = %d", code)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("reset left the state file: %v", err)
+	}
+	if code := runState([]string{"reset"}, path, &bytes.Buffer{}); code != exitOK {
+		t.Errorf("resetting twice should succeed, got %d", code)
+	}
+}
@@ -306,6 +306,17 @@ func GetDiff(dir string) string {
 	return result
 }
 
+// GetLog returns the last n commits with their file stats.
+func GetLog(dir string, n int) string {
+	cmd := exec.Command("git", "log", "--stat", "-n", strconv.Itoa(n))
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 // GetInfo collects all git information for the given directory.
 // Returns nil if the directory is not a git repository.
 func GetInfo(dir string) *Info {
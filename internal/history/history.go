@@ -0,0 +1,220 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stwalsh4118/navi/internal/pathutil"
+)
+
+// Store constants
+const (
+	// DefaultPath is where the metrics history is persisted between runs.
+	DefaultPath = "~/.config/navi/metrics-history.json"
+
+	// MaxSamples bounds the number of samples kept per session (1h at SampleInterval).
+	MaxSamples = 360
+
+	// SampleInterval is the minimum spacing between two recorded samples for a session.
+	SampleInterval = 10 * time.Second
+
+	// RetentionPeriod is how long a series is kept after its last sample.
+	RetentionPeriod = 24 * time.Hour
+)
+
+// Sample is a single point-in-time observation of a session.
+type Sample struct {
+	Timestamp int64  `json:"ts"`
+	Tokens    int64  `json:"tokens"`
+	RSSBytes  int64  `json:"rss"`
+	ToolCount int    `json:"tools"`
+	Status    string `json:"status"`
+}
+
+// Store holds a bounded time series of samples per session key.
+// It is safe for concurrent use.
+type Store struct {
+	mu     sync.Mutex
+	path   string
+	series map[string][]Sample
+}
+
+// NewStore creates an empty store that persists to path.
+func NewStore(path string) *Store {
+	return &Store{
+		path:   path,
+		series: make(map[string][]Sample),
+	}
+}
+
+// Load reads a store from path. A missing file yields an empty store.
+func Load(path string) (*Store, error) {
+	s := NewStore(path)
+
+	data, err := os.ReadFile(pathutil.ExpandPath(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return s, err
+	}
+
+	var series map[string][]Sample
+	if err := json.Unmarshal(data, &series); err != nil {
+		return s, err
+	}
+	for key, samples := range series {
+		s.series[key] = trimSamples(samples)
+	}
+
+	return s, nil
+}
+
+// Record appends a sample for key. Samples closer than SampleInterval to the
+// previous one are dropped. Returns true if the sample was stored.
+func (s *Store) Record(key string, sample Sample) bool {
+	if s == nil || key == "" {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing := s.series[key]
+	if n := len(existing); n > 0 {
+		last := existing[n-1]
+		if sample.Timestamp-last.Timestamp < int64(SampleInterval/time.Second) {
+			return false
+		}
+	}
+
+	s.series[key] = trimSamples(append(existing, sample))
+	return true
+}
+
+// Series returns a copy of the samples recorded for key, oldest first.
+func (s *Store) Series(key string) []Sample {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	samples := s.series[key]
+	if len(samples) == 0 {
+		return nil
+	}
+	return append([]Sample(nil), samples...)
+}
+
+// Save prunes expired series and writes the store atomically to disk.
+func (s *Store) Save() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.pruneLocked(time.Now())
+	data, err := json.Marshal(s.series)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	path := pathutil.ExpandPath(s.path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "metrics-history-*.json")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
+
+// pruneLocked drops series whose newest sample is older than RetentionPeriod.
+// Caller must hold s.mu.
+func (s *Store) pruneLocked(now time.Time) {
+	cutoff := now.Add(-RetentionPeriod).Unix()
+	for key, samples := range s.series {
+		if len(samples) == 0 || samples[len(samples)-1].Timestamp < cutoff {
+			delete(s.series, key)
+		}
+	}
+}
+
+// trimSamples keeps only the newest MaxSamples entries.
+func trimSamples(samples []Sample) []Sample {
+	if len(samples) <= MaxSamples {
+		return samples
+	}
+	return append([]Sample(nil), samples[len(samples)-MaxSamples:]...)
+}
+
+// TokenRate returns tokens per minute between consecutive samples.
+// Negative deltas (e.g. a new transcript) are reported as zero.
+func TokenRate(samples []Sample) []float64 {
+	if len(samples) < 2 {
+		return nil
+	}
+
+	rates := make([]float64, 0, len(samples)-1)
+	for i := 1; i < len(samples); i++ {
+		elapsed := samples[i].Timestamp - samples[i-1].Timestamp
+		delta := samples[i].Tokens - samples[i-1].Tokens
+		if elapsed <= 0 || delta < 0 {
+			rates = append(rates, 0)
+			continue
+		}
+		rates = append(rates, float64(delta)*60/float64(elapsed))
+	}
+	return rates
+}
+
+// RSSValues returns the RSS bytes of each sample as a float series.
+func RSSValues(samples []Sample) []float64 {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	values := make([]float64, len(samples))
+	for i, sample := range samples {
+		values[i] = float64(sample.RSSBytes)
+	}
+	return values
+}
+
+// StatusTimeline returns the status of each sample, oldest first.
+func StatusTimeline(samples []Sample) []string {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	statuses := make([]string, len(samples))
+	for i, sample := range samples {
+		statuses[i] = sample.Status
+	}
+	return statuses
+}
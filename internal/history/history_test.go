@@ -0,0 +1,161 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecord_RespectsSampleInterval(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "history.json"))
+	now := time.Now().Unix()
+
+	if !s.Record("api", Sample{Timestamp: now, Tokens: 100}) {
+		t.Fatal("first sample should be recorded")
+	}
+	if s.Record("api", Sample{Timestamp: now + 1, Tokens: 200}) {
+		t.Error("sample within SampleInterval should be dropped")
+	}
+	if !s.Record("api", Sample{Timestamp: now + int64(SampleInterval/time.Second), Tokens: 300}) {
+		t.Error("sample after SampleInterval should be recorded")
+	}
+
+	if got := len(s.Series("api")); got != 2 {
+		t.Errorf("len(Series) = %d, want 2", got)
+	}
+}
+
+func TestRecord_BoundedToMaxSamples(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "history.json"))
+	step := int64(SampleInterval / time.Second)
+	start := time.Now().Unix()
+
+	for i := 0; i < MaxSamples+25; i++ {
+		s.Record("api", Sample{Timestamp: start + int64(i)*step, Tokens: int64(i)})
+	}
+
+	series := s.Series("api")
+	if len(series) != MaxSamples {
+		t.Fatalf("len(Series) = %d, want %d", len(series), MaxSamples)
+	}
+	if series[0].Tokens != 25 {
+		t.Errorf("oldest sample tokens = %d, want 25", series[0].Tokens)
+	}
+}
+
+func TestRecord_EmptyKeyIgnored(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "history.json"))
+	if s.Record("", Sample{Timestamp: 1}) {
+		t.Error("empty key should not be recorded")
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.json")
+	now := time.Now().Unix()
+
+	s := NewStore(path)
+	s.Record("api", Sample{Timestamp: now, Tokens: 10, RSSBytes: 2048, ToolCount: 3, Status: "working"})
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	series := loaded.Series("api")
+	if len(series) != 1 {
+		t.Fatalf("len(Series) = %d, want 1", len(series))
+	}
+	want := Sample{Timestamp: now, Tokens: 10, RSSBytes: 2048, ToolCount: 3, Status: "working"}
+	if series[0] != want {
+		t.Errorf("loaded sample = %+v, want %+v", series[0], want)
+	}
+}
+
+func TestSave_PrunesExpiredSeries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	old := time.Now().Add(-RetentionPeriod - time.Hour).Unix()
+
+	s := NewStore(path)
+	s.Record("stale", Sample{Timestamp: old})
+	s.Record("fresh", Sample{Timestamp: time.Now().Unix()})
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	if s.Series("stale") != nil {
+		t.Error("expired series should be pruned on save")
+	}
+	if s.Series("fresh") == nil {
+		t.Error("fresh series should be retained")
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if s.Series("api") != nil {
+		t.Error("expected empty store for missing file")
+	}
+}
+
+func TestLoad_MalformedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(path)
+	if err == nil {
+		t.Error("expected error for malformed file")
+	}
+	if s == nil {
+		t.Fatal("expected usable empty store even on error")
+	}
+}
+
+func TestTokenRate(t *testing.T) {
+	samples := []Sample{
+		{Timestamp: 0, Tokens: 0},
+		{Timestamp: 60, Tokens: 600},
+		{Timestamp: 90, Tokens: 900},
+		{Timestamp: 120, Tokens: 100}, // transcript reset
+	}
+
+	rates := TokenRate(samples)
+	want := []float64{600, 600, 0}
+	if len(rates) != len(want) {
+		t.Fatalf("len(rates) = %d, want %d", len(rates), len(want))
+	}
+	for i := range want {
+		if rates[i] != want[i] {
+			t.Errorf("rates[%d] = %v, want %v", i, rates[i], want[i])
+		}
+	}
+
+	if TokenRate(samples[:1]) != nil {
+		t.Error("single sample should yield no rate")
+	}
+}
+
+func TestRSSValuesAndStatusTimeline(t *testing.T) {
+	samples := []Sample{
+		{RSSBytes: 1024, Status: "working"},
+		{RSSBytes: 2048, Status: "waiting"},
+	}
+
+	rss := RSSValues(samples)
+	if len(rss) != 2 || rss[0] != 1024 || rss[1] != 2048 {
+		t.Errorf("RSSValues = %v, want [1024 2048]", rss)
+	}
+
+	statuses := StatusTimeline(samples)
+	if len(statuses) != 2 || statuses[0] != "working" || statuses[1] != "waiting" {
+		t.Errorf("StatusTimeline = %v, want [working waiting]", statuses)
+	}
+}
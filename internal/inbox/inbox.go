@@ -0,0 +1,245 @@
+package inbox
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stwalsh4118/navi/internal/pathutil"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// Store constants
+const (
+	// DefaultPath is where the inbox is persisted between runs.
+	DefaultPath = "~/.config/navi/inbox.json"
+
+	// MaxItems bounds the number of items kept; the oldest are dropped first.
+	MaxItems = 500
+
+	// RetentionPeriod is how long an item is kept after it was recorded.
+	RetentionPeriod = 7 * 24 * time.Hour
+)
+
+// Transition constants
+const (
+	// StatusStalled marks a session that has reported working without any
+	// status update for StallAfter.
+	StatusStalled = "stalled"
+
+	// StallAfter is how long a working session may go without a status update
+	// before it is considered stalled.
+	StallAfter = 10 * time.Minute
+
+	// SourceClaude is the source of transitions reported by Claude Code itself.
+	SourceClaude = "claude"
+)
+
+// priorityStatuses are the statuses whose transitions are recorded.
+var priorityStatuses = map[string]bool{
+	session.StatusPermission: true,
+	session.StatusWaiting:    true,
+	session.StatusError:      true,
+	session.StatusDone:       true,
+	StatusStalled:            true,
+}
+
+// IsPriority reports whether a transition to status belongs in the inbox.
+func IsPriority(status string) bool {
+	return priorityStatuses[status]
+}
+
+// Item is one recorded transition.
+type Item struct {
+	ID        int64  `json:"id"`
+	Timestamp int64  `json:"ts"`
+	Session   string `json:"session"`    // session.Info.Label
+	SessionID string `json:"session_id"` // session.Info.ID when recorded
+	Status    string `json:"status"`
+	Message   string `json:"message,omitempty"`
+	Source    string `json:"source"` // SourceClaude, an external agent type, or a teammate name
+	Acked     bool   `json:"acked,omitempty"`
+}
+
+// Store holds recorded transitions, oldest first. It is safe for concurrent use.
+type Store struct {
+	mu     sync.Mutex
+	path   string
+	items  []Item
+	nextID int64
+}
+
+// NewStore creates an empty store that persists to path.
+func NewStore(path string) *Store {
+	return &Store{path: path, nextID: 1}
+}
+
+// Load reads a store from path. A missing file yields an empty store.
+func Load(path string) (*Store, error) {
+	s := NewStore(path)
+
+	data, err := os.ReadFile(pathutil.ExpandPath(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return s, err
+	}
+
+	var items []Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return s, err
+	}
+	s.items = items
+	for _, item := range items {
+		s.nextID = max(s.nextID, item.ID+1)
+	}
+	return s, nil
+}
+
+// Add records item, assigning its ID, and returns it.
+func (s *Store) Add(item Item) Item {
+	if s == nil {
+		return item
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item.ID = s.nextID
+	s.nextID++
+	s.items = append(s.items, item)
+	if len(s.items) > MaxItems {
+		s.items = append([]Item(nil), s.items[len(s.items)-MaxItems:]...)
+	}
+	return item
+}
+
+// Items returns a copy of the recorded items, newest first.
+func (s *Store) Items() []Item {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := make([]Item, len(s.items))
+	for i, item := range s.items {
+		items[len(s.items)-1-i] = item
+	}
+	return items
+}
+
+// Unread returns the number of items not yet acknowledged.
+func (s *Store) Unread() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, item := range s.items {
+		if !item.Acked {
+			n++
+		}
+	}
+	return n
+}
+
+// Ack acknowledges the item with id. Returns true if it was unread.
+func (s *Store) Ack(id int64) bool {
+	if s == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.items {
+		if s.items[i].ID == id {
+			changed := !s.items[i].Acked
+			s.items[i].Acked = true
+			return changed
+		}
+	}
+	return false
+}
+
+// AckAll acknowledges every item and returns how many were unread.
+func (s *Store) AckAll() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for i := range s.items {
+		if !s.items[i].Acked {
+			s.items[i].Acked = true
+			n++
+		}
+	}
+	return n
+}
+
+// Save prunes expired items and writes the store atomically to disk.
+func (s *Store) Save() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.pruneLocked(time.Now())
+	data, err := json.Marshal(s.items)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	path := pathutil.ExpandPath(s.path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "inbox-*.json")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
+
+// pruneLocked drops items older than RetentionPeriod. Caller must hold s.mu.
+func (s *Store) pruneLocked(now time.Time) {
+	cutoff := now.Add(-RetentionPeriod).Unix()
+	kept := s.items[:0]
+	for _, item := range s.items {
+		if item.Timestamp >= cutoff {
+			kept = append(kept, item)
+		}
+	}
+	s.items = kept
+}
@@ -0,0 +1,128 @@
+package inbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAdd_AssignsIDsNewestFirst(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "inbox.json"))
+	now := time.Now().Unix()
+
+	first := s.Add(Item{Timestamp: now, Session: "api", Status: "waiting"})
+	second := s.Add(Item{Timestamp: now + 1, Session: "web", Status: "done"})
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("IDs = %d, %d, want 1, 2", first.ID, second.ID)
+	}
+
+	items := s.Items()
+	if len(items) != 2 || items[0].Session != "web" {
+		t.Errorf("Items = %+v, want web first", items)
+	}
+	if s.Unread() != 2 {
+		t.Errorf("Unread = %d, want 2", s.Unread())
+	}
+}
+
+func TestAdd_BoundedToMaxItems(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "inbox.json"))
+	for i := 0; i < MaxItems+10; i++ {
+		s.Add(Item{Timestamp: int64(i), Session: "api"})
+	}
+	items := s.Items()
+	if len(items) != MaxItems || items[len(items)-1].ID != 11 {
+		t.Errorf("kept %d items from ID %d, want %d from 11", len(items), items[len(items)-1].ID, MaxItems)
+	}
+}
+
+func TestAck(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "inbox.json"))
+	a := s.Add(Item{Session: "api"})
+	s.Add(Item{Session: "web"})
+
+	if !s.Ack(a.ID) {
+		t.Error("first ack should report a change")
+	}
+	if s.Ack(a.ID) {
+		t.Error("second ack should report no change")
+	}
+	if s.Unread() != 1 {
+		t.Errorf("Unread = %d, want 1", s.Unread())
+	}
+	if n := s.AckAll(); n != 1 || s.Unread() != 0 {
+		t.Errorf("AckAll = %d leaving %d unread, want 1 leaving 0", n, s.Unread())
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "inbox.json")
+	s := NewStore(path)
+	now := time.Now().Unix()
+	a := s.Add(Item{Timestamp: now, Session: "api", Status: "permission", Message: "Bash", Source: SourceClaude})
+	s.Add(Item{Timestamp: now, Session: "web", Status: "done", Source: SourceClaude})
+	s.Ack(a.ID)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	items := loaded.Items()
+	if len(items) != 2 || !items[1].Acked || items[1].Message != "Bash" || loaded.Unread() != 1 {
+		t.Errorf("loaded %+v, want the ack and message kept", items)
+	}
+	if next := loaded.Add(Item{Timestamp: now}); next.ID != 3 {
+		t.Errorf("next ID = %d, want 3", next.ID)
+	}
+}
+
+func TestSave_PrunesExpiredItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inbox.json")
+	s := NewStore(path)
+	s.Add(Item{Timestamp: time.Now().Add(-RetentionPeriod - time.Hour).Unix(), Session: "old"})
+	s.Add(Item{Timestamp: time.Now().Unix(), Session: "new"})
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	items := s.Items()
+	if len(items) != 1 || items[0].Session != "new" {
+		t.Errorf("Items = %+v, want only the recent item", items)
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(s.Items()) != 0 {
+		t.Error("expected an empty store")
+	}
+}
+
+func TestLoad_MalformedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inbox.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err == nil {
+		t.Error("expected an error for malformed JSON")
+	}
+	if s == nil || len(s.Items()) != 0 {
+		t.Error("expected an empty store on error")
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	var s *Store
+	s.Add(Item{})
+	if s.Items() != nil || s.Unread() != 0 || s.Ack(1) || s.AckAll() != 0 || s.Save() != nil {
+		t.Error("nil store should be a no-op")
+	}
+}
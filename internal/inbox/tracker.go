@@ -0,0 +1,76 @@
+package inbox
+
+import (
+	"time"
+
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// Tracker detects priority transitions between successive session polls.
+// The zero value is ready to use.
+type Tracker struct {
+	last map[string]string // Last status by session ID and source
+}
+
+// Observe compares sessions with the previous poll and returns an item for
+// each session, external agent or teammate that moved to a priority status.
+// The first call only records the current statuses, so sessions that were
+// already waiting at startup are not reported.
+func (t *Tracker) Observe(sessions []session.Info, now time.Time) []Item {
+	current := make(map[string]string)
+	var candidates []Item
+
+	observe := func(key, status string, item Item) {
+		current[key] = status
+		if old, ok := t.last[key]; ok && old != status && IsPriority(status) {
+			item.Status = status
+			if item.Timestamp == 0 {
+				item.Timestamp = now.Unix()
+			}
+			candidates = append(candidates, item)
+		}
+	}
+
+	for _, s := range sessions {
+		id := s.ID()
+		base := Item{Session: s.Label(), SessionID: id}
+
+		item := base
+		item.Source, item.Message = SourceClaude, s.Message
+		status := EffectiveStatus(s, now)
+		if status != StatusStalled {
+			item.Timestamp = s.Timestamp
+		}
+		observe(id, status, item)
+
+		for agentType, agent := range s.Agents {
+			item := base
+			item.Source, item.Timestamp = agentType, agent.Timestamp
+			observe(id+"\x00agent:"+agentType, agent.Status, item)
+		}
+
+		if s.Team != nil {
+			for _, agent := range s.Team.Agents {
+				item := base
+				item.Source, item.Timestamp = agent.Name, agent.Timestamp
+				observe(id+"\x00team:"+agent.Name, agent.Status, item)
+			}
+		}
+	}
+
+	first := t.last == nil
+	t.last = current
+	if first {
+		return nil
+	}
+	return candidates
+}
+
+// EffectiveStatus returns the session's status, or StatusStalled when it has
+// reported working without a status update for StallAfter.
+func EffectiveStatus(s session.Info, now time.Time) string {
+	if s.Status == session.StatusWorking && s.Timestamp > 0 && now.Sub(time.Unix(s.Timestamp, 0)) >= StallAfter {
+		return StatusStalled
+	}
+	return s.Status
+}
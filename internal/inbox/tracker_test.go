@@ -0,0 +1,85 @@
+package inbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+func TestTracker_FirstObserveOnlyRecords(t *testing.T) {
+	var tr Tracker
+	now := time.Now()
+	if items := tr.Observe([]session.Info{{TmuxSession: "api", Status: session.StatusWaiting, Timestamp: now.Unix()}}, now); items != nil {
+		t.Errorf("first observe = %+v, want nothing", items)
+	}
+}
+
+func TestTracker_PriorityTransitions(t *testing.T) {
+	var tr Tracker
+	now := time.Now()
+	tr.Observe([]session.Info{
+		{TmuxSession: "api", Status: session.StatusWorking, Timestamp: now.Unix()},
+		{TmuxSession: "web", Status: session.StatusWaiting, Timestamp: now.Unix()},
+	}, now)
+
+	items := tr.Observe([]session.Info{
+		{TmuxSession: "api", Status: session.StatusPermission, Message: "Bash: rm -rf build", Timestamp: now.Unix() + 5},
+		{TmuxSession: "web", Status: session.StatusWorking, Timestamp: now.Unix() + 5},
+		{TmuxSession: "new", Status: session.StatusWaiting, Timestamp: now.Unix() + 5},
+	}, now.Add(5*time.Second))
+
+	if len(items) != 1 {
+		t.Fatalf("items = %+v, want only api's permission", items)
+	}
+	got := items[0]
+	if got.Session != "api" || got.Status != session.StatusPermission || got.Message != "Bash: rm -rf build" ||
+		got.Source != SourceClaude || got.Timestamp != now.Unix()+5 {
+		t.Errorf("item = %+v", got)
+	}
+}
+
+func TestTracker_AgentsAndTeammates(t *testing.T) {
+	var tr Tracker
+	now := time.Now()
+	s := session.Info{
+		TmuxSession: "api", Remote: "devbox", Status: session.StatusWorking, Timestamp: now.Unix(),
+		Agents: map[string]session.ExternalAgent{"codex": {Status: session.StatusWorking}},
+		Team:   &session.TeamInfo{Agents: []session.AgentInfo{{Name: "tester", Status: session.StatusWorking}}},
+	}
+	tr.Observe([]session.Info{s}, now)
+
+	s.Agents = map[string]session.ExternalAgent{"codex": {Status: session.StatusError, Timestamp: now.Unix()}}
+	s.Team = &session.TeamInfo{Agents: []session.AgentInfo{{Name: "tester", Status: session.StatusDone}}}
+	items := tr.Observe([]session.Info{s}, now)
+
+	sources := map[string]string{}
+	for _, item := range items {
+		sources[item.Source] = item.Status
+		if item.Session != "api@devbox" {
+			t.Errorf("session = %q, want api@devbox", item.Session)
+		}
+	}
+	if sources["codex"] != session.StatusError || sources["tester"] != session.StatusDone || len(items) != 2 {
+		t.Errorf("items = %+v", items)
+	}
+}
+
+func TestTracker_Stalled(t *testing.T) {
+	var tr Tracker
+	start := time.Now()
+	s := session.Info{TmuxSession: "api", Status: session.StatusWorking, Timestamp: start.Unix()}
+	tr.Observe([]session.Info{s}, start)
+
+	if items := tr.Observe([]session.Info{s}, start.Add(StallAfter-time.Second)); len(items) != 0 {
+		t.Errorf("items = %+v before StallAfter, want none", items)
+	}
+	later := start.Add(StallAfter)
+	items := tr.Observe([]session.Info{s}, later)
+	if len(items) != 1 || items[0].Status != StatusStalled || items[0].Timestamp != later.Unix() {
+		t.Fatalf("items = %+v, want one stalled item at detection time", items)
+	}
+	if items := tr.Observe([]session.Info{s}, later.Add(time.Minute)); len(items) != 0 {
+		t.Errorf("a stall should be reported once, got %+v", items)
+	}
+}
@@ -0,0 +1,163 @@
+package keymap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/stwalsh4118/navi/internal/pathutil"
+)
+
+// DefaultConfigPath is the default path for key binding overrides.
+const DefaultConfigPath = "~/.config/navi/keys.yaml"
+
+// Config holds key overrides by context and action, as written in keys.yaml:
+//
+//	sessions:
+//	  kill: [X]
+//	  down: [j, down, ctrl+n]
+//
+// Listed keys replace the action's defaults; an empty list unbinds it.
+type Config map[Context]map[Action][]string
+
+// ConflictError reports a key bound to more than one action in a context.
+type ConflictError struct {
+	Context Context
+	Key     string
+	Actions []Action
+}
+
+func (e *ConflictError) Error() string {
+	names := make([]string, len(e.Actions))
+	for i, a := range e.Actions {
+		names[i] = string(a)
+	}
+	return fmt.Sprintf("%s: key %q is bound to %s", e.Context, DisplayKey(e.Key), strings.Join(names, " and "))
+}
+
+// Load reads key overrides from path and applies them to the defaults.
+// A missing file yields the default keymap. Unknown contexts or actions and
+// keys bound twice in one context are errors.
+func Load(path string) (*Keymap, error) {
+	configPath := path
+	if configPath == "" {
+		configPath = DefaultConfigPath
+	}
+
+	data, err := os.ReadFile(pathutil.ExpandPath(configPath))
+	if os.IsNotExist(err) {
+		return Default(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse key config: %w", err)
+	}
+
+	k := Default()
+	if err := k.Override(cfg); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Override replaces the keys of the actions in cfg and checks every context
+// for conflicts. On error the keymap is left unchanged.
+func (k *Keymap) Override(cfg Config) error {
+	updated := make(map[Context][]Binding, len(k.bindings))
+	for ctx, bindings := range k.bindings {
+		updated[ctx] = append([]Binding(nil), bindings...)
+	}
+
+	var errs []error
+	for ctx, actions := range cfg {
+		bindings, ok := updated[ctx]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown context %q", ctx))
+			continue
+		}
+		for action, keys := range actions {
+			i := bindingIndex(bindings, action)
+			if i < 0 {
+				errs = append(errs, fmt.Errorf("%s: unknown action %q", ctx, action))
+				continue
+			}
+			bindings[i].Keys = normalizeKeys(keys)
+		}
+	}
+
+	for _, ctx := range contextOrder {
+		errs = append(errs, conflicts(ctx, updated[ctx])...)
+	}
+	if len(errs) > 0 {
+		sortErrors(errs)
+		return errors.Join(errs...)
+	}
+
+	k.bindings = updated
+	k.buildLookup()
+	return nil
+}
+
+// bindingIndex returns the index of action in bindings, or -1.
+func bindingIndex(bindings []Binding, action Action) int {
+	for i, b := range bindings {
+		if b.Action == action {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeKeys accepts "space" for the space bar, which Bubble Tea reports
+// as " ", and drops empty entries.
+func normalizeKeys(keys []string) []string {
+	normalized := make([]string, 0, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		switch {
+		case key == "":
+			continue
+		case strings.EqualFold(key, "space"):
+			key = " "
+		}
+		normalized = append(normalized, key)
+	}
+	return normalized
+}
+
+// conflicts returns an error for each key bound to several actions in ctx.
+func conflicts(ctx Context, bindings []Binding) []error {
+	byKey := make(map[string][]Action)
+	var order []string
+	for _, b := range bindings {
+		for _, key := range b.Keys {
+			if len(byKey[key]) == 0 {
+				order = append(order, key)
+			}
+			byKey[key] = append(byKey[key], b.Action)
+		}
+	}
+
+	var errs []error
+	for _, key := range order {
+		if actions := byKey[key]; len(actions) > 1 {
+			errs = append(errs, &ConflictError{Context: ctx, Key: key, Actions: actions})
+		}
+	}
+	return errs
+}
+
+// sortErrors orders errors by message so reports are stable across runs.
+func sortErrors(errs []error) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+}
@@ -0,0 +1,416 @@
+package keymap
+
+import "strings"
+
+// Context names a focus context. Each context has its own bindings, so the
+// same key can mean different things in the session list and the task panel.
+type Context string
+
+// Focus contexts
+const (
+	Sessions Context = "sessions" // Session list
+	Search   Context = "search"   // Session list while a search query is active; checked before Sessions
+	Preview  Context = "preview"  // Focused preview pane
+	Tasks    Context = "tasks"    // Focused task panel
+	PM       Context = "pm"       // PM view
+	Git      Context = "git"      // Git detail view
+	Viewer   Context = "viewer"   // Content viewer
+	Mosaic   Context = "mosaic"   // Mosaic view
+	Inbox    Context = "inbox"    // Notification inbox
+
+	RemotesPanel Context = "remotes"   // Remotes panel
+	ProcessTree  Context = "processes" // Process tree dialog
+)
+
+// Action names a bindable command. Names are unique within a context and are
+// the keys used in keys.yaml.
+type Action string
+
+// Actions shared by several contexts
+const (
+	Up       Action = "up"
+	Down     Action = "down"
+	Left     Action = "left"
+	Right    Action = "right"
+	PageUp   Action = "page_up"
+	PageDown Action = "page_down"
+	Top      Action = "top"
+	Bottom   Action = "bottom"
+	Back     Action = "back"
+	Focus    Action = "focus"
+	Open     Action = "open"
+	Toggle   Action = "toggle"
+	Refresh  Action = "refresh"
+	Sort     Action = "sort"
+	Filter   Action = "filter"
+	Find     Action = "search"
+	Help     Action = "help"
+	Palette  Action = "palette"
+	Quit     Action = "quit"
+)
+
+// Session list actions
+const (
+	Attach        Action = "attach"
+	Dismiss       Action = "dismiss"
+	NewSession    Action = "new_session"
+	Kill          Action = "kill"
+	Rename        Action = "rename"
+	TogglePreview Action = "preview"
+	Shrink        Action = "shrink"
+	Grow          Action = "grow"
+	Layout        Action = "layout"
+	Wrap          Action = "wrap"
+	Color         Action = "color"
+	GitDetail     Action = "git"
+	Metrics       Action = "metrics"
+	Remotes       Action = "remotes"
+	Processes     Action = "processes"
+	TaskPanel     Action = "tasks"
+	Offline       Action = "offline"
+	ClearFilters  Action = "clear_filters"
+	FilterWaiting Action = "filter_waiting"
+	FilterPerm    Action = "filter_permission"
+	FilterWorking Action = "filter_working"
+	FilterDone    Action = "filter_done"
+	FilterError   Action = "filter_error"
+	PMView        Action = "pm"
+	Mute          Action = "mute"
+	Sounds        Action = "sounds"
+	Select        Action = "select"
+	SelectMatches Action = "select_matches"
+	SelectStatus  Action = "select_status"
+	SendPrompt    Action = "send_prompt"
+	Group         Action = "group"
+	NextMatch     Action = "next_match"
+	PrevMatch     Action = "prev_match"
+	ClosePanel    Action = "close"
+	Reverse       Action = "reverse"
+	NextGroup     Action = "next_group"
+	PrevGroup     Action = "prev_group"
+	ExpandAll     Action = "expand_all"
+	Accordion     Action = "accordion"
+	Invoke        Action = "invoke"
+	Diff          Action = "diff"
+	DiffStat      Action = "stat"
+	Log           Action = "log"
+	File          Action = "file"
+	Comments      Action = "comments"
+	MosaicView    Action = "mosaic"
+	Zoom          Action = "zoom"
+	InboxView     Action = "inbox"
+	Ack           Action = "ack"
+	AckAll        Action = "ack_all"
+	Add           Action = "add"
+	Edit          Action = "edit"
+	Delete        Action = "delete"
+	Reconnect     Action = "reconnect"
+	Disconnect    Action = "disconnect"
+)
+
+// Binding ties an action to its keys within a context.
+type Binding struct {
+	Action Action
+	Keys   []string
+	Help   string
+}
+
+// contextOrder lists the contexts in the order the help overlay shows them.
+var contextOrder = []Context{Sessions, Search, Preview, Tasks, PM, Mosaic, Inbox, RemotesPanel, ProcessTree, Git, Viewer}
+
+// contextTitles are the headings used for each context in the help overlay.
+var contextTitles = map[Context]string{
+	Sessions: "Session list",
+	Search:   "Session list while searching",
+	Preview:  "Preview pane",
+	Tasks:    "Task panel",
+	PM:       "PM view",
+	Mosaic:   "Mosaic view",
+	Inbox:    "Inbox",
+	Git:      "Git detail",
+	Viewer:   "Content viewer",
+
+	RemotesPanel: "Remotes panel",
+	ProcessTree:  "Process tree",
+}
+
+// defaults is the built-in registry. Every bindable action appears here.
+var defaults = map[Context][]Binding{
+	Sessions: {
+		{Up, []string{"up", "k"}, "Move up"},
+		{Down, []string{"down", "j"}, "Move down"},
+		{Attach, []string{"enter"}, "Attach to session"},
+		{Select, []string{" "}, "Select/deselect session"},
+		{SelectMatches, []string{"V"}, "Select search matches (all listed when not searching)"},
+		{SelectStatus, []string{"*"}, "Select sessions with the cursor session's status"},
+		{Dismiss, []string{"d"}, "Dismiss notification (selection: all selected)"},
+		{Kill, []string{"x"}, "Kill session (selection: all selected)"},
+		{SendPrompt, []string{">"}, "Send a prompt to the selection"},
+		{Group, []string{"g"}, "Move the selection to a group"},
+		{NewSession, []string{"n"}, "New session"},
+		{Rename, []string{"R"}, "Rename session"},
+		{Focus, []string{"tab"}, "Focus task panel or preview"},
+		{TogglePreview, []string{"p"}, "Toggle preview pane"},
+		{Shrink, []string{"["}, "Shrink panel"},
+		{Grow, []string{"]"}, "Grow panel"},
+		{Layout, []string{"L"}, "Toggle preview layout"},
+		{Wrap, []string{"W"}, "Toggle preview wrap"},
+		{Color, []string{"C"}, "Toggle preview colors"},
+		{GitDetail, []string{"G"}, "Git detail view"},
+		{Metrics, []string{"i"}, "Metrics detail view"},
+		{Processes, []string{"t"}, "Process tree"},
+		{Remotes, []string{"H"}, "Remotes panel"},
+		{TaskPanel, []string{"T"}, "Toggle task panel"},
+		{PMView, []string{"P"}, "Toggle PM view"},
+		{MosaicView, []string{"M"}, "Toggle mosaic view"},
+		{InboxView, []string{"I"}, "Notification inbox"},
+		{Find, []string{"/"}, "Search"},
+		{Back, []string{"esc"}, "Clear selection, search, then filters"},
+		{Sort, []string{"s"}, "Cycle sort mode"},
+		{Offline, []string{"o"}, "Toggle offline sessions"},
+		{Filter, []string{"f"}, "Cycle host filter"},
+		{ClearFilters, []string{"0"}, "Clear status and project filters"},
+		{FilterWaiting, []string{"1"}, "Filter: waiting"},
+		{FilterPerm, []string{"2"}, "Filter: permission"},
+		{FilterWorking, []string{"3"}, "Filter: working"},
+		{FilterDone, []string{"4"}, "Filter: done"},
+		{FilterError, []string{"5"}, "Filter: error"},
+		{Mute, []string{"m"}, "Mute notifications"},
+		{Sounds, []string{"S"}, "Sound packs"},
+		{Refresh, []string{"r"}, "Refresh"},
+		{Help, []string{"?"}, "Key help"},
+		{Palette, []string{"ctrl+p"}, "Command palette"},
+		{Quit, []string{"q", "ctrl+c"}, "Quit"},
+	},
+	Search: {
+		{NextMatch, []string{"n"}, "Next match"},
+		{PrevMatch, []string{"N"}, "Previous match"},
+	},
+	Preview: {
+		{Up, []string{"up", "k"}, "Scroll up"},
+		{Down, []string{"down", "j"}, "Scroll down"},
+		{PageUp, []string{"pgup"}, "Page up"},
+		{PageDown, []string{"pgdown"}, "Page down"},
+		{Top, []string{"g"}, "Top, loading older scrollback"},
+		{Bottom, []string{"G"}, "Bottom and follow live output"},
+		{Find, []string{"/"}, "Search the full scrollback"},
+		{NextMatch, []string{"n"}, "Next match"},
+		{PrevMatch, []string{"N"}, "Previous match"},
+		{Back, []string{"tab", "esc"}, "Clear search, then return to session list"},
+		{Help, []string{"?"}, "Key help"},
+		{Palette, []string{"ctrl+p"}, "Command palette"},
+		{Quit, []string{"q", "ctrl+c"}, "Quit"},
+	},
+	Tasks: {
+		{Up, []string{"up", "k"}, "Move up"},
+		{Down, []string{"down", "j"}, "Move down"},
+		{PageUp, []string{"pgup"}, "Page up"},
+		{PageDown, []string{"pgdown"}, "Page down"},
+		{Top, []string{"g"}, "Top"},
+		{Bottom, []string{"G"}, "Bottom"},
+		{NextGroup, []string{"J"}, "Next group"},
+		{PrevGroup, []string{"K"}, "Previous group"},
+		{Open, []string{"enter"}, "Open task / toggle group"},
+		{Toggle, []string{" "}, "Toggle group"},
+		{ExpandAll, []string{"e"}, "Expand/collapse all"},
+		{Accordion, []string{"a"}, "Toggle accordion mode"},
+		{Sort, []string{"s"}, "Cycle sort mode"},
+		{Reverse, []string{"S"}, "Reverse sort"},
+		{Filter, []string{"f"}, "Cycle filter"},
+		{Find, []string{"/"}, "Search tasks"},
+		{NextMatch, []string{"n"}, "Next match"},
+		{PrevMatch, []string{"N"}, "Previous match"},
+		{Refresh, []string{"r"}, "Refresh tasks"},
+		{Focus, []string{"tab"}, "Return to session list"},
+		{Back, []string{"esc"}, "Clear search, then return to session list"},
+		{ClosePanel, []string{"T"}, "Close task panel"},
+		{Help, []string{"?"}, "Key help"},
+		{Palette, []string{"ctrl+p"}, "Command palette"},
+		{Quit, []string{"q", "ctrl+c"}, "Quit"},
+	},
+	PM: {
+		{Up, []string{"up", "k"}, "Move up / scroll up"},
+		{Down, []string{"down", "j"}, "Move down / scroll down"},
+		{PageUp, []string{"pgup"}, "Page up (events)"},
+		{PageDown, []string{"pgdown"}, "Page down (events)"},
+		{Top, []string{"g"}, "Top (events)"},
+		{Bottom, []string{"G"}, "Bottom (events)"},
+		{Open, []string{"enter"}, "Show the project's sessions"},
+		{Toggle, []string{" "}, "Expand project"},
+		{Focus, []string{"tab"}, "Switch projects/events"},
+		{Refresh, []string{"r"}, "Refresh snapshots"},
+		{Invoke, []string{"i"}, "Ask the PM agent"},
+		{Back, []string{"P", "esc"}, "Close PM view"},
+		{Help, []string{"?"}, "Key help"},
+		{Palette, []string{"ctrl+p"}, "Command palette"},
+		{Quit, []string{"q", "ctrl+c"}, "Quit"},
+	},
+	Mosaic: {
+		{Up, []string{"up", "k"}, "Tile above"},
+		{Down, []string{"down", "j"}, "Tile below"},
+		{Left, []string{"left", "h"}, "Previous tile"},
+		{Right, []string{"right", "l"}, "Next tile"},
+		{PageUp, []string{"pgup"}, "Previous page"},
+		{PageDown, []string{"pgdown"}, "Next page"},
+		{Zoom, []string{"enter", "z"}, "Zoom into tile / back to grid"},
+		{Attach, []string{"a"}, "Attach to session"},
+		{Color, []string{"C"}, "Toggle preview colors"},
+		{Back, []string{"esc", "M"}, "Leave zoom, then close mosaic view"},
+		{Help, []string{"?"}, "Key help"},
+		{Palette, []string{"ctrl+p"}, "Command palette"},
+		{Quit, []string{"q", "ctrl+c"}, "Quit"},
+	},
+	Inbox: {
+		{Up, []string{"up", "k"}, "Move up"},
+		{Down, []string{"down", "j"}, "Move down"},
+		{PageUp, []string{"pgup"}, "Page up"},
+		{PageDown, []string{"pgdown"}, "Page down"},
+		{Top, []string{"g"}, "Newest"},
+		{Bottom, []string{"G"}, "Oldest"},
+		{Attach, []string{"enter"}, "Jump to session, acknowledging the item"},
+		{Ack, []string{"a", " "}, "Acknowledge item"},
+		{AckAll, []string{"A"}, "Acknowledge all"},
+		{Filter, []string{"u"}, "Show unread only / all"},
+		{Back, []string{"esc", "I"}, "Close inbox"},
+		{Help, []string{"?"}, "Key help"},
+	},
+	RemotesPanel: {
+		{Up, []string{"up", "k"}, "Move up"},
+		{Down, []string{"down", "j"}, "Move down"},
+		{Add, []string{"a"}, "Add remote"},
+		{Edit, []string{"e"}, "Edit remote"},
+		{Delete, []string{"d"}, "Delete remote (asks to confirm)"},
+		{Reconnect, []string{"c"}, "Reconnect"},
+		{Disconnect, []string{"x"}, "Disconnect and pause polling"},
+		{Back, []string{"esc", "H"}, "Close remotes panel"},
+		{Help, []string{"?"}, "Key help"},
+	},
+	ProcessTree: {
+		{Up, []string{"up", "k"}, "Move up"},
+		{Down, []string{"down", "j"}, "Move down"},
+		{Kill, []string{"x"}, "Send SIGTERM to the selected process"},
+		{Back, []string{"esc"}, "Close process tree"},
+		{Help, []string{"?"}, "Key help"},
+	},
+	Git: {
+		{Diff, []string{"d"}, "View diff"},
+		{DiffStat, []string{"s"}, "View diffstat"},
+		{Log, []string{"l"}, "View log"},
+		{File, []string{"f"}, "View a file"},
+		{Open, []string{"enter", "o"}, "Open PR/issue link"},
+		{Comments, []string{"c"}, "PR comments"},
+		{Refresh, []string{"r"}, "Refresh PR"},
+		{Help, []string{"?"}, "Key help"},
+	},
+	Viewer: {
+		{Up, []string{"up", "k"}, "Scroll up"},
+		{Down, []string{"down", "j"}, "Scroll down"},
+		{PageUp, []string{"pgup"}, "Page up"},
+		{PageDown, []string{"pgdown", " "}, "Page down"},
+		{Top, []string{"g", "home"}, "Top"},
+		{Bottom, []string{"G", "end"}, "Bottom"},
+		{Back, []string{"esc", "q"}, "Close"},
+	},
+}
+
+// Keymap resolves keys to actions per context.
+type Keymap struct {
+	bindings map[Context][]Binding
+	lookup   map[Context]map[string]Action
+}
+
+// defaultKeymap backs a nil *Keymap, so zero-value models use the defaults.
+var defaultKeymap = Default()
+
+// Default returns the built-in keymap.
+func Default() *Keymap {
+	k := &Keymap{bindings: make(map[Context][]Binding, len(defaults))}
+	for ctx, bindings := range defaults {
+		copied := make([]Binding, len(bindings))
+		for i, b := range bindings {
+			copied[i] = Binding{Action: b.Action, Keys: append([]string(nil), b.Keys...), Help: b.Help}
+		}
+		k.bindings[ctx] = copied
+	}
+	k.buildLookup()
+	return k
+}
+
+// buildLookup indexes bindings by key. Conflicts are checked by Override, so
+// a later binding never silently replaces an earlier one here.
+func (k *Keymap) buildLookup() {
+	k.lookup = make(map[Context]map[string]Action, len(k.bindings))
+	for ctx, bindings := range k.bindings {
+		keys := make(map[string]Action)
+		for _, b := range bindings {
+			for _, key := range b.Keys {
+				if _, ok := keys[key]; !ok {
+					keys[key] = b.Action
+				}
+			}
+		}
+		k.lookup[ctx] = keys
+	}
+}
+
+// Action returns the action bound to key in ctx, or "" if none.
+func (k *Keymap) Action(ctx Context, key string) Action {
+	if k == nil {
+		k = defaultKeymap
+	}
+	return k.lookup[ctx][key]
+}
+
+// Keys returns the keys bound to action in ctx.
+func (k *Keymap) Keys(ctx Context, action Action) []string {
+	if k == nil {
+		k = defaultKeymap
+	}
+	for _, b := range k.bindings[ctx] {
+		if b.Action == action {
+			return b.Keys
+		}
+	}
+	return nil
+}
+
+// Bindings returns the effective bindings of ctx in registry order.
+func (k *Keymap) Bindings(ctx Context) []Binding {
+	if k == nil {
+		k = defaultKeymap
+	}
+	return k.bindings[ctx]
+}
+
+// Contexts returns every context in help overlay order.
+func Contexts() []Context {
+	return append([]Context(nil), contextOrder...)
+}
+
+// Title returns the help overlay heading for ctx.
+func Title(ctx Context) string {
+	return contextTitles[ctx]
+}
+
+// DisplayKey formats a key for help text and footers.
+func DisplayKey(key string) string {
+	switch key {
+	case " ":
+		return "space"
+	case "enter":
+		return "⏎"
+	}
+	return key
+}
+
+// FormatKeys joins the display form of keys, or returns "unbound".
+func FormatKeys(keys []string) string {
+	if len(keys) == 0 {
+		return "unbound"
+	}
+	display := make([]string, len(keys))
+	for i, key := range keys {
+		display[i] = DisplayKey(key)
+	}
+	return strings.Join(display, ", ")
+}
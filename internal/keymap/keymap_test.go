@@ -0,0 +1,201 @@
+package keymap
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultsHaveNoConflicts(t *testing.T) {
+	for _, ctx := range Contexts() {
+		if errs := conflicts(ctx, Default().Bindings(ctx)); len(errs) > 0 {
+			t.Errorf("default %s bindings conflict: %v", ctx, errors.Join(errs...))
+		}
+		if Title(ctx) == "" {
+			t.Errorf("context %s has no title", ctx)
+		}
+	}
+}
+
+func TestDefaultLookup(t *testing.T) {
+	k := Default()
+	tests := []struct {
+		ctx  Context
+		key  string
+		want Action
+	}{
+		{Sessions, "x", Kill},
+		{Sessions, " ", Select},
+		{Sessions, "?", Help},
+		{Search, "n", NextMatch},
+		{Tasks, " ", Toggle},
+		{Git, "o", Open},
+		{Viewer, " ", PageDown},
+		{RemotesPanel, "d", Delete},
+		{RemotesPanel, "H", Back},
+		{ProcessTree, "x", Kill},
+		{Sessions, "F12", ""},
+	}
+	for _, tt := range tests {
+		if got := k.Action(tt.ctx, tt.key); got != tt.want {
+			t.Errorf("Action(%s, %q) = %q, want %q", tt.ctx, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestNilKeymapUsesDefaults(t *testing.T) {
+	var k *Keymap
+	if got := k.Action(Sessions, "x"); got != Kill {
+		t.Errorf("Action = %q, want %q", got, Kill)
+	}
+	if got := FormatKeys(k.Keys(Sessions, Select)); got != "space" {
+		t.Errorf("Keys = %q, want %q", got, "space")
+	}
+}
+
+func TestOverrideReplacesAndUnbinds(t *testing.T) {
+	k := Default()
+	err := k.Override(Config{
+		Sessions: {
+			Kill:  {"X"},
+			Down:  {"j", "down", "ctrl+n"},
+			Mute:  {},
+			Group: {"space"},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected conflict between group and select on space")
+	}
+
+	k = Default()
+	err = k.Override(Config{
+		Sessions: {
+			Kill:   {"X"},
+			Down:   {"j", "down", "ctrl+n"},
+			Mute:   {},
+			Select: {"space"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Override error: %v", err)
+	}
+	if got := k.Action(Sessions, "X"); got != Kill {
+		t.Errorf("X = %q, want %q", got, Kill)
+	}
+	if got := k.Action(Sessions, "x"); got != "" {
+		t.Errorf("x = %q, want unbound", got)
+	}
+	if got := k.Action(Sessions, "ctrl+n"); got != Down {
+		t.Errorf("ctrl+n = %q, want %q", got, Down)
+	}
+	if got := k.Action(Sessions, "m"); got != "" {
+		t.Errorf("m = %q, want unbound", got)
+	}
+	if got := k.Action(Sessions, " "); got != Select {
+		t.Errorf("space = %q, want %q", got, Select)
+	}
+	if got := FormatKeys(k.Keys(Sessions, Mute)); got != "unbound" {
+		t.Errorf("FormatKeys = %q, want unbound", got)
+	}
+
+	// Other contexts keep their defaults.
+	if got := k.Action(Tasks, "x"); got != "" {
+		t.Errorf("tasks x = %q, want unbound", got)
+	}
+	if got := Default().Action(Sessions, "x"); got != Kill {
+		t.Errorf("Default() changed by Override: x = %q", got)
+	}
+}
+
+func TestOverrideConflictLeavesKeymapUnchanged(t *testing.T) {
+	k := Default()
+	err := k.Override(Config{Sessions: {Kill: {"d"}}})
+
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("error = %v, want ConflictError", err)
+	}
+	if conflict.Context != Sessions || conflict.Key != "d" {
+		t.Errorf("conflict = %+v", conflict)
+	}
+	if !strings.Contains(err.Error(), "dismiss and kill") {
+		t.Errorf("error %q does not name both actions", err)
+	}
+	if got := k.Action(Sessions, "x"); got != Kill {
+		t.Errorf("keymap changed on error: x = %q", got)
+	}
+}
+
+func TestOverrideUnknownNames(t *testing.T) {
+	k := Default()
+	err := k.Override(Config{
+		"sidebar": {Up: {"k"}},
+		Git:       {"explode": {"e"}},
+	})
+	if err == nil {
+		t.Fatal("expected errors for unknown context and action")
+	}
+	for _, want := range []string{`unknown context "sidebar"`, `git: unknown action "explode"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	content := strings.Join([]string{
+		"sessions:",
+		"  kill: [X]",
+		"viewer:",
+		"  page_down: [pgdown, ctrl+f]",
+	}, "\n")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if got := k.Action(Sessions, "X"); got != Kill {
+		t.Errorf("X = %q, want %q", got, Kill)
+	}
+	if got := k.Action(Viewer, "ctrl+f"); got != PageDown {
+		t.Errorf("ctrl+f = %q, want %q", got, PageDown)
+	}
+	if got := k.Action(Viewer, " "); got != "" {
+		t.Errorf("space = %q, want unbound", got)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	k, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if got := k.Action(Sessions, "x"); got != Kill {
+		t.Errorf("x = %q, want %q", got, Kill)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	malformed := filepath.Join(dir, "malformed.yaml")
+	if err := os.WriteFile(malformed, []byte("sessions: [x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(malformed); err == nil {
+		t.Error("expected parse error")
+	}
+
+	conflicting := filepath.Join(dir, "conflict.yaml")
+	if err := os.WriteFile(conflicting, []byte("tasks:\n  sort: [f]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(conflicting); err == nil {
+		t.Error("expected conflict error")
+	}
+}
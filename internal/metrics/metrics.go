@@ -37,7 +37,11 @@ type ToolMetrics struct {
 
 // ResourceMetrics tracks resource usage for a session.
 type ResourceMetrics struct {
-	RSSBytes int64 `json:"rss_bytes"`
+	RSSBytes     int64    `json:"rss_bytes"`
+	CPUPercent   float64  `json:"cpu_percent,omitempty"`
+	ProcessCount int      `json:"process_count,omitempty"`
+	Ports        []int    `json:"ports,omitempty"`
+	Alerts       []string `json:"alerts,omitempty"`
 }
 
 // Metrics aggregates all session metrics data.
@@ -116,3 +120,24 @@ func FormatBytes(bytes int64) string {
 	}
 	return fmt.Sprintf("%.1fG", float64(bytes)/bytesPerGiB)
 }
+
+// FormatMillis returns an abbreviated duration string for millisecond values.
+// Examples: "0ms", "850ms", "1.2s", "45s", "2m 5s"
+func FormatMillis(ms int64) string {
+	if ms < 1000 {
+		return fmt.Sprintf("%dms", ms)
+	}
+	if ms < 10000 {
+		return fmt.Sprintf("%.1fs", float64(ms)/1000)
+	}
+	seconds := ms / 1000
+	if seconds < 60 {
+		return fmt.Sprintf("%ds", seconds)
+	}
+	mins := seconds / 60
+	secs := seconds % 60
+	if secs > 0 {
+		return fmt.Sprintf("%dm %ds", mins, secs)
+	}
+	return fmt.Sprintf("%dm", mins)
+}
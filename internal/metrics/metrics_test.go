@@ -313,6 +313,28 @@ func TestFormatBytes(t *testing.T) {
 	}
 }
 
+func TestFormatMillis(t *testing.T) {
+	tests := []struct {
+		ms       int64
+		expected string
+	}{
+		{0, "0ms"},
+		{850, "850ms"},
+		{1000, "1.0s"},
+		{1250, "1.2s"},
+		{45000, "45s"},
+		{60000, "1m"},
+		{125000, "2m 5s"},
+	}
+
+	for _, tt := range tests {
+		result := FormatMillis(tt.ms)
+		if result != tt.expected {
+			t.Errorf("FormatMillis(%d) = %q, want %q", tt.ms, result, tt.expected)
+		}
+	}
+}
+
 func TestResourceMetricsJSON(t *testing.T) {
 	rm := ResourceMetrics{RSSBytes: 268435456}
 	data, err := json.Marshal(rm)
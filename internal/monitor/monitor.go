@@ -9,11 +9,16 @@ import (
 	"github.com/stwalsh4118/navi/internal/session"
 )
 
+// RemoteSource returns the current sessions on remotes. It is called from the
+// monitor's remote polling goroutine and may block on the network.
+type RemoteSource func() []session.Info
+
 // AttachMonitor polls session status files in the background while users are attached.
 type AttachMonitor struct {
-	notifier  *audio.Notifier
-	statusDir string
-	interval  time.Duration
+	notifier     *audio.Notifier
+	statusDir    string
+	interval     time.Duration
+	remoteSource RemoteSource
 
 	mu          sync.Mutex
 	states      map[string]string
@@ -35,7 +40,17 @@ func New(notifier *audio.Notifier, statusDir string, pollInterval time.Duration)
 	return m
 }
 
-// Start launches the background polling loop.
+// SetRemoteSource makes the monitor also poll remote sessions through source.
+// It must be called before Start.
+func (m *AttachMonitor) SetRemoteSource(source RemoteSource) {
+	if m == nil {
+		return
+	}
+	m.remoteSource = source
+}
+
+// Start launches the background polling loop. Remote sessions are polled in a
+// separate loop, so a slow remote never delays local notifications.
 func (m *AttachMonitor) Start(ctx context.Context, initialStates map[string]string, initialAgentStates map[string]map[string]string) {
 	if m == nil {
 		return
@@ -47,73 +62,111 @@ func (m *AttachMonitor) Start(ctx context.Context, initialStates map[string]stri
 	skipInitialPoll := len(m.states) == 0 && len(m.agentStates) == 0
 	m.mu.Unlock()
 
-	go func() {
-		ticker := time.NewTicker(m.interval)
-		defer ticker.Stop()
+	go m.poll(ctx, skipInitialPoll, func() ([]session.Info, bool) {
+		sessions, err := session.ReadStatusFiles(m.statusDir)
+		// Everything in the local status directory is a local session,
+		// whatever its remote field says.
+		for i := range sessions {
+			sessions[i].Remote = ""
+		}
+		return sessions, err == nil
+	})
+
+	if m.remoteSource != nil {
+		go m.poll(ctx, skipInitialPoll, func() ([]session.Info, bool) {
+			return m.remoteSource(), true
+		})
+	}
+}
 
-		for {
-			select {
-			case <-ctx.Done():
+// poll runs one polling loop. Each loop owns the keys it reported last, so the
+// local and remote loops update their own sessions without clobbering each other.
+func (m *AttachMonitor) poll(ctx context.Context, skipInitialPoll bool, read func() ([]session.Info, bool)) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	var ownedKeys map[string]bool
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			currentSessions, ok := read()
+			if !ok {
+				continue
+			}
+			if ctx.Err() != nil {
 				return
-			case <-ticker.C:
-				currentSessions, err := session.ReadStatusFiles(m.statusDir)
-				if err != nil {
+			}
+
+			currentStates := make(map[string]string, len(currentSessions))
+			currentAgentStates := make(map[string]map[string]string)
+			labels := make(map[string]string, len(currentSessions))
+			for _, s := range currentSessions {
+				key := s.ID()
+				labels[key] = s.Label()
+				currentStates[key] = s.Status
+				if len(s.Agents) == 0 {
 					continue
 				}
 
-				currentStates := make(map[string]string, len(currentSessions))
-				currentAgentStates := make(map[string]map[string]string)
-				for _, s := range currentSessions {
-					currentStates[s.TmuxSession] = s.Status
-					if len(s.Agents) == 0 {
-						continue
-					}
-
-					agentStates := make(map[string]string, len(s.Agents))
-					for agentType, agent := range s.Agents {
-						agentStates[agentType] = agent.Status
-					}
-					currentAgentStates[s.TmuxSession] = agentStates
+				agentStates := make(map[string]string, len(s.Agents))
+				for agentType, agent := range s.Agents {
+					agentStates[agentType] = agent.Status
 				}
+				currentAgentStates[key] = agentStates
+			}
 
-				m.mu.Lock()
-				if skipInitialPoll {
-					m.states = currentStates
-					m.agentStates = currentAgentStates
-					skipInitialPoll = false
-					m.mu.Unlock()
-					continue
-				}
+			m.mu.Lock()
+			if !skipInitialPoll {
+				m.notifyChanges(currentStates, currentAgentStates, labels)
+			}
+			skipInitialPoll = false
 
-				for sessionName, newStatus := range currentStates {
-					if oldStatus, ok := m.states[sessionName]; ok && oldStatus != newStatus {
-						m.notifyFn(sessionName, newStatus)
-					}
-				}
+			for key := range ownedKeys {
+				delete(m.states, key)
+				delete(m.agentStates, key)
+			}
+			for key, status := range currentStates {
+				m.states[key] = status
+			}
+			for key, agentStates := range currentAgentStates {
+				m.agentStates[key] = agentStates
+			}
+			ownedKeys = make(map[string]bool, len(currentStates))
+			for key := range currentStates {
+				ownedKeys[key] = true
+			}
+			m.mu.Unlock()
+		}
+	}
+}
 
-				for sessionName, agentStates := range currentAgentStates {
-					lastSessionAgentStates, ok := m.agentStates[sessionName]
-					if !ok {
-						continue
-					}
-
-					for agentType, newStatus := range agentStates {
-						oldStatus, ok := lastSessionAgentStates[agentType]
-						if !ok {
-							continue
-						}
-						if oldStatus != newStatus {
-							m.notifyFn(sessionName+":"+agentType, newStatus)
-						}
-					}
-				}
+// notifyChanges notifies for sessions and agents whose status differs from the
+// last known state, naming them by their labels. Callers must hold m.mu.
+func (m *AttachMonitor) notifyChanges(currentStates map[string]string, currentAgentStates map[string]map[string]string, labels map[string]string) {
+	for id, newStatus := range currentStates {
+		if oldStatus, ok := m.states[id]; ok && oldStatus != newStatus {
+			m.notifyFn(labels[id], newStatus)
+		}
+	}
 
-				m.states = currentStates
-				m.agentStates = currentAgentStates
-				m.mu.Unlock()
+	for id, agentStates := range currentAgentStates {
+		lastSessionAgentStates, ok := m.agentStates[id]
+		if !ok {
+			continue
+		}
+
+		for agentType, newStatus := range agentStates {
+			oldStatus, ok := lastSessionAgentStates[agentType]
+			if !ok {
+				continue
+			}
+			if oldStatus != newStatus {
+				m.notifyFn(labels[id]+":"+agentType, newStatus)
 			}
 		}
-	}()
+	}
 }
 
 // States returns a thread-safe copy of the monitor states.
@@ -171,6 +171,50 @@ func TestNilNotifierDoesNotPanicAndTracksState(t *testing.T) {
 	}, 500*time.Millisecond)
 }
 
+func TestRemoteSourceNotifiesWithRemoteName(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeStatus(dir, session.Info{TmuxSession: "api", Status: session.StatusWorking}); err != nil {
+		t.Fatalf("writeStatus setup failed: %v", err)
+	}
+
+	var remoteMu sync.Mutex
+	remoteStatus := session.StatusWorking
+	m := New(nil, dir, testPollInterval)
+	m.SetRemoteSource(func() []session.Info {
+		remoteMu.Lock()
+		defer remoteMu.Unlock()
+		return []session.Info{{TmuxSession: "api", SessionID: "sid-1", Status: remoteStatus, Remote: "devbox"}}
+	})
+	called := make(chan string, 4)
+	m.notifyFn = func(sessionName, newStatus string) {
+		called <- sessionName + ":" + newStatus
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.Start(ctx, map[string]string{"api": session.StatusWorking, "api@devbox#sid-1": session.StatusWorking}, nil)
+
+	remoteMu.Lock()
+	remoteStatus = session.StatusDone
+	remoteMu.Unlock()
+
+	select {
+	case got := <-called:
+		if got != "api@devbox:"+session.StatusDone {
+			t.Fatalf("notification = %q, want %q", got, "api@devbox:"+session.StatusDone)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("expected remote transition notification")
+	}
+
+	// The local loop must not drop the remote session's state.
+	time.Sleep(5 * testPollInterval)
+	states := m.States()
+	if states["api"] != session.StatusWorking || states["api@devbox#sid-1"] != session.StatusDone {
+		t.Fatalf("states = %v, want local and remote api tracked separately", states)
+	}
+}
+
 func writeStatus(dir string, info session.Info) error {
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
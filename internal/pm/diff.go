@@ -19,7 +19,11 @@ func DiffSnapshots(oldSnapshot, newSnapshot ProjectSnapshot) []Event {
 	events := make([]Event, 0, 7)
 
 	if oldSnapshot.HeadSHA != "" && newSnapshot.HeadSHA != "" && oldSnapshot.HeadSHA != newSnapshot.HeadSHA {
-		commits := commitsBetweenFunc(newSnapshot.ProjectDir, oldSnapshot.HeadSHA, newSnapshot.HeadSHA)
+		// Commit lists come from local git, so remote projects report only the SHAs
+		var commits []string
+		if newSnapshot.Remote == "" {
+			commits = commitsBetweenFunc(newSnapshot.ProjectDir, oldSnapshot.HeadSHA, newSnapshot.HeadSHA)
+		}
 		events = append(events, newEvent(newSnapshot, EventCommit, now, map[string]string{
 			"old_head_sha": oldSnapshot.HeadSHA,
 			"new_head_sha": newSnapshot.HeadSHA,
@@ -104,6 +108,7 @@ func newEvent(snapshot ProjectSnapshot, eventType EventType, timestamp time.Time
 		Timestamp:   timestamp,
 		ProjectName: snapshot.ProjectName,
 		ProjectDir:  snapshot.ProjectDir,
+		Remote:      snapshot.Remote,
 		Payload:     payload,
 	}
 }
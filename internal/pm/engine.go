@@ -10,6 +10,7 @@ import (
 // Engine orchestrates snapshot capture, diffing, and event logging.
 type Engine struct {
 	prevSnapshots map[string]ProjectSnapshot
+	remoteGit     RemoteGitFunc
 }
 
 // NewEngine creates a PM engine with empty snapshot cache.
@@ -17,28 +18,41 @@ func NewEngine() *Engine {
 	return &Engine{prevSnapshots: make(map[string]ProjectSnapshot)}
 }
 
-// Run executes one PM pipeline cycle.
+// SetRemoteGit sets how git state is looked up for projects on remotes.
+// Without it, remote snapshots have no git information.
+func (e *Engine) SetRemoteGit(fn RemoteGitFunc) {
+	e.remoteGit = fn
+}
+
+// Run executes one PM pipeline cycle. Task results are keyed by project key
+// (see task.ProjectKey).
 func (e *Engine) Run(sessions []session.Info, taskResults map[string]*task.ProviderResult) (*PMOutput, error) {
 	projects := DiscoverProjects(sessions)
-	projectDirs := make([]string, 0, len(projects))
-	for projectDir := range projects {
-		projectDirs = append(projectDirs, projectDir)
+	projectKeys := make([]string, 0, len(projects))
+	for key := range projects {
+		projectKeys = append(projectKeys, key)
 	}
-	sort.Strings(projectDirs)
+	sort.Strings(projectKeys)
 
-	snapshots := make([]ProjectSnapshot, 0, len(projectDirs))
+	snapshots := make([]ProjectSnapshot, 0, len(projectKeys))
 	allEvents := make([]Event, 0)
-	nextSnapshots := make(map[string]ProjectSnapshot, len(projectDirs))
+	nextSnapshots := make(map[string]ProjectSnapshot, len(projectKeys))
 
-	for _, projectDir := range projectDirs {
-		snapshot := CaptureSnapshot(projectDir, projects[projectDir], taskResults[projectDir])
+	for _, key := range projectKeys {
+		group := projects[key]
+		var snapshot ProjectSnapshot
+		if remoteName := group[0].Remote; remoteName != "" {
+			snapshot = CaptureRemoteSnapshot(remoteName, group[0].CWD, group, taskResults[key], e.remoteGit)
+		} else {
+			snapshot = CaptureSnapshot(key, group, taskResults[key])
+		}
 		snapshots = append(snapshots, snapshot)
 
-		if oldSnapshot, ok := e.prevSnapshots[projectDir]; ok {
+		if oldSnapshot, ok := e.prevSnapshots[key]; ok {
 			allEvents = append(allEvents, DiffSnapshots(oldSnapshot, snapshot)...)
 		}
 
-		nextSnapshots[projectDir] = snapshot
+		nextSnapshots[key] = snapshot
 	}
 
 	if err := AppendEvents(allEvents); err != nil {
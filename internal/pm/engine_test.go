@@ -92,3 +92,81 @@ func TestEngineRunNoSessions(t *testing.T) {
 		t.Fatalf("events = %d, want 0", len(out.Events))
 	}
 }
+
+func TestEngineRunRemoteProjects(t *testing.T) {
+	tempDir := t.TempDir()
+	originalGitInfo := gitInfoFunc
+	originalCommitsBetween := commitsBetweenFunc
+	originalEventLogPath := eventLogPath
+	t.Cleanup(func() {
+		gitInfoFunc = originalGitInfo
+		commitsBetweenFunc = originalCommitsBetween
+		eventLogPath = originalEventLogPath
+	})
+	eventLogPath = filepath.Join(tempDir, "events.jsonl")
+
+	gitInfoFunc = func(string) *git.Info {
+		t.Error("local git lookup used for a remote project")
+		return nil
+	}
+	commitsBetweenFunc = func(_, _, _ string) []string {
+		t.Error("local commit lookup used for a remote project")
+		return nil
+	}
+
+	headSHA := "oldsha"
+	var lookups []string
+	engine := NewEngine()
+	engine.SetRemoteGit(func(remoteName, dir string) (*git.Info, string) {
+		lookups = append(lookups, remoteName+":"+dir)
+		return &git.Info{Branch: "main", Ahead: 2}, headSHA
+	})
+
+	// The same path on two hosts is two projects
+	sessions := []session.Info{
+		{TmuxSession: "api", CWD: "/srv/api", Remote: "devbox", Status: session.StatusWorking, Timestamp: 10},
+		{TmuxSession: "api2", CWD: "/srv/api", Remote: "gpu", Status: session.StatusWaiting, Timestamp: 20},
+	}
+	taskResults := map[string]*task.ProviderResult{
+		"devbox:/srv/api": {Tasks: []task.Task{{ID: "1", Status: "done"}, {ID: "2", Status: "open"}}},
+	}
+
+	output, err := engine.Run(sessions, taskResults)
+	if err != nil {
+		t.Fatalf("engine run failed: %v", err)
+	}
+	if len(output.Snapshots) != 2 {
+		t.Fatalf("snapshots = %d, want one per host", len(output.Snapshots))
+	}
+	devbox := output.Snapshots[0]
+	if devbox.ProjectName != "devbox:api" || devbox.Remote != "devbox" || devbox.Key() != "devbox:/srv/api" {
+		t.Errorf("devbox snapshot = %+v", devbox)
+	}
+	if devbox.HeadSHA != "oldsha" || devbox.Branch != "main" || devbox.CommitsAhead != 2 {
+		t.Errorf("devbox git state = %+v", devbox)
+	}
+	if devbox.TaskCounts.Total != 2 || devbox.TaskCounts.Done != 1 {
+		t.Errorf("devbox task counts = %+v, want results matched by project key", devbox.TaskCounts)
+	}
+	if output.Snapshots[1].TaskCounts.Total != 0 {
+		t.Error("gpu project should not get devbox's task results")
+	}
+	if len(lookups) != 2 {
+		t.Errorf("remote git lookups = %v", lookups)
+	}
+
+	headSHA = "newsha"
+	output, err = engine.Run(sessions, taskResults)
+	if err != nil {
+		t.Fatalf("second engine run failed: %v", err)
+	}
+	var commit *Event
+	for i := range output.Events {
+		if output.Events[i].Type == EventCommit {
+			commit = &output.Events[i]
+		}
+	}
+	if commit == nil || commit.Remote == "" || commit.Payload["new_head_sha"] != "newsha" {
+		t.Errorf("events = %+v, want a remote commit event", output.Events)
+	}
+}
@@ -3,6 +3,7 @@ package pm
 import (
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -26,7 +27,13 @@ var inProgressTaskStatuses = map[string]bool{
 var gitInfoFunc = git.GetInfo
 var getHeadSHAFunc = GetHeadSHA
 
-// DiscoverProjects groups sessions by expanded project directory.
+// RemoteGitFunc looks up git info and the HEAD SHA for a directory on a
+// remote. It returns a nil info for directories that aren't git repositories.
+type RemoteGitFunc func(remoteName, dir string) (*git.Info, string)
+
+// DiscoverProjects groups sessions by project. Local sessions are grouped by
+// expanded project directory; remote sessions by task.ProjectKey of their
+// remote and working directory, which is used as-is.
 func DiscoverProjects(sessions []session.Info) map[string][]session.Info {
 	projects := make(map[string][]session.Info)
 
@@ -35,6 +42,12 @@ func DiscoverProjects(sessions []session.Info) map[string][]session.Info {
 			continue
 		}
 
+		if s.Remote != "" {
+			key := task.ProjectKey(s.Remote, s.CWD)
+			projects[key] = append(projects[key], s)
+			continue
+		}
+
 		expanded := pathutil.ExpandPath(s.CWD)
 		abs, err := filepath.Abs(expanded)
 		if err != nil {
@@ -63,6 +76,37 @@ func CaptureSnapshot(projectDir string, sessions []session.Info, taskResult *tas
 		snapshot.HeadSHA = getHeadSHAFunc(projectDir)
 	}
 
+	fillSnapshot(&snapshot, sessions, taskResult)
+	return snapshot
+}
+
+// CaptureRemoteSnapshot captures project state for a project directory on a
+// remote, with git state from fetchGit. The project is named
+// "<remote>:<dir name>" so it is distinct from local checkouts of the same repo.
+func CaptureRemoteSnapshot(remoteName, projectDir string, sessions []session.Info, taskResult *task.ProviderResult, fetchGit RemoteGitFunc) ProjectSnapshot {
+	snapshot := ProjectSnapshot{
+		ProjectName:  remoteName + ":" + path.Base(projectDir),
+		ProjectDir:   projectDir,
+		SessionCount: len(sessions),
+		Remote:       remoteName,
+	}
+
+	if fetchGit != nil {
+		if info, headSHA := fetchGit(remoteName, projectDir); info != nil {
+			snapshot.Branch = info.Branch
+			snapshot.CommitsAhead = info.Ahead
+			snapshot.Dirty = info.Dirty
+			snapshot.PRNumber = info.PRNum
+			snapshot.HeadSHA = headSHA
+		}
+	}
+
+	fillSnapshot(&snapshot, sessions, taskResult)
+	return snapshot
+}
+
+// fillSnapshot sets the task counts, current PBI and session state.
+func fillSnapshot(snapshot *ProjectSnapshot, sessions []session.Info, taskResult *task.ProviderResult) {
 	if taskResult != nil {
 		snapshot.TaskCounts = getTaskCounts(taskResult)
 	}
@@ -70,7 +114,7 @@ func CaptureSnapshot(projectDir string, sessions []session.Info, taskResult *tas
 	resolvedCurrentPBI := ResolveCurrentPBI(ResolverInput{
 		TaskResult: taskResult,
 		Sessions:   sessions,
-		ProjectDir: projectDir,
+		ProjectDir: snapshot.ProjectDir,
 		Branch:     snapshot.Branch,
 	})
 	snapshot.CurrentPBIID = resolvedCurrentPBI.PBIID
@@ -82,8 +126,6 @@ func CaptureSnapshot(projectDir string, sessions []session.Info, taskResult *tas
 	if activity > 0 {
 		snapshot.LastActivity = time.Unix(activity, 0).UTC()
 	}
-
-	return snapshot
 }
 
 // GetHeadSHA returns the full HEAD SHA for a git repository directory.
@@ -32,6 +32,25 @@ func TestDiscoverProjects(t *testing.T) {
 	}
 }
 
+func TestDiscoverProjectsKeepsRemotePathsApart(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	sessions := []session.Info{
+		{TmuxSession: "local", CWD: filepath.Join(home, "proj")},
+		{TmuxSession: "remote", CWD: filepath.Join(home, "proj"), Remote: "devbox"},
+		{TmuxSession: "remote-tilde", CWD: "~/proj", Remote: "devbox"},
+	}
+
+	projects := DiscoverProjects(sessions)
+	if len(projects) != 3 {
+		t.Fatalf("project count = %d, want 3: %v", len(projects), projects)
+	}
+	if got := projects["devbox:~/proj"]; len(got) != 1 || got[0].TmuxSession != "remote-tilde" {
+		t.Errorf("remote ~ path should not expand to the local home: %v", projects)
+	}
+}
+
 func TestCaptureSnapshot(t *testing.T) {
 	projectDir := t.TempDir()
 
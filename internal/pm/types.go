@@ -1,6 +1,10 @@
 package pm
 
-import "time"
+import (
+	"time"
+
+	"github.com/stwalsh4118/navi/internal/task"
+)
 
 type EventType string
 
@@ -35,6 +39,13 @@ type ProjectSnapshot struct {
 	LastActivity     time.Time  `json:"last_activity"`
 	SessionCount     int        `json:"session_count"`
 	PRNumber         int        `json:"pr_number,omitempty"`
+	Remote           string     `json:"remote,omitempty"`
+}
+
+// Key returns the key identifying the snapshot's project, matching the keys
+// of task results (see task.ProjectKey).
+func (s ProjectSnapshot) Key() string {
+	return task.ProjectKey(s.Remote, s.ProjectDir)
 }
 
 type Event struct {
@@ -42,6 +53,7 @@ type Event struct {
 	Timestamp   time.Time         `json:"timestamp"`
 	ProjectName string            `json:"project_name"`
 	ProjectDir  string            `json:"project_dir"`
+	Remote      string            `json:"remote,omitempty"`
 	Payload     map[string]string `json:"payload,omitempty"`
 }
 
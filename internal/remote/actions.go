@@ -1,8 +1,24 @@
 package remote
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// Exit statuses of the create command for failures detected on the remote.
+const (
+	createExitNoDir  = 3
+	createExitExists = 4
+)
+
+// Errors returned by CreateSession for failures detected on the remote.
+var (
+	ErrRemoteDirNotFound = errors.New("directory does not exist on remote")
+	ErrRemoteNameExists  = errors.New("session name already exists on remote")
 )
 
 // resolveSessionsDir handles tilde expansion for the remote sessions directory.
@@ -23,6 +39,55 @@ func shellQuote(s string) string {
 	return "'" + strings.ReplaceAll(s, "'", "'\"'\"'") + "'"
 }
 
+// doubleQuote wraps a string in double quotes for the remote shell, escaping
+// everything except $ so that variables such as $HOME still expand.
+func doubleQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`")
+	return `"` + r.Replace(s) + `"`
+}
+
+// remoteDirExpr quotes a directory typed by the user for the remote shell,
+// expanding a leading ~ to the remote $HOME. An empty dir means $HOME.
+func remoteDirExpr(dir string) string {
+	switch {
+	case dir == "" || dir == "~":
+		return `"$HOME"`
+	case strings.HasPrefix(dir, "~/"):
+		return `"$HOME"/` + shellQuote(dir[2:])
+	}
+	return shellQuote(dir)
+}
+
+// buildCreateCommand builds the shell command that validates dir, creates a
+// detached tmux session there and types the claude command into it. On success
+// it prints the absolute session directory.
+func buildCreateCommand(sessionName, dir string, skipPermissions bool) string {
+	name := shellQuote(sessionName)
+	return fmt.Sprintf(
+		"cd %s 2>/dev/null || exit %d; tmux has-session -t %s 2>/dev/null && exit %d; tmux new-session -d -s %s -c \"$PWD\" || exit; tmux send-keys -t %s %s Enter; pwd",
+		remoteDirExpr(dir), createExitNoDir,
+		shellQuote("="+sessionName), createExitExists,
+		name,
+		name, shellQuote(session.ClaudeCommand(skipPermissions)),
+	)
+}
+
+// buildSeedStatusCommand builds the shell command that writes a session's
+// initial status file. Only the directory is double-quoted, so $HOME expands;
+// the file name is single-quoted like every other user-provided value.
+func buildSeedStatusCommand(status session.Info, sessionsDir string) (string, error) {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		"mkdir -p %s && printf '%%s\\n' %s > %s",
+		doubleQuote(sessionsDir),
+		shellQuote(string(data)),
+		doubleQuote(sessionsDir)+"/"+shellQuote(status.TmuxSession+".json"),
+	), nil
+}
+
 // buildKillCommand builds the shell command to kill a tmux session and remove its status file.
 // Uses ; so cleanup runs even if tmux kill fails.
 func buildKillCommand(sessionName, sessionsDir string) string {
@@ -61,6 +126,18 @@ func buildDismissCommand(sessionName, sessionsDir string) string {
 	)
 }
 
+// buildSendPromptCommand builds the shell command that types a prompt into a
+// tmux session and submits it. The prompt is sent literally (-l) so words
+// such as "Enter" are not read as key names.
+func buildSendPromptCommand(sessionName, prompt string) string {
+	return fmt.Sprintf(
+		"tmux send-keys -t %s -l %s && tmux send-keys -t %s Enter",
+		shellQuote(sessionName),
+		shellQuote(prompt),
+		shellQuote(sessionName),
+	)
+}
+
 // KillSession kills a remote tmux session and removes its status file.
 // Uses ; instead of && so the file cleanup runs even if tmux kill fails
 // (e.g., the session was already gone).
@@ -91,3 +168,42 @@ func DismissSession(pool *SSHPool, remoteName, sessionName, sessionsDir string)
 	_, err := pool.Execute(remoteName, cmd)
 	return err
 }
+
+// CreateSession creates a tmux session on a remote, starts claude in it and
+// seeds its status file so it shows up before the first hook fires. The
+// directory is checked on the remote; a leading ~ refers to the remote home.
+func CreateSession(pool *SSHPool, remoteName, sessionName, dir string, skipPermissions bool, sessionsDir string) error {
+	output, err := pool.Execute(remoteName, buildCreateCommand(sessionName, dir, skipPermissions))
+	if err != nil {
+		if code, ok := exitStatus(err); ok {
+			switch code {
+			case createExitNoDir:
+				return ErrRemoteDirNotFound
+			case createExitExists:
+				return ErrRemoteNameExists
+			}
+			if msg := strings.TrimSpace(string(output)); msg != "" {
+				return fmt.Errorf("%s", msg)
+			}
+		}
+		return err
+	}
+
+	// Best effort, like the local status file; the hook writes it later anyway
+	cwd := strings.TrimSpace(string(output))
+	if cmd, err := buildSeedStatusCommand(session.NewStatus(sessionName, cwd), resolveSessionsDir(sessionsDir)); err == nil {
+		pool.Execute(remoteName, cmd)
+	}
+	return nil
+}
+
+// SendPrompt types a prompt into a remote tmux session and presses Enter.
+func SendPrompt(pool *SSHPool, remoteName, sessionName, prompt string) error {
+	output, err := pool.Execute(remoteName, buildSendPromptCommand(sessionName, prompt))
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%s", msg)
+		}
+	}
+	return err
+}
@@ -1,8 +1,13 @@
 package remote
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/stwalsh4118/navi/internal/session"
 )
 
 func TestShellQuote(t *testing.T) {
@@ -228,3 +233,73 @@ func TestDismissSessionWithAbsolutePath(t *testing.T) {
 		t.Error("dismiss command should use absolute path when provided")
 	}
 }
+
+func TestRemoteDirExpr(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", `"$HOME"`},
+		{"~", `"$HOME"`},
+		{"~/src/my app", `"$HOME"/'src/my app'`},
+		{"/opt/it's", `'/opt/it'"'"'s'`},
+	}
+	for _, tt := range tests {
+		if got := remoteDirExpr(tt.input); got != tt.want {
+			t.Errorf("remoteDirExpr(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestBuildCreateCommand(t *testing.T) {
+	cmd := buildCreateCommand("api", "~/src", true)
+	for _, want := range []string{
+		`cd "$HOME"/'src' 2>/dev/null || exit 3`,
+		`tmux has-session -t '=api' 2>/dev/null && exit 4`,
+		`tmux new-session -d -s 'api' -c "$PWD"`,
+		`tmux send-keys -t 'api' 'claude --dangerously-skip-permissions' Enter`,
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("command missing %q:\n%s", want, cmd)
+		}
+	}
+}
+
+func TestBuildSendPromptCommand(t *testing.T) {
+	cmd := buildSendPromptCommand("api", "fix it; rm -rf / 'now'")
+
+	want := "tmux send-keys -t 'api' -l " + shellQuote("fix it; rm -rf / 'now'") + " && tmux send-keys -t 'api' Enter"
+	if cmd != want {
+		t.Errorf("buildSendPromptCommand() = %q, want %q", cmd, want)
+	}
+}
+
+func TestBuildSeedStatusCommandQuotesName(t *testing.T) {
+	name := "x$(touch pwned)`touch pwned2`"
+	cmd, err := buildSeedStatusCommand(session.Info{TmuxSession: name}, "$HOME/.claude-sessions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"$HOME/.claude-sessions"/'` + name + `.json'`; !strings.HasSuffix(cmd, want) {
+		t.Errorf("command = %q, want the file name single-quoted as %q", cmd, want)
+	}
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	home := t.TempDir()
+	c := exec.Command("sh", "-c", cmd)
+	c.Dir = home
+	c.Env = []string{"HOME=" + home, "PATH=/usr/bin:/bin"}
+	if out, err := c.CombinedOutput(); err != nil {
+		t.Fatalf("seed command failed: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".claude-sessions", name+".json")); err != nil {
+		t.Errorf("status file not written under its literal name: %v", err)
+	}
+	for _, f := range []string{"pwned", "pwned2"} {
+		if _, err := os.Stat(filepath.Join(home, f)); err == nil {
+			t.Errorf("command substitution in the name ran and created %s", f)
+		}
+	}
+}
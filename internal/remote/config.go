@@ -2,7 +2,9 @@ package remote
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
@@ -17,20 +19,34 @@ const (
 
 // Validation error messages
 var (
-	ErrRemoteNameRequired = errors.New("remote name is required")
-	ErrRemoteHostRequired = errors.New("remote host is required")
-	ErrRemoteUserRequired = errors.New("remote user is required")
-	ErrRemoteKeyRequired  = errors.New("remote SSH key path is required")
+	ErrRemoteNameRequired    = errors.New("remote name is required")
+	ErrRemoteHostRequired    = errors.New("remote host is required")
+	ErrRemoteCommandRequired = errors.New("remote command is required for the command transport")
+	ErrUnknownTransport      = errors.New("unknown remote transport")
+	ErrDuplicateRemote       = errors.New("duplicate remote name")
 )
 
 // Config represents a single remote machine configuration.
+// Host may be a Host alias from ~/.ssh/config; User, Port, Key and JumpHost
+// override the alias's User, Port, IdentityFile and ProxyJump when set.
+// JumpHost accepts a comma-separated chain of [user@]host[:port] hops.
+//
+// With Transport "command", Host and the SSH fields are unused. Instead,
+// Command is a prefix such as "docker exec -i dev sh -c". Each remote shell
+// command is appended to it as a single argument. AttachCommand is the prefix
+// used for interactive attach (e.g. "docker exec -it dev sh -c"); it defaults
+// to Command.
 type Config struct {
-	Name        string `yaml:"name"`
-	Host        string `yaml:"host"`
-	User        string `yaml:"user"`
-	Key         string `yaml:"key"`
-	SessionsDir string `yaml:"sessions_dir,omitempty"`
-	JumpHost    string `yaml:"jump_host,omitempty"`
+	Name          string `yaml:"name"`
+	Host          string `yaml:"host,omitempty"`
+	User          string `yaml:"user,omitempty"`
+	Port          int    `yaml:"port,omitempty"`
+	Key           string `yaml:"key,omitempty"`
+	SessionsDir   string `yaml:"sessions_dir,omitempty"`
+	JumpHost      string `yaml:"jump_host,omitempty"`
+	Transport     string `yaml:"transport,omitempty"` // ssh (default) or command
+	Command       string `yaml:"command,omitempty"`
+	AttachCommand string `yaml:"attach_command,omitempty"`
 }
 
 // RemotesConfig is the root structure for the remotes YAML configuration file.
@@ -39,46 +55,93 @@ type RemotesConfig struct {
 }
 
 // LoadConfig loads the remote machine configuration from the YAML file.
+// It fails on the first remote that doesn't validate.
 func LoadConfig() ([]Config, error) {
-	configPath := pathutil.ExpandPath(ConfigPath)
+	remotes, invalid, err := LoadConfigFile(ExpandConfigPath())
+	if err != nil {
+		return nil, err
+	}
+	if len(invalid) > 0 {
+		return nil, invalid[0].Err
+	}
+	return remotes, nil
+}
 
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return []Config{}, nil
+// ConfigError is a remotes.yaml entry that failed validation.
+type ConfigError struct {
+	Name string // may be empty when the entry has no name
+	Err  error
+}
+
+func (e ConfigError) Error() string {
+	if e.Name == "" {
+		return e.Err.Error()
 	}
+	return fmt.Sprintf("%s: %v", e.Name, e.Err)
+}
 
-	data, err := os.ReadFile(configPath)
+// LoadConfigFile loads remotes from path, with defaults applied. Entries that
+// fail validation, or reuse an earlier entry's name, are skipped and returned
+// as invalid so one bad entry doesn't disable the rest. A missing file yields
+// no remotes; err is set only when the file can't be read or parsed.
+func LoadConfigFile(path string) (remotes []Config, invalid []ConfigError, err error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return []Config{}, nil, nil
+	}
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var config RemotesConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, err
+	config, err := ParseConfigData(data)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	remotes = []Config{}
+	seen := make(map[string]bool)
 	for i := range config.Remotes {
-		if err := ValidateConfig(&config.Remotes[i]); err != nil {
-			return nil, err
+		rc := config.Remotes[i]
+		if err := ValidateConfig(&rc); err != nil {
+			invalid = append(invalid, ConfigError{Name: rc.Name, Err: err})
+			continue
+		}
+		if seen[rc.Name] {
+			invalid = append(invalid, ConfigError{Name: rc.Name, Err: ErrDuplicateRemote})
+			continue
 		}
-		ApplyDefaults(&config.Remotes[i])
+		seen[rc.Name] = true
+		ApplyDefaults(&rc)
+		remotes = append(remotes, rc)
 	}
 
-	return config.Remotes, nil
+	return remotes, invalid, nil
 }
 
 // ValidateConfig validates that all required fields are present in a Config.
+// User and Key are optional; they fall back to ~/.ssh/config, the local user
+// name, ssh-agent and the default identity files. Command transports need a
+// Command instead of a Host.
 func ValidateConfig(rc *Config) error {
 	if rc.Name == "" {
 		return ErrRemoteNameRequired
 	}
-	if rc.Host == "" {
-		return ErrRemoteHostRequired
-	}
-	if rc.User == "" {
-		return ErrRemoteUserRequired
-	}
-	if rc.Key == "" {
-		return ErrRemoteKeyRequired
+	switch rc.Transport {
+	case "", TransportSSH:
+		if rc.Host == "" {
+			return ErrRemoteHostRequired
+		}
+	case TransportCommand:
+		if strings.TrimSpace(rc.Command) == "" {
+			return ErrRemoteCommandRequired
+		}
+		for _, prefix := range []string{rc.Command, rc.AttachCommand} {
+			if _, err := SplitCommand(prefix); err != nil {
+				return fmt.Errorf("remote %s: %w", rc.Name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("%w %q for remote %s", ErrUnknownTransport, rc.Transport, rc.Name)
 	}
 	return nil
 }
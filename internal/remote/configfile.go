@@ -0,0 +1,253 @@
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrRemoteNotFound is returned when a remote is not in the config file.
+var ErrRemoteNotFound = errors.New("remote not found in config")
+
+// configIndent matches the two-space indentation used in the README examples.
+const configIndent = 2
+
+// ReadRawConfig returns the remotes in the config file at path as written,
+// without validation or defaults, for editing.
+func ReadRawConfig(path string) ([]Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	config, err := ParseConfigData(data)
+	if err != nil {
+		return nil, err
+	}
+	return config.Remotes, nil
+}
+
+// SaveRemote writes rc to the config file at path. The entry named oldName is
+// updated in place, or rc is appended when oldName is empty or not found.
+// Comments, key order and keys navi doesn't know are preserved; known keys
+// that are empty in rc are removed from the entry.
+func SaveRemote(path, oldName string, rc Config) error {
+	doc, perm, err := readConfigNode(path)
+	if err != nil {
+		return err
+	}
+	seq, err := remotesSequence(doc)
+	if err != nil {
+		return err
+	}
+
+	var updated yaml.Node
+	if err := updated.Encode(rc); err != nil {
+		return fmt.Errorf("encode remote: %w", err)
+	}
+
+	if entry := findRemoteNode(seq, oldName); oldName != "" && entry != nil {
+		mergeMapping(entry, &updated)
+	} else {
+		seq.Content = append(seq.Content, &updated)
+	}
+
+	return writeConfigNode(path, doc, perm)
+}
+
+// DeleteRemote removes the entry named name from the config file at path,
+// keeping the comments on the other entries.
+func DeleteRemote(path, name string) error {
+	doc, perm, err := readConfigNode(path)
+	if err != nil {
+		return err
+	}
+	seq, err := remotesSequence(doc)
+	if err != nil {
+		return err
+	}
+
+	for i, entry := range seq.Content {
+		if remoteNodeName(entry) == name {
+			seq.Content = append(seq.Content[:i], seq.Content[i+1:]...)
+			return writeConfigNode(path, doc, perm)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrRemoteNotFound, name)
+}
+
+// readConfigNode parses the config file into a document node, returning an
+// empty document for a missing or empty file along with the file mode to keep.
+func readConfigNode(path string) (*yaml.Node, os.FileMode, error) {
+	perm := os.FileMode(0o644)
+	doc := &yaml.Node{Kind: yaml.DocumentNode}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return doc, perm, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("read config: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, 0, fmt.Errorf("parse config: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc.Kind = yaml.DocumentNode
+	}
+	return doc, perm, nil
+}
+
+// remotesSequence returns the remotes sequence node, creating the root
+// mapping and the remotes key if needed.
+func remotesSequence(doc *yaml.Node) (*yaml.Node, error) {
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parse config: top level is not a mapping")
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "remotes" {
+			continue
+		}
+		seq := root.Content[i+1]
+		if seq.Kind == yaml.ScalarNode && seq.Tag == "!!null" {
+			// "remotes:" with no entries
+			*seq = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", HeadComment: seq.HeadComment, LineComment: seq.LineComment}
+		}
+		if seq.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("parse config: remotes is not a list")
+		}
+		seq.Style = 0 // write a flow-style [] as a block list
+		return seq, nil
+	}
+
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "remotes"}, seq)
+	return seq, nil
+}
+
+// findRemoteNode returns the entry in seq whose name is name, or nil.
+func findRemoteNode(seq *yaml.Node, name string) *yaml.Node {
+	for _, entry := range seq.Content {
+		if remoteNodeName(entry) == name {
+			return entry
+		}
+	}
+	return nil
+}
+
+// remoteNodeName returns the name value of a remote entry node.
+func remoteNodeName(entry *yaml.Node) string {
+	if entry.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(entry.Content); i += 2 {
+		if entry.Content[i].Value == "name" {
+			return entry.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+// mergeMapping copies the values in src into dst. Existing keys keep their
+// position and comments, new keys are appended, and known Config keys missing
+// from src are removed.
+func mergeMapping(dst, src *yaml.Node) {
+	values := make(map[string]*yaml.Node)
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		values[src.Content[i].Value] = src.Content[i+1]
+	}
+	known := configKeys()
+
+	kept := dst.Content[:0]
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		key, value := dst.Content[i], dst.Content[i+1]
+		next, ok := values[key.Value]
+		if !ok && known[key.Value] {
+			continue
+		}
+		if ok {
+			value.Kind, value.Tag, value.Value, value.Content = next.Kind, next.Tag, next.Value, next.Content
+			value.Style = next.Style
+			seen[key.Value] = true
+		}
+		kept = append(kept, key, value)
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		if !seen[src.Content[i].Value] {
+			kept = append(kept, src.Content[i], src.Content[i+1])
+		}
+	}
+	dst.Content = kept
+}
+
+// configKeys returns the YAML keys of the Config fields.
+func configKeys() map[string]bool {
+	keys := make(map[string]bool)
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get("yaml"); tag != "" {
+			keys[strings.Split(tag, ",")[0]] = true
+		}
+	}
+	return keys
+}
+
+// writeConfigNode writes the document to path through a temp file so a
+// concurrent reload never sees a partial file.
+func writeConfigNode(path string, doc *yaml.Node, perm os.FileMode) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(configIndent)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create config directory: %w", err)
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "remotes-*.yaml")
+	if err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(buf.Bytes()); err != nil {
+		tmpFile.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write config: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write config: %w", err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write config: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package remote
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const commentedConfig = `# navi remotes
+remotes:
+  # main dev machine
+  - name: devbox
+    host: devbox # ssh alias
+    user: me
+    color: blue
+  - name: staging
+    host: staging.example.com
+`
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "remotes.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSaveRemoteUpdatePreservesComments(t *testing.T) {
+	path := writeConfigFile(t, commentedConfig)
+
+	if err := SaveRemote(path, "devbox", Config{Name: "devbox", Host: "devbox.lan", Port: 2222}); err != nil {
+		t.Fatalf("SaveRemote: %v", err)
+	}
+
+	got := readFile(t, path)
+	for _, want := range []string{"# navi remotes", "# main dev machine", "host: devbox.lan # ssh alias", "port: 2222", "color: blue", "name: staging"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("config missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "user: me") {
+		t.Errorf("cleared user should be removed:\n%s", got)
+	}
+
+	remotes, err := ReadRawConfig(path)
+	if err != nil || len(remotes) != 2 || remotes[0].Host != "devbox.lan" {
+		t.Errorf("ReadRawConfig = %+v, %v", remotes, err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600 kept", info.Mode().Perm())
+	}
+}
+
+func TestSaveRemoteRename(t *testing.T) {
+	path := writeConfigFile(t, commentedConfig)
+
+	if err := SaveRemote(path, "staging", Config{Name: "prod", Host: "prod.example.com"}); err != nil {
+		t.Fatalf("SaveRemote: %v", err)
+	}
+	remotes, _ := ReadRawConfig(path)
+	if len(remotes) != 2 || remotes[1].Name != "prod" {
+		t.Errorf("remotes = %+v, want staging renamed to prod", remotes)
+	}
+}
+
+func TestSaveRemoteAppend(t *testing.T) {
+	for name, content := range map[string]string{
+		"missing file": "",
+		"empty list":   "remotes: []\n",
+		"null list":    "# keep me\nremotes:\n",
+		"existing":     commentedConfig,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "navi", "remotes.yaml")
+			if content != "" {
+				path = writeConfigFile(t, content)
+			}
+			rc := Config{Name: "ctr", Transport: TransportCommand, Command: "docker exec -i dev sh -c"}
+			if err := SaveRemote(path, "", rc); err != nil {
+				t.Fatalf("SaveRemote: %v", err)
+			}
+
+			remotes, invalid, err := LoadConfigFile(path)
+			if err != nil || len(invalid) != 0 {
+				t.Fatalf("LoadConfigFile = %v, %v", invalid, err)
+			}
+			last := remotes[len(remotes)-1]
+			if last.Name != "ctr" || last.Command != rc.Command {
+				t.Errorf("appended remote = %+v", last)
+			}
+			if content == commentedConfig && !strings.Contains(readFile(t, path), "# main dev machine") {
+				t.Error("comments lost on append")
+			}
+		})
+	}
+}
+
+func TestDeleteRemote(t *testing.T) {
+	path := writeConfigFile(t, commentedConfig)
+
+	if err := DeleteRemote(path, "staging"); err != nil {
+		t.Fatalf("DeleteRemote: %v", err)
+	}
+	got := readFile(t, path)
+	if strings.Contains(got, "staging") || !strings.Contains(got, "# main dev machine") {
+		t.Errorf("config after delete:\n%s", got)
+	}
+
+	if err := DeleteRemote(path, "staging"); !errors.Is(err, ErrRemoteNotFound) {
+		t.Errorf("second delete error = %v, want ErrRemoteNotFound", err)
+	}
+}
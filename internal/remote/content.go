@@ -0,0 +1,116 @@
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stwalsh4118/navi/internal/git"
+)
+
+// MaxContentBytes limits content fetched for the content viewer. Output
+// beyond it is cut on the remote.
+const MaxContentBytes = 1 << 20
+
+// ContentTruncatedMsg is appended to content cut at MaxContentBytes.
+const ContentTruncatedMsg = "\n... (truncated)"
+
+// Content fetch errors.
+var (
+	ErrNotGitRepo         = errors.New("not a git repository")
+	ErrRemoteFileNotFound = errors.New("file does not exist on remote")
+)
+
+// Exit statuses of the content fetch commands.
+const (
+	contentExitNoDir   = 3
+	contentExitNotRepo = 4
+	contentExitNoFile  = 5
+)
+
+// FetchDiff returns the working tree diff of a repository on a remote,
+// truncated like git.GetDiff.
+func FetchDiff(pool *SSHPool, remoteName, dir string) (string, error) {
+	diff, err := fetchGitContent(pool, remoteName, dir, "git diff")
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(diff, "\n")
+	if len(lines) > git.DiffMaxLines {
+		diff = strings.Join(lines[:git.DiffMaxLines], "\n") + git.DiffTruncatedMsg
+	}
+	return diff, nil
+}
+
+// FetchDiffStat returns git diff --stat for a repository on a remote.
+func FetchDiffStat(pool *SSHPool, remoteName, dir string) (string, error) {
+	return fetchGitContent(pool, remoteName, dir, "git diff --stat")
+}
+
+// FetchLog returns the last n commits with their file stats for a repository
+// on a remote.
+func FetchLog(pool *SSHPool, remoteName, dir string, n int) (string, error) {
+	return fetchGitContent(pool, remoteName, dir, fmt.Sprintf("git log --stat -n %d", n))
+}
+
+// ReadFile returns the contents of a file on a remote, cut at MaxContentBytes
+// with ContentTruncatedMsg appended. A leading ~ means the remote $HOME.
+func ReadFile(pool *SSHPool, remoteName, filePath string) ([]byte, error) {
+	p := remoteDirExpr(filePath)
+	cmd := fmt.Sprintf("[ -f %s ] || exit %d; head -c %d %s", p, contentExitNoFile, MaxContentBytes+1, p)
+	output, err := pool.Execute(remoteName, cmd)
+	if err != nil {
+		if code, ok := exitStatus(err); ok && code == contentExitNoFile {
+			return nil, fmt.Errorf("%w: %s", ErrRemoteFileNotFound, filePath)
+		}
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return nil, fmt.Errorf("%s: %w", msg, err)
+		}
+		return nil, err
+	}
+	content, truncated := limitContent(output)
+	if truncated {
+		content = append(content, ContentTruncatedMsg...)
+	}
+	return content, nil
+}
+
+// fetchGitContent runs a git command in dir on a remote and returns its
+// trimmed output, cut at MaxContentBytes.
+func fetchGitContent(pool *SSHPool, remoteName, dir, gitCmd string) (string, error) {
+	cmd := fmt.Sprintf("cd %s 2>/dev/null || exit %d; git rev-parse --git-dir >/dev/null 2>&1 || exit %d; %s 2>&1 | head -c %d",
+		remoteDirExpr(dir), contentExitNoDir, contentExitNotRepo, gitCmd, MaxContentBytes+1)
+	output, err := pool.Execute(remoteName, cmd)
+	if err != nil {
+		if code, ok := exitStatus(err); ok {
+			switch code {
+			case contentExitNoDir:
+				return "", fmt.Errorf("%w: %s", ErrRemoteDirNotFound, dir)
+			case contentExitNotRepo:
+				return "", fmt.Errorf("%w: %s", ErrNotGitRepo, dir)
+			}
+		}
+		return "", fmt.Errorf("failed to run %q on %s: %w", gitCmd, remoteName, err)
+	}
+
+	content, truncated := limitContent(output)
+	result := strings.TrimSpace(string(content))
+	if truncated {
+		result += ContentTruncatedMsg
+	}
+	return result, nil
+}
+
+// limitContent cuts output to MaxContentBytes, at the last full line when
+// there is one, and reports whether anything was cut.
+func limitContent(output []byte) ([]byte, bool) {
+	if len(output) <= MaxContentBytes {
+		return output, false
+	}
+	output = output[:MaxContentBytes]
+	if i := bytes.LastIndexByte(output, '\n'); i > 0 {
+		output = output[:i]
+	}
+	return output, true
+}
@@ -0,0 +1,99 @@
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newGitRepo creates a repository with one commit of a.txt and an unstaged
+// change to it.
+func newGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("one\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "a.txt")
+	run("-c", "user.email=navi@example.com", "-c", "user.name=navi", "commit", "-q", "-m", "add a.txt")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("one\ntwo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestFetchGitContent(t *testing.T) {
+	pool := newLocalShellPool(t)
+	repo := newGitRepo(t)
+
+	diff, err := FetchDiff(pool, "ctr", repo)
+	if err != nil || !strings.Contains(diff, "+two") {
+		t.Errorf("FetchDiff = %q, %v; want the unstaged change", diff, err)
+	}
+
+	stat, err := FetchDiffStat(pool, "ctr", repo)
+	if err != nil || !strings.Contains(stat, "a.txt") || !strings.Contains(stat, "1 insertion") {
+		t.Errorf("FetchDiffStat = %q, %v", stat, err)
+	}
+
+	log, err := FetchLog(pool, "ctr", repo, 5)
+	if err != nil || !strings.Contains(log, "add a.txt") || !strings.Contains(log, "a.txt | 1 +") {
+		t.Errorf("FetchLog = %q, %v", log, err)
+	}
+
+	if _, err := FetchDiff(pool, "ctr", t.TempDir()); !errors.Is(err, ErrNotGitRepo) {
+		t.Errorf("non-repo error = %v, want ErrNotGitRepo", err)
+	}
+	if _, err := FetchDiff(pool, "ctr", filepath.Join(repo, "missing")); !errors.Is(err, ErrRemoteDirNotFound) {
+		t.Errorf("missing dir error = %v, want ErrRemoteDirNotFound", err)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	pool := newLocalShellPool(t)
+	dir := t.TempDir()
+	small := filepath.Join(dir, "small.md")
+	if err := os.WriteFile(small, []byte("# Task\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ReadFile(pool, "ctr", small)
+	if err != nil || string(data) != "# Task\n" {
+		t.Errorf("ReadFile = %q, %v", data, err)
+	}
+
+	if _, err := ReadFile(pool, "ctr", filepath.Join(dir, "missing.md")); !errors.Is(err, ErrRemoteFileNotFound) {
+		t.Errorf("missing file error = %v, want ErrRemoteFileNotFound", err)
+	}
+
+	large := filepath.Join(dir, "large.log")
+	line := strings.Repeat("x", 99) + "\n"
+	if err := os.WriteFile(large, bytes.Repeat([]byte(line), MaxContentBytes/len(line)+10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data, err = ReadFile(pool, "ctr", large)
+	if err != nil {
+		t.Fatalf("ReadFile(large) error = %v", err)
+	}
+	if !strings.HasSuffix(string(data), line[:len(line)-1]+ContentTruncatedMsg) {
+		t.Errorf("large file should end at a full line followed by the truncation note")
+	}
+	if len(data) > MaxContentBytes+len(ContentTruncatedMsg) {
+		t.Errorf("large file read %d bytes, limit is %d", len(data), MaxContentBytes)
+	}
+}
@@ -0,0 +1,102 @@
+package remote
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// fakeTmux logs its arguments and reports a session named "taken" as existing.
+const fakeTmux = `#!/bin/sh
+echo "$@" >> "$TMUX_LOG"
+[ "$1" = has-session ] && [ "$3" = "=taken" ] && exit 0
+[ "$1" = has-session ] && exit 1
+exit 0
+`
+
+// newShellRemotePool returns a pool whose remote runs commands with sh, using
+// home as $HOME and a fake tmux that logs to the returned file.
+func newShellRemotePool(t *testing.T) (*SSHPool, string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	remoteHome := t.TempDir()
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "tmux"), []byte(fakeTmux), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tmuxLog := filepath.Join(t.TempDir(), "tmux.log")
+
+	handler := func(cmd string, ch ssh.Channel) uint32 {
+		c := exec.Command("sh", "-c", cmd)
+		c.Env = []string{"HOME=" + remoteHome, "PATH=" + binDir + ":/usr/bin:/bin", "TMUX_LOG=" + tmuxLog}
+		c.Stdout = ch
+		c.Stderr = ch.Stderr()
+		err := c.Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return uint32(exitErr.ExitCode())
+		}
+		return 0
+	}
+
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	clientKey := writeTestKey(t, keyPath)
+	server := newTestSSHServerWithExec(t, clientKey.PublicKey(), handler)
+	pool, _ := newTestPool(t, server, Config{Key: keyPath})
+	t.Cleanup(pool.Close)
+	trustServer(t, pool, server)
+	return pool, remoteHome, tmuxLog
+}
+
+func TestCreateSession(t *testing.T) {
+	pool, remoteHome, tmuxLog := newShellRemotePool(t)
+	if err := os.MkdirAll(filepath.Join(remoteHome, "src", "api"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CreateSession(pool, "dev", "api", "~/src/api", true, "~/.claude-sessions"); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	log, _ := os.ReadFile(tmuxLog)
+	wantDir := filepath.Join(remoteHome, "src", "api")
+	for _, want := range []string{
+		"new-session -d -s api -c " + wantDir,
+		"send-keys -t api claude --dangerously-skip-permissions Enter",
+	} {
+		if !strings.Contains(string(log), want) {
+			t.Errorf("tmux log missing %q:\n%s", want, log)
+		}
+	}
+
+	sessions, err := session.ReadStatusFiles(filepath.Join(remoteHome, ".claude-sessions"))
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("seeded status files = %+v, %v", sessions, err)
+	}
+	if s := sessions[0]; s.TmuxSession != "api" || s.Status != session.StatusWorking || s.CWD != wantDir {
+		t.Errorf("seeded status = %+v", s)
+	}
+}
+
+func TestCreateSessionRemoteErrors(t *testing.T) {
+	pool, _, _ := newShellRemotePool(t)
+
+	err := CreateSession(pool, "dev", "api", "/does/not/exist", false, "")
+	if !errors.Is(err, ErrRemoteDirNotFound) {
+		t.Errorf("missing dir error = %v, want ErrRemoteDirNotFound", err)
+	}
+
+	err = CreateSession(pool, "dev", "taken", "~", false, "")
+	if !errors.Is(err, ErrRemoteNameExists) {
+		t.Errorf("existing name error = %v, want ErrRemoteNameExists", err)
+	}
+}
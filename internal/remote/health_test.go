@@ -0,0 +1,147 @@
+package remote
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+var errDialFailed = errors.New("dial failed")
+
+// failingDialer returns a dialer that always fails and counts its calls.
+func failingDialer(calls *atomic.Int32, delay time.Duration) Dialer {
+	return func(*Config) (*ssh.Client, error) {
+		calls.Add(1)
+		time.Sleep(delay)
+		return nil, errDialFailed
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, BackoffInitial},
+		{2, 2 * BackoffInitial},
+		{3, 4 * BackoffInitial},
+		{20, BackoffMax},
+	}
+	for _, tt := range tests {
+		if got := backoffDelay(tt.failures); got != tt.want {
+			t.Errorf("backoffDelay(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestConnectBacksOffAfterFailure(t *testing.T) {
+	pool := NewSSHPool([]Config{{Name: "dead", Host: "dead.example.com"}})
+	var calls atomic.Int32
+	pool.SetDialer(failingDialer(&calls, 0))
+
+	if _, err := pool.Connect("dead"); !errors.Is(err, errDialFailed) {
+		t.Fatalf("first connect error = %v, want dial error", err)
+	}
+	_, err := pool.Connect("dead")
+	if !errors.Is(err, ErrBackoff) {
+		t.Fatalf("second connect error = %v, want ErrBackoff", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("dialed %d times, want 1 while backing off", calls.Load())
+	}
+
+	st := pool.GetStatus("dead")
+	if st.Status != StatusError || st.Failures != 1 || !errors.Is(st.LastError, errDialFailed) {
+		t.Errorf("status = %+v", st)
+	}
+	if wait := time.Until(st.NextRetry); wait <= 0 || wait > BackoffInitial {
+		t.Errorf("next retry in %s, want within %s", wait, BackoffInitial)
+	}
+
+	// Reconnect dials at once and restarts the failure count
+	if err := pool.Reconnect("dead"); !errors.Is(err, errDialFailed) {
+		t.Errorf("reconnect error = %v, want dial error", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("dialed %d times, want reconnect to dial", calls.Load())
+	}
+	if st := pool.GetStatus("dead"); st.Failures != 1 {
+		t.Errorf("failures after reconnect = %d, want count restarted", st.Failures)
+	}
+}
+
+func TestDeadRemoteDoesNotBlockOthers(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	clientKey := writeTestKey(t, keyPath)
+	server := newTestSSHServer(t, clientKey.PublicKey())
+	pool, _ := newTestPool(t, server, Config{Name: "good", Key: keyPath})
+	defer pool.Close()
+	trustServer(t, pool, server)
+
+	// Add a second remote whose dials hang before failing
+	pool.remotes["dead"] = &Config{Name: "dead", Host: "dead.example.com"}
+	pool.status["dead"] = &RemoteStatus{}
+	pool.connectMu["dead"] = new(sync.Mutex)
+	realDial := pool.dial
+	var deadCalls atomic.Int32
+	slowFail := failingDialer(&deadCalls, 500*time.Millisecond)
+	pool.SetDialer(func(rc *Config) (*ssh.Client, error) {
+		if rc.Name == "dead" {
+			return slowFail(rc)
+		}
+		return realDial(rc)
+	})
+
+	go pool.Connect("dead")
+	time.Sleep(20 * time.Millisecond)
+
+	start := time.Now()
+	if _, err := pool.Execute("good", "true"); err != nil {
+		t.Fatalf("Execute(good): %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("good remote took %s while dead remote was dialing", elapsed)
+	}
+
+	st := pool.GetStatus("good")
+	if st.Status != StatusConnected || st.Latency <= 0 {
+		t.Errorf("good status = %+v, want connected with latency", st)
+	}
+}
+
+func TestPauseAndReconnect(t *testing.T) {
+	pool, fake := newFakeRemotePool(t, false)
+	remote := *pool.GetRemoteConfig("dev")
+	writeStatusFile(t, fake.dir, "api", "idle")
+
+	if _, err := PollSingleRemote(pool, remote); err != nil {
+		t.Fatalf("PollSingleRemote: %v", err)
+	}
+	st := pool.GetStatus("dev")
+	if st.Sessions != 1 || st.LastSuccess.IsZero() {
+		t.Errorf("status after poll = %+v, want 1 session and a success time", st)
+	}
+
+	pool.Pause("dev")
+	if _, err := PollSingleRemote(pool, remote); !errors.Is(err, ErrPaused) {
+		t.Errorf("poll while paused error = %v, want ErrPaused", err)
+	}
+	if _, err := pool.Connect("dev"); !errors.Is(err, ErrPaused) {
+		t.Errorf("connect while paused error = %v, want ErrPaused", err)
+	}
+	if got := pool.GetStatus("dev").Status; got != StatusPaused {
+		t.Errorf("status = %v, want paused", got)
+	}
+
+	if err := pool.Reconnect("dev"); err != nil {
+		t.Fatalf("Reconnect: %v", err)
+	}
+	if _, err := PollSingleRemote(pool, remote); err != nil {
+		t.Errorf("poll after reconnect: %v", err)
+	}
+}
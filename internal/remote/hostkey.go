@@ -0,0 +1,186 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/stwalsh4118/navi/internal/pathutil"
+)
+
+// KnownHostsPath is the known_hosts file used to verify remote host keys.
+const KnownHostsPath = "~/.ssh/known_hosts"
+
+// Host key verification errors
+var (
+	ErrHostKeyUnknown  = errors.New("host key is not in known_hosts")
+	ErrHostKeyRejected = errors.New("host key was rejected")
+	ErrHostKeyMismatch = errors.New("host key does not match known_hosts")
+)
+
+// HostKeyPrompt describes an unknown host key awaiting a trust decision.
+type HostKeyPrompt struct {
+	Remote string        // Remote name from remotes.yaml
+	Host   string        // Address the key was presented for (host:port)
+	Key    ssh.PublicKey // Key offered by the server
+}
+
+// Fingerprint returns the SHA256 fingerprint of the offered key.
+func (h HostKeyPrompt) Fingerprint() string {
+	return ssh.FingerprintSHA256(h.Key)
+}
+
+// id identifies the host and key pair for rejection bookkeeping.
+func (h HostKeyPrompt) id() string {
+	return knownhosts.Normalize(h.Host) + " " + h.Fingerprint()
+}
+
+// hostKeyCallback returns a callback that verifies host keys against known_hosts.
+// Unknown keys are queued as a HostKeyPrompt for the named remote and the
+// connection fails until the key is trusted; changed keys always fail.
+func (p *SSHPool) hostKeyCallback(remoteName string) ssh.HostKeyCallback {
+	return func(hostname string, remoteAddr net.Addr, key ssh.PublicKey) error {
+		if verify, err := knownhosts.New(p.knownHostsFile()); err == nil {
+			err = verify(hostname, remoteAddr, key)
+			if err == nil {
+				return nil
+			}
+			var keyErr *knownhosts.KeyError
+			if !errors.As(err, &keyErr) {
+				return err
+			}
+			if len(keyErr.Want) > 0 {
+				return fmt.Errorf("%w for %s (offered %s): possible man-in-the-middle attack", ErrHostKeyMismatch, hostname, ssh.FingerprintSHA256(key))
+			}
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read known_hosts: %w", err)
+		}
+
+		prompt := HostKeyPrompt{Remote: remoteName, Host: hostname, Key: key}
+
+		p.hostKeyMu.Lock()
+		defer p.hostKeyMu.Unlock()
+		if p.rejectedHostKeys[prompt.id()] {
+			return fmt.Errorf("%w: %s %s", ErrHostKeyRejected, hostname, prompt.Fingerprint())
+		}
+		if _, ok := p.pendingHostKeys[remoteName]; !ok {
+			p.pendingHostKeys[remoteName] = &prompt
+		}
+		return fmt.Errorf("%w: %s %s", ErrHostKeyUnknown, hostname, prompt.Fingerprint())
+	}
+}
+
+// knownHostKeyAlgorithms returns the host key algorithms matching the keys
+// known_hosts holds for hostname (host:port), so that, as with OpenSSH, a
+// server offering several key types is asked for the one that can be
+// verified. Returns nil, meaning the defaults, when no key is known.
+func (p *SSHPool) knownHostKeyAlgorithms(hostname string) []string {
+	verify, err := knownhosts.New(p.knownHostsFile())
+	if err != nil {
+		return nil
+	}
+
+	// Checking a key that matches nothing lists the known keys in the error
+	var keyErr *knownhosts.KeyError
+	if !errors.As(verify(hostname, &net.TCPAddr{IP: net.IPv4zero}, placeholderHostKey{}), &keyErr) {
+		return nil
+	}
+
+	var algos []string
+	seen := make(map[string]bool)
+	for _, known := range keyErr.Want {
+		keyType := known.Key.Type()
+		candidates := []string{keyType}
+		if keyType == ssh.KeyAlgoRSA {
+			candidates = []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+		}
+		for _, algo := range candidates {
+			if !seen[algo] {
+				seen[algo] = true
+				algos = append(algos, algo)
+			}
+		}
+	}
+	return algos
+}
+
+// placeholderHostKey is a key that matches no known_hosts entry.
+type placeholderHostKey struct{}
+
+func (placeholderHostKey) Type() string                        { return "placeholder" }
+func (placeholderHostKey) Marshal() []byte                     { return []byte("placeholder") }
+func (placeholderHostKey) Verify([]byte, *ssh.Signature) error { return errors.New("placeholder key") }
+
+// knownHostsFile returns the expanded known_hosts path for the pool.
+func (p *SSHPool) knownHostsFile() string {
+	return pathutil.ExpandPath(p.knownHostsPath)
+}
+
+// hasPendingHostKey reports whether a trust decision is outstanding for a remote.
+func (p *SSHPool) hasPendingHostKey(remoteName string) bool {
+	p.hostKeyMu.Lock()
+	defer p.hostKeyMu.Unlock()
+	_, ok := p.pendingHostKeys[remoteName]
+	return ok
+}
+
+// PendingHostKey returns the next unknown host key awaiting a trust decision,
+// ordered by remote name, or nil when none are pending.
+func (p *SSHPool) PendingHostKey() *HostKeyPrompt {
+	p.hostKeyMu.Lock()
+	defer p.hostKeyMu.Unlock()
+
+	if len(p.pendingHostKeys) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(p.pendingHostKeys))
+	for name := range p.pendingHostKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	prompt := *p.pendingHostKeys[names[0]]
+	return &prompt
+}
+
+// TrustHostKey appends the prompted key to known_hosts and clears the prompt
+// so the next connection attempt succeeds.
+func (p *SSHPool) TrustHostKey(prompt HostKeyPrompt) error {
+	path := p.knownHostsFile()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create known_hosts directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open known_hosts: %w", err)
+	}
+	line := knownhosts.Line([]string{knownhosts.Normalize(prompt.Host)}, prompt.Key)
+	if _, err := fmt.Fprintln(f, line); err != nil {
+		f.Close()
+		return fmt.Errorf("write known_hosts: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write known_hosts: %w", err)
+	}
+
+	p.hostKeyMu.Lock()
+	delete(p.pendingHostKeys, prompt.Remote)
+	p.hostKeyMu.Unlock()
+	p.resetBackoff(prompt.Remote)
+	return nil
+}
+
+// RejectHostKey clears the prompt and refuses the key for the rest of the
+// session, so navi does not prompt for it again.
+func (p *SSHPool) RejectHostKey(prompt HostKeyPrompt) {
+	p.hostKeyMu.Lock()
+	defer p.hostKeyMu.Unlock()
+	p.rejectedHostKeys[prompt.id()] = true
+	delete(p.pendingHostKeys, prompt.Remote)
+}
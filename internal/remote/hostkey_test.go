@@ -0,0 +1,496 @@
+package remote
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// testSSHServer is an in-process SSH server that accepts one client key,
+// answers exec requests with "ok", and forwards direct-tcpip channels so it
+// can act as its own jump host.
+type testSSHServer struct {
+	addr    string
+	hostKey ssh.Signer
+}
+
+// testExecHandler serves one exec request, writing output to ch, and returns
+// the exit status.
+type testExecHandler func(cmd string, ch ssh.Channel) uint32
+
+func newTestSSHServer(t *testing.T, clientKey ssh.PublicKey) *testSSHServer {
+	t.Helper()
+	return newTestSSHServerWithExec(t, clientKey, nil)
+}
+
+// newTestSSHServerWithExec starts a test server that runs exec requests with
+// handler. A nil handler answers every command with "ok".
+func newTestSSHServerWithExec(t *testing.T, clientKey ssh.PublicKey, handler testExecHandler) *testSSHServer {
+	t.Helper()
+
+	hostKey := newTestSigner(t)
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) == string(clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown key")
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSSHConn(conn, config, handler)
+		}
+	}()
+
+	return &testSSHServer{addr: ln.Addr().String(), hostKey: hostKey}
+}
+
+func serveTestSSHConn(conn net.Conn, config *ssh.ServerConfig, handler testExecHandler) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newCh := range chans {
+		switch newCh.ChannelType() {
+		case "session":
+			ch, requests, err := newCh.Accept()
+			if err != nil {
+				continue
+			}
+			go func() {
+				for req := range requests {
+					if req.Type != "exec" {
+						req.Reply(false, nil)
+						continue
+					}
+					req.Reply(true, nil)
+					var code uint32
+					if handler != nil {
+						var exec struct{ Command string }
+						ssh.Unmarshal(req.Payload, &exec)
+						code = handler(exec.Command, ch)
+					} else {
+						io.WriteString(ch, "ok")
+					}
+					ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{code}))
+					ch.Close()
+				}
+			}()
+		case "direct-tcpip":
+			var target struct {
+				Host     string
+				Port     uint32
+				OrigHost string
+				OrigPort uint32
+			}
+			if err := ssh.Unmarshal(newCh.ExtraData(), &target); err != nil {
+				newCh.Reject(ssh.ConnectionFailed, err.Error())
+				continue
+			}
+			upstream, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+			if err != nil {
+				newCh.Reject(ssh.ConnectionFailed, err.Error())
+				continue
+			}
+			ch, requests, err := newCh.Accept()
+			if err != nil {
+				upstream.Close()
+				continue
+			}
+			go ssh.DiscardRequests(requests)
+			go func() {
+				io.Copy(ch, upstream)
+				ch.CloseWrite()
+			}()
+			go func() {
+				io.Copy(upstream, ch)
+				upstream.Close()
+			}()
+		default:
+			newCh.Reject(ssh.UnknownChannelType, "unsupported")
+		}
+	}
+}
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// writeTestKey writes an OpenSSH private key file and returns its signer.
+func writeTestKey(t *testing.T, path string) ssh.Signer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// newTestPool returns a pool for one remote with an isolated HOME, ssh config
+// and known_hosts, and no ssh-agent. A remote without a Host points at server.
+func newTestPool(t *testing.T, server *testSSHServer, rc Config) (*SSHPool, string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	if rc.Host == "" {
+		host, portStr, _ := net.SplitHostPort(server.addr)
+		rc.Host = host
+		rc.Port, _ = strconv.Atoi(portStr)
+	}
+	if rc.Name == "" {
+		rc.Name = "dev"
+	}
+
+	pool := NewSSHPool([]Config{rc})
+	pool.sshConfigPath = filepath.Join(home, ".ssh", "config")
+	pool.knownHostsPath = filepath.Join(home, ".ssh", "known_hosts")
+	return pool, home
+}
+
+func TestHostKeyTrustOnFirstUse(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	clientKey := writeTestKey(t, keyPath)
+	server := newTestSSHServer(t, clientKey.PublicKey())
+	pool, _ := newTestPool(t, server, Config{Key: keyPath})
+	defer pool.Close()
+
+	_, err := pool.Execute("dev", "true")
+	if !errors.Is(err, ErrHostKeyUnknown) {
+		t.Fatalf("first connect error = %v, want ErrHostKeyUnknown", err)
+	}
+
+	prompt := pool.PendingHostKey()
+	if prompt == nil {
+		t.Fatal("expected a pending host key prompt")
+	}
+	if prompt.Remote != "dev" {
+		t.Errorf("prompt remote = %q, want dev", prompt.Remote)
+	}
+	if prompt.Fingerprint() != ssh.FingerprintSHA256(server.hostKey.PublicKey()) {
+		t.Errorf("prompt fingerprint = %s, want server key", prompt.Fingerprint())
+	}
+
+	// While the prompt is pending, connections fail without redialing
+	if _, err := pool.Connect("dev"); !errors.Is(err, ErrHostKeyUnknown) {
+		t.Errorf("pending connect error = %v, want ErrHostKeyUnknown", err)
+	}
+
+	if err := pool.TrustHostKey(*prompt); err != nil {
+		t.Fatalf("TrustHostKey: %v", err)
+	}
+	if pool.PendingHostKey() != nil {
+		t.Error("prompt should be cleared after trusting")
+	}
+
+	data, err := os.ReadFile(pool.knownHostsFile())
+	if err != nil {
+		t.Fatalf("known_hosts not written: %v", err)
+	}
+	if !strings.Contains(string(data), knownhosts.Normalize(server.addr)) {
+		t.Errorf("known_hosts = %q, want entry for %s", data, server.addr)
+	}
+
+	out, err := pool.Execute("dev", "true")
+	if err != nil {
+		t.Fatalf("connect after trust: %v", err)
+	}
+	if string(out) != "ok" {
+		t.Errorf("output = %q, want ok", out)
+	}
+}
+
+func TestHostKeyMismatchFails(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	clientKey := writeTestKey(t, keyPath)
+	server := newTestSSHServer(t, clientKey.PublicKey())
+	pool, _ := newTestPool(t, server, Config{Key: keyPath})
+	defer pool.Close()
+
+	// known_hosts holds a different key for the server's address
+	other := newTestSigner(t)
+	line := knownhosts.Line([]string{knownhosts.Normalize(server.addr)}, other.PublicKey())
+	if err := os.MkdirAll(filepath.Dir(pool.knownHostsFile()), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pool.knownHostsFile(), []byte(line+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := pool.Execute("dev", "true")
+	if !errors.Is(err, ErrHostKeyMismatch) {
+		t.Fatalf("error = %v, want ErrHostKeyMismatch", err)
+	}
+	if pool.PendingHostKey() != nil {
+		t.Error("a changed key must not be offered for trust")
+	}
+}
+
+func TestHostKeyReject(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	clientKey := writeTestKey(t, keyPath)
+	server := newTestSSHServer(t, clientKey.PublicKey())
+	pool, _ := newTestPool(t, server, Config{Key: keyPath})
+	defer pool.Close()
+
+	if _, err := pool.Execute("dev", "true"); !errors.Is(err, ErrHostKeyUnknown) {
+		t.Fatalf("error = %v, want ErrHostKeyUnknown", err)
+	}
+	pool.RejectHostKey(*pool.PendingHostKey())
+
+	_, err := pool.Execute("dev", "true")
+	if !errors.Is(err, ErrHostKeyRejected) {
+		t.Fatalf("error after reject = %v, want ErrHostKeyRejected", err)
+	}
+	if pool.PendingHostKey() != nil {
+		t.Error("rejected key should not be prompted again")
+	}
+}
+
+// trustServer records the server's host key in the pool's known_hosts.
+func trustServer(t *testing.T, pool *SSHPool, server *testSSHServer) {
+	t.Helper()
+	line := knownhosts.Line([]string{knownhosts.Normalize(server.addr)}, server.hostKey.PublicKey())
+	if err := os.MkdirAll(filepath.Dir(pool.knownHostsFile()), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pool.knownHostsFile(), []byte(line+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSSHConfigAliasAndProxyJumpChain(t *testing.T) {
+	keyDir := t.TempDir()
+	keyPath := filepath.Join(keyDir, "id_ed25519")
+	clientKey := writeTestKey(t, keyPath)
+	server := newTestSSHServer(t, clientKey.PublicKey())
+
+	// The remote entry is just an alias; ssh config supplies everything else,
+	// including a two-hop ProxyJump chain through the same test server.
+	pool, home := newTestPool(t, server, Config{Name: "devbox", Host: "devbox"})
+	trustServer(t, pool, server)
+
+	host, port, _ := net.SplitHostPort(server.addr)
+	sshConfig := strings.Join([]string{
+		"Host devbox",
+		"  HostName " + host,
+		"  Port " + port,
+		"  IdentityFile " + keyPath,
+		"  ProxyJump bastion1,bastion2",
+		"Host bastion*",
+		"  HostName " + host,
+		"  Port " + port,
+		"  IdentityFile " + keyPath,
+	}, "\n")
+	if err := os.MkdirAll(filepath.Join(home, ".ssh"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pool.sshConfigPath, []byte(sshConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	out, err := pool.Execute("devbox", "true")
+	if err != nil {
+		t.Fatalf("Execute through jump chain: %v", err)
+	}
+	if string(out) != "ok" {
+		t.Errorf("output = %q, want ok", out)
+	}
+}
+
+func TestSSHAgentAuthentication(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agentKey, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newTestSSHServer(t, agentKey.PublicKey())
+	pool, home := newTestPool(t, server, Config{})
+	trustServer(t, pool, server)
+	defer pool.Close()
+
+	// Serve an in-memory agent holding the only accepted key; no key files exist
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: priv}); err != nil {
+		t.Fatal(err)
+	}
+	sock := filepath.Join(home, "agent.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go agent.ServeAgent(keyring, conn)
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", sock)
+
+	out, err := pool.Execute("dev", "true")
+	if err != nil {
+		t.Fatalf("Execute with agent: %v", err)
+	}
+	if string(out) != "ok" {
+		t.Errorf("output = %q, want ok", out)
+	}
+}
+
+func TestNoIdentityAvailable(t *testing.T) {
+	clientKey := newTestSigner(t)
+	server := newTestSSHServer(t, clientKey.PublicKey())
+	pool, _ := newTestPool(t, server, Config{})
+	trustServer(t, pool, server)
+	defer pool.Close()
+
+	_, err := pool.Execute("dev", "true")
+	if err == nil || !strings.Contains(err.Error(), "no SSH identity") {
+		t.Fatalf("error = %v, want missing identity error", err)
+	}
+}
+
+func TestKnownHostKeyAlgorithmsPreferPinnedKey(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	clientKey := writeTestKey(t, keyPath)
+
+	// The server offers ECDSA, which Go's client prefers, as well as the
+	// pinned ed25519 key
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaSigner, err := ssh.NewSignerFromKey(ecdsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed25519Signer := newTestSigner(t)
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) == string(clientKey.PublicKey().Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown key")
+		},
+	}
+	config.AddHostKey(ecdsaSigner)
+	config.AddHostKey(ed25519Signer)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSSHConn(conn, config, nil)
+		}
+	}()
+	server := &testSSHServer{addr: ln.Addr().String(), hostKey: ed25519Signer}
+
+	pool, _ := newTestPool(t, server, Config{Key: keyPath})
+	defer pool.Close()
+	if algos := pool.knownHostKeyAlgorithms(server.addr); algos != nil {
+		t.Errorf("algorithms without known_hosts = %v, want nil", algos)
+	}
+	trustServer(t, pool, server)
+
+	if algos := pool.knownHostKeyAlgorithms(server.addr); len(algos) != 1 || algos[0] != ssh.KeyAlgoED25519 {
+		t.Errorf("algorithms = %v, want [%s]", algos, ssh.KeyAlgoED25519)
+	}
+	out, err := pool.Execute("dev", "true")
+	if err != nil {
+		t.Fatalf("connect with pinned ed25519 key: %v", err)
+	}
+	if string(out) != "ok" {
+		t.Errorf("output = %q, want ok", out)
+	}
+}
+
+func TestKnownHostKeyAlgorithmsRSA(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ssh.NewPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := NewSSHPool(nil)
+	pool.knownHostsPath = filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize("devbox:22")}, pub)
+	if err := os.WriteFile(pool.knownHostsPath, []byte(line+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	if got := pool.knownHostKeyAlgorithms("devbox:22"); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("algorithms = %v, want %v", got, want)
+	}
+	if got := pool.knownHostKeyAlgorithms("other:22"); got != nil {
+		t.Errorf("algorithms for an unknown host = %v, want nil", got)
+	}
+}
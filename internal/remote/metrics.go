@@ -0,0 +1,124 @@
+package remote
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stwalsh4118/navi/internal/debug"
+	"github.com/stwalsh4118/navi/internal/metrics"
+	"github.com/stwalsh4118/navi/internal/session"
+	"github.com/stwalsh4118/navi/internal/tokens"
+)
+
+// Executor runs a shell command on a named remote and returns its stdout.
+// *SSHPool implements it; tests can substitute a fake.
+type Executor interface {
+	Execute(remoteName, command string) ([]byte, error)
+}
+
+// Metrics output label prefixes used in the bundled metrics command.
+const (
+	labelSession = "SESSION:"
+	labelRSS     = "RSS:"
+	labelProcs   = "PROCS:"
+	labelTokens  = "TOKENS:"
+)
+
+// metricsPrelude defines the shell helpers shared by every session block.
+// navi_tree prints each pane PID and its descendants using /proc/<pid>/task/*/children.
+// navi_usage sums assistant usage from a Claude transcript, counting cache reads and
+// cache creation as input the same way tokens.ParseTranscriptTokens does.
+const metricsPrelude = `navi_tree() { for p in "$@"; do echo "$p"; navi_tree $(cat /proc/"$p"/task/*/children 2>/dev/null); done; }
+navi_page=$(getconf PAGESIZE 2>/dev/null || echo 4096)
+navi_usage() { awk 'function num(key,  s) { if (match($0, key "[0-9]+")) { s = substr($0, RSTART + length(key), RLENGTH - length(key)); return s + 0 } return 0 }
+/"type":"assistant"/ { i += num("\"input_tokens\":") + num("\"cache_read_input_tokens\":") + num("\"cache_creation_input_tokens\":"); o += num("\"output_tokens\":") }
+END { print i + 0, o + 0 }' "$1" 2>/dev/null; }
+`
+
+// FetchMetrics collects resource and token metrics for the given sessions on a
+// remote with a single bundled command. The result is keyed by tmux session name;
+// sessions whose tmux session is gone are omitted. Returns an error only on
+// execution failures.
+func FetchMetrics(executor Executor, remoteName string, sessions []session.Info) (map[string]*metrics.Metrics, error) {
+	if executor == nil || len(sessions) == 0 {
+		return nil, nil
+	}
+
+	output, err := executor.Execute(remoteName, buildMetricsCommand(sessions))
+	if err != nil {
+		debug.Log("remote[%s]: metrics command failed: %v", remoteName, err)
+		return nil, err
+	}
+
+	return parseMetricsOutput(string(output)), nil
+}
+
+// buildMetricsCommand constructs a shell script that, for each session, walks the
+// process tree of its tmux panes, sums RSS from /proc, and totals token usage from
+// the newest transcript in the session's Claude project folder.
+func buildMetricsCommand(sessions []session.Info) string {
+	var b strings.Builder
+	b.WriteString(metricsPrelude)
+
+	for _, s := range sessions {
+		name := shellQuote(s.TmuxSession)
+		project := shellQuote(tokens.CWDToProjectPath(s.CWD))
+		fmt.Fprintf(&b, `navi_pids=$(tmux list-panes -s -t %s -F '#{pane_pid}' 2>/dev/null)
+if [ -n "$navi_pids" ]; then
+echo "SESSION:"%s
+navi_all=$(navi_tree $navi_pids | sort -u)
+echo "PROCS:$(echo "$navi_all" | grep -c .)"
+echo "RSS:$(for p in $navi_all; do cat /proc/"$p"/statm 2>/dev/null; done | awk -v pg="$navi_page" '{ s += $2 } END { print s * pg }')"
+navi_t=$(ls -t "$HOME/.claude/projects/"%s/*.jsonl 2>/dev/null | head -1)
+[ -n "$navi_t" ] && echo "TOKENS:$(navi_usage "$navi_t")"
+fi
+`, name, name, project)
+	}
+
+	return b.String()
+}
+
+// parseMetricsOutput parses the labeled output of the bundled metrics command.
+// Each SESSION line starts a new block; unknown or malformed lines are ignored.
+func parseMetricsOutput(output string) map[string]*metrics.Metrics {
+	result := make(map[string]*metrics.Metrics)
+
+	var current *metrics.Metrics
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, labelSession):
+			name := strings.TrimPrefix(line, labelSession)
+			if name == "" {
+				current = nil
+				continue
+			}
+			current = &metrics.Metrics{Resource: &metrics.ResourceMetrics{}}
+			result[name] = current
+		case current == nil:
+			continue
+		case strings.HasPrefix(line, labelRSS):
+			if n, err := strconv.ParseInt(strings.TrimPrefix(line, labelRSS), 10, 64); err == nil {
+				current.Resource.RSSBytes = n
+			}
+		case strings.HasPrefix(line, labelProcs):
+			if n, err := strconv.Atoi(strings.TrimPrefix(line, labelProcs)); err == nil {
+				current.Resource.ProcessCount = n
+			}
+		case strings.HasPrefix(line, labelTokens):
+			fields := strings.Fields(strings.TrimPrefix(line, labelTokens))
+			if len(fields) != 2 {
+				continue
+			}
+			input, err1 := strconv.ParseInt(fields[0], 10, 64)
+			output, err2 := strconv.ParseInt(fields[1], 10, 64)
+			if err1 != nil || err2 != nil || input+output == 0 {
+				continue
+			}
+			current.Tokens = &metrics.TokenMetrics{Input: input, Output: output, Total: input + output}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,208 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// fakeExecutor records commands and returns canned output.
+type fakeExecutor struct {
+	output   string
+	err      error
+	remotes  []string
+	commands []string
+}
+
+func (f *fakeExecutor) Execute(remoteName, command string) ([]byte, error) {
+	f.remotes = append(f.remotes, remoteName)
+	f.commands = append(f.commands, command)
+	return []byte(f.output), f.err
+}
+
+// localExecutor runs the command through sh on this machine.
+type localExecutor struct {
+	env []string
+}
+
+func (l localExecutor) Execute(_, command string) ([]byte, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = l.env
+	return cmd.Output()
+}
+
+func TestParseMetricsOutput(t *testing.T) {
+	output := strings.Join([]string{
+		"SESSION:api",
+		"PROCS:4",
+		"RSS:1048576",
+		"TOKENS:1200 300",
+		"SESSION:web",
+		"PROCS:2",
+		"RSS:2048",
+		"SESSION:idle",
+		"PROCS:1",
+		"RSS:4096",
+		"TOKENS:0 0",
+		"",
+	}, "\n")
+
+	got := parseMetricsOutput(output)
+	if len(got) != 3 {
+		t.Fatalf("got %d sessions, want 3", len(got))
+	}
+
+	api := got["api"]
+	if api.Resource.RSSBytes != 1048576 || api.Resource.ProcessCount != 4 {
+		t.Errorf("api resource = %+v", api.Resource)
+	}
+	if api.Tokens == nil || api.Tokens.Input != 1200 || api.Tokens.Output != 300 || api.Tokens.Total != 1500 {
+		t.Errorf("api tokens = %+v", api.Tokens)
+	}
+
+	if got["web"].Tokens != nil {
+		t.Errorf("web tokens = %+v, want nil without TOKENS line", got["web"].Tokens)
+	}
+	if got["idle"].Tokens != nil {
+		t.Errorf("idle tokens = %+v, want nil for zero usage", got["idle"].Tokens)
+	}
+}
+
+func TestParseMetricsOutputIgnoresMalformedLines(t *testing.T) {
+	output := "RSS:100\nSESSION:\nPROCS:3\nSESSION:a\nRSS:abc\nPROCS:x\nTOKENS:5\nnoise\n"
+
+	got := parseMetricsOutput(output)
+	if len(got) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(got))
+	}
+	a := got["a"]
+	if a.Resource.RSSBytes != 0 || a.Resource.ProcessCount != 0 || a.Tokens != nil {
+		t.Errorf("a = resource %+v tokens %+v, want zero values", a.Resource, a.Tokens)
+	}
+}
+
+func TestBuildMetricsCommand(t *testing.T) {
+	cmd := buildMetricsCommand([]session.Info{
+		{TmuxSession: "it's", CWD: "/home/dev/api"},
+		{TmuxSession: "web", CWD: "/srv/web"},
+	})
+
+	for _, want := range []string{
+		`tmux list-panes -s -t 'it'"'"'s'`,
+		`tmux list-panes -s -t 'web'`,
+		`"$HOME/.claude/projects/"'-home-dev-api'/*.jsonl`,
+		`"$HOME/.claude/projects/"'-srv-web'/*.jsonl`,
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("command missing %q", want)
+		}
+	}
+}
+
+func TestFetchMetrics(t *testing.T) {
+	executor := &fakeExecutor{output: "SESSION:api\nPROCS:2\nRSS:512\nTOKENS:10 5\n"}
+	sessions := []session.Info{{TmuxSession: "api", CWD: "/srv/api", Remote: "dev"}}
+
+	got, err := FetchMetrics(executor, "dev", sessions)
+	if err != nil {
+		t.Fatalf("FetchMetrics error: %v", err)
+	}
+	if len(executor.commands) != 1 {
+		t.Fatalf("executed %d commands, want 1 batched command", len(executor.commands))
+	}
+	if executor.remotes[0] != "dev" {
+		t.Errorf("remote = %q, want dev", executor.remotes[0])
+	}
+	if got["api"] == nil || got["api"].Resource.RSSBytes != 512 || got["api"].Tokens.Total != 15 {
+		t.Errorf("metrics = %+v", got["api"])
+	}
+}
+
+func TestFetchMetricsError(t *testing.T) {
+	executor := &fakeExecutor{err: errors.New("connection refused")}
+
+	got, err := FetchMetrics(executor, "dev", []session.Info{{TmuxSession: "api"}})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got != nil {
+		t.Errorf("metrics = %v, want nil", got)
+	}
+}
+
+func TestFetchMetricsNoSessions(t *testing.T) {
+	executor := &fakeExecutor{}
+
+	got, err := FetchMetrics(executor, "dev", nil)
+	if err != nil || got != nil {
+		t.Errorf("FetchMetrics = %v, %v; want nil, nil", got, err)
+	}
+	if len(executor.commands) != 0 {
+		t.Errorf("executed %d commands, want 0", len(executor.commands))
+	}
+}
+
+func TestMetricsCommandRunsInShell(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("requires /proc")
+	}
+
+	dir := t.TempDir()
+	home := filepath.Join(dir, "home")
+	bin := filepath.Join(dir, "bin")
+
+	// Fake tmux reports this test process as the only pane.
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tmux := fmt.Sprintf("#!/bin/sh\n[ \"$4\" = live ] && echo %d\nexit 0\n", os.Getpid())
+	if err := os.WriteFile(filepath.Join(bin, "tmux"), []byte(tmux), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	project := filepath.Join(home, ".claude", "projects", "-srv-api")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	transcript := strings.Join([]string{
+		`{"type":"user","message":{"usage":{"input_tokens":999}}}`,
+		`{"type":"assistant","message":{"usage":{"input_tokens":10,"cache_creation_input_tokens":5,"cache_read_input_tokens":100,"output_tokens":7}}}`,
+		`{"type":"assistant","message":{"usage":{"input_tokens":1,"output_tokens":2}}}`,
+	}, "\n")
+	if err := os.WriteFile(filepath.Join(project, "s.jsonl"), []byte(transcript), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	executor := localExecutor{env: []string{"HOME=" + home, "PATH=" + bin + ":" + os.Getenv("PATH")}}
+	got, err := FetchMetrics(executor, "local", []session.Info{
+		{TmuxSession: "live", CWD: "/srv/api"},
+		{TmuxSession: "gone", CWD: "/srv/gone"},
+	})
+	if err != nil {
+		t.Fatalf("FetchMetrics error: %v", err)
+	}
+
+	if _, ok := got["gone"]; ok {
+		t.Error("session without panes should be omitted")
+	}
+	live := got["live"]
+	if live == nil {
+		t.Fatal("missing metrics for live session")
+	}
+	if live.Resource.RSSBytes <= 0 {
+		t.Errorf("RSSBytes = %d, want > 0", live.Resource.RSSBytes)
+	}
+	if live.Resource.ProcessCount < 1 {
+		t.Errorf("ProcessCount = %d, want >= 1", live.Resource.ProcessCount)
+	}
+	if live.Tokens == nil || live.Tokens.Input != 116 || live.Tokens.Output != 9 {
+		t.Errorf("Tokens = %+v, want input 116 output 9", live.Tokens)
+	}
+}
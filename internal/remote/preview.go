@@ -2,40 +2,124 @@ package remote
 
 import (
 	"fmt"
-	"regexp"
+	"math"
+	"strconv"
 	"strings"
+
+	"github.com/stwalsh4118/navi/internal/sgr"
 )
 
-// ANSI escape sequence patterns for stripping terminal control codes.
-// These are duplicated from internal/tui/preview.go to avoid a circular
-// dependency (tui already imports remote).
-var (
-	ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
-	oscSequenceRegex = regexp.MustCompile(`\x1b\][^\x07]*\x07`)
-	controlCharRegex = regexp.MustCompile(`[\x00-\x08\x0b\x0c\x0e-\x1a\x1c-\x1f]`)
+// Range bounds for CapturePaneRange. Other bounds are tmux line numbers:
+// 0 is the first visible line and negative numbers count back into history.
+const (
+	HistoryTop = math.MinInt // The oldest line in the pane's history
+	PaneBottom = math.MaxInt // The last visible line of the pane
 )
 
-// stripANSI removes ANSI escape sequences and other control characters from input.
-func stripANSI(input string) string {
-	result := ansiEscapeRegex.ReplaceAllString(input, "")
-	result = oscSequenceRegex.ReplaceAllString(result, "")
-	result = controlCharRegex.ReplaceAllString(result, "")
-	return result
+// historySizeFormat prints the number of history lines before the capture.
+const historySizeFormat = "#{history_size}"
+
+// PaneRange is a ranged capture of a pane and its scrollback.
+type PaneRange struct {
+	Lines       []string // Sanitized lines, one per captured row
+	Start       int      // Index of Lines[0] counted from the oldest history line
+	HistorySize int      // History lines in the pane at capture time
+}
+
+// End returns the index just past the last captured line.
+func (r PaneRange) End() int {
+	return r.Start + len(r.Lines)
 }
 
-// CapturePane captures the recent output from a remote tmux session pane via SSH.
-// It executes tmux capture-pane on the remote, strips ANSI escape codes, and
-// returns the cleaned output matching the local preview format.
+// CapturePane captures the recent output from a remote tmux session pane
+// through the remote's transport. It executes tmux capture-pane -e on the
+// remote, keeps only SGR color codes, and returns the cleaned output matching
+// the local preview format.
 func CapturePane(pool *SSHPool, remoteName, sessionName string, lines int) (string, error) {
+	t := pool.Transport(remoteName)
+	if t == nil {
+		return "", fmt.Errorf("unknown remote: %s", remoteName)
+	}
+	return t.CapturePane(sessionName, lines)
+}
+
+// capturePaneWith runs tmux capture-pane through t and sanitizes its output.
+func capturePaneWith(t Transport, remoteName, sessionName string, lines int) (string, error) {
 	lineArg := fmt.Sprintf("-%d", lines)
-	cmd := fmt.Sprintf("tmux capture-pane -t %q -p -S %s",
+	cmd := fmt.Sprintf("tmux capture-pane -t %q -p -e -S %s",
 		sessionName, lineArg)
 
-	output, err := pool.Execute(remoteName, cmd)
+	output, err := t.Execute(cmd)
 	if err != nil {
 		return "", fmt.Errorf("capture-pane failed for %s/%s: %w", remoteName, sessionName, err)
 	}
 
-	cleaned := stripANSI(string(output))
-	return strings.TrimRight(cleaned, "\n\t "), nil
+	cleaned := sgr.Sanitize(string(output))
+	return sgr.TrimRight(cleaned), nil
+}
+
+// CapturePaneRange captures lines start through end of a remote tmux pane,
+// including scrollback, through the remote's transport. The pane's history
+// size is read in the same tmux invocation, so Start locates the capture
+// exactly even while the pane is producing output.
+func CapturePaneRange(pool *SSHPool, remoteName, sessionName string, start, end int) (PaneRange, error) {
+	t := pool.Transport(remoteName)
+	if t == nil {
+		return PaneRange{}, fmt.Errorf("unknown remote: %s", remoteName)
+	}
+	return captureRangeWith(t, remoteName, sessionName, start, end)
+}
+
+// captureRangeWith runs a ranged tmux capture through t and parses it.
+func captureRangeWith(t Transport, remoteName, sessionName string, start, end int) (PaneRange, error) {
+	args := CaptureRangeArgs(sessionName, start, end)
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+
+	output, err := t.Execute("tmux " + strings.Join(quoted, " "))
+	if err != nil {
+		return PaneRange{}, fmt.Errorf("capture-pane failed for %s/%s: %w", remoteName, sessionName, err)
+	}
+	return ParseCaptureRange(output, start)
+}
+
+// CaptureRangeArgs returns the tmux arguments that print a pane's history
+// size and then capture lines start through end with their colors.
+func CaptureRangeArgs(sessionName string, start, end int) []string {
+	return []string{
+		"display-message", "-p", "-t", sessionName, historySizeFormat, ";",
+		"capture-pane", "-p", "-e", "-t", sessionName, "-S", rangeBound(start), "-E", rangeBound(end),
+	}
+}
+
+// ParseCaptureRange parses the output of the command built by
+// CaptureRangeArgs. start must be the bound the capture was made with.
+func ParseCaptureRange(output []byte, start int) (PaneRange, error) {
+	first, rest, _ := strings.Cut(string(output), "\n")
+	size, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil {
+		return PaneRange{}, fmt.Errorf("unexpected history size %q", first)
+	}
+
+	// tmux clamps bounds above the oldest history line to it
+	r := PaneRange{HistorySize: size}
+	if start != HistoryTop {
+		r.Start = max(size+start, 0)
+	}
+
+	// Every captured row, blank or not, ends with a newline
+	if rest != "" {
+		r.Lines = strings.Split(sgr.Sanitize(strings.TrimSuffix(rest, "\n")), "\n")
+	}
+	return r, nil
+}
+
+// rangeBound formats a line bound for capture-pane -S or -E.
+func rangeBound(n int) string {
+	if n == HistoryTop || n == PaneBottom {
+		return "-"
+	}
+	return strconv.Itoa(n)
 }
@@ -1,110 +1,123 @@
 package remote
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
-func TestStripANSI(t *testing.T) {
-	t.Run("removes color codes", func(t *testing.T) {
-		input := "\x1b[31mred text\x1b[0m"
-		result := stripANSI(input)
-		expected := "red text"
-		if result != expected {
-			t.Errorf("expected %q, got %q", expected, result)
-		}
-	})
+// fakeTransport returns canned output for every command.
+type fakeTransport struct {
+	output  string
+	err     error
+	command string
+}
 
-	t.Run("removes multiple color codes", func(t *testing.T) {
-		input := "\x1b[1;31mbold red\x1b[0m normal \x1b[32mgreen\x1b[0m"
-		result := stripANSI(input)
-		expected := "bold red normal green"
-		if result != expected {
-			t.Errorf("expected %q, got %q", expected, result)
-		}
-	})
+func (f *fakeTransport) Execute(command string) ([]byte, error) {
+	f.command = command
+	return []byte(f.output), f.err
+}
+
+func (f *fakeTransport) AttachCommand(string) []string { return nil }
 
-	t.Run("removes OSC sequences", func(t *testing.T) {
-		input := "\x1b]0;Window Title\x07some text"
-		result := stripANSI(input)
-		expected := "some text"
+func (f *fakeTransport) CapturePane(sessionName string, lines int) (string, error) {
+	return capturePaneWith(f, "fake", sessionName, lines)
+}
+
+func TestCapturePaneWith(t *testing.T) {
+	t.Run("captures with escapes and keeps only SGR", func(t *testing.T) {
+		ft := &fakeTransport{output: "\x1b]0;title\x07\x1b[32m✓\x1b[0m Done\x1b[2K\n"}
+		result, err := ft.CapturePane("api", 50)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(ft.command, "capture-pane -t \"api\" -p -e -S -50") {
+			t.Errorf("command = %q, want capture-pane with -e", ft.command)
+		}
+		expected := "\x1b[32m✓\x1b[0m Done"
 		if result != expected {
 			t.Errorf("expected %q, got %q", expected, result)
 		}
 	})
 
-	t.Run("handles empty string", func(t *testing.T) {
-		result := stripANSI("")
-		if result != "" {
-			t.Errorf("empty string should remain empty, got %q", result)
+	t.Run("empty output", func(t *testing.T) {
+		result, err := (&fakeTransport{}).CapturePane("api", 50)
+		if err != nil || result != "" {
+			t.Errorf("expected empty output, got %q, %v", result, err)
 		}
 	})
 
-	t.Run("preserves newlines and tabs", func(t *testing.T) {
-		input := "line1\nline2\tindented"
-		result := stripANSI(input)
-		if result != input {
-			t.Errorf("newlines and tabs should be preserved, got %q", result)
+	t.Run("trims trailing blank lines", func(t *testing.T) {
+		result, _ := (&fakeTransport{output: "some output\n\n\x1b[0m\n   \n"}).CapturePane("api", 50)
+		expected := "some output"
+		if result != expected {
+			t.Errorf("expected %q, got %q", expected, result)
 		}
 	})
 
-	t.Run("removes control characters", func(t *testing.T) {
-		input := "text\x00with\x1fcontrol\x08chars"
-		result := stripANSI(input)
-		expected := "textwithcontrolchars"
-		if result != expected {
-			t.Errorf("expected %q, got %q", expected, result)
+	t.Run("wraps command errors", func(t *testing.T) {
+		_, err := (&fakeTransport{err: errors.New("no server")}).CapturePane("api", 50)
+		if err == nil || !strings.Contains(err.Error(), "fake/api") {
+			t.Errorf("error = %v, want remote and session named", err)
 		}
 	})
 }
 
-func TestCapturePane_emptyOutput(t *testing.T) {
-	// CapturePane requires an SSHPool, but we can test the post-processing
-	// logic by verifying that stripANSI + TrimRight on empty input gives empty output.
-	input := ""
-	cleaned := stripANSI(input)
-	result := cleaned // TrimRight of "" is ""
-	if result != "" {
-		t.Errorf("empty output should return empty string, got %q", result)
+func TestCapturePaneRange(t *testing.T) {
+	ft := &fakeTransport{output: "120\n\x1b[31mone\x1b[0m\n\ntwo\x1b[2K\n"}
+	r, err := captureRangeWith(ft, "dev", "api", -30, -28)
+	if err != nil {
+		t.Fatal(err)
 	}
-}
-
-func TestCapturePane_trailingWhitespace(t *testing.T) {
-	// Simulate what CapturePane does after getting output
-	input := "some output\n\n\n   \n"
-	cleaned := stripANSI(input)
-	// Mimic the TrimRight from CapturePane
-	result := trimPreviewOutput(cleaned)
-	expected := "some output"
-	if result != expected {
-		t.Errorf("expected %q, got %q", expected, result)
+	want := "tmux 'display-message' '-p' '-t' 'api' '#{history_size}' ';' 'capture-pane' '-p' '-e' '-t' 'api' '-S' '-30' '-E' '-28'"
+	if ft.command != want {
+		t.Errorf("command = %q\nwant      %q", ft.command, want)
+	}
+	if r.HistorySize != 120 || r.Start != 90 || r.End() != 93 {
+		t.Errorf("range = start %d end %d size %d, want 90 93 120", r.Start, r.End(), r.HistorySize)
+	}
+	if len(r.Lines) != 3 || r.Lines[0] != "\x1b[31mone\x1b[0m" || r.Lines[1] != "" || r.Lines[2] != "two" {
+		t.Errorf("lines = %q", r.Lines)
 	}
-}
 
-func TestCapturePane_ansiAndTrailingWhitespace(t *testing.T) {
-	input := "\x1b[32m✓ Done\x1b[0m\n\n  \n"
-	cleaned := stripANSI(input)
-	result := trimPreviewOutput(cleaned)
-	expected := "✓ Done"
-	if result != expected {
-		t.Errorf("expected %q, got %q", expected, result)
+	if _, err := CapturePaneRange(NewSSHPool(nil), "missing", "api", -30, -28); err == nil {
+		t.Error("expected an error for an unknown remote")
 	}
 }
 
-// trimPreviewOutput mirrors the TrimRight logic from CapturePane for testing.
-func trimPreviewOutput(s string) string {
-	// This duplicates the strings.TrimRight call in CapturePane
-	// so we can test it without needing a real SSH connection.
-	return trimRight(s)
-}
+func TestParseCaptureRange(t *testing.T) {
+	t.Run("clamps at the oldest history line", func(t *testing.T) {
+		r, err := ParseCaptureRange([]byte("10\na\nb\n"), -50)
+		if err != nil || r.Start != 0 || len(r.Lines) != 2 {
+			t.Errorf("got %+v, %v; want start 0 with 2 lines", r, err)
+		}
+	})
 
-func trimRight(s string) string {
-	// Same cutset as CapturePane
-	i := len(s)
-	for i > 0 {
-		r := s[i-1]
-		if r == '\n' || r == '\t' || r == ' ' {
-			i--
-		} else {
-			break
+	t.Run("history top bound", func(t *testing.T) {
+		r, _ := ParseCaptureRange([]byte("3\na\nb\nc\nd\n"), HistoryTop)
+		if r.Start != 0 || r.End() != 4 {
+			t.Errorf("got start %d end %d, want 0 4", r.Start, r.End())
 		}
+	})
+
+	t.Run("single blank row", func(t *testing.T) {
+		r, _ := ParseCaptureRange([]byte("3\n\n"), -1)
+		if len(r.Lines) != 1 || r.Lines[0] != "" {
+			t.Errorf("lines = %q, want one blank line", r.Lines)
+		}
+	})
+
+	t.Run("rejects a missing history size", func(t *testing.T) {
+		if _, err := ParseCaptureRange([]byte("can't find session\n"), -1); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestCaptureRangeArgsBounds(t *testing.T) {
+	args := CaptureRangeArgs("api", HistoryTop, PaneBottom)
+	got := strings.Join(args[len(args)-4:], " ")
+	if got != "-S - -E -" {
+		t.Errorf("bounds = %q, want %q", got, "-S - -E -")
 	}
-	return s[:i]
 }
@@ -0,0 +1,148 @@
+package remote
+
+import (
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ConfigWatcher detects changes to the remotes config file by comparing its
+// modification time and size between checks.
+type ConfigWatcher struct {
+	mu      sync.Mutex
+	path    string
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// NewConfigWatcher returns a watcher for path, treating its current state as seen.
+func NewConfigWatcher(path string) *ConfigWatcher {
+	w := &ConfigWatcher{path: path}
+	w.Changed()
+	return w
+}
+
+// Path returns the watched file path.
+func (w *ConfigWatcher) Path() string {
+	return w.path
+}
+
+// Changed reports whether the file was created, removed or modified since the
+// last call.
+func (w *ConfigWatcher) Changed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	info, err := os.Stat(w.path)
+	exists := err == nil
+	var modTime time.Time
+	var size int64
+	if exists {
+		modTime, size = info.ModTime(), info.Size()
+	}
+
+	changed := exists != w.exists || !modTime.Equal(w.modTime) || size != w.size
+	w.exists, w.modTime, w.size = exists, modTime, size
+	return changed
+}
+
+// Reconcile updates the pool to serve remotes. New remotes are added
+// disconnected; remotes no longer listed are disconnected and dropped; remotes
+// whose config changed are disconnected, so the next poll dials with the new
+// settings. Unchanged remotes keep their connection and status.
+func (p *SSHPool) Reconcile(remotes []Config) (added, removed, updated []string) {
+	want := make(map[string]Config, len(remotes))
+	for _, r := range remotes {
+		want[r.Name] = r
+	}
+
+	p.mu.RLock()
+	for name, rc := range p.remotes {
+		next, ok := want[name]
+		switch {
+		case !ok:
+			removed = append(removed, name)
+		case next != *rc:
+			updated = append(updated, name)
+		}
+	}
+	for _, r := range remotes {
+		if _, ok := p.remotes[r.Name]; !ok {
+			added = append(added, r.Name)
+		}
+	}
+	// Hold the dial lock of every dropped or changed remote, in name order,
+	// so a Connect in flight finishes before its config and status are
+	// replaced and none starts with the old config afterwards
+	stale := append(append([]string(nil), removed...), updated...)
+	sort.Strings(stale)
+	var locks []*sync.Mutex
+	for _, name := range stale {
+		if mu, ok := p.connectMu[name]; ok {
+			locks = append(locks, mu)
+		}
+	}
+	p.mu.RUnlock()
+
+	for _, mu := range locks {
+		mu.Lock()
+	}
+	defer func() {
+		for _, mu := range locks {
+			mu.Unlock()
+		}
+	}()
+
+	for _, name := range stale {
+		p.Disconnect(name)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, name := range removed {
+		delete(p.remotes, name)
+		delete(p.status, name)
+		delete(p.connectMu, name)
+	}
+	for _, name := range updated {
+		rc := want[name]
+		p.remotes[name] = &rc
+		p.status[name] = &RemoteStatus{Status: StatusDisconnected}
+	}
+	for _, name := range added {
+		rc := want[name]
+		p.remotes[name] = &rc
+		p.status[name] = &RemoteStatus{Status: StatusDisconnected}
+		p.connectMu[name] = &sync.Mutex{}
+	}
+
+	p.streamMu.Lock()
+	for _, name := range append(removed, updated...) {
+		delete(p.agentRetryAt, name)
+	}
+	p.streamMu.Unlock()
+
+	p.hostKeyMu.Lock()
+	for _, name := range append(removed, updated...) {
+		delete(p.pendingHostKeys, name)
+	}
+	p.hostKeyMu.Unlock()
+
+	return added, removed, updated
+}
+
+// CheckRemote validates rc and runs a trivial command on it through a
+// temporary pool, for testing a remote before it is saved.
+func CheckRemote(rc Config) error {
+	if err := ValidateConfig(&rc); err != nil {
+		return err
+	}
+	ApplyDefaults(&rc)
+
+	pool := NewSSHPool([]Config{rc})
+	defer pool.Close()
+	_, err := pool.Execute(rc.Name, "true")
+	return err
+}
@@ -0,0 +1,197 @@
+package remote
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestLoadConfigFileSkipsInvalidEntries(t *testing.T) {
+	path := writeConfigFile(t, `remotes:
+  - name: good
+    host: good.example.com
+  - name: nohost
+  - name: good
+    host: other.example.com
+  - name: ctr
+    transport: telnet
+`)
+
+	remotes, invalid, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile: %v", err)
+	}
+	if len(remotes) != 1 || remotes[0].Name != "good" || remotes[0].SessionsDir != DefaultSessionsDir {
+		t.Errorf("remotes = %+v, want only good with defaults", remotes)
+	}
+
+	wantErrs := []error{ErrRemoteHostRequired, ErrDuplicateRemote, ErrUnknownTransport}
+	if len(invalid) != len(wantErrs) {
+		t.Fatalf("invalid = %v, want %d entries", invalid, len(wantErrs))
+	}
+	for i, want := range wantErrs {
+		if !errors.Is(invalid[i].Err, want) {
+			t.Errorf("invalid[%d] = %v, want %v", i, invalid[i], want)
+		}
+	}
+
+	if remotes, invalid, err := LoadConfigFile(filepath.Join(t.TempDir(), "none.yaml")); err != nil || len(remotes) != 0 || invalid != nil {
+		t.Errorf("missing file = %v, %v, %v", remotes, invalid, err)
+	}
+	if _, _, err := LoadConfigFile(writeConfigFile(t, "remotes: [")); err == nil {
+		t.Error("malformed YAML should fail")
+	}
+}
+
+func TestConfigWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remotes.yaml")
+	w := NewConfigWatcher(path)
+	if w.Changed() {
+		t.Error("missing file reported as changed")
+	}
+
+	if err := os.WriteFile(path, []byte("remotes: []\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Changed() {
+		t.Error("created file not reported")
+	}
+	if w.Changed() {
+		t.Error("unchanged file reported twice")
+	}
+
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Changed() {
+		t.Error("modified file not reported")
+	}
+
+	os.Remove(path)
+	if !w.Changed() {
+		t.Error("removed file not reported")
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	pool := NewSSHPool([]Config{
+		{Name: "keep", Host: "keep.example.com"},
+		{Name: "edit", Host: "old.example.com"},
+		{Name: "drop", Host: "drop.example.com"},
+	})
+	defer pool.Close()
+	pool.recordPoll("keep", 3, nil)
+
+	added, removed, updated := pool.Reconcile([]Config{
+		{Name: "keep", Host: "keep.example.com"},
+		{Name: "edit", Host: "new.example.com"},
+		{Name: "ctr", Transport: TransportCommand, Command: "sh -c"},
+	})
+	if !reflect.DeepEqual(added, []string{"ctr"}) || !reflect.DeepEqual(removed, []string{"drop"}) || !reflect.DeepEqual(updated, []string{"edit"}) {
+		t.Errorf("Reconcile = %v, %v, %v", added, removed, updated)
+	}
+
+	if pool.GetRemoteConfig("drop") != nil {
+		t.Error("removed remote still configured")
+	}
+	if got := pool.GetRemoteConfig("edit").Host; got != "new.example.com" {
+		t.Errorf("edit host = %q, want updated", got)
+	}
+	if st := pool.GetStatus("keep"); st.Sessions != 3 {
+		t.Errorf("unchanged remote lost its status: %+v", st)
+	}
+	if out, err := pool.Execute("ctr", "echo hi"); err != nil || string(out) != "hi\n" {
+		t.Errorf("added remote Execute = %q, %v", out, err)
+	}
+
+	names := make([]string, 0)
+	for name := range pool.GetAllStatus() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"ctr", "edit", "keep"}) {
+		t.Errorf("status names = %v", names)
+	}
+}
+
+func TestCheckRemote(t *testing.T) {
+	if err := CheckRemote(Config{Name: "ctr", Transport: TransportCommand, Command: "sh -c"}); err != nil {
+		t.Errorf("CheckRemote(sh -c) = %v", err)
+	}
+	if err := CheckRemote(Config{Name: "ctr", Transport: TransportCommand, Command: "false"}); err == nil {
+		t.Error("CheckRemote should report a failing command")
+	}
+	if err := CheckRemote(Config{Name: "box"}); !errors.Is(err, ErrRemoteHostRequired) {
+		t.Errorf("CheckRemote without host = %v", err)
+	}
+}
+
+func TestReconcileWaitsForConnectInFlight(t *testing.T) {
+	pool := NewSSHPool([]Config{{Name: "drop", Host: "drop.example.com"}})
+	defer pool.Close()
+
+	dialing, release := make(chan struct{}), make(chan struct{})
+	pool.SetDialer(func(*Config) (*ssh.Client, error) {
+		close(dialing)
+		<-release
+		return nil, errDialFailed
+	})
+
+	connectErr := make(chan error, 1)
+	go func() {
+		_, err := pool.Connect("drop")
+		connectErr <- err
+	}()
+	<-dialing
+
+	reconciled := make(chan struct{})
+	go func() {
+		pool.Reconcile(nil)
+		close(reconciled)
+	}()
+
+	select {
+	case <-reconciled:
+		t.Fatal("Reconcile dropped the remote while it was being dialed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-connectErr; err == nil {
+		t.Error("Connect should fail")
+	}
+	<-reconciled
+	if pool.GetRemoteConfig("drop") != nil {
+		t.Error("removed remote still configured")
+	}
+	if _, err := pool.Connect("drop"); err == nil {
+		t.Error("Connect to a removed remote should fail")
+	}
+}
+
+func TestConnectableAfterReconcile(t *testing.T) {
+	pool := NewSSHPool([]Config{{Name: "dev", Host: "old.example.com"}})
+	defer pool.Close()
+
+	// A Connect that looked up the dial lock before the remote was removed
+	// and added back must not proceed with it
+	stale := pool.connectMu["dev"]
+	pool.Reconcile(nil)
+	pool.Reconcile([]Config{{Name: "dev", Host: "new.example.com"}})
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if pool.connectableLocked("dev", stale) {
+		t.Error("a stale dial lock should not be connectable")
+	}
+	if !pool.connectableLocked("dev", pool.connectMu["dev"]) {
+		t.Error("the current dial lock should be connectable")
+	}
+}
@@ -65,8 +65,20 @@ func PollSessions(pool *SSHPool, remotes []Config) []session.Info {
 	return allSessions
 }
 
-// PollSingleRemote polls a single remote for session status.
+// PollSingleRemote polls a single remote for session status. When the remote
+// runs navi agent the sessions come from its event stream; otherwise, and while
+// the stream is starting, the status files are read with cat.
 func PollSingleRemote(pool *SSHPool, remote Config) ([]session.Info, error) {
+	if pool.GetStatus(remote.Name).Status == StatusPaused {
+		return nil, ErrPaused
+	}
+
+	if sessions, ok := pool.AgentSessions(remote); ok {
+		debug.Log("remote[%s]: %d sessions from agent stream", remote.Name, len(sessions))
+		pool.recordPoll(remote.Name, len(sessions), nil)
+		return sessions, nil
+	}
+
 	sessionsDir := remote.SessionsDir
 	if sessionsDir == "" {
 		sessionsDir = DefaultSessionsDir
@@ -85,6 +97,7 @@ func PollSingleRemote(pool *SSHPool, remote Config) ([]session.Info, error) {
 	output, err := pool.Execute(remote.Name, cmd)
 	if err != nil {
 		debug.Log("remote[%s]: execute error: %v", remote.Name, err)
+		pool.recordPoll(remote.Name, 0, err)
 		return nil, fmt.Errorf("failed to execute remote command: %w", err)
 	}
 
@@ -93,6 +106,7 @@ func PollSingleRemote(pool *SSHPool, remote Config) ([]session.Info, error) {
 	sessions := ParseSessionOutput(string(output), remote.Name)
 
 	debug.Log("remote[%s]: parsed %d sessions", remote.Name, len(sessions))
+	pool.recordPoll(remote.Name, len(sessions), nil)
 
 	return sessions, nil
 }
@@ -1,14 +1,21 @@
 package remote
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"os"
+	"os/user"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
 
 	"github.com/stwalsh4118/navi/internal/debug"
+	"github.com/stwalsh4118/navi/internal/pathutil"
 )
 
 // SSH connection constants
@@ -18,6 +25,19 @@ const (
 	SSHCommandTimeout = 30 * time.Second
 )
 
+// Reconnect backoff for failing remotes: the delay doubles with each
+// consecutive failure, up to BackoffMax.
+const (
+	BackoffInitial = 5 * time.Second
+	BackoffMax     = 5 * time.Minute
+)
+
+// Errors returned by Connect without dialing.
+var (
+	ErrBackoff = errors.New("remote is backing off after failures")
+	ErrPaused  = errors.New("remote disconnected by user")
+)
+
 // ConnectionStatus represents the state of a remote connection.
 type ConnectionStatus int
 
@@ -25,6 +45,7 @@ const (
 	StatusDisconnected ConnectionStatus = iota
 	StatusConnected
 	StatusError
+	StatusPaused // Disconnected by the user; not dialed until Reconnect
 )
 
 // String returns a human-readable status string.
@@ -36,32 +57,85 @@ func (s ConnectionStatus) String() string {
 		return "disconnected"
 	case StatusError:
 		return "error"
+	case StatusPaused:
+		return "paused"
 	default:
 		return "unknown"
 	}
 }
 
-// RemoteStatus holds the connection status and last error for a remote.
+// RemoteStatus holds the connection status and health of a remote.
 type RemoteStatus struct {
-	Status    ConnectionStatus
-	LastError error
-	LastPoll  time.Time
+	Status      ConnectionStatus
+	LastError   error
+	LastPoll    time.Time     // Last connection attempt or poll
+	LastSuccess time.Time     // Last successful session poll
+	Latency     time.Duration // Round trip of the last keepalive
+	Sessions    int           // Sessions found by the last successful poll
+	Failures    int           // Consecutive connection failures
+	NextRetry   time.Time     // No dial before this time while Failures > 0
 }
 
+// Dialer opens an SSH client to a remote. Tests substitute one with SetDialer.
+type Dialer func(remote *Config) (*ssh.Client, error)
+
+// backoffDelay returns the wait after the given number of consecutive failures.
+func backoffDelay(failures int) time.Duration {
+	delay := BackoffInitial
+	for i := 1; i < failures && delay < BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > BackoffMax {
+		delay = BackoffMax
+	}
+	return delay
+}
+
+// defaultIdentityFiles are tried when neither remotes.yaml nor ~/.ssh/config
+// names an identity, mirroring OpenSSH's defaults.
+var defaultIdentityFiles = []string{"~/.ssh/id_ed25519", "~/.ssh/id_ecdsa", "~/.ssh/id_rsa"}
+
 // SSHPool manages SSH connections to multiple remote machines.
 type SSHPool struct {
 	mu      sync.RWMutex
 	remotes map[string]*Config
 	clients map[string]*ssh.Client
 	status  map[string]*RemoteStatus
+
+	// dial opens new connections. It runs without mu held so a slow remote
+	// does not block the others; connectMu serializes dials per remote.
+	dial      Dialer
+	connectMu map[string]*sync.Mutex
+
+	sshConfigPath  string // ssh_config consulted for Host aliases
+	knownHostsPath string // known_hosts used for host key verification
+
+	// Host key trust state, guarded separately because verification runs
+	// during the dial, concurrently for different remotes and without mu.
+	hostKeyMu        sync.Mutex
+	pendingHostKeys  map[string]*HostKeyPrompt // by remote name
+	rejectedHostKeys map[string]bool           // by HostKeyPrompt.id
+
+	// navi agent streams, guarded separately so polls never wait on a dial.
+	streamMu     sync.Mutex
+	streams      map[string]*agentStream // by remote name
+	agentRetryAt map[string]time.Time    // by remote name; no stream before this time
+	noStreams    bool                    // Poll with cat only; see DisableAgentStreams
 }
 
 // NewSSHPool creates a new SSH connection pool for the given remotes.
 func NewSSHPool(remotes []Config) *SSHPool {
 	pool := &SSHPool{
-		remotes: make(map[string]*Config),
-		clients: make(map[string]*ssh.Client),
-		status:  make(map[string]*RemoteStatus),
+		remotes:          make(map[string]*Config),
+		clients:          make(map[string]*ssh.Client),
+		status:           make(map[string]*RemoteStatus),
+		connectMu:        make(map[string]*sync.Mutex),
+		sshConfigPath:    SSHConfigPath,
+		knownHostsPath:   KnownHostsPath,
+		pendingHostKeys:  make(map[string]*HostKeyPrompt),
+		rejectedHostKeys: make(map[string]bool),
+		streams:          make(map[string]*agentStream),
+		agentRetryAt:     make(map[string]time.Time),
 	}
 
 	for _, r := range remotes {
@@ -70,11 +144,20 @@ func NewSSHPool(remotes []Config) *SSHPool {
 		pool.status[rCopy.Name] = &RemoteStatus{
 			Status: StatusDisconnected,
 		}
+		pool.connectMu[rCopy.Name] = &sync.Mutex{}
 	}
+	pool.dial = pool.dialRemote
 
 	return pool
 }
 
+// SetDialer replaces how the pool opens connections.
+func (p *SSHPool) SetDialer(dial Dialer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dial = dial
+}
+
 // GetStatus returns the connection status for a remote.
 func (p *SSHPool) GetStatus(remoteName string) *RemoteStatus {
 	p.mu.RLock()
@@ -84,7 +167,8 @@ func (p *SSHPool) GetStatus(remoteName string) *RemoteStatus {
 	if !ok {
 		return &RemoteStatus{Status: StatusDisconnected}
 	}
-	return status
+	copied := *status
+	return &copied
 }
 
 // GetAllStatus returns the status of all configured remotes.
@@ -94,24 +178,45 @@ func (p *SSHPool) GetAllStatus() map[string]*RemoteStatus {
 
 	result := make(map[string]*RemoteStatus, len(p.status))
 	for name, status := range p.status {
-		result[name] = &RemoteStatus{
-			Status:    status.Status,
-			LastError: status.LastError,
-			LastPoll:  status.LastPoll,
-		}
+		copied := *status
+		result[name] = &copied
 	}
 	return result
 }
 
 // Connect establishes or returns an existing connection to the named remote.
+// A remote that failed recently is not redialed until its backoff expires,
+// and a paused remote is not dialed at all.
 func (p *SSHPool) Connect(remoteName string) (*ssh.Client, error) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	p.mu.RLock()
+	connectMu, ok := p.connectMu[remoteName]
+	p.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown remote: %s", remoteName)
+	}
+	connectMu.Lock()
+	defer connectMu.Unlock()
 
+	// Reconcile may drop or replace the remote whenever mu is released, so
+	// every relock checks that it is still the one this call was made for
+	p.mu.Lock()
+	if !p.connectableLocked(remoteName, connectMu) {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("unknown remote: %s", remoteName)
+	}
 	if client, ok := p.clients[remoteName]; ok {
+		p.mu.Unlock()
+		start := time.Now()
 		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		p.mu.Lock()
+		if !p.connectableLocked(remoteName, connectMu) {
+			p.mu.Unlock()
+			return nil, fmt.Errorf("remote %s was removed while connecting", remoteName)
+		}
 		if err == nil {
 			debug.Log("ssh[%s]: reusing existing connection", remoteName)
+			p.status[remoteName].Latency = time.Since(start)
+			p.mu.Unlock()
 			return client, nil
 		}
 		debug.Log("ssh[%s]: existing connection dead, reconnecting: %v", remoteName, err)
@@ -119,37 +224,102 @@ func (p *SSHPool) Connect(remoteName string) (*ssh.Client, error) {
 		delete(p.clients, remoteName)
 	}
 
-	remote, ok := p.remotes[remoteName]
-	if !ok {
-		return nil, fmt.Errorf("unknown remote: %s", remoteName)
+	remote := p.remotes[remoteName]
+	status := p.status[remoteName]
+	dial := p.dial
+	if !remote.isSSH() {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("remote %s uses the %s transport, not SSH", remoteName, remote.Transport)
+	}
+	if status.Status == StatusPaused {
+		p.mu.Unlock()
+		return nil, ErrPaused
+	}
+	if status.Failures > 0 && time.Now().Before(status.NextRetry) {
+		wait := time.Until(status.NextRetry).Round(time.Second)
+		lastErr := status.LastError
+		p.mu.Unlock()
+		return nil, fmt.Errorf("%w (retry in %s): %v", ErrBackoff, wait, lastErr)
 	}
+	p.mu.Unlock()
 
-	debug.Log("ssh[%s]: connecting to %s@%s (key: %s)", remoteName, remote.User, remote.Host, remote.Key)
+	// Don't redial while the user has not decided whether to trust the host key
+	if p.hasPendingHostKey(remoteName) {
+		return nil, fmt.Errorf("%w: awaiting confirmation for %s", ErrHostKeyUnknown, remoteName)
+	}
+
+	debug.Log("ssh[%s]: connecting to %s (user: %q, key: %q)", remoteName, remote.Host, remote.User, remote.Key)
 
-	client, err := p.dialRemote(remote)
+	client, err := dial(remote)
 	if err != nil {
-		debug.Log("ssh[%s]: connection failed: %v", remoteName, err)
-		p.status[remoteName] = &RemoteStatus{
-			Status:    StatusError,
-			LastError: err,
-			LastPoll:  time.Now(),
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if !p.connectableLocked(remoteName, connectMu) || p.remotes[remoteName] != remote {
+			return nil, fmt.Errorf("remote %s was removed or changed while connecting: %w", remoteName, err)
+		}
+		status.Failures++
+		status.Status = StatusError
+		status.LastError = err
+		status.LastPoll = time.Now()
+		// An unknown host key waits on the user, not on a timer
+		if !errors.Is(err, ErrHostKeyUnknown) {
+			status.NextRetry = status.LastPoll.Add(backoffDelay(status.Failures))
 		}
+		debug.Log("ssh[%s]: connection failed (%d in a row): %v", remoteName, status.Failures, err)
 		return nil, err
 	}
 
+	start := time.Now()
+	_, _, pingErr := client.SendRequest("keepalive@openssh.com", true, nil)
+	latency := time.Since(start)
+
 	debug.Log("ssh[%s]: connected successfully", remoteName)
 
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.connectableLocked(remoteName, connectMu) || p.remotes[remoteName] != remote {
+		// Dialed with a config that is no longer current
+		client.Close()
+		return nil, fmt.Errorf("remote %s was removed or changed while connecting", remoteName)
+	}
+	if status.Status == StatusPaused {
+		// Paused while dialing
+		client.Close()
+		return nil, ErrPaused
+	}
 	p.clients[remoteName] = client
-	p.status[remoteName] = &RemoteStatus{
-		Status:   StatusConnected,
-		LastPoll: time.Now(),
+	status.Status = StatusConnected
+	status.LastError = nil
+	status.LastPoll = time.Now()
+	status.Failures = 0
+	status.NextRetry = time.Time{}
+	if pingErr == nil {
+		status.Latency = latency
 	}
 
 	return client, nil
 }
 
-// Execute runs a command on the named remote and returns the output.
+// connectableLocked reports whether remoteName is still configured with
+// connectMu as its dial lock. Caller must hold p.mu.
+func (p *SSHPool) connectableLocked(remoteName string, connectMu *sync.Mutex) bool {
+	_, hasRemote := p.remotes[remoteName]
+	_, hasStatus := p.status[remoteName]
+	return hasRemote && hasStatus && p.connectMu[remoteName] == connectMu
+}
+
+// Execute runs a command on the named remote through its transport and
+// returns the output.
 func (p *SSHPool) Execute(remoteName, command string) ([]byte, error) {
+	t := p.Transport(remoteName)
+	if t == nil {
+		return nil, fmt.Errorf("unknown remote: %s", remoteName)
+	}
+	return t.Execute(command)
+}
+
+// executeSSH runs a command on the named remote over its pooled SSH connection.
+func (p *SSHPool) executeSSH(remoteName, command string) ([]byte, error) {
 	client, err := p.Connect(remoteName)
 	if err != nil {
 		return nil, err
@@ -188,16 +358,16 @@ func (p *SSHPool) Execute(remoteName, command string) ([]byte, error) {
 
 // Close closes all connections in the pool.
 func (p *SSHPool) Close() {
+	p.stopStreams()
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	for name, client := range p.clients {
 		client.Close()
 		delete(p.clients, name)
-		p.status[name] = &RemoteStatus{
-			Status:   StatusDisconnected,
-			LastPoll: time.Now(),
-		}
+		p.status[name].Status = StatusDisconnected
+		p.status[name].LastPoll = time.Now()
 	}
 }
 
@@ -221,17 +391,34 @@ func (p *SSHPool) RemoteNames() []string {
 }
 
 // BuildSSHAttachCommand builds the SSH command for attaching to a remote tmux session.
+// Options left empty in remotes.yaml are omitted so ssh applies ~/.ssh/config itself.
 func BuildSSHAttachCommand(remote *Config, sessionName string) []string {
 	args := []string{"ssh"}
 
-	args = append(args, "-i", remote.Key)
+	if remote.Key != "" {
+		args = append(args, "-i", remote.Key)
+	}
+
+	if remote.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(remote.Port))
+	}
 
 	if remote.JumpHost != "" {
-		args = append(args, "-J", fmt.Sprintf("%s@%s", remote.User, remote.JumpHost))
+		hops := splitJumpHosts(remote.JumpHost)
+		for i, hop := range hops {
+			if !strings.Contains(hop, "@") && remote.User != "" {
+				hops[i] = remote.User + "@" + hop
+			}
+		}
+		args = append(args, "-J", strings.Join(hops, ","))
+	}
+
+	target := remote.Host
+	if remote.User != "" {
+		target = remote.User + "@" + remote.Host
 	}
 
-	args = append(args, "-t")
-	args = append(args, fmt.Sprintf("%s@%s", remote.User, remote.Host))
+	args = append(args, "-t", target)
 	args = append(args, "tmux", "attach-session", "-t", sessionName)
 
 	return args
@@ -252,6 +439,8 @@ func (p *SSHPool) IsConnected() bool {
 
 // Disconnect closes the connection to a specific remote.
 func (p *SSHPool) Disconnect(remoteName string) {
+	p.stopStream(remoteName)
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -266,71 +455,318 @@ func (p *SSHPool) Disconnect(remoteName string) {
 	}
 }
 
-func (p *SSHPool) dialRemote(remote *Config) (*ssh.Client, error) {
-	signer, err := LoadSSHKey(remote.Key)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load SSH key: %w", err)
+// Pause disconnects a remote and keeps it disconnected until Reconnect.
+func (p *SSHPool) Pause(remoteName string) {
+	p.Disconnect(remoteName)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if status, ok := p.status[remoteName]; ok {
+		status.Status = StatusPaused
+	}
+}
+
+// Reconnect drops any existing connection to a remote, clears its pause and
+// backoff, and dials it immediately.
+func (p *SSHPool) Reconnect(remoteName string) error {
+	p.Disconnect(remoteName)
+	p.resetBackoff(remoteName)
+
+	p.streamMu.Lock()
+	delete(p.agentRetryAt, remoteName)
+	p.streamMu.Unlock()
+
+	if rc := p.GetRemoteConfig(remoteName); rc != nil && !rc.isSSH() {
+		_, err := p.Execute(remoteName, "true")
+		return err
+	}
+	_, err := p.Connect(remoteName)
+	return err
+}
+
+// resetBackoff forgets consecutive failures so the next Connect dials at once.
+func (p *SSHPool) resetBackoff(remoteName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if status, ok := p.status[remoteName]; ok {
+		status.Failures = 0
+		status.NextRetry = time.Time{}
 	}
+}
 
-	config := &ssh.ClientConfig{
-		User: remote.User,
-		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(signer),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         SSHConnectTimeout,
+// recordPoll records the outcome of a session poll of a remote.
+func (p *SSHPool) recordPoll(remoteName string, sessions int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status, ok := p.status[remoteName]
+	if !ok {
+		return
 	}
+	status.LastPoll = time.Now()
+	if err != nil {
+		status.LastError = err
+		return
+	}
+	status.LastError = nil
+	status.LastSuccess = status.LastPoll
+	status.Sessions = sessions
+}
 
-	targetAddr := fmt.Sprintf("%s:%d", remote.Host, SSHDefaultPort)
+// hop is one fully resolved SSH connection in a route to a remote.
+type hop struct {
+	host          string
+	port          int
+	user          string
+	identityFiles []string
+	explicitKey   bool // identity came from remotes.yaml and must load
+}
+
+// addr returns the host:port to dial.
+func (h hop) addr() string {
+	return net.JoinHostPort(h.host, strconv.Itoa(h.port))
+}
 
+// resolveRoute resolves a remote through ssh_config into the jump hosts to
+// traverse, in order, followed by the target itself. Values set in
+// remotes.yaml take precedence over ~/.ssh/config.
+func resolveRoute(remote *Config, sshCfg *SSHConfig) []hop {
+	hc := sshCfg.Resolve(remote.Host)
+	target := hop{
+		host:          firstNonEmpty(hc.HostName, remote.Host),
+		port:          firstPositive(remote.Port, hc.Port, SSHDefaultPort),
+		user:          firstNonEmpty(remote.User, hc.User, localUsername()),
+		identityFiles: hc.IdentityFiles,
+	}
+	if remote.Key != "" {
+		target.identityFiles = []string{remote.Key}
+		target.explicitKey = true
+	}
+
+	jumps := hc.ProxyJump
 	if remote.JumpHost != "" {
-		return p.dialThroughJumpHost(remote, config, targetAddr)
+		jumps = splitJumpHosts(remote.JumpHost)
 	}
 
-	client, err := ssh.Dial("tcp", targetAddr, config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to %s: %w", remote.Host, err)
+	route := make([]hop, 0, len(jumps)+1)
+	for _, spec := range jumps {
+		specUser, specHost, specPort := parseJumpSpec(spec)
+		jc := sshCfg.Resolve(specHost)
+		h := hop{
+			host:          firstNonEmpty(jc.HostName, specHost),
+			port:          firstPositive(specPort, jc.Port, SSHDefaultPort),
+			user:          firstNonEmpty(specUser, jc.User, remote.User, localUsername()),
+			identityFiles: jc.IdentityFiles,
+		}
+		if len(h.identityFiles) == 0 && remote.Key != "" {
+			h.identityFiles = []string{remote.Key}
+			h.explicitKey = true
+		}
+		route = append(route, h)
 	}
 
-	return client, nil
+	return append(route, target)
 }
 
-func (p *SSHPool) dialThroughJumpHost(remote *Config, targetConfig *ssh.ClientConfig, targetAddr string) (*ssh.Client, error) {
-	signer, err := LoadSSHKey(remote.Key)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load SSH key for jump host: %w", err)
+// parseJumpSpec splits a ProxyJump entry of the form [ssh://][user@]host[:port].
+func parseJumpSpec(spec string) (string, string, int) {
+	spec = strings.TrimPrefix(spec, "ssh://")
+
+	var userName string
+	if i := strings.LastIndex(spec, "@"); i >= 0 {
+		userName, spec = spec[:i], spec[i+1:]
 	}
 
-	jumpConfig := &ssh.ClientConfig{
-		User: remote.User,
-		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(signer),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         SSHConnectTimeout,
+	if strings.HasPrefix(spec, "[") || strings.Count(spec, ":") == 1 {
+		if host, portStr, err := net.SplitHostPort(spec); err == nil {
+			port, _ := strconv.Atoi(portStr)
+			return userName, host, port
+		}
 	}
+	return userName, spec, 0
+}
 
-	jumpAddr := fmt.Sprintf("%s:%d", remote.JumpHost, SSHDefaultPort)
-	jumpClient, err := ssh.Dial("tcp", jumpAddr, jumpConfig)
+// dialRemote connects to a remote, traversing any jump hosts. Jump host
+// connections are closed when the returned client closes.
+func (p *SSHPool) dialRemote(remote *Config) (*ssh.Client, error) {
+	sshCfg, err := LoadSSHConfig(p.sshConfigPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to jump host %s: %w", remote.JumpHost, err)
+		debug.Log("ssh[%s]: ignoring unreadable ssh config: %v", remote.Name, err)
+		sshCfg = &SSHConfig{}
+	}
+	route := resolveRoute(remote, sshCfg)
+
+	agentClient, closeAgent := connectAgent()
+	defer closeAgent()
+
+	var jumps []*ssh.Client
+	closeJumps := func() {
+		for i := len(jumps) - 1; i >= 0; i-- {
+			jumps[i].Close()
+		}
+	}
+
+	for i, h := range route {
+		config, err := p.clientConfig(remote.Name, h, agentClient)
+		if err != nil {
+			closeJumps()
+			return nil, err
+		}
+
+		var via *ssh.Client
+		if len(jumps) > 0 {
+			via = jumps[len(jumps)-1]
+		}
+		client, err := dialHop(via, h, config)
+		if err != nil {
+			closeJumps()
+			if i < len(route)-1 {
+				return nil, fmt.Errorf("failed to connect to jump host %s: %w", h.host, err)
+			}
+			return nil, fmt.Errorf("failed to connect to %s: %w", h.host, err)
+		}
+
+		if i < len(route)-1 {
+			jumps = append(jumps, client)
+			continue
+		}
+
+		if len(jumps) > 0 {
+			go func() {
+				_ = client.Wait()
+				closeJumps()
+			}()
+		}
+		return client, nil
+	}
+
+	return nil, fmt.Errorf("no route to %s", remote.Host)
+}
+
+// dialHop connects to h directly, or through via when it is non-nil.
+func dialHop(via *ssh.Client, h hop, config *ssh.ClientConfig) (*ssh.Client, error) {
+	if via == nil {
+		return ssh.Dial("tcp", h.addr(), config)
 	}
 
-	conn, err := jumpClient.Dial("tcp", targetAddr)
+	conn, err := via.Dial("tcp", h.addr())
 	if err != nil {
-		jumpClient.Close()
-		return nil, fmt.Errorf("failed to dial target through jump host: %w", err)
+		return nil, err
 	}
 
-	ncc, chans, reqs, err := ssh.NewClientConn(conn, targetAddr, targetConfig)
+	ncc, chans, reqs, err := ssh.NewClientConn(conn, h.addr(), config)
 	if err != nil {
 		conn.Close()
-		jumpClient.Close()
-		return nil, fmt.Errorf("failed to create SSH connection through jump host: %w", err)
+		return nil, err
 	}
 
-	client := ssh.NewClient(ncc, chans, reqs)
-	return client, nil
+	return ssh.NewClient(ncc, chans, reqs), nil
+}
+
+// clientConfig builds the SSH client configuration for one hop, offering its
+// identity files first and then any keys held by ssh-agent.
+func (p *SSHPool) clientConfig(remoteName string, h hop, agentClient agent.Agent) (*ssh.ClientConfig, error) {
+	signers, err := loadIdentitySigners(h, agentClient != nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(signers) == 0 && agentClient == nil {
+		return nil, fmt.Errorf("no SSH identity for %s@%s: set key in remotes.yaml, IdentityFile in ~/.ssh/config, or start ssh-agent", h.user, h.host)
+	}
+
+	keys := func() ([]ssh.Signer, error) {
+		all := append([]ssh.Signer{}, signers...)
+		if agentClient != nil {
+			agentSigners, err := agentClient.Signers()
+			if err != nil {
+				debug.Log("ssh[%s]: ssh-agent signers unavailable: %v", remoteName, err)
+			}
+			all = append(all, agentSigners...)
+		}
+		return all, nil
+	}
+
+	return &ssh.ClientConfig{
+		User:              h.user,
+		Auth:              []ssh.AuthMethod{ssh.PublicKeysCallback(keys)},
+		HostKeyCallback:   p.hostKeyCallback(remoteName),
+		HostKeyAlgorithms: p.knownHostKeyAlgorithms(h.addr()),
+		Timeout:           SSHConnectTimeout,
+	}, nil
+}
+
+// loadIdentitySigners loads the private keys for a hop, falling back to the
+// default identity files. Keys that cannot be loaded are skipped, except an
+// explicit remotes.yaml key, which must load unless it is passphrase-protected
+// and ssh-agent is available to hold it.
+func loadIdentitySigners(h hop, haveAgent bool) ([]ssh.Signer, error) {
+	files := h.identityFiles
+	if len(files) == 0 {
+		files = defaultIdentityFiles
+	}
+
+	var signers []ssh.Signer
+	for _, file := range files {
+		file = pathutil.ExpandPath(file)
+		signer, err := LoadSSHKey(file)
+		if err == nil {
+			signers = append(signers, signer)
+			continue
+		}
+
+		var passErr *ssh.PassphraseMissingError
+		if h.explicitKey && !(haveAgent && errors.As(err, &passErr)) {
+			return nil, fmt.Errorf("failed to load SSH key: %w", err)
+		}
+		if !os.IsNotExist(errors.Unwrap(err)) {
+			debug.Log("ssh: skipping identity %s: %v", file, err)
+		}
+	}
+	return signers, nil
+}
+
+// connectAgent connects to the ssh-agent named by SSH_AUTH_SOCK.
+// Returns a nil agent when none is reachable; the close function is always safe to call.
+func connectAgent() (agent.Agent, func()) {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, func() {}
+	}
+
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		debug.Log("ssh: ssh-agent unavailable: %v", err)
+		return nil, func() {}
+	}
+
+	return agent.NewClient(conn), func() { conn.Close() }
+}
+
+// localUsername returns the current user's login name, the ssh default user.
+func localUsername() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// firstNonEmpty returns the first non-empty string.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// firstPositive returns the first value greater than zero.
+func firstPositive(values ...int) int {
+	for _, v := range values {
+		if v > 0 {
+			return v
+		}
+	}
+	return 0
 }
 
 // LoadSSHKey loads and parses an SSH private key from a file.
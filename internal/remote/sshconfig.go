@@ -0,0 +1,251 @@
+package remote
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/stwalsh4118/navi/internal/pathutil"
+)
+
+// SSHConfigPath is the OpenSSH client configuration consulted for Host aliases.
+const SSHConfigPath = "~/.ssh/config"
+
+// maxIncludeDepth bounds recursive Include directives, matching OpenSSH.
+const maxIncludeDepth = 16
+
+// HostConfig holds the ssh_config settings that apply to one host alias.
+// Zero values mean the setting was not specified.
+type HostConfig struct {
+	HostName      string
+	User          string
+	Port          int
+	IdentityFiles []string
+	ProxyJump     []string
+}
+
+// SSHConfig is a parsed OpenSSH client configuration.
+// Only the options navi needs are interpreted: HostName, User, Port,
+// IdentityFile and ProxyJump. Match blocks are skipped.
+type SSHConfig struct {
+	blocks []hostBlock
+}
+
+// hostBlock is a Host section with its options in file order.
+type hostBlock struct {
+	patterns []string // nil for "Match" blocks, which never apply
+	options  [][2]string
+}
+
+// LoadSSHConfig reads and parses an ssh_config file, following Include directives.
+// A missing file yields an empty configuration and no error.
+func LoadSSHConfig(configPath string) (*SSHConfig, error) {
+	if configPath == "" {
+		configPath = SSHConfigPath
+	}
+	configPath = pathutil.ExpandPath(configPath)
+
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return &SSHConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSSHConfig(data, filepath.Dir(configPath)), nil
+}
+
+// ParseSSHConfig parses ssh_config data. Relative Include paths are resolved
+// against dir; unreadable includes are ignored.
+func ParseSSHConfig(data []byte, dir string) *SSHConfig {
+	cfg := &SSHConfig{}
+	// Options before the first Host line apply to every host.
+	cfg.blocks = parseSSHConfigBlocks(data, dir, hostBlock{patterns: []string{"*"}}, 0)
+	return cfg
+}
+
+// parseSSHConfigBlocks parses data into host blocks. Options that appear before
+// the first Host line in data belong to current, so an Include inside a Host
+// section inherits that section's patterns.
+func parseSSHConfigBlocks(data []byte, dir string, current hostBlock, depth int) []hostBlock {
+	var blocks []hostBlock
+	flush := func() {
+		if len(current.options) > 0 {
+			blocks = append(blocks, current)
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value := splitSSHConfigLine(scanner.Text())
+		if key == "" {
+			continue
+		}
+
+		switch key {
+		case "host":
+			flush()
+			current = hostBlock{patterns: strings.Fields(value)}
+		case "match":
+			flush()
+			current = hostBlock{}
+			if strings.EqualFold(strings.TrimSpace(value), "all") {
+				current.patterns = []string{"*"}
+			}
+		case "include":
+			if depth >= maxIncludeDepth {
+				continue
+			}
+			flush()
+			for _, pattern := range strings.Fields(value) {
+				for _, file := range expandInclude(pattern, dir) {
+					included, err := os.ReadFile(file)
+					if err != nil {
+						continue
+					}
+					blocks = append(blocks, parseSSHConfigBlocks(included, dir, hostBlock{patterns: current.patterns}, depth+1)...)
+				}
+			}
+			current = hostBlock{patterns: current.patterns}
+		default:
+			current.options = append(current.options, [2]string{key, value})
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+// splitSSHConfigLine returns the lowercased keyword and unquoted value of a
+// config line. Blank lines and comments yield an empty keyword.
+func splitSSHConfigLine(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", ""
+	}
+
+	idx := strings.IndexAny(line, " \t=")
+	if idx < 0 {
+		return strings.ToLower(line), ""
+	}
+	key := strings.ToLower(line[:idx])
+	value := strings.TrimLeft(line[idx:], " \t")
+	value = strings.TrimPrefix(value, "=")
+	value = strings.TrimSpace(value)
+	value = strings.Trim(value, `"`)
+	return key, value
+}
+
+// expandInclude resolves an Include pattern to the matching file paths.
+func expandInclude(pattern, dir string) []string {
+	pattern = pathutil.ExpandPath(pattern)
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(dir, pattern)
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil
+	}
+	return matches
+}
+
+// Resolve returns the settings that apply to alias. As in OpenSSH the first
+// value found for each option wins, except IdentityFile, which accumulates.
+func (c *SSHConfig) Resolve(alias string) HostConfig {
+	var hc HostConfig
+	if c == nil {
+		return hc
+	}
+
+	seen := make(map[string]bool)
+	for _, block := range c.blocks {
+		if !matchHostPatterns(block.patterns, alias) {
+			continue
+		}
+		for _, opt := range block.options {
+			key, value := opt[0], opt[1]
+			if key == "identityfile" {
+				hc.IdentityFiles = append(hc.IdentityFiles, value)
+				continue
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			switch key {
+			case "hostname":
+				hc.HostName = strings.ReplaceAll(value, "%h", alias)
+			case "user":
+				hc.User = value
+			case "port":
+				if port, err := strconv.Atoi(value); err == nil {
+					hc.Port = port
+				}
+			case "proxyjump":
+				if !strings.EqualFold(value, "none") {
+					hc.ProxyJump = splitJumpHosts(value)
+				}
+			}
+		}
+	}
+
+	host := alias
+	if hc.HostName != "" {
+		host = hc.HostName
+	}
+	for i, file := range hc.IdentityFiles {
+		hc.IdentityFiles[i] = expandIdentityFile(file, host, hc.User)
+	}
+
+	return hc
+}
+
+// matchHostPatterns reports whether alias matches a Host line's patterns:
+// at least one positive pattern must match and no negated pattern may.
+func matchHostPatterns(patterns []string, alias string) bool {
+	alias = strings.ToLower(alias)
+	matched := false
+	for _, p := range patterns {
+		negate := strings.HasPrefix(p, "!")
+		p = strings.ToLower(strings.TrimPrefix(p, "!"))
+		ok, err := path.Match(p, alias)
+		if err != nil || !ok {
+			continue
+		}
+		if negate {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+// splitJumpHosts splits a comma-separated ProxyJump or jump_host value.
+func splitJumpHosts(value string) []string {
+	var hops []string
+	for _, hop := range strings.Split(value, ",") {
+		if hop = strings.TrimSpace(hop); hop != "" {
+			hops = append(hops, hop)
+		}
+	}
+	return hops
+}
+
+// expandIdentityFile expands ~ and the %d, %u, %h, %r and %% tokens in an IdentityFile path.
+func expandIdentityFile(file, host, remoteUser string) string {
+	home, _ := os.UserHomeDir()
+	localUser := ""
+	if u, err := user.Current(); err == nil {
+		localUser = u.Username
+	}
+
+	r := strings.NewReplacer("%%", "%", "%d", home, "%u", localUser, "%h", host, "%r", remoteUser)
+	return pathutil.ExpandPath(r.Replace(file))
+}
@@ -0,0 +1,250 @@
+package remote
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSSHConfigResolve(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	cfg := ParseSSHConfig([]byte(`
+# Options before any Host line apply to every host, and the first value wins
+User fallback
+
+Host devbox dev
+    HostName devbox.internal.example.com
+    User sean
+    Port 2222
+    IdentityFile ~/.ssh/devbox_ed25519
+    ProxyJump bastion
+
+Host *.internal !secret.internal
+    User=ops
+    IdentityFile "~/.ssh/%h_key"
+
+Host direct
+    ProxyJump none
+
+Match host devbox
+    User ignored
+
+Host *
+    User everyone
+    Port 2200
+    IdentityFile ~/.ssh/id_default
+`), "/unused")
+
+	tests := []struct {
+		alias string
+		want  HostConfig
+	}{
+		{
+			alias: "devbox",
+			want: HostConfig{
+				HostName:      "devbox.internal.example.com",
+				User:          "fallback",
+				Port:          2222,
+				IdentityFiles: []string{home + "/.ssh/devbox_ed25519", home + "/.ssh/id_default"},
+				ProxyJump:     []string{"bastion"},
+			},
+		},
+		{
+			alias: "api.internal",
+			want: HostConfig{
+				User:          "fallback",
+				Port:          2200,
+				IdentityFiles: []string{home + "/.ssh/api.internal_key", home + "/.ssh/id_default"},
+			},
+		},
+		{
+			alias: "secret.internal",
+			want: HostConfig{
+				User:          "fallback",
+				Port:          2200,
+				IdentityFiles: []string{home + "/.ssh/id_default"},
+			},
+		},
+		{
+			alias: "direct",
+			want: HostConfig{
+				User:          "fallback",
+				Port:          2200,
+				IdentityFiles: []string{home + "/.ssh/id_default"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			got := cfg.Resolve(tt.alias)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve(%q) = %+v, want %+v", tt.alias, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSSHConfigFirstValueWins(t *testing.T) {
+	cfg := ParseSSHConfig([]byte(`
+Host prod
+    User deploy
+    ProxyJump jump1,jump2:2022,admin@jump3
+
+Host prod
+    User other
+    ProxyJump ignored
+`), "")
+
+	got := cfg.Resolve("PROD")
+	if got.User != "deploy" {
+		t.Errorf("User = %q, want deploy (matching is case-insensitive, first value wins)", got.User)
+	}
+	want := []string{"jump1", "jump2:2022", "admin@jump3"}
+	if !reflect.DeepEqual(got.ProxyJump, want) {
+		t.Errorf("ProxyJump = %v, want %v", got.ProxyJump, want)
+	}
+}
+
+func TestSSHConfigInclude(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "conf.d"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	included := "Host lab\n    HostName 10.0.0.5\n    User lab\n"
+	if err := os.WriteFile(filepath.Join(dir, "conf.d", "lab.conf"), []byte(included), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	main := "Include conf.d/*.conf\n\nHost lab\n    User overridden\n"
+	configPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(configPath, []byte(main), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadSSHConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadSSHConfig: %v", err)
+	}
+	got := cfg.Resolve("lab")
+	if got.HostName != "10.0.0.5" || got.User != "lab" {
+		t.Errorf("Resolve(lab) = %+v, want included HostName and User", got)
+	}
+}
+
+func TestLoadSSHConfigMissingFile(t *testing.T) {
+	cfg, err := LoadSSHConfig(filepath.Join(t.TempDir(), "nope"))
+	if err != nil {
+		t.Fatalf("LoadSSHConfig error: %v", err)
+	}
+	if got := cfg.Resolve("anything"); !reflect.DeepEqual(got, HostConfig{}) {
+		t.Errorf("Resolve = %+v, want empty", got)
+	}
+}
+
+func TestResolveRoute(t *testing.T) {
+	cfg := ParseSSHConfig([]byte(`
+Host devbox
+    HostName devbox.example.com
+    User sean
+    IdentityFile /keys/devbox
+    ProxyJump bastion,ops@inner:2022
+
+Host bastion
+    HostName bastion.example.com
+    Port 2200
+    IdentityFile /keys/bastion
+`), "")
+
+	t.Run("alias with jump chain", func(t *testing.T) {
+		route := resolveRoute(&Config{Name: "devbox", Host: "devbox"}, cfg)
+		if len(route) != 3 {
+			t.Fatalf("route has %d hops, want 3: %+v", len(route), route)
+		}
+		if route[0].addr() != "bastion.example.com:2200" || route[0].user != localUsername() {
+			t.Errorf("hop 0 = %+v", route[0])
+		}
+		if !reflect.DeepEqual(route[0].identityFiles, []string{"/keys/bastion"}) {
+			t.Errorf("hop 0 identities = %v", route[0].identityFiles)
+		}
+		if route[1].addr() != "inner:2022" || route[1].user != "ops" {
+			t.Errorf("hop 1 = %+v", route[1])
+		}
+		target := route[2]
+		if target.addr() != "devbox.example.com:22" || target.user != "sean" {
+			t.Errorf("target = %+v", target)
+		}
+		if !reflect.DeepEqual(target.identityFiles, []string{"/keys/devbox"}) || target.explicitKey {
+			t.Errorf("target identities = %v explicit=%v", target.identityFiles, target.explicitKey)
+		}
+	})
+
+	t.Run("remotes.yaml overrides ssh config", func(t *testing.T) {
+		route := resolveRoute(&Config{
+			Name:     "devbox",
+			Host:     "devbox",
+			User:     "admin",
+			Port:     2222,
+			Key:      "/keys/admin",
+			JumpHost: "jump.example.com",
+		}, cfg)
+		if len(route) != 2 {
+			t.Fatalf("route has %d hops, want 2: %+v", len(route), route)
+		}
+		// Jump hosts without their own user or identity reuse the remote's
+		if route[0].addr() != "jump.example.com:22" || route[0].user != "admin" || !route[0].explicitKey {
+			t.Errorf("jump = %+v", route[0])
+		}
+		target := route[1]
+		if target.addr() != "devbox.example.com:2222" || target.user != "admin" || !target.explicitKey {
+			t.Errorf("target = %+v", target)
+		}
+	})
+
+	t.Run("plain host without ssh config", func(t *testing.T) {
+		route := resolveRoute(&Config{Name: "x", Host: "10.1.2.3"}, &SSHConfig{})
+		if len(route) != 1 || route[0].addr() != "10.1.2.3:22" || route[0].user != localUsername() {
+			t.Errorf("route = %+v", route)
+		}
+	})
+}
+
+func TestParseJumpSpec(t *testing.T) {
+	tests := []struct {
+		spec     string
+		wantUser string
+		wantHost string
+		wantPort int
+	}{
+		{"bastion", "", "bastion", 0},
+		{"ops@bastion", "ops", "bastion", 0},
+		{"ops@bastion:2022", "ops", "bastion", 2022},
+		{"ssh://ops@bastion:2022", "ops", "bastion", 2022},
+		{"[fe80::1]:2022", "", "fe80::1", 2022},
+		{"fe80::1", "", "fe80::1", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			user, host, port := parseJumpSpec(tt.spec)
+			if user != tt.wantUser || host != tt.wantHost || port != tt.wantPort {
+				t.Errorf("parseJumpSpec(%q) = %q, %q, %d; want %q, %q, %d",
+					tt.spec, user, host, port, tt.wantUser, tt.wantHost, tt.wantPort)
+			}
+		})
+	}
+}
+
+func TestBuildSSHAttachCommandAlias(t *testing.T) {
+	args := BuildSSHAttachCommand(&Config{Name: "devbox", Host: "devbox"}, "api")
+	got := strings.Join(args, " ")
+	if got != "ssh -t devbox tmux attach-session -t api" {
+		t.Errorf("attach command = %q, want ssh to resolve the alias itself", got)
+	}
+
+	args = BuildSSHAttachCommand(&Config{Host: "h", User: "u", Port: 2222, JumpHost: "j1,ops@j2"}, "api")
+	got = strings.Join(args, " ")
+	if got != "ssh -p 2222 -J u@j1,ops@j2 -t u@h tmux attach-session -t api" {
+		t.Errorf("attach command = %q", got)
+	}
+}
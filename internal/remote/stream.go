@@ -0,0 +1,304 @@
+package remote
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/stwalsh4118/navi/internal/agent"
+	"github.com/stwalsh4118/navi/internal/debug"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// Agent streaming constants
+const (
+	agentHelloTimeout     = 5 * time.Second            // Time allowed for the agent's hello event
+	agentStaleAfter       = 3 * agent.DefaultHeartbeat // Silence after which a stream is considered dead
+	agentRestartDelay     = 15 * time.Second           // Wait before restarting a stream that dropped
+	agentRetryInterval    = 5 * time.Minute            // Wait before retrying a remote without the agent
+	agentMaxEventBytes    = 1 << 20                    // Largest accepted event line
+	agentExitNotInstalled = 127                        // Exit status of the launcher when navi is missing
+)
+
+// ErrAgentUnavailable is returned when the remote has no navi binary that speaks the agent protocol.
+var ErrAgentUnavailable = errors.New("navi agent is not available on the remote")
+
+// streamState describes an agent stream as seen by a poll.
+type streamState int
+
+const (
+	streamStarting    streamState = iota // Connected or connecting, initial snapshot not complete
+	streamLive                           // Synced and receiving events
+	streamEnded                          // Stream dropped or went silent
+	streamUnavailable                    // Remote has no usable navi agent
+)
+
+// agentStream mirrors the status files of one remote from its navi agent.
+type agentStream struct {
+	mu        sync.Mutex
+	sessions  map[string]session.Info // By status file name
+	hello     bool
+	synced    bool
+	done      bool
+	err       error
+	lastEvent time.Time
+	stop      func()
+}
+
+func newAgentStream() *agentStream {
+	return &agentStream{sessions: make(map[string]session.Info), lastEvent: time.Now()}
+}
+
+// apply updates the mirrored state with one event. It returns false when the
+// first event is not a compatible hello, meaning the remote is not a navi agent.
+func (s *agentStream) apply(ev agent.Event, remoteName string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.hello {
+		if ev.Type != agent.EventHello || ev.Version != agent.ProtocolVersion {
+			return false
+		}
+		s.hello = true
+	}
+	s.lastEvent = time.Now()
+
+	switch ev.Type {
+	case agent.EventUpsert:
+		if ev.Session != nil {
+			info := *ev.Session
+			info.Remote = remoteName
+			s.sessions[ev.Name] = info
+		}
+	case agent.EventRemove:
+		delete(s.sessions, ev.Name)
+	case agent.EventSync:
+		s.synced = true
+	}
+	return true
+}
+
+// hasHello reports whether the agent has identified itself.
+func (s *agentStream) hasHello() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hello
+}
+
+// setStop records how to tear the stream down.
+func (s *agentStream) setStop(stop func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stop = stop
+}
+
+// close tears the stream down if it is running.
+func (s *agentStream) close() {
+	s.mu.Lock()
+	stop := s.stop
+	s.mu.Unlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+// finish marks the stream as ended with err.
+func (s *agentStream) finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done = true
+	s.err = err
+}
+
+// snapshot returns the stream state and, when live, its sessions sorted by name.
+func (s *agentStream) snapshot(now time.Time) ([]session.Info, streamState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case s.done && errors.Is(s.err, ErrAgentUnavailable):
+		return nil, streamUnavailable
+	case s.done:
+		return nil, streamEnded
+	case !s.synced:
+		return nil, streamStarting
+	case now.Sub(s.lastEvent) > agentStaleAfter:
+		return nil, streamEnded
+	}
+
+	names := make([]string, 0, len(s.sessions))
+	for name := range s.sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sessions := make([]session.Info, 0, len(names))
+	for _, name := range names {
+		sessions = append(sessions, s.sessions[name])
+	}
+	return sessions, streamLive
+}
+
+// buildAgentCommand builds the shell command that launches navi agent on the
+// remote, trying PATH and then ~/.local/bin, and exiting 127 if neither exists.
+func buildAgentCommand(sessionsDir string) string {
+	dir := doubleQuote(resolveSessionsDir(sessionsDir))
+	return fmt.Sprintf(
+		`if command -v navi >/dev/null 2>&1; then exec navi agent --dir %s; elif [ -x "$HOME/.local/bin/navi" ]; then exec "$HOME/.local/bin/navi" agent --dir %s; else exit %d; fi`,
+		dir, dir, agentExitNotInstalled,
+	)
+}
+
+// DisableAgentStreams makes the pool poll status files instead of starting
+// navi agent streams. Short-lived pools use it, since a stream costs a channel
+// and a remote process that would be torn down almost at once.
+func (p *SSHPool) DisableAgentStreams() {
+	p.streamMu.Lock()
+	defer p.streamMu.Unlock()
+	p.noStreams = true
+}
+
+// AgentSessions returns the sessions mirrored from the remote's navi agent
+// stream. ok is false until a live, synced stream exists, in which case the
+// caller should fall back to polling. Calls start or restart the stream as
+// needed; remotes without the agent are retried after agentRetryInterval.
+func (p *SSHPool) AgentSessions(remote Config) ([]session.Info, bool) {
+	// Streaming needs a long-lived SSH channel; other transports poll
+	if !remote.isSSH() {
+		return nil, false
+	}
+
+	p.streamMu.Lock()
+	defer p.streamMu.Unlock()
+	if p.noStreams {
+		return nil, false
+	}
+
+	now := time.Now()
+	if retryAt, ok := p.agentRetryAt[remote.Name]; ok && now.Before(retryAt) {
+		return nil, false
+	}
+
+	if stream := p.streams[remote.Name]; stream != nil {
+		sessions, state := stream.snapshot(now)
+		switch state {
+		case streamLive:
+			return sessions, true
+		case streamStarting:
+			return nil, false
+		case streamUnavailable:
+			debug.Log("remote[%s]: navi agent unavailable, polling instead", remote.Name)
+			delete(p.streams, remote.Name)
+			p.agentRetryAt[remote.Name] = now.Add(agentRetryInterval)
+			return nil, false
+		case streamEnded:
+			debug.Log("remote[%s]: agent stream ended, restarting after %s", remote.Name, agentRestartDelay)
+			stream.close()
+			delete(p.streams, remote.Name)
+			p.agentRetryAt[remote.Name] = now.Add(agentRestartDelay)
+			return nil, false
+		}
+	}
+
+	stream := newAgentStream()
+	p.streams[remote.Name] = stream
+	go func() {
+		stream.finish(p.runAgentStream(remote, stream))
+	}()
+	return nil, false
+}
+
+// runAgentStream runs navi agent on the remote over one SSH session channel and
+// applies its events to stream until the channel closes.
+func (p *SSHPool) runAgentStream(remote Config, stream *agentStream) error {
+	client, err := p.Connect(remote.Name)
+	if err != nil {
+		return err
+	}
+
+	sess, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("failed to create agent session: %w", err)
+	}
+	defer sess.Close()
+
+	stdout, err := sess.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	// Held open for the life of the stream; the agent exits when it closes
+	stdin, err := sess.StdinPipe()
+	if err != nil {
+		return err
+	}
+	defer stdin.Close()
+
+	if err := sess.Start(buildAgentCommand(remote.SessionsDir)); err != nil {
+		return fmt.Errorf("failed to start agent: %w", err)
+	}
+	stream.setStop(func() { sess.Close() })
+	debug.Log("remote[%s]: agent stream started", remote.Name)
+
+	helloTimer := time.AfterFunc(agentHelloTimeout, func() {
+		if !stream.hasHello() {
+			sess.Close()
+		}
+	})
+	defer helloTimer.Stop()
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), agentMaxEventBytes)
+	for scanner.Scan() {
+		var ev agent.Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			if !stream.hasHello() {
+				sess.Close()
+				break
+			}
+			debug.Log("remote[%s]: skipping malformed agent event: %v", remote.Name, err)
+			continue
+		}
+		if !stream.apply(ev, remote.Name) {
+			sess.Close()
+			break
+		}
+	}
+
+	waitErr := sess.Wait()
+	if !stream.hasHello() {
+		var exitErr *ssh.ExitError
+		if errors.As(waitErr, &exitErr) && exitErr.ExitStatus() == agentExitNotInstalled {
+			return fmt.Errorf("%w: navi not found", ErrAgentUnavailable)
+		}
+		return fmt.Errorf("%w: no hello from agent (%v)", ErrAgentUnavailable, waitErr)
+	}
+	if waitErr != nil {
+		return fmt.Errorf("agent stream ended: %w", waitErr)
+	}
+	return errors.New("agent stream ended")
+}
+
+// stopStreams closes every agent stream.
+func (p *SSHPool) stopStreams() {
+	p.streamMu.Lock()
+	defer p.streamMu.Unlock()
+	for name, stream := range p.streams {
+		stream.close()
+		delete(p.streams, name)
+	}
+}
+
+// stopStream closes the agent stream for one remote.
+func (p *SSHPool) stopStream(remoteName string) {
+	p.streamMu.Lock()
+	defer p.streamMu.Unlock()
+	if stream, ok := p.streams[remoteName]; ok {
+		stream.close()
+		delete(p.streams, remoteName)
+	}
+}
@@ -0,0 +1,234 @@
+package remote
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/stwalsh4118/navi/internal/agent"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// fakeRemote serves the agent and cat commands from a local status directory.
+type fakeRemote struct {
+	dir        string
+	installed  bool
+	agentCalls atomic.Int32
+	catCalls   atomic.Int32
+}
+
+func (f *fakeRemote) exec(cmd string, ch ssh.Channel) uint32 {
+	if strings.Contains(cmd, " agent --dir ") {
+		f.agentCalls.Add(1)
+		if !f.installed {
+			return agentExitNotInstalled
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			io.Copy(io.Discard, ch)
+			cancel()
+		}()
+		agent.Run(ctx, f.dir, ch, 10*time.Millisecond, time.Second)
+		return 0
+	}
+
+	f.catCalls.Add(1)
+	paths, _ := filepath.Glob(filepath.Join(f.dir, "*.json"))
+	for _, path := range paths {
+		data, _ := os.ReadFile(path)
+		ch.Write(append(data, '\n'))
+	}
+	return 0
+}
+
+func newFakeRemotePool(t *testing.T, installed bool) (*SSHPool, *fakeRemote) {
+	t.Helper()
+	fake := &fakeRemote{dir: t.TempDir(), installed: installed}
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	clientKey := writeTestKey(t, keyPath)
+	server := newTestSSHServerWithExec(t, clientKey.PublicKey(), fake.exec)
+	pool, _ := newTestPool(t, server, Config{Key: keyPath})
+	t.Cleanup(pool.Close)
+	trustServer(t, pool, server)
+	return pool, fake
+}
+
+func writeStatusFile(t *testing.T, dir, name, status string) {
+	t.Helper()
+	data := `{"tmux_session":"` + name + `","status":"` + status + `","timestamp":1}`
+	if err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitFor polls cond until it holds or the deadline passes.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPollSingleRemoteUsesAgentStream(t *testing.T) {
+	pool, fake := newFakeRemotePool(t, true)
+	remote := *pool.GetRemoteConfig("dev")
+	writeStatusFile(t, fake.dir, "api", "working")
+
+	// The first poll starts the stream and reads the files directly meanwhile
+	sessions, err := PollSingleRemote(pool, remote)
+	if err != nil {
+		t.Fatalf("PollSingleRemote: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].TmuxSession != "api" {
+		t.Fatalf("fallback sessions = %+v", sessions)
+	}
+
+	var live []session.Info
+	waitFor(t, "agent stream to sync", func() bool {
+		var ok bool
+		live, ok = pool.AgentSessions(remote)
+		return ok
+	})
+	if len(live) != 1 || live[0].Remote != "dev" || live[0].Status != "working" {
+		t.Fatalf("stream sessions = %+v", live)
+	}
+
+	catCalls := fake.catCalls.Load()
+	writeStatusFile(t, fake.dir, "web", "waiting")
+	waitFor(t, "new session to stream in", func() bool {
+		sessions, _ = PollSingleRemote(pool, remote)
+		return len(sessions) == 2
+	})
+	if sessions[0].TmuxSession != "api" || sessions[1].TmuxSession != "web" {
+		t.Errorf("sessions not sorted by name: %+v", sessions)
+	}
+
+	if err := os.Remove(filepath.Join(fake.dir, "api.json")); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "removed session to disappear", func() bool {
+		sessions, _ = PollSingleRemote(pool, remote)
+		return len(sessions) == 1 && sessions[0].TmuxSession == "web"
+	})
+
+	if got := fake.catCalls.Load(); got != catCalls {
+		t.Errorf("cat ran %d more times while the stream was live", got-catCalls)
+	}
+	if got := fake.agentCalls.Load(); got != 1 {
+		t.Errorf("agent started %d times, want 1", got)
+	}
+}
+
+func TestPollSingleRemoteFallsBackWithoutAgent(t *testing.T) {
+	pool, fake := newFakeRemotePool(t, false)
+	remote := *pool.GetRemoteConfig("dev")
+	writeStatusFile(t, fake.dir, "api", "idle")
+
+	if _, err := PollSingleRemote(pool, remote); err != nil {
+		t.Fatalf("PollSingleRemote: %v", err)
+	}
+	waitFor(t, "agent launch to fail", func() bool {
+		pool.streamMu.Lock()
+		defer pool.streamMu.Unlock()
+		stream := pool.streams["dev"]
+		if stream == nil {
+			return false
+		}
+		_, state := stream.snapshot(time.Now())
+		return state == streamUnavailable
+	})
+
+	for i := 0; i < 3; i++ {
+		sessions, err := PollSingleRemote(pool, remote)
+		if err != nil {
+			t.Fatalf("PollSingleRemote: %v", err)
+		}
+		if len(sessions) != 1 || sessions[0].Remote != "dev" {
+			t.Fatalf("sessions = %+v", sessions)
+		}
+	}
+	if got := fake.agentCalls.Load(); got != 1 {
+		t.Errorf("agent launched %d times, want 1 until the retry interval passes", got)
+	}
+	if got := fake.catCalls.Load(); got != 4 {
+		t.Errorf("cat ran %d times, want every poll", got)
+	}
+}
+
+func TestDisableAgentStreamsPollsWithCat(t *testing.T) {
+	pool, fake := newFakeRemotePool(t, true)
+	pool.DisableAgentStreams()
+	remote := *pool.GetRemoteConfig("dev")
+	writeStatusFile(t, fake.dir, "api", "idle")
+
+	for i := 0; i < 2; i++ {
+		sessions, err := PollSingleRemote(pool, remote)
+		if err != nil {
+			t.Fatalf("PollSingleRemote: %v", err)
+		}
+		if len(sessions) != 1 || sessions[0].TmuxSession != "api" {
+			t.Fatalf("sessions = %+v", sessions)
+		}
+	}
+	if _, ok := pool.AgentSessions(remote); ok {
+		t.Error("AgentSessions reported a stream with streams disabled")
+	}
+
+	pool.streamMu.Lock()
+	streams := len(pool.streams)
+	pool.streamMu.Unlock()
+	if streams != 0 {
+		t.Errorf("%d streams started, want none", streams)
+	}
+	if got := fake.agentCalls.Load(); got != 0 {
+		t.Errorf("agent launched %d times, want 0", got)
+	}
+	if got := fake.catCalls.Load(); got != 2 {
+		t.Errorf("cat ran %d times, want every poll", got)
+	}
+}
+
+func TestAgentStreamRejectsNonAgentOutput(t *testing.T) {
+	stream := newAgentStream()
+	if stream.apply(agent.Event{Type: agent.EventUpsert, Name: "x"}, "dev") {
+		t.Error("events before hello should be rejected")
+	}
+	if stream.apply(agent.Event{Type: agent.EventHello, Version: agent.ProtocolVersion + 1}, "dev") {
+		t.Error("unknown protocol version should be rejected")
+	}
+	if !stream.apply(agent.Event{Type: agent.EventHello, Version: agent.ProtocolVersion}, "dev") {
+		t.Fatal("hello rejected")
+	}
+	if _, state := stream.snapshot(time.Now()); state != streamStarting {
+		t.Errorf("state before sync = %v, want starting", state)
+	}
+	stream.apply(agent.Event{Type: agent.EventSync}, "dev")
+	if _, state := stream.snapshot(time.Now().Add(agentStaleAfter + time.Second)); state != streamEnded {
+		t.Errorf("silent stream state = %v, want ended", state)
+	}
+}
+
+func TestBuildAgentCommand(t *testing.T) {
+	got := buildAgentCommand("~/.claude-sessions")
+	for _, want := range []string{
+		`exec navi agent --dir "$HOME/.claude-sessions"`,
+		`exec "$HOME/.local/bin/navi" agent --dir "$HOME/.claude-sessions"`,
+		"exit 127",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("command missing %q:\n%s", want, got)
+		}
+	}
+}
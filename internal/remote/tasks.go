@@ -0,0 +1,215 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stwalsh4118/navi/internal/debug"
+	"github.com/stwalsh4118/navi/internal/task"
+)
+
+// Markers separating sections of remote task command output.
+const (
+	projectMarker = "@@NAVI_PROJECT "
+	stderrMarker  = "\n@@NAVI_STDERR\n"
+)
+
+// Exit statuses of the remote provider command.
+const (
+	providerExitTimeout  = 124 // from timeout(1)
+	providerExitNotFound = 127
+)
+
+// DiscoverTaskProjects finds the .navi.yaml project for each session working
+// directory on a remote, in one round trip. Configs are parsed locally, merged
+// with the global config and deduplicated by project directory.
+func DiscoverTaskProjects(pool *SSHPool, remoteName string, cwds []string, global *task.GlobalConfig) ([]task.ProjectConfig, error) {
+	cmd := buildDiscoverCommand(cwds)
+	if cmd == "" {
+		return nil, nil
+	}
+
+	output, err := pool.Execute(remoteName, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover task projects: %w", err)
+	}
+
+	var configs []task.ProjectConfig
+	seen := make(map[string]bool)
+	for _, section := range strings.Split(string(output), projectMarker)[1:] {
+		dir, data, _ := strings.Cut(section, "\n")
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		cfg, err := task.ParseProjectConfig([]byte(data), dir)
+		if err != nil {
+			debug.Log("remote[%s]: skipping invalid %s in %s: %v", remoteName, task.ProjectConfigFile, dir, err)
+			continue
+		}
+		cfg.Remote = remoteName
+		if global != nil {
+			task.MergeConfig(cfg, global)
+		}
+		configs = append(configs, *cfg)
+	}
+	return configs, nil
+}
+
+// buildDiscoverCommand builds a shell command that walks up from each cwd to
+// the nearest .navi.yaml and prints a project marker line with its directory
+// followed by the file contents.
+func buildDiscoverCommand(cwds []string) string {
+	var parts []string
+	seen := make(map[string]bool)
+	for _, cwd := range cwds {
+		if cwd == "" || seen[cwd] {
+			continue
+		}
+		seen[cwd] = true
+		parts = append(parts, fmt.Sprintf(
+			`d=%s; while :; do if [ -f "$d/%s" ]; then printf '%%s%%s\n' %s "$d"; cat "$d/%s"; echo; break; fi; [ "$d" = / ] || [ "$d" = . ] && break; d=$(dirname "$d"); done`,
+			remoteDirExpr(cwd), task.ProjectConfigFile, shellQuote(projectMarker), task.ProjectConfigFile))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, "; ") + "; true"
+}
+
+// ExecuteTaskProvider runs a project's task provider on its remote and parses
+// the JSON it prints locally. Built-in providers are sent from this machine's
+// ProvidersDir, so they need not be installed on the remote; other providers
+// are resolved against the remote project directory. Errors are
+// *task.ProviderError values, as with task.ExecuteProvider.
+func ExecuteTaskProvider(pool *SSHPool, cfg task.ProjectConfig, timeout time.Duration) (*task.ProviderResult, error) {
+	cmd, err := buildProviderCommand(cfg, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := pool.Execute(cfg.Remote, cmd)
+	stdout, stderr := splitProviderOutput(string(output))
+	if err != nil {
+		code, ok := exitStatus(err)
+		switch {
+		case !ok:
+			return nil, &task.ProviderError{
+				Type:    task.ErrExec,
+				Message: fmt.Sprintf("failed to run provider on %s: %v", cfg.Remote, err),
+			}
+		case code == providerExitNotFound:
+			return nil, &task.ProviderError{
+				Type:    task.ErrNotFound,
+				Message: fmt.Sprintf("provider not found on %s: %s", cfg.Remote, cfg.Tasks.Provider),
+				Stderr:  stderr,
+			}
+		case code == providerExitTimeout:
+			return nil, &task.ProviderError{
+				Type:    task.ErrTimeout,
+				Message: fmt.Sprintf("provider timed out after %s", timeout),
+				Stderr:  stderr,
+			}
+		}
+		return nil, &task.ProviderError{
+			Type:    task.ErrExec,
+			Message: fmt.Sprintf("provider exited with error: %v", err),
+			Stderr:  stderr,
+		}
+	}
+
+	result, err := task.ParseProviderOutput([]byte(stdout))
+	if err != nil {
+		return nil, &task.ProviderError{
+			Type:    task.ErrParse,
+			Message: fmt.Sprintf("failed to parse provider output: %v", err),
+			Stderr:  stderr,
+		}
+	}
+	return result, nil
+}
+
+// buildProviderCommand builds the remote shell command for a provider. The
+// provider's stdout passes through untouched; its stderr is captured and
+// printed after stderrMarker so the two can be told apart locally.
+func buildProviderCommand(cfg task.ProjectConfig, timeout time.Duration) (string, error) {
+	name := cfg.Tasks.Provider
+	var runner string
+	if scriptPath, ok := task.BuiltinProviderPath(name); ok {
+		script, err := os.ReadFile(scriptPath)
+		if err != nil {
+			return "", &task.ProviderError{
+				Type:    task.ErrNotFound,
+				Message: fmt.Sprintf("provider script not found: %s", scriptPath),
+			}
+		}
+		runner = fmt.Sprintf("%s -c %s %s", scriptInterpreter(script), shellQuote(string(script)), shellQuote(name))
+	} else {
+		scriptPath := name
+		if !path.IsAbs(scriptPath) {
+			scriptPath = path.Join(cfg.ProjectDir, scriptPath)
+		}
+		runner = fmt.Sprintf("[ -e %s ] || exit %d; %s", shellQuote(scriptPath), providerExitNotFound, shellQuote(scriptPath))
+	}
+
+	env := task.BuildEnvVars(cfg.Tasks.Args)
+	sort.Strings(env)
+	envPrefix := ""
+	for _, kv := range env {
+		envPrefix += "export " + shellQuote(kv) + "; "
+	}
+
+	secs := int(timeout.Round(time.Second) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+
+	return fmt.Sprintf(
+		`cd %s || exit %d; %st=""; command -v timeout >/dev/null 2>&1 && t="timeout %d"; exec 3>&1; err=$( { $t sh -c %s 1>&3 3>&-; } 2>&1 ); status=$?; printf %s "$err"; exit $status`,
+		shellQuote(cfg.ProjectDir), providerExitNotFound, envPrefix, secs, shellQuote(runner), shellQuote(strings.ReplaceAll(stderrMarker, "\n", `\n`)+"%s")), nil
+}
+
+// scriptInterpreter returns the interpreter named by a script's #! line,
+// resolving "/usr/bin/env bash" to "bash". Scripts without one run with sh.
+func scriptInterpreter(script []byte) string {
+	line, _, _ := strings.Cut(string(script), "\n")
+	if !strings.HasPrefix(line, "#!") {
+		return "sh"
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return "sh"
+	}
+	if path.Base(fields[0]) == "env" && len(fields) > 1 {
+		return shellQuote(fields[1])
+	}
+	return shellQuote(fields[0])
+}
+
+// splitProviderOutput separates provider stdout from the stderr section
+// appended after stderrMarker.
+func splitProviderOutput(output string) (stdout, stderr string) {
+	i := strings.LastIndex(output, stderrMarker)
+	if i < 0 {
+		return output, ""
+	}
+	return output[:i], strings.TrimSpace(output[i+len(stderrMarker):])
+}
+
+// ErrNoHeadSHA is returned when a remote directory has no resolvable HEAD.
+var ErrNoHeadSHA = errors.New("no HEAD commit")
+
+// FetchHeadSHA returns the full HEAD SHA of a git repository on a remote.
+func FetchHeadSHA(pool *SSHPool, remoteName, dir string) (string, error) {
+	output, err := pool.Execute(remoteName, fmt.Sprintf("cd %s && git rev-parse HEAD", remoteDirExpr(dir)))
+	if err != nil {
+		return "", fmt.Errorf("%w in %s: %v", ErrNoHeadSHA, dir, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
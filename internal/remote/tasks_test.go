@@ -0,0 +1,194 @@
+package remote
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stwalsh4118/navi/internal/task"
+)
+
+// newLocalShellPool returns a pool with one command remote, "ctr", that runs
+// commands with the local sh.
+func newLocalShellPool(t *testing.T) *SSHPool {
+	t.Helper()
+	pool := NewSSHPool([]Config{{Name: "ctr", Transport: TransportCommand, Command: "sh -c"}})
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func writeExecutable(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscoverTaskProjects(t *testing.T) {
+	pool := newLocalShellPool(t)
+	root := t.TempDir()
+	proj := filepath.Join(root, "proj")
+	if err := os.MkdirAll(filepath.Join(proj, "sub", "deep"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(proj, task.ProjectConfigFile), []byte("tasks:\n  provider: ./tasks.sh\n  args:\n    label: bug\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(root, "other")
+	if err := os.MkdirAll(other, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	global := &task.GlobalConfig{Tasks: task.GlobalTaskConfig{Interval: task.Duration{Duration: time.Minute}}}
+	configs, err := DiscoverTaskProjects(pool, "ctr", []string{proj, filepath.Join(proj, "sub", "deep"), other, ""}, global)
+	if err != nil {
+		t.Fatalf("DiscoverTaskProjects: %v", err)
+	}
+	if len(configs) != 1 {
+		t.Fatalf("configs = %+v, want one project", configs)
+	}
+	cfg := configs[0]
+	if cfg.ProjectDir != proj || cfg.Remote != "ctr" || cfg.Tasks.Provider != "./tasks.sh" || cfg.Tasks.Args["label"] != "bug" {
+		t.Errorf("config = %+v", cfg)
+	}
+	if cfg.Tasks.Interval.Duration != time.Minute {
+		t.Errorf("interval = %s, want global default merged", cfg.Tasks.Interval.Duration)
+	}
+	if cfg.Key() != "ctr:"+proj {
+		t.Errorf("Key() = %q", cfg.Key())
+	}
+
+	if configs, err := DiscoverTaskProjects(pool, "ctr", nil, global); err != nil || configs != nil {
+		t.Errorf("no cwds = %v, %v", configs, err)
+	}
+}
+
+func TestExecuteTaskProvider(t *testing.T) {
+	pool := newLocalShellPool(t)
+	proj := t.TempDir()
+	writeExecutable(t, filepath.Join(proj, "tasks.sh"), `#!/bin/sh
+echo "working in $(pwd)" >&2
+printf '{"tasks":[{"id":"1","title":"%s","status":"open"}]}\n' "$NAVI_TASK_ARG_LABEL"
+`)
+	writeExecutable(t, filepath.Join(proj, "fail.sh"), "#!/bin/sh\necho boom >&2\nexit 2\n")
+	writeExecutable(t, filepath.Join(proj, "garbage.sh"), "#!/bin/sh\necho not json\n")
+
+	cfg := task.ProjectConfig{ProjectDir: proj, Remote: "ctr", Tasks: task.ProjectTaskConfig{Provider: "./tasks.sh", Args: map[string]string{"label": "it's a bug"}}}
+	result, err := ExecuteTaskProvider(pool, cfg, time.Minute)
+	if err != nil {
+		t.Fatalf("ExecuteTaskProvider: %v", err)
+	}
+	if len(result.Tasks) != 1 || result.Tasks[0].Title != "it's a bug" {
+		t.Errorf("result = %+v, want the arg passed through", result)
+	}
+
+	tests := []struct {
+		provider string
+		want     task.ProviderErrorType
+		stderr   string
+	}{
+		{"./fail.sh", task.ErrExec, "boom"},
+		{"./garbage.sh", task.ErrParse, ""},
+		{"./missing.sh", task.ErrNotFound, ""},
+		{filepath.Join(proj, "fail.sh"), task.ErrExec, "boom"},
+	}
+	for _, tt := range tests {
+		cfg.Tasks.Provider = tt.provider
+		_, err := ExecuteTaskProvider(pool, cfg, time.Minute)
+		var pe *task.ProviderError
+		if !errors.As(err, &pe) || pe.Type != tt.want || pe.Stderr != tt.stderr {
+			t.Errorf("%s: error = %#v, want type %d with stderr %q", tt.provider, err, tt.want, tt.stderr)
+		}
+	}
+}
+
+func TestExecuteTaskProviderTimeout(t *testing.T) {
+	if _, err := exec.LookPath("timeout"); err != nil {
+		t.Skip("timeout not available")
+	}
+	pool := newLocalShellPool(t)
+	proj := t.TempDir()
+	writeExecutable(t, filepath.Join(proj, "slow.sh"), "#!/bin/sh\nsleep 5\n")
+
+	cfg := task.ProjectConfig{ProjectDir: proj, Remote: "ctr", Tasks: task.ProjectTaskConfig{Provider: "./slow.sh"}}
+	_, err := ExecuteTaskProvider(pool, cfg, time.Second)
+	var pe *task.ProviderError
+	if !errors.As(err, &pe) || pe.Type != task.ErrTimeout {
+		t.Errorf("error = %v, want timeout", err)
+	}
+}
+
+func TestExecuteTaskProviderBuiltin(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available")
+	}
+	pool := newLocalShellPool(t)
+	providers := t.TempDir()
+	writeExecutable(t, filepath.Join(providers, "markdown-tasks.sh"), `#!/usr/bin/env bash
+set -euo pipefail
+echo "{\"tasks\":[{\"id\":\"1\",\"title\":\"$(basename "$PWD")\",\"status\":\"done\"}]}"
+`)
+	oldDir := task.ProvidersDir
+	task.ProvidersDir = providers
+	t.Cleanup(func() { task.ProvidersDir = oldDir })
+
+	// The remote project has no copy of the script; it is sent from here
+	proj := filepath.Join(t.TempDir(), "api")
+	if err := os.Mkdir(proj, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := task.ProjectConfig{ProjectDir: proj, Remote: "ctr", Tasks: task.ProjectTaskConfig{Provider: "markdown-tasks"}}
+	result, err := ExecuteTaskProvider(pool, cfg, time.Minute)
+	if err != nil {
+		t.Fatalf("ExecuteTaskProvider: %v", err)
+	}
+	if len(result.Tasks) != 1 || result.Tasks[0].Title != "api" {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestScriptInterpreter(t *testing.T) {
+	tests := map[string]string{
+		"#!/usr/bin/env bash\necho": "'bash'",
+		"#!/bin/zsh -e\n":           "'/bin/zsh'",
+		"echo hi\n":                 "sh",
+		"#!\n":                      "sh",
+	}
+	for script, want := range tests {
+		if got := scriptInterpreter([]byte(script)); got != want {
+			t.Errorf("scriptInterpreter(%q) = %q, want %q", script, got, want)
+		}
+	}
+}
+
+func TestFetchHeadSHA(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	pool := newLocalShellPool(t)
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.email=navi@example.com", "-c", "user.name=navi", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	want, _ := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+
+	sha, err := FetchHeadSHA(pool, "ctr", repo)
+	if err != nil || sha != strings.TrimSpace(string(want)) {
+		t.Errorf("FetchHeadSHA = %q, %v; want %s", sha, err, want)
+	}
+
+	if _, err := FetchHeadSHA(pool, "ctr", t.TempDir()); !errors.Is(err, ErrNoHeadSHA) {
+		t.Errorf("non-repo error = %v, want ErrNoHeadSHA", err)
+	}
+}
@@ -0,0 +1,193 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Transport names accepted in remotes.yaml.
+const (
+	TransportSSH     = "ssh"
+	TransportCommand = "command"
+)
+
+// Transport runs commands in a remote environment.
+type Transport interface {
+	// Execute runs a shell command on the remote and returns its combined output.
+	Execute(command string) ([]byte, error)
+	// AttachCommand returns the local argv that attaches to a remote tmux session.
+	AttachCommand(sessionName string) []string
+	// CapturePane returns the last lines of a remote tmux pane, keeping only SGR color codes.
+	CapturePane(sessionName string, lines int) (string, error)
+}
+
+// Transport returns the transport for a remote, or nil if the remote is unknown.
+func (p *SSHPool) Transport(remoteName string) Transport {
+	p.mu.RLock()
+	rc := p.remotes[remoteName]
+	p.mu.RUnlock()
+	if rc == nil {
+		return nil
+	}
+	if rc.Transport == TransportCommand {
+		return &commandTransport{pool: p, remote: rc}
+	}
+	return &sshTransport{pool: p, remote: rc}
+}
+
+// isSSH reports whether a remote is reached over SSH.
+func (rc *Config) isSSH() bool {
+	return rc.Transport == "" || rc.Transport == TransportSSH
+}
+
+// BuildAttachCommand builds the local command that attaches to a tmux session
+// on a remote, over SSH or through the remote's attach command prefix.
+func BuildAttachCommand(remote *Config, sessionName string) []string {
+	if remote.isSSH() {
+		return BuildSSHAttachCommand(remote, sessionName)
+	}
+	return (&commandTransport{remote: remote}).AttachCommand(sessionName)
+}
+
+// sshTransport runs commands over the pool's SSH connection to a remote.
+type sshTransport struct {
+	pool   *SSHPool
+	remote *Config
+}
+
+func (t *sshTransport) Execute(command string) ([]byte, error) {
+	return t.pool.executeSSH(t.remote.Name, command)
+}
+
+func (t *sshTransport) AttachCommand(sessionName string) []string {
+	return BuildSSHAttachCommand(t.remote, sessionName)
+}
+
+func (t *sshTransport) CapturePane(sessionName string, lines int) (string, error) {
+	return capturePaneWith(t, t.remote.Name, sessionName, lines)
+}
+
+// commandTransport runs commands by appending them, as one argument, to a
+// local command prefix such as "docker exec -i dev sh -c".
+type commandTransport struct {
+	pool   *SSHPool
+	remote *Config
+}
+
+func (t *commandTransport) Execute(command string) ([]byte, error) {
+	prefix, err := SplitCommand(t.remote.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(prefix) == 0 {
+		return nil, ErrRemoteCommandRequired
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), SSHCommandTimeout)
+	defer cancel()
+
+	start := time.Now()
+	output, err := exec.CommandContext(ctx, prefix[0], append(prefix[1:], command)...).CombinedOutput()
+	if t.pool != nil {
+		t.pool.recordExec(t.remote.Name, time.Since(start), err)
+	}
+	return output, err
+}
+
+func (t *commandTransport) AttachCommand(sessionName string) []string {
+	prefix, err := SplitCommand(t.remote.AttachCommand)
+	if err != nil || len(prefix) == 0 {
+		prefix, _ = SplitCommand(t.remote.Command)
+	}
+	return append(prefix, "tmux attach-session -t "+shellQuote(sessionName))
+}
+
+func (t *commandTransport) CapturePane(sessionName string, lines int) (string, error) {
+	return capturePaneWith(t, t.remote.Name, sessionName, lines)
+}
+
+// recordExec records the outcome of a command transport call, which has no
+// connection of its own: a failing command marks the remote as errored.
+func (p *SSHPool) recordExec(remoteName string, elapsed time.Duration, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status, ok := p.status[remoteName]
+	if !ok || status.Status == StatusPaused {
+		return
+	}
+	status.LastPoll = time.Now()
+	if err != nil {
+		status.Status = StatusError
+		status.LastError = err
+		return
+	}
+	status.Status = StatusConnected
+	status.LastError = nil
+	status.Latency = elapsed
+}
+
+// exitStatus returns the exit status carried by an SSH or local command error.
+func exitStatus(err error) (int, bool) {
+	var sshErr *ssh.ExitError
+	if errors.As(err, &sshErr) {
+		return sshErr.ExitStatus(), true
+	}
+	var execErr *exec.ExitError
+	if errors.As(err, &execErr) {
+		return execErr.ExitCode(), true
+	}
+	return 0, false
+}
+
+// SplitCommand splits a command prefix into arguments, honoring single and
+// double quotes and backslash escapes outside single quotes.
+func SplitCommand(s string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in command %q", s)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,166 @@
+package remote
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"docker exec -i dev sh -c", []string{"docker", "exec", "-i", "dev", "sh", "-c"}},
+		{`kubectl exec -i "my pod" -- sh -c`, []string{"kubectl", "exec", "-i", "my pod", "--", "sh", "-c"}},
+		{`podman exec 'a b'\ c sh -c`, []string{"podman", "exec", "a b c", "sh", "-c"}},
+		{`sh -c ''`, []string{"sh", "-c", ""}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommand(tt.input)
+		if err != nil {
+			t.Errorf("SplitCommand(%q) error: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitCommand(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	if _, err := SplitCommand(`docker exec "dev`); err == nil {
+		t.Error("unterminated quote should fail")
+	}
+}
+
+func TestValidateConfigTransport(t *testing.T) {
+	tests := []struct {
+		name string
+		rc   Config
+		want error
+	}{
+		{"command without host", Config{Name: "ctr", Transport: TransportCommand, Command: "docker exec -i dev sh -c"}, nil},
+		{"command missing", Config{Name: "ctr", Transport: TransportCommand}, ErrRemoteCommandRequired},
+		{"unknown transport", Config{Name: "ctr", Transport: "telnet", Host: "h"}, ErrUnknownTransport},
+		{"ssh still needs host", Config{Name: "box", Transport: TransportSSH}, ErrRemoteHostRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateConfig(&tt.rc)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ValidateConfig = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildAttachCommandTransports(t *testing.T) {
+	rc := &Config{Name: "ctr", Transport: TransportCommand, Command: "docker exec -i dev sh -c", AttachCommand: "docker exec -it dev sh -c"}
+	got := BuildAttachCommand(rc, "api")
+	want := []string{"docker", "exec", "-it", "dev", "sh", "-c", "tmux attach-session -t 'api'"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("attach = %q, want %q", got, want)
+	}
+
+	rc.AttachCommand = ""
+	if got := BuildAttachCommand(rc, "api"); got[2] != "-i" {
+		t.Errorf("attach without attach_command = %q, want the command prefix", got)
+	}
+
+	ssh := BuildAttachCommand(&Config{Name: "box", Host: "box"}, "api")
+	if ssh[0] != "ssh" {
+		t.Errorf("ssh remote attach = %q", ssh)
+	}
+}
+
+// commandTmux behaves like fakeTmux and also prints a colored pane for capture-pane.
+const commandTmux = `#!/bin/sh
+echo "$@" >> "$TMUX_LOG"
+[ "$1" = capture-pane ] && printf '\033]0;title\007\033[31mred\033[0m line\n\n' && exit 0
+[ "$1" = has-session ] && [ "$3" = "=taken" ] && exit 0
+[ "$1" = has-session ] && exit 1
+exit 0
+`
+
+// newCommandPool returns a pool with one command remote that runs through
+// sh -c locally, with HOME and a fake tmux isolated to temp directories.
+func newCommandPool(t *testing.T) (*SSHPool, string) {
+	t.Helper()
+	home := t.TempDir()
+	binDir := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("TMUX_LOG", filepath.Join(home, "tmux.log"))
+	if err := os.WriteFile(filepath.Join(binDir, "tmux"), []byte(commandTmux), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	pool := NewSSHPool([]Config{{Name: "ctr", Transport: TransportCommand, Command: "sh -c", SessionsDir: DefaultSessionsDir}})
+	t.Cleanup(pool.Close)
+	return pool, home
+}
+
+func TestCommandTransport(t *testing.T) {
+	pool, home := newCommandPool(t)
+
+	out, err := pool.Execute("ctr", `echo "$HOME"`)
+	if err != nil || strings.TrimSpace(string(out)) != home {
+		t.Fatalf("Execute = %q, %v", out, err)
+	}
+	st := pool.GetStatus("ctr")
+	if st.Status != StatusConnected || st.Latency <= 0 {
+		t.Errorf("status after exec = %+v", st)
+	}
+
+	if _, err := pool.Execute("ctr", "exit 3"); err == nil {
+		t.Fatal("failing command should return an error")
+	} else if code, ok := exitStatus(err); !ok || code != 3 {
+		t.Errorf("exit status = %d, %v; want 3", code, ok)
+	}
+	if st := pool.GetStatus("ctr"); st.Status != StatusError {
+		t.Errorf("status after failure = %v, want error", st.Status)
+	}
+
+	content, err := CapturePane(pool, "ctr", "api", 50)
+	if err != nil || content != "\x1b[31mred\x1b[0m line" {
+		t.Errorf("CapturePane = %q, %v; want colors kept and other escapes dropped", content, err)
+	}
+
+	if _, err := pool.Connect("ctr"); err == nil {
+		t.Error("Connect should refuse a command transport remote")
+	}
+}
+
+func TestCommandTransportSessions(t *testing.T) {
+	pool, home := newCommandPool(t)
+	statusDir := filepath.Join(home, ".claude-sessions")
+	if err := os.MkdirAll(statusDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeStatusFile(t, statusDir, "api", "waiting")
+
+	remote := *pool.GetRemoteConfig("ctr")
+	sessions, err := PollSingleRemote(pool, remote)
+	if err != nil {
+		t.Fatalf("PollSingleRemote: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Remote != "ctr" || sessions[0].Status != "waiting" {
+		t.Errorf("sessions = %+v", sessions)
+	}
+	if len(pool.streams) != 0 {
+		t.Error("command remotes should not start an agent stream")
+	}
+
+	if err := os.MkdirAll(filepath.Join(home, "proj"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateSession(pool, "ctr", "web", "~/proj", false, remote.SessionsDir); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	if err := CreateSession(pool, "ctr", "web", "~/missing", false, remote.SessionsDir); !errors.Is(err, ErrRemoteDirNotFound) {
+		t.Errorf("missing dir error = %v, want ErrRemoteDirNotFound", err)
+	}
+}
@@ -0,0 +1,81 @@
+package resource
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// tcpStateListen is the hex socket state for LISTEN in /proc/net/tcp.
+const tcpStateListen = "0A"
+
+// socketLinkPrefix is the readlink target prefix for socket file descriptors.
+const socketLinkPrefix = "socket:["
+
+// listeningSocketInodes maps socket inode to local port for all listening TCP
+// sockets in /proc/net/tcp and /proc/net/tcp6.
+func listeningSocketInodes() map[string]int {
+	inodes := make(map[string]int)
+	for _, name := range []string{"tcp", "tcp6"} {
+		parseListeningSockets(filepath.Join(procFS, "net", name), inodes)
+	}
+	return inodes
+}
+
+// parseListeningSockets reads a /proc/net/tcp-format table and records the
+// inode and port of each socket in LISTEN state.
+func parseListeningSockets(path string, inodes map[string]int) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != tcpStateListen {
+			continue
+		}
+		sep := strings.LastIndexByte(fields[1], ':')
+		if sep < 0 {
+			continue
+		}
+		port, err := strconv.ParseInt(fields[1][sep+1:], 16, 32)
+		if err != nil || fields[9] == "0" {
+			continue
+		}
+		inodes[fields[9]] = int(port)
+	}
+}
+
+// processPorts returns the sorted listening ports held open by a process's
+// file descriptors. fds that cannot be read (e.g. other users' processes) are skipped.
+func processPorts(pid int, listening map[string]int) []int {
+	fdDir := filepath.Join(procFS, strconv.Itoa(pid), "fd")
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[int]bool)
+	var ports []int
+	for _, entry := range entries {
+		target, err := os.Readlink(filepath.Join(fdDir, entry.Name()))
+		if err != nil || !strings.HasPrefix(target, socketLinkPrefix) {
+			continue
+		}
+		inode := strings.TrimSuffix(strings.TrimPrefix(target, socketLinkPrefix), "]")
+		if port, ok := listening[inode]; ok && !seen[port] {
+			seen[port] = true
+			ports = append(ports, port)
+		}
+	}
+	sort.Ints(ports)
+	return ports
+}
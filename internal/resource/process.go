@@ -0,0 +1,256 @@
+package resource
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// clockTicksPerSecond is the kernel USER_HZ used for utime/stime in /proc/<pid>/stat.
+// It is 100 on all mainstream Linux architectures.
+const clockTicksPerSecond = 100
+
+// cpuSampleMaxAge is how long a CPU sample is kept after its process was last seen.
+const cpuSampleMaxAge = time.Minute
+
+// ErrNotInSession is returned by KillProcess when the PID is not a descendant of the session's panes.
+var ErrNotInSession = errors.New("process is not a child of the session")
+
+// Process describes one process in a session's process tree.
+type Process struct {
+	PID        int
+	PPID       int
+	Depth      int // 0 for pane root shells
+	Command    string
+	RSSBytes   int64
+	CPUPercent float64
+	Ports      []int // TCP ports this process is listening on
+}
+
+// Usage is a point-in-time snapshot of a session's process tree resources.
+type Usage struct {
+	RSSBytes     int64
+	CPUPercent   float64
+	ProcessCount int
+	Ports        []int     // Sorted, de-duplicated listening TCP ports across the tree
+	Processes    []Process // Depth-first order, pane roots first
+}
+
+// cpuSample is the cumulative CPU time of a process at a point in time.
+type cpuSample struct {
+	ticks uint64
+	at    time.Time
+}
+
+// Sampler computes CPU percentages from /proc/<pid>/stat deltas between calls.
+// It is safe for concurrent use. A nil Sampler reports 0% CPU.
+type Sampler struct {
+	mu   sync.Mutex
+	prev map[int]cpuSample
+}
+
+// NewSampler creates an empty CPU sampler.
+func NewSampler() *Sampler {
+	return &Sampler{prev: make(map[int]cpuSample)}
+}
+
+// SessionUsage walks the process tree of every pane in a tmux session and
+// returns its RSS, CPU, process count, listening ports, and process list.
+// Returns a zero Usage if the session has no panes.
+func (s *Sampler) SessionUsage(sessionName string) Usage {
+	return s.treeUsage(getPanePIDs(sessionName), time.Now())
+}
+
+// treeUsage builds a Usage for the process trees rooted at the given PIDs.
+func (s *Sampler) treeUsage(roots []int, now time.Time) Usage {
+	var usage Usage
+	if len(roots) == 0 {
+		return usage
+	}
+
+	visited := make(map[int]bool)
+	for _, pid := range roots {
+		collectTree(pid, 0, visited, &usage.Processes)
+	}
+
+	listening := listeningSocketInodes()
+	portSet := make(map[int]bool)
+	for i := range usage.Processes {
+		p := &usage.Processes[i]
+		p.CPUPercent = s.cpuPercent(p.PID, readCPUTicks(p.PID), now)
+		if len(listening) > 0 {
+			p.Ports = processPorts(p.PID, listening)
+		}
+
+		usage.RSSBytes += p.RSSBytes
+		usage.CPUPercent += p.CPUPercent
+		for _, port := range p.Ports {
+			portSet[port] = true
+		}
+	}
+	usage.ProcessCount = len(usage.Processes)
+	for port := range portSet {
+		usage.Ports = append(usage.Ports, port)
+	}
+	sort.Ints(usage.Ports)
+
+	s.prune(now)
+	return usage
+}
+
+// collectTree appends pid and its descendants to out in depth-first order.
+// Processes that have exited between listing and reading are skipped.
+func collectTree(pid, depth int, visited map[int]bool, out *[]Process) {
+	if visited[pid] {
+		return
+	}
+	visited[pid] = true
+
+	ppid, ok := readPPID(pid)
+	if !ok {
+		return
+	}
+	*out = append(*out, Process{
+		PID:      pid,
+		PPID:     ppid,
+		Depth:    depth,
+		Command:  readCommand(pid),
+		RSSBytes: readRSSBytes(pid),
+	})
+
+	children := getChildPIDs(pid)
+	sort.Ints(children)
+	for _, child := range children {
+		collectTree(child, depth+1, visited, out)
+	}
+}
+
+// cpuPercent returns the CPU usage of pid since the previous sample, as a
+// percentage of one core. The first sample of a process reports 0.
+func (s *Sampler) cpuPercent(pid int, ticks uint64, now time.Time) float64 {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, ok := s.prev[pid]
+	s.prev[pid] = cpuSample{ticks: ticks, at: now}
+	if !ok || ticks < prev.ticks {
+		return 0
+	}
+	elapsed := now.Sub(prev.at).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(ticks-prev.ticks) / clockTicksPerSecond / elapsed * 100
+}
+
+// prune drops CPU samples for processes not seen within cpuSampleMaxAge.
+func (s *Sampler) prune(now time.Time) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for pid, sample := range s.prev {
+		if now.Sub(sample.at) > cpuSampleMaxAge {
+			delete(s.prev, pid)
+		}
+	}
+}
+
+// readStatFields returns the fields of /proc/<pid>/stat that follow the
+// parenthesized command name (starting with state). The command name may
+// itself contain spaces and parentheses, so we split after the last ')'.
+func readStatFields(pid int) []string {
+	data, err := os.ReadFile(filepath.Join(procFS, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return nil
+	}
+	stat := string(data)
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return nil
+	}
+	return strings.Fields(stat[end+1:])
+}
+
+// readPPID reads the parent PID of a process. Returns false if the process is gone.
+func readPPID(pid int) (int, bool) {
+	fields := readStatFields(pid)
+	if len(fields) < 2 {
+		return 0, false
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, false
+	}
+	return ppid, true
+}
+
+// readCPUTicks returns utime+stime for a process in clock ticks. Returns 0 on any error.
+func readCPUTicks(pid int) uint64 {
+	fields := readStatFields(pid)
+	// After the command name: state(0) ppid(1) ... utime(11) stime(12)
+	if len(fields) < 13 {
+		return 0
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return 0
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return utime + stime
+}
+
+// readCommand returns the full command line of a process, falling back to
+// the bracketed comm name for kernel threads and zombies.
+func readCommand(pid int) string {
+	pidDir := filepath.Join(procFS, strconv.Itoa(pid))
+	if data, err := os.ReadFile(filepath.Join(pidDir, "cmdline")); err == nil {
+		args := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+		if cmd := strings.TrimSpace(strings.Join(args, " ")); cmd != "" {
+			return cmd
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(pidDir, "comm")); err == nil {
+		return "[" + strings.TrimSpace(string(data)) + "]"
+	}
+	return ""
+}
+
+// KillProcess sends SIGTERM to pid after verifying it is still a non-root
+// process in the named session's tree. This guards against killing a pane's
+// shell or an unrelated process that reused the PID.
+func KillProcess(sessionName string, pid int) error {
+	return killTreeProcess(getPanePIDs(sessionName), pid)
+}
+
+// killTreeProcess sends SIGTERM to pid if it is a descendant of one of roots.
+func killTreeProcess(roots []int, pid int) error {
+	visited := make(map[int]bool)
+	var procs []Process
+	for _, root := range roots {
+		collectTree(root, 0, visited, &procs)
+	}
+
+	for _, p := range procs {
+		if p.PID == pid && p.Depth > 0 {
+			return syscall.Kill(pid, syscall.SIGTERM)
+		}
+	}
+	return ErrNotInSession
+}
@@ -0,0 +1,195 @@
+package resource
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// writeFakeProc creates /proc/<pid> entries for a fake process under root.
+func writeFakeProc(t *testing.T, root string, pid, ppid int, comm, cmdline string, ticks int, children []int, sockets []string) {
+	t.Helper()
+	pidDir := filepath.Join(root, strconv.Itoa(pid))
+	taskDir := filepath.Join(pidDir, "task", strconv.Itoa(pid))
+	fdDir := filepath.Join(pidDir, "fd")
+	for _, dir := range []string{taskDir, fdDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// utime is field 14 overall; split evenly between utime and stime
+	stat := strconv.Itoa(pid) + " (" + comm + ") S " + strconv.Itoa(ppid) +
+		" 0 0 0 0 0 0 0 0 0 " + strconv.Itoa(ticks/2) + " " + strconv.Itoa(ticks-ticks/2) + " 0 0 20 0 1 0\n"
+	childList := ""
+	for _, c := range children {
+		childList += strconv.Itoa(c) + " "
+	}
+	files := map[string]string{
+		filepath.Join(pidDir, "stat"):      stat,
+		filepath.Join(pidDir, "statm"):     "100 10 0 0 0 0 0\n",
+		filepath.Join(pidDir, "cmdline"):   cmdline,
+		filepath.Join(pidDir, "comm"):      comm + "\n",
+		filepath.Join(taskDir, "children"): childList,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, inode := range sockets {
+		if err := os.Symlink("socket:["+inode+"]", filepath.Join(fdDir, strconv.Itoa(i+3))); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func setupFakeProc(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	orig := procFS
+	procFS = root
+	t.Cleanup(func() { procFS = orig })
+
+	// 100 (bash) -> 200 (npm run dev, listening on 3000) -> 300 (node, listening on 9229)
+	writeFakeProc(t, root, 100, 1, "bash", "-bash\x00", 10, []int{200}, nil)
+	writeFakeProc(t, root, 200, 100, "npm run (dev)", "npm\x00run\x00dev\x00", 50, []int{300}, []string{"1111"})
+	writeFakeProc(t, root, 300, 200, "node", "", 100, nil, []string{"2222", "9999"})
+
+	netDir := filepath.Join(root, "net")
+	if err := os.MkdirAll(netDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	tcp := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0BB8 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 1111 1 0 100 0 0 10 0
+   1: 0100007F:2411 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 2222 1 0 100 0 0 10 0
+   2: 0100007F:1F90 0100007F:D431 01 00000000:00000000 00:00000000 00000000  1000        0 9999 1 0 100 0 0 10 0
+`
+	if err := os.WriteFile(filepath.Join(netDir, "tcp"), []byte(tcp), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestTreeUsage_FakeProc(t *testing.T) {
+	setupFakeProc(t)
+
+	usage := NewSampler().treeUsage([]int{100}, time.Now())
+	if usage.ProcessCount != 3 {
+		t.Fatalf("ProcessCount = %d, want 3", usage.ProcessCount)
+	}
+	if usage.RSSBytes != 3*10*pageSize {
+		t.Errorf("RSSBytes = %d, want %d", usage.RSSBytes, 3*10*pageSize)
+	}
+	if len(usage.Ports) != 2 || usage.Ports[0] != 3000 || usage.Ports[1] != 9233 {
+		t.Errorf("Ports = %v, want [3000 9233] (established sockets excluded)", usage.Ports)
+	}
+
+	wantDepths := []int{0, 1, 2}
+	for i, p := range usage.Processes {
+		if p.Depth != wantDepths[i] {
+			t.Errorf("Processes[%d].Depth = %d, want %d", i, p.Depth, wantDepths[i])
+		}
+	}
+	if got := usage.Processes[1].Command; got != "npm run dev" {
+		t.Errorf("Command = %q, want %q", got, "npm run dev")
+	}
+	if got := usage.Processes[2].Command; got != "[node]" {
+		t.Errorf("Command fallback = %q, want %q", got, "[node]")
+	}
+	if usage.Processes[1].PPID != 100 {
+		t.Errorf("PPID = %d, want 100 (comm with parens parsed)", usage.Processes[1].PPID)
+	}
+}
+
+func TestTreeUsage_NoRoots(t *testing.T) {
+	usage := NewSampler().treeUsage(nil, time.Now())
+	if usage.ProcessCount != 0 || usage.Processes != nil {
+		t.Errorf("expected zero usage, got %+v", usage)
+	}
+}
+
+func TestSamplerCPUPercent(t *testing.T) {
+	s := NewSampler()
+	now := time.Now()
+
+	if got := s.cpuPercent(42, 1000, now); got != 0 {
+		t.Errorf("first sample = %v, want 0", got)
+	}
+	// 50 ticks over 1s at 100 ticks/s = 50%
+	if got := s.cpuPercent(42, 1050, now.Add(time.Second)); got != 50 {
+		t.Errorf("cpuPercent = %v, want 50", got)
+	}
+	// Counter went backwards (PID reuse) -> 0
+	if got := s.cpuPercent(42, 10, now.Add(2*time.Second)); got != 0 {
+		t.Errorf("cpuPercent after reset = %v, want 0", got)
+	}
+
+	var nilSampler *Sampler
+	if got := nilSampler.cpuPercent(42, 100, now); got != 0 {
+		t.Errorf("nil sampler cpuPercent = %v, want 0", got)
+	}
+}
+
+func TestSamplerPrune(t *testing.T) {
+	s := NewSampler()
+	now := time.Now()
+	s.cpuPercent(1, 10, now.Add(-2*cpuSampleMaxAge))
+	s.cpuPercent(2, 10, now)
+	s.prune(now)
+
+	if _, ok := s.prev[1]; ok {
+		t.Error("stale sample should be pruned")
+	}
+	if _, ok := s.prev[2]; !ok {
+		t.Error("fresh sample should be kept")
+	}
+}
+
+func TestReadCPUTicks_FakeProc(t *testing.T) {
+	setupFakeProc(t)
+	if got := readCPUTicks(300); got != 100 {
+		t.Errorf("readCPUTicks(300) = %d, want 100", got)
+	}
+	if got := readCPUTicks(999); got != 0 {
+		t.Errorf("readCPUTicks(missing) = %d, want 0", got)
+	}
+}
+
+func TestKillTreeProcess_RejectsRootAndStrangers(t *testing.T) {
+	setupFakeProc(t)
+
+	if err := killTreeProcess([]int{100}, 100); !errors.Is(err, ErrNotInSession) {
+		t.Errorf("killing pane root: err = %v, want ErrNotInSession", err)
+	}
+	if err := killTreeProcess([]int{100}, 4242); !errors.Is(err, ErrNotInSession) {
+		t.Errorf("killing unrelated PID: err = %v, want ErrNotInSession", err)
+	}
+}
+
+func TestTreeUsage_CurrentProcessListeningPort(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	usage := NewSampler().treeUsage([]int{os.Getpid()}, time.Now())
+	if usage.ProcessCount == 0 {
+		t.Fatal("expected current process in tree")
+	}
+	found := false
+	for _, p := range usage.Ports {
+		if p == port {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Ports = %v, want to include %d", usage.Ports, port)
+	}
+}
@@ -11,7 +11,8 @@ import (
 )
 
 // procFS is the root of the Linux process filesystem.
-const procFS = "/proc"
+// Tests can override this to use a fake process tree.
+var procFS = "/proc"
 
 // tmuxCmdTimeout bounds how long we wait for tmux to respond.
 const tmuxCmdTimeout = 5 * time.Second
@@ -0,0 +1,60 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// InvalidNameChars are the characters a new session name may not contain:
+// '.' and ':' separate tmux targets, and the rest are shell metacharacters,
+// since names are embedded in commands run by the remote shell.
+const InvalidNameChars = ".:$`\\\"'!&|;<>(){}[]*?#~\n\r"
+
+// ValidName reports whether name, already trimmed, is usable for a new session.
+func ValidName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, InvalidNameChars)
+}
+
+// ClaudeCommand returns the command typed into a new session to start claude.
+func ClaudeCommand(skipPermissions bool) string {
+	if skipPermissions {
+		return "claude --dangerously-skip-permissions"
+	}
+	return "claude"
+}
+
+// NewStatus returns the initial status recorded for a freshly created session.
+func NewStatus(name, dir string) Info {
+	return Info{
+		TmuxSession: name,
+		Status:      StatusWorking,
+		CWD:         dir,
+		Timestamp:   time.Now().Unix(),
+	}
+}
+
+// Create starts a detached tmux session named name in dir and types the
+// claude command into its shell, so the session stays open when claude exits.
+// It also writes an initial status file to statusDir so the session appears
+// before the first hook fires. Only the tmux session creation can fail; the
+// rest is best effort.
+func Create(name, dir string, skipPermissions bool, statusDir string) error {
+	if err := exec.Command("tmux", "new-session", "-d", "-s", name, "-c", dir).Run(); err != nil {
+		return err
+	}
+
+	// Ignore error - session is created, claude just won't auto-start
+	exec.Command("tmux", "send-keys", "-t", name, ClaudeCommand(skipPermissions), "Enter").Run()
+
+	data, err := json.MarshalIndent(NewStatus(name, dir), "", "  ")
+	if err != nil {
+		return nil
+	}
+	os.MkdirAll(statusDir, 0755)
+	os.WriteFile(filepath.Join(statusDir, name+".json"), data, 0644) // Hook will create it later if needed
+	return nil
+}
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/stwalsh4118/navi/internal/pathutil"
+)
+
+// DefaultGroupsPath is where session group assignments are persisted.
+const DefaultGroupsPath = "~/.config/navi/groups.json"
+
+// Groups maps a session label (see Info.Label) to the group it was moved to.
+// Labels are used rather than IDs so a group survives a restarted claude.
+type Groups map[string]string
+
+// LoadGroups reads group assignments from path. A missing file yields an
+// empty map.
+func LoadGroups(path string) (Groups, error) {
+	groups := make(Groups)
+
+	data, err := os.ReadFile(pathutil.ExpandPath(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return groups, nil
+		}
+		return groups, err
+	}
+
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return make(Groups), err
+	}
+	return groups, nil
+}
+
+// SaveGroups writes group assignments to path atomically.
+func SaveGroups(path string, groups Groups) error {
+	data, err := json.MarshalIndent(groups, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path = pathutil.ExpandPath(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "groups-*.json")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// Apply sets the Group of each session from the assignments.
+func (g Groups) Apply(sessions []Info) {
+	for i := range sessions {
+		sessions[i].Group = g[sessions[i].Label()]
+	}
+}
@@ -0,0 +1,30 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGroupsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "navi", "groups.json")
+
+	groups, err := LoadGroups(path)
+	if err != nil || len(groups) != 0 {
+		t.Fatalf("missing file should load empty, got %v, %v", groups, err)
+	}
+
+	groups["api@devbox"] = "sprint"
+	if err := SaveGroups(path, groups); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadGroups(path)
+	if err != nil || loaded["api@devbox"] != "sprint" {
+		t.Fatalf("LoadGroups() = %v, %v", loaded, err)
+	}
+
+	sessions := []Info{{TmuxSession: "api"}, {TmuxSession: "api", Remote: "devbox"}}
+	loaded.Apply(sessions)
+	if sessions[0].Group != "" || sessions[1].Group != "sprint" {
+		t.Errorf("Apply() groups = %q, %q", sessions[0].Group, sessions[1].Group)
+	}
+}
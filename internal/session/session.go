@@ -68,6 +68,7 @@ type ExternalAgent struct {
 // Info represents the status data for a single Claude Code session.
 type Info struct {
 	TmuxSession     string                   `json:"tmux_session"`
+	SessionID       string                   `json:"session_id,omitempty"`
 	Status          string                   `json:"status"`
 	Message         string                   `json:"message"`
 	CWD             string                   `json:"cwd"`
@@ -79,6 +80,28 @@ type Info struct {
 	Metrics         *metrics.Metrics         `json:"metrics,omitempty"`
 	Team            *TeamInfo                `json:"team,omitempty"`
 	Agents          map[string]ExternalAgent `json:"agents,omitempty"`
+
+	// Group is the user-assigned group, applied from Groups by the TUI.
+	Group string `json:"-"`
+}
+
+// Label returns the name a session is shown and announced under: the tmux
+// session name, with "@<remote>" appended for remote sessions.
+func (s Info) Label() string {
+	if s.Remote == "" {
+		return s.TmuxSession
+	}
+	return s.TmuxSession + "@" + s.Remote
+}
+
+// ID returns an identifier that is unique across hosts: the Label plus, when
+// the hooks have recorded it, "#<claude session id>". A tmux session that is
+// recreated under the same name gets a new ID.
+func (s Info) ID() string {
+	if s.SessionID == "" {
+		return s.Label()
+	}
+	return s.Label() + "#" + s.SessionID
 }
 
 // FilterMode represents the session filter state.
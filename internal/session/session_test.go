@@ -901,3 +901,32 @@ func TestCurrentPBIJSONBehavior(t *testing.T) {
 		}
 	})
 }
+
+func TestSessionIDAndLabel(t *testing.T) {
+	tests := []struct {
+		info      Info
+		wantLabel string
+		wantID    string
+	}{
+		{Info{TmuxSession: "api"}, "api", "api"},
+		{Info{TmuxSession: "api", Remote: "devbox"}, "api@devbox", "api@devbox"},
+		{Info{TmuxSession: "api", SessionID: "abc"}, "api", "api#abc"},
+		{Info{TmuxSession: "api", Remote: "devbox", SessionID: "abc"}, "api@devbox", "api@devbox#abc"},
+	}
+	for _, tt := range tests {
+		if got := tt.info.Label(); got != tt.wantLabel {
+			t.Errorf("Label() = %q, want %q", got, tt.wantLabel)
+		}
+		if got := tt.info.ID(); got != tt.wantID {
+			t.Errorf("ID() = %q, want %q", got, tt.wantID)
+		}
+	}
+
+	var decoded Info
+	if err := json.Unmarshal([]byte(`{"tmux_session":"api","session_id":"abc"}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.SessionID != "abc" {
+		t.Errorf("SessionID = %q, want it decoded from session_id", decoded.SessionID)
+	}
+}
@@ -0,0 +1,226 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToolTrackerHookPairsPreAndPostToolUse(t *testing.T) {
+	if _, err := exec.LookPath("jq"); err != nil {
+		t.Skip("jq is required for tool tracker hook tests")
+	}
+
+	home := t.TempDir()
+	sessionName := resolveNotifySessionName(t, home)
+
+	runToolTrackerHook(t, home, map[string]any{
+		"hook_event_name": "PreToolUse",
+		"tool_name":       "Bash",
+		"tool_use_id":     "toolu_01",
+		"cwd":             "/work/project",
+	})
+	pendingPath := filepath.Join(home, ".claude-sessions", "tool-calls", "pending", sessionName, "toolu_01")
+	if _, err := os.Stat(pendingPath); err != nil {
+		t.Fatalf("expected pending start file: %v", err)
+	}
+
+	runToolTrackerHook(t, home, map[string]any{
+		"hook_event_name": "PostToolUse",
+		"tool_name":       "Bash",
+		"tool_use_id":     "toolu_01",
+		"cwd":             "/work/project",
+		"tool_response":   map[string]any{"interrupted": true},
+	})
+	if _, err := os.Stat(pendingPath); !os.IsNotExist(err) {
+		t.Fatalf("expected pending start file to be removed, stat err = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".claude-sessions", "tool-calls", sessionName+".jsonl"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("call log lines = %d, want 1", len(lines))
+	}
+
+	var call map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &call); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if call["tool"] != "Bash" || call["tool_use_id"] != "toolu_01" || call["cwd"] != "/work/project" {
+		t.Fatalf("unexpected call record: %v", call)
+	}
+	if call["error"] != true {
+		t.Fatalf("error = %v, want true for interrupted tool", call["error"])
+	}
+	if d, ok := call["duration_ms"].(float64); !ok || d < 0 {
+		t.Fatalf("duration_ms = %v, want non-negative number", call["duration_ms"])
+	}
+}
+
+func TestToolTrackerHookPreToolUseDoesNotCount(t *testing.T) {
+	if _, err := exec.LookPath("jq"); err != nil {
+		t.Skip("jq is required for tool tracker hook tests")
+	}
+
+	home := t.TempDir()
+	sessionName := resolveNotifySessionName(t, home)
+
+	runToolTrackerHook(t, home, map[string]any{
+		"hook_event_name": "PreToolUse",
+		"tool_name":       "Read",
+		"tool_use_id":     "toolu_02",
+	})
+
+	statusPath := filepath.Join(home, ".claude-sessions", sessionName+".json")
+	if _, err := os.Stat(statusPath); !os.IsNotExist(err) {
+		t.Fatalf("PreToolUse should not write the status file, stat err = %v", err)
+	}
+}
+
+func TestToolTrackerHookLogsFailureEvent(t *testing.T) {
+	if _, err := exec.LookPath("jq"); err != nil {
+		t.Skip("jq is required for tool tracker hook tests")
+	}
+
+	home := t.TempDir()
+	sessionName := resolveNotifySessionName(t, home)
+
+	runToolTrackerHook(t, home, map[string]any{
+		"hook_event_name": "PreToolUse",
+		"tool_name":       "Bash",
+		"tool_use_id":     "toolu_03",
+	})
+	runToolTrackerHook(t, home, map[string]any{
+		"hook_event_name": "PostToolUseFailure",
+		"tool_name":       "Bash",
+		"tool_use_id":     "toolu_03",
+		"error":           "exit status 1",
+	})
+
+	data, err := os.ReadFile(filepath.Join(home, ".claude-sessions", "tool-calls", sessionName+".jsonl"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var call map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(data), &call); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if call["tool_use_id"] != "toolu_03" || call["error"] != true {
+		t.Fatalf("call record = %v, want a failed toolu_03", call)
+	}
+}
+
+func TestToolTrackerHookPrunesStalePendingFiles(t *testing.T) {
+	if _, err := exec.LookPath("jq"); err != nil {
+		t.Skip("jq is required for tool tracker hook tests")
+	}
+
+	home := t.TempDir()
+	sessionName := resolveNotifySessionName(t, home)
+	pendingDir := filepath.Join(home, ".claude-sessions", "tool-calls", "pending")
+
+	// A call of a session that crashed two days ago never completed
+	staleDir := filepath.Join(pendingDir, "gone")
+	stalePath := filepath.Join(staleDir, "toolu_old")
+	if err := os.MkdirAll(staleDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stalePath, []byte("1000"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(stalePath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	runToolTrackerHook(t, home, map[string]any{
+		"hook_event_name": "PreToolUse",
+		"tool_name":       "Read",
+		"tool_use_id":     "toolu_new",
+	})
+
+	if _, err := os.Stat(staleDir); !os.IsNotExist(err) {
+		t.Errorf("expected stale pending directory to be removed, stat err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(pendingDir, sessionName, "toolu_new")); err != nil {
+		t.Errorf("expected the new start file to be kept: %v", err)
+	}
+}
+
+func TestHookConfigRegistersToolTrackerEvents(t *testing.T) {
+	_, currentFile, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("runtime.Caller() failed")
+	}
+	repoRoot := filepath.Clean(filepath.Join(filepath.Dir(currentFile), "..", ".."))
+
+	data, err := os.ReadFile(filepath.Join(repoRoot, "hooks", "config.json"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var config struct {
+		Hooks map[string][]struct {
+			Hooks []struct {
+				Command string `json:"command"`
+			} `json:"hooks"`
+		} `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	install, err := os.ReadFile(filepath.Join(repoRoot, "install.sh"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	for _, event := range []string{"PreToolUse", "PostToolUse", "PostToolUseFailure"} {
+		registered := false
+		for _, matcher := range config.Hooks[event] {
+			for _, hook := range matcher.Hooks {
+				if strings.HasSuffix(hook.Command, "/tool-tracker.sh") {
+					registered = true
+				}
+			}
+		}
+		if !registered {
+			t.Errorf("hooks/config.json does not run tool-tracker.sh on %s", event)
+		}
+		if !strings.Contains(string(install), `"`+event+`"`) {
+			t.Errorf("install.sh HOOK_TYPES is missing %s", event)
+		}
+	}
+}
+
+func runToolTrackerHook(t *testing.T, home string, payload map[string]any) {
+	t.Helper()
+
+	_, currentFile, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("runtime.Caller() failed")
+	}
+	repoRoot := filepath.Clean(filepath.Join(filepath.Dir(currentFile), "..", ".."))
+	scriptPath := filepath.Join(repoRoot, "hooks", "tool-tracker.sh")
+
+	stdinPayload, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	cmd := exec.Command("bash", scriptPath)
+	cmd.Dir = repoRoot
+	cmd.Env = append(os.Environ(), "HOME="+home)
+	cmd.Stdin = bytes.NewReader(stdinPayload)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("tool-tracker.sh failed: %v, output: %s", err, string(out))
+	}
+}
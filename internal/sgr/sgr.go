@@ -0,0 +1,169 @@
+// Package sgr filters terminal output down to SGR escape sequences, the
+// colors and text attributes, so captured panes can be shown with their
+// colors but without cursor movement, titles or other control codes.
+package sgr
+
+import (
+	"strings"
+)
+
+// Reset clears every SGR attribute.
+const Reset = "\x1b[0m"
+
+const (
+	esc = 0x1b
+	bel = 0x07
+)
+
+// Sanitize keeps SGR sequences, newlines and tabs and drops every other
+// escape sequence and control character.
+func Sanitize(s string) string {
+	return filter(s, true)
+}
+
+// Strip drops every escape sequence and control character except newlines
+// and tabs, leaving only the visible text.
+func Strip(s string) string {
+	return filter(s, false)
+}
+
+// filter scans s once, copying text and, when keepSGR is set, SGR sequences.
+func filter(s string, keepSGR bool) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == esc:
+			n, isSGR := sequenceLen(s[i:])
+			if keepSGR && isSGR {
+				b.WriteString(s[i : i+n])
+			}
+			i += n
+		case c == '\n' || c == '\t':
+			b.WriteByte(c)
+			i++
+		case c < 0x20 || c == 0x7f:
+			i++
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// sequenceLen returns the length of the escape sequence at the start of s
+// and whether it is an SGR sequence. Unterminated sequences run to the end
+// of s.
+func sequenceLen(s string) (n int, isSGR bool) {
+	if len(s) < 2 {
+		return len(s), false
+	}
+
+	switch s[1] {
+	case '[':
+		// CSI: parameter bytes 0x30-0x3f, intermediate bytes 0x20-0x2f, final byte 0x40-0x7e
+		plain := true
+		for i := 2; i < len(s); i++ {
+			c := s[i]
+			switch {
+			case c >= 0x40 && c <= 0x7e:
+				return i + 1, c == 'm' && plain
+			case c >= 0x30 && c <= 0x3f:
+				if c != ';' && c != ':' && (c < '0' || c > '9') {
+					plain = false // private parameters such as "?"
+				}
+			case c >= 0x20 && c <= 0x2f:
+				plain = false
+			default:
+				return i, false
+			}
+		}
+		return len(s), false
+
+	case ']', 'P', '_', '^', 'X':
+		// OSC, DCS, APC, PM, SOS: terminated by BEL or ST (ESC \)
+		for i := 2; i < len(s); i++ {
+			if s[i] == bel {
+				return i + 1, false
+			}
+			if s[i] == esc && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2, false
+			}
+		}
+		return len(s), false
+
+	default:
+		// Two-byte escape such as ESC 7 or ESC =, or ESC followed by
+		// intermediate bytes and a final byte (character set selection)
+		i := 1
+		for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+			i++
+		}
+		if i < len(s) {
+			i++
+		}
+		return i, false
+	}
+}
+
+// Lines splits s into lines that each carry their own SGR state. A line
+// starts by re-applying the attributes left active by earlier lines and ends
+// with a reset, so colors never bleed into borders or the next line and
+// each line can be truncated or scrolled on its own. s must be sanitized.
+func Lines(s string) []string {
+	lines := strings.Split(s, "\n")
+	var active []string
+	for i, line := range lines {
+		prefix := strings.Join(active, "")
+		active = track(active, line)
+		if prefix == "" && !strings.Contains(line, "\x1b[") {
+			continue
+		}
+		lines[i] = prefix + line + Reset
+	}
+	return lines
+}
+
+// track updates the active SGR sequences with those in line. A reset
+// clears the state; anything else is appended.
+func track(active []string, line string) []string {
+	for i := 0; i < len(line); {
+		if line[i] != esc {
+			i++
+			continue
+		}
+		n, isSGR := sequenceLen(line[i:])
+		if isSGR {
+			seq := line[i : i+n]
+			params := seq[2 : len(seq)-1]
+			switch {
+			case params == "" || params == "0":
+				active = active[:0]
+			case strings.HasPrefix(params, "0;"):
+				active = append(active[:0], "\x1b["+params[2:]+"m")
+			default:
+				active = append(active, seq)
+			}
+		}
+		i += n
+	}
+	return active
+}
+
+// TrimRight drops trailing lines that are blank once escape sequences are
+// removed, and trailing blanks on the last remaining line.
+func TrimRight(s string) string {
+	lines := strings.Split(s, "\n")
+	for len(lines) > 0 && strings.TrimSpace(Strip(lines[len(lines)-1])) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	last := len(lines) - 1
+	lines[last] = strings.TrimRight(lines[last], " \t")
+	return strings.Join(lines, "\n")
+}
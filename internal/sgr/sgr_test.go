@@ -0,0 +1,88 @@
+package sgr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"color codes", "\x1b[31mred text\x1b[0m", "red text"},
+		{"multiple color codes", "\x1b[1;31mbold red\x1b[0m normal \x1b[32mgreen\x1b[0m", "bold red normal green"},
+		{"cursor movement", "\x1b[2Amove up\x1b[3Bmove down", "move upmove down"},
+		{"OSC with BEL", "\x1b]0;Window Title\x07some text", "some text"},
+		{"OSC with ST", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"private CSI", "\x1b[?25lhidden cursor\x1b[?25h", "hidden cursor"},
+		{"charset selection", "\x1b(Bplain", "plain"},
+		{"empty", "", ""},
+		{"newlines and tabs", "line1\nline2\tindented", "line1\nline2\tindented"},
+		{"control characters", "text\x00with\x1fcontrol\x08chars\r", "textwithcontrolchars"},
+		{"unterminated sequence", "text\x1b[31", "text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Strip(tt.input); got != tt.want {
+				t.Errorf("Strip(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"keeps SGR", "\x1b[1;31mbold red\x1b[0m", "\x1b[1;31mbold red\x1b[0m"},
+		{"keeps 256 and true color", "\x1b[38;5;208mx\x1b[38:2::1:2:3my", "\x1b[38;5;208mx\x1b[38:2::1:2:3my"},
+		{"drops cursor movement", "\x1b[2A\x1b[32mok\x1b[K", "\x1b[32mok"},
+		{"drops OSC", "\x1b]0;title\x07\x1b[32mok", "\x1b[32mok"},
+		{"drops private CSI ending in m", "\x1b[>4;2mtext", "text"},
+		{"drops control characters", "a\x07b\rc", "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.input); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	input := "plain\n\x1b[31mred starts\nstill red\n\x1b[1mbold too\x1b[0m done\nplain again\n\x1b[0;32mgreen"
+	want := []string{
+		"plain",
+		"\x1b[31mred starts" + Reset,
+		"\x1b[31mstill red" + Reset,
+		"\x1b[31m\x1b[1mbold too\x1b[0m done" + Reset,
+		"plain again",
+		"\x1b[0;32mgreen" + Reset,
+	}
+	if got := Lines(input); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines =\n%q\nwant\n%q", got, want)
+	}
+
+	// A combined reset keeps only the attributes after it.
+	got := Lines("\x1b[1m\x1b[0;32mgreen\nnext")
+	if got[1] != "\x1b[32mnext"+Reset {
+		t.Errorf("line after combined reset = %q", got[1])
+	}
+}
+
+func TestTrimRight(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"", ""},
+		{"some output\n\n\n   \n", "some output"},
+		{"\x1b[32m✓ Done\x1b[0m\n\x1b[0m\n  \n", "\x1b[32m✓ Done\x1b[0m"},
+		{"keep\n\ninner blank  ", "keep\n\ninner blank"},
+	}
+	for _, tt := range tests {
+		if got := TrimRight(tt.input); got != tt.want {
+			t.Errorf("TrimRight(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -25,13 +25,7 @@ func FindProjectConfig(dir string) (*ProjectConfig, error) {
 			if err != nil {
 				return nil, err
 			}
-
-			var cfg ProjectConfig
-			if err := yaml.Unmarshal(data, &cfg); err != nil {
-				return nil, err
-			}
-			cfg.ProjectDir = dir
-			return &cfg, nil
+			return ParseProjectConfig(data, dir)
 		}
 
 		parent := filepath.Dir(dir)
@@ -43,6 +37,16 @@ func FindProjectConfig(dir string) (*ProjectConfig, error) {
 	}
 }
 
+// ParseProjectConfig parses .navi.yaml contents found in dir.
+func ParseProjectConfig(data []byte, dir string) (*ProjectConfig, error) {
+	var cfg ProjectConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	cfg.ProjectDir = dir
+	return &cfg, nil
+}
+
 // LoadGlobalConfig loads from ~/.navi/config.yaml.
 // Returns a default GlobalConfig if the file doesn't exist (not an error).
 func LoadGlobalConfig() (*GlobalConfig, error) {
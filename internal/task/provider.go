@@ -51,6 +51,16 @@ func (e *ProviderError) Error() string {
 	return e.Message
 }
 
+// BuiltinProviderPath returns the bundled script path for a built-in provider
+// name, and false if name is not a built-in.
+func BuiltinProviderPath(name string) (string, bool) {
+	filename, ok := builtinProviders[name]
+	if !ok {
+		return "", false
+	}
+	return filepath.Join(ProvidersDir, filename), true
+}
+
 // ResolveProvider resolves a provider name to an executable path.
 // Built-in names ("github-issues", "markdown-tasks") resolve to bundled script paths under ProvidersDir.
 // Relative paths are resolved relative to the project directory.
@@ -104,7 +114,7 @@ func ExecuteProvider(config ProjectConfig, timeout time.Duration) (*ProviderResu
 
 	cmd := exec.CommandContext(ctx, scriptPath)
 	cmd.Dir = config.ProjectDir
-	cmd.Env = append(os.Environ(), buildEnvVars(config.Tasks.Args)...)
+	cmd.Env = append(os.Environ(), BuildEnvVars(config.Tasks.Args)...)
 	// WaitDelay ensures child processes are killed after context cancellation.
 	cmd.WaitDelay = time.Second
 
@@ -139,8 +149,8 @@ func ExecuteProvider(config ProjectConfig, timeout time.Duration) (*ProviderResu
 	return result, nil
 }
 
-// buildEnvVars converts config args to NAVI_TASK_ARG_ environment variables.
-func buildEnvVars(args map[string]string) []string {
+// BuildEnvVars converts config args to NAVI_TASK_ARG_ environment variables.
+func BuildEnvVars(args map[string]string) []string {
 	if len(args) == 0 {
 		return nil
 	}
@@ -58,7 +58,7 @@ func TestBuildEnvVars(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := buildEnvVars(tt.args)
+			result := BuildEnvVars(tt.args)
 			if tt.want == nil {
 				if result != nil {
 					t.Errorf("expected nil, got %v", result)
@@ -64,6 +64,22 @@ type ProjectConfig struct {
 	Tasks ProjectTaskConfig `yaml:"tasks"`
 	// ProjectDir is the directory where .navi.yaml was found (set during discovery, not from YAML).
 	ProjectDir string `yaml:"-"`
+	// Remote is the remote host the project lives on; empty for local projects.
+	Remote string `yaml:"-"`
+}
+
+// Key returns the key identifying the project in caches and result maps.
+func (c ProjectConfig) Key() string {
+	return ProjectKey(c.Remote, c.ProjectDir)
+}
+
+// ProjectKey identifies a project directory on a host. Local projects are
+// keyed by their directory; remote ones by "<remote>:<dir>".
+func ProjectKey(remote, dir string) string {
+	if remote == "" {
+		return dir
+	}
+	return remote + ":" + dir
 }
 
 // ProjectTaskConfig holds task-specific settings from .navi.yaml.
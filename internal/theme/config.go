@@ -0,0 +1,146 @@
+package theme
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/stwalsh4118/navi/internal/pathutil"
+)
+
+// DefaultConfigPath is the default path for theme configuration.
+const DefaultConfigPath = "~/.config/navi/theme.yaml"
+
+// Config selects a theme and defines user themes, as written in theme.yaml:
+//
+//	theme: solarized
+//	themes:
+//	  solarized:
+//	    base: light
+//	    colors:
+//	      working: "#268bd2"
+type Config struct {
+	Theme  string                `yaml:"theme"`
+	Themes map[string]Definition `yaml:"themes"`
+}
+
+// Definition is a user theme. Colors left empty are taken from the base
+// theme, which defaults to DefaultTheme.
+type Definition struct {
+	Base     string  `yaml:"base"`
+	Border   string  `yaml:"border"`
+	Colors   Palette `yaml:"colors"`
+	Colors16 Palette `yaml:"colors16"`
+}
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// LoadConfig reads the theme configuration from YAML. Missing files return
+// an empty config, which resolves to the default theme.
+func LoadConfig(path string) (*Config, error) {
+	configPath := path
+	if configPath == "" {
+		configPath = DefaultConfigPath
+	}
+
+	data, err := os.ReadFile(pathutil.ExpandPath(configPath))
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse theme config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Load reads the theme configuration at path and resolves the selected theme.
+func Load(path string) (*Theme, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Resolve()
+}
+
+// Resolve returns the selected theme. User themes may shadow built-ins and
+// may be based on a built-in theme, but not on another user theme.
+func (c *Config) Resolve() (*Theme, error) {
+	name := c.Theme
+	if name == "" {
+		name = DefaultTheme
+	}
+
+	def, ok := c.Themes[name]
+	if !ok {
+		t, ok := Builtin(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown theme %q (built-in themes: %s)", name, strings.Join(BuiltinNames(), ", "))
+		}
+		return t, nil
+	}
+
+	baseName := def.Base
+	if baseName == "" {
+		baseName = DefaultTheme
+	}
+	base, ok := Builtin(baseName)
+	if !ok {
+		return nil, fmt.Errorf("theme %q: unknown base theme %q", name, baseName)
+	}
+
+	t := &Theme{
+		Name:     name,
+		Border:   base.Border,
+		Colors:   base.Colors.merge(def.Colors),
+		Colors16: base.Colors16.merge(def.Colors16),
+	}
+	if def.Border != "" {
+		t.Border = def.Border
+	}
+	if err := t.validate(); err != nil {
+		return nil, fmt.Errorf("theme %q: %w", name, err)
+	}
+	return t, nil
+}
+
+// validate checks the border name and every color of both palettes.
+func (t *Theme) validate() error {
+	switch t.Border {
+	case BorderRounded, BorderNormal, BorderThick, BorderDouble, BorderHidden:
+	default:
+		return fmt.Errorf("unknown border %q", t.Border)
+	}
+	for _, p := range []struct {
+		name     string
+		palette  Palette
+		maxIndex int
+	}{{"colors", t.Colors, 255}, {"colors16", t.Colors16, 15}} {
+		for _, r := range p.palette.roles() {
+			if !validColor(r, p.maxIndex) {
+				return fmt.Errorf("%s.%s: invalid color %q", p.name, r.name, *r.color)
+			}
+		}
+	}
+	return nil
+}
+
+// validColor accepts an ANSI index up to maxIndex or a hex color, and
+// SelectionReverse for the selection. Hex colors are allowed in colors16 too;
+// lipgloss maps them to the nearest ANSI color.
+func validColor(r role, maxIndex int) bool {
+	value := *r.color
+	if hexColorPattern.MatchString(value) || (r.name == "selection" && value == SelectionReverse) {
+		return true
+	}
+	n, err := strconv.Atoi(value)
+	return err == nil && n >= 0 && n <= maxIndex
+}
@@ -0,0 +1,179 @@
+package theme
+
+import "sort"
+
+// Built-in theme names
+const (
+	Dark         = "dark"
+	Light        = "light"
+	HighContrast = "high-contrast"
+)
+
+// DefaultTheme is used when no theme is configured.
+const DefaultTheme = Dark
+
+// SelectionReverse as the selection color highlights the selected row in
+// reverse video instead of a background color.
+const SelectionReverse = "reverse"
+
+// Border names accepted in theme definitions
+const (
+	BorderRounded = "rounded"
+	BorderNormal  = "normal"
+	BorderThick   = "thick"
+	BorderDouble  = "double"
+	BorderHidden  = "hidden"
+)
+
+// Mode is the color capability a palette is chosen for.
+type Mode int
+
+// Color modes
+const (
+	ModeFull    Mode = iota // 256 colors or true color
+	Mode16                  // Basic 16 ANSI colors
+	ModeNoColor             // NO_COLOR or no color support; bold and reverse only
+)
+
+// Palette holds the colors for each UI role. Values are lipgloss colors: an
+// ANSI index ("0"-"255") or a hex string ("#rrggbb").
+type Palette struct {
+	Waiting    string `yaml:"waiting"`
+	Done       string `yaml:"done"`
+	Permission string `yaml:"permission"`
+	Working    string `yaml:"working"`
+	Error      string `yaml:"error"`
+	Offline    string `yaml:"offline"`
+	Muted      string `yaml:"muted"`     // Secondary text, empty states, metadata
+	Accent     string `yaml:"accent"`    // Dialog borders and titles, focused inputs, active filters
+	Border     string `yaml:"border"`    // Unfocused box borders
+	Focus      string `yaml:"focus"`     // Focused pane borders
+	Selection  string `yaml:"selection"` // Selected row background, or SelectionReverse
+	Match      string `yaml:"match"`     // Search match gutter
+	DiffAdd    string `yaml:"diff_add"`
+	DiffRemove string `yaml:"diff_remove"`
+	DiffHunk   string `yaml:"diff_hunk"`
+	DiffMeta   string `yaml:"diff_meta"`
+}
+
+// role is a palette color and its YAML name.
+type role struct {
+	name  string
+	color *string
+}
+
+// roles lists every color of p with its YAML name.
+func (p *Palette) roles() []role {
+	return []role{
+		{"waiting", &p.Waiting}, {"done", &p.Done}, {"permission", &p.Permission},
+		{"working", &p.Working}, {"error", &p.Error}, {"offline", &p.Offline},
+		{"muted", &p.Muted}, {"accent", &p.Accent}, {"border", &p.Border},
+		{"focus", &p.Focus}, {"selection", &p.Selection}, {"match", &p.Match},
+		{"diff_add", &p.DiffAdd}, {"diff_remove", &p.DiffRemove},
+		{"diff_hunk", &p.DiffHunk}, {"diff_meta", &p.DiffMeta},
+	}
+}
+
+// merge returns p with every non-empty color of override applied.
+func (p Palette) merge(override Palette) Palette {
+	merged := p
+	dst, src := merged.roles(), override.roles()
+	for i := range dst {
+		if *src[i].color != "" {
+			*dst[i].color = *src[i].color
+		}
+	}
+	return merged
+}
+
+// Theme is a resolved set of palettes and a border shape.
+type Theme struct {
+	Name     string
+	Border   string
+	Colors   Palette // Used on 256-color and true color terminals
+	Colors16 Palette // Used on 16-color terminals
+}
+
+// Palette returns the colors to use in mode. ModeNoColor yields an empty
+// palette that selects rows in reverse video.
+func (t *Theme) Palette(mode Mode) Palette {
+	switch mode {
+	case Mode16:
+		return t.Colors16
+	case ModeNoColor:
+		return Palette{Selection: SelectionReverse}
+	}
+	return t.Colors
+}
+
+// builtins are the themes shipped with navi. Dark matches the original
+// palette.
+var builtins = map[string]Theme{
+	Dark: {
+		Name:   Dark,
+		Border: BorderRounded,
+		Colors: Palette{
+			Waiting: "226", Done: "46", Permission: "201", Working: "51", Error: "196", Offline: "245",
+			Muted: "241", Accent: "99", Border: "240", Focus: "46", Selection: "236", Match: "226",
+			DiffAdd: "46", DiffRemove: "196", DiffHunk: "51", DiffMeta: "241",
+		},
+		Colors16: Palette{
+			Waiting: "11", Done: "10", Permission: "13", Working: "14", Error: "9", Offline: "7",
+			Muted: "8", Accent: "12", Border: "8", Focus: "10", Selection: SelectionReverse, Match: "11",
+			DiffAdd: "2", DiffRemove: "1", DiffHunk: "6", DiffMeta: "8",
+		},
+	},
+	Light: {
+		Name:   Light,
+		Border: BorderRounded,
+		Colors: Palette{
+			Waiting: "130", Done: "28", Permission: "127", Working: "25", Error: "160", Offline: "244",
+			Muted: "243", Accent: "56", Border: "250", Focus: "28", Selection: "254", Match: "166",
+			DiffAdd: "28", DiffRemove: "160", DiffHunk: "25", DiffMeta: "243",
+		},
+		Colors16: Palette{
+			Waiting: "3", Done: "2", Permission: "5", Working: "4", Error: "1", Offline: "8",
+			Muted: "8", Accent: "5", Border: "8", Focus: "2", Selection: SelectionReverse, Match: "3",
+			DiffAdd: "2", DiffRemove: "1", DiffHunk: "4", DiffMeta: "8",
+		},
+	},
+	HighContrast: {
+		Name:   HighContrast,
+		Border: BorderThick,
+		Colors: Palette{
+			Waiting: "226", Done: "46", Permission: "207", Working: "51", Error: "196", Offline: "252",
+			Muted: "250", Accent: "231", Border: "252", Focus: "46", Selection: SelectionReverse, Match: "226",
+			DiffAdd: "46", DiffRemove: "196", DiffHunk: "51", DiffMeta: "250",
+		},
+		Colors16: Palette{
+			Waiting: "11", Done: "10", Permission: "13", Working: "14", Error: "9", Offline: "15",
+			Muted: "7", Accent: "15", Border: "15", Focus: "10", Selection: SelectionReverse, Match: "11",
+			DiffAdd: "10", DiffRemove: "9", DiffHunk: "14", DiffMeta: "7",
+		},
+	},
+}
+
+// Default returns the default theme.
+func Default() *Theme {
+	t, _ := Builtin(DefaultTheme)
+	return t
+}
+
+// Builtin returns a copy of the named built-in theme.
+func Builtin(name string) (*Theme, bool) {
+	t, ok := builtins[name]
+	if !ok {
+		return nil, false
+	}
+	return &t, true
+}
+
+// BuiltinNames returns the built-in theme names in sorted order.
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,163 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinsAreValid(t *testing.T) {
+	for _, name := range BuiltinNames() {
+		th, ok := Builtin(name)
+		if !ok {
+			t.Fatalf("Builtin(%q) not found", name)
+		}
+		if err := th.validate(); err != nil {
+			t.Errorf("built-in %s: %v", name, err)
+		}
+		for _, r := range th.Colors.roles() {
+			if *r.color == "" {
+				t.Errorf("built-in %s: colors.%s is empty", name, r.name)
+			}
+		}
+		for _, r := range th.Colors16.roles() {
+			if *r.color == "" {
+				t.Errorf("built-in %s: colors16.%s is empty", name, r.name)
+			}
+		}
+	}
+}
+
+func TestPaletteForMode(t *testing.T) {
+	th := Default()
+	if got := th.Palette(ModeFull).Working; got != "51" {
+		t.Errorf("full Working = %q, want 51", got)
+	}
+	if got := th.Palette(Mode16).Working; got != "14" {
+		t.Errorf("16-color Working = %q, want 14", got)
+	}
+	noColor := th.Palette(ModeNoColor)
+	if noColor.Working != "" || noColor.Selection != SelectionReverse {
+		t.Errorf("no-color palette = %+v, want empty colors and reverse selection", noColor)
+	}
+}
+
+func TestResolveBuiltin(t *testing.T) {
+	th, err := (&Config{}).Resolve()
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	if th.Name != DefaultTheme {
+		t.Errorf("Name = %q, want %q", th.Name, DefaultTheme)
+	}
+
+	th, err = (&Config{Theme: Light}).Resolve()
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	if th.Name != Light {
+		t.Errorf("Name = %q, want %q", th.Name, Light)
+	}
+
+	if _, err := (&Config{Theme: "neon"}).Resolve(); err == nil || !strings.Contains(err.Error(), "high-contrast") {
+		t.Errorf("error = %v, want unknown theme listing built-ins", err)
+	}
+}
+
+func TestResolveUserTheme(t *testing.T) {
+	cfg := &Config{
+		Theme: "mine",
+		Themes: map[string]Definition{
+			"mine": {
+				Base:     Light,
+				Border:   BorderNormal,
+				Colors:   Palette{Working: "#268bd2", Selection: SelectionReverse},
+				Colors16: Palette{Working: "6"},
+			},
+		},
+	}
+	th, err := cfg.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	light, _ := Builtin(Light)
+	if th.Name != "mine" || th.Border != BorderNormal {
+		t.Errorf("theme = %q border %q", th.Name, th.Border)
+	}
+	if th.Colors.Working != "#268bd2" || th.Colors.Selection != SelectionReverse {
+		t.Errorf("overrides not applied: %+v", th.Colors)
+	}
+	if th.Colors.Done != light.Colors.Done || th.Colors16.Done != light.Colors16.Done {
+		t.Error("unset colors should come from the base theme")
+	}
+	if th.Colors16.Working != "6" {
+		t.Errorf("colors16 Working = %q, want 6", th.Colors16.Working)
+	}
+}
+
+func TestResolveInvalidUserTheme(t *testing.T) {
+	tests := []struct {
+		name string
+		def  Definition
+		want string
+	}{
+		{"unknown base", Definition{Base: "mine"}, `unknown base theme "mine"`},
+		{"bad border", Definition{Border: "wavy"}, `unknown border "wavy"`},
+		{"bad color", Definition{Colors: Palette{Error: "red"}}, `colors.error: invalid color "red"`},
+		{"16-color index", Definition{Colors16: Palette{Done: "46"}}, `colors16.done: invalid color "46"`},
+		{"reverse outside selection", Definition{Colors: Palette{Match: SelectionReverse}}, `colors.match`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Theme: "mine", Themes: map[string]Definition{"mine": tt.def}}
+			_, err := cfg.Resolve()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.yaml")
+	content := strings.Join([]string{
+		"theme: solarized",
+		"themes:",
+		"  solarized:",
+		"    base: light",
+		"    colors:",
+		`      diff_add: "#859900"`,
+	}, "\n")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	th, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if th.Name != "solarized" || th.Colors.DiffAdd != "#859900" {
+		t.Errorf("theme = %q, diff_add = %q", th.Name, th.Colors.DiffAdd)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	th, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if th.Name != DefaultTheme {
+		t.Errorf("Name = %q, want %q", th.Name, DefaultTheme)
+	}
+}
+
+func TestLoadMalformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.yaml")
+	if err := os.WriteFile(path, []byte("theme: [dark"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected parse error")
+	}
+}
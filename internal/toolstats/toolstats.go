@@ -0,0 +1,208 @@
+package toolstats
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CallLogDir is the subdirectory of the session status directory holding
+// per-session tool call logs written by the tool-tracker hook.
+const CallLogDir = "tool-calls"
+
+// callLogExt is the file extension of a per-session tool call log.
+const callLogExt = ".jsonl"
+
+// Call is a single completed tool invocation, paired from PreToolUse/PostToolUse.
+type Call struct {
+	Tool       string `json:"tool"`
+	ToolUseID  string `json:"tool_use_id"`
+	Session    string `json:"session"`
+	CWD        string `json:"cwd"`
+	Timestamp  int64  `json:"ts"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      bool   `json:"error"`
+}
+
+// Stats aggregates timing and outcome for one tool.
+type Stats struct {
+	Tool    string `json:"tool"`
+	Calls   int    `json:"calls"`
+	Errors  int    `json:"errors"`
+	TotalMs int64  `json:"total_ms"`
+	MaxMs   int64  `json:"max_ms"`
+}
+
+// AvgMs returns the mean call duration in milliseconds.
+func (s Stats) AvgMs() int64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.TotalMs / int64(s.Calls)
+}
+
+// FailureRate returns the fraction of calls that failed (0-1).
+func (s Stats) FailureRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Calls)
+}
+
+// Summary is the combined view of a set of calls.
+type Summary struct {
+	Calls   int     `json:"calls"`
+	Errors  int     `json:"errors"`
+	TotalMs int64   `json:"total_ms"`
+	Tools   []Stats `json:"tools"`
+}
+
+// FailureRate returns the fraction of all calls that failed (0-1).
+func (s Summary) FailureRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Calls)
+}
+
+// Share returns the fraction of all calls made with the given tool (0-1).
+func (s Summary) Share(tool string) float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	for _, t := range s.Tools {
+		if t.Tool == tool {
+			return float64(t.Calls) / float64(s.Calls)
+		}
+	}
+	return 0
+}
+
+// LogDir returns the tool call log directory for a status directory.
+func LogDir(statusDir string) string {
+	return filepath.Join(statusDir, CallLogDir)
+}
+
+// ReadSessionCalls reads the tool call log for a single session.
+// A missing log yields no calls and no error.
+func ReadSessionCalls(statusDir, sessionName string) ([]Call, error) {
+	calls, err := readCallLog(filepath.Join(LogDir(statusDir), sessionName+callLogExt))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return calls, err
+}
+
+// ReadAllCalls reads the tool call logs of every session in statusDir.
+// Unreadable logs are skipped.
+func ReadAllCalls(statusDir string) ([]Call, error) {
+	matches, err := filepath.Glob(filepath.Join(LogDir(statusDir), "*"+callLogExt))
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []Call
+	for _, path := range matches {
+		sessionCalls, err := readCallLog(path)
+		if err != nil {
+			continue
+		}
+		calls = append(calls, sessionCalls...)
+	}
+	return calls, nil
+}
+
+// readCallLog parses a JSONL call log, skipping malformed lines.
+func readCallLog(path string) ([]Call, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var calls []Call
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var c Call
+		if err := json.Unmarshal([]byte(line), &c); err != nil || c.Tool == "" {
+			continue
+		}
+		calls = append(calls, c)
+	}
+	return calls, scanner.Err()
+}
+
+// Summarize aggregates calls per tool. Tools are sorted slowest first by
+// average duration, then by name.
+func Summarize(calls []Call) Summary {
+	byTool := make(map[string]*Stats)
+	var summary Summary
+	for _, c := range calls {
+		st, ok := byTool[c.Tool]
+		if !ok {
+			st = &Stats{Tool: c.Tool}
+			byTool[c.Tool] = st
+		}
+		st.Calls++
+		st.TotalMs += c.DurationMs
+		if c.DurationMs > st.MaxMs {
+			st.MaxMs = c.DurationMs
+		}
+		summary.Calls++
+		summary.TotalMs += c.DurationMs
+		if c.Error {
+			st.Errors++
+			summary.Errors++
+		}
+	}
+
+	summary.Tools = make([]Stats, 0, len(byTool))
+	for _, st := range byTool {
+		summary.Tools = append(summary.Tools, *st)
+	}
+	sort.Slice(summary.Tools, func(i, j int) bool {
+		a, b := summary.Tools[i], summary.Tools[j]
+		if a.AvgMs() != b.AvgMs() {
+			return a.AvgMs() > b.AvgMs()
+		}
+		return a.Tool < b.Tool
+	})
+	return summary
+}
+
+// FilterProject returns the calls made in dir or any of its subdirectories.
+func FilterProject(calls []Call, dir string) []Call {
+	dir = filepath.Clean(dir)
+	var out []Call
+	for _, c := range calls {
+		if c.CWD == "" {
+			continue
+		}
+		cwd := filepath.Clean(c.CWD)
+		if cwd == dir || strings.HasPrefix(cwd, dir+string(filepath.Separator)) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// GroupByProject groups calls by their working directory.
+func GroupByProject(calls []Call) map[string][]Call {
+	groups := make(map[string][]Call)
+	for _, c := range calls {
+		if c.CWD == "" {
+			continue
+		}
+		key := filepath.Clean(c.CWD)
+		groups[key] = append(groups[key], c)
+	}
+	return groups
+}
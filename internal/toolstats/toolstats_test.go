@@ -0,0 +1,135 @@
+package toolstats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeCallLog(t *testing.T, statusDir, sessionName, content string) {
+	t.Helper()
+	dir := LogDir(statusDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, sessionName+callLogExt), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadSessionCalls(t *testing.T) {
+	dir := t.TempDir()
+	writeCallLog(t, dir, "api",
+		`{"tool":"Bash","tool_use_id":"t1","session":"api","cwd":"/p","ts":1,"duration_ms":1200,"error":false}
+not json
+{"tool":"Edit","tool_use_id":"t2","session":"api","cwd":"/p","ts":2,"duration_ms":40,"error":true}
+
+`)
+
+	calls, err := ReadSessionCalls(dir, "api")
+	if err != nil {
+		t.Fatalf("ReadSessionCalls() error: %v", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("len(calls) = %d, want 2 (malformed lines skipped)", len(calls))
+	}
+	if calls[0].Tool != "Bash" || calls[0].DurationMs != 1200 || calls[0].Error {
+		t.Errorf("unexpected first call: %+v", calls[0])
+	}
+	if !calls[1].Error {
+		t.Error("expected second call to be an error")
+	}
+}
+
+func TestReadSessionCalls_MissingLog(t *testing.T) {
+	calls, err := ReadSessionCalls(t.TempDir(), "missing")
+	if err != nil {
+		t.Fatalf("ReadSessionCalls() error: %v", err)
+	}
+	if calls != nil {
+		t.Errorf("calls = %v, want nil", calls)
+	}
+}
+
+func TestReadAllCalls(t *testing.T) {
+	dir := t.TempDir()
+	writeCallLog(t, dir, "a", `{"tool":"Bash","session":"a","duration_ms":10}`+"\n")
+	writeCallLog(t, dir, "b", `{"tool":"Read","session":"b","duration_ms":5}`+"\n")
+
+	calls, err := ReadAllCalls(dir)
+	if err != nil {
+		t.Fatalf("ReadAllCalls() error: %v", err)
+	}
+	if len(calls) != 2 {
+		t.Errorf("len(calls) = %d, want 2", len(calls))
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	calls := []Call{
+		{Tool: "Bash", DurationMs: 1000},
+		{Tool: "Bash", DurationMs: 3000, Error: true},
+		{Tool: "Edit", DurationMs: 50},
+		{Tool: "Edit", DurationMs: 150},
+	}
+
+	summary := Summarize(calls)
+	if summary.Calls != 4 || summary.Errors != 1 || summary.TotalMs != 4200 {
+		t.Errorf("unexpected summary totals: %+v", summary)
+	}
+	if len(summary.Tools) != 2 {
+		t.Fatalf("len(Tools) = %d, want 2", len(summary.Tools))
+	}
+
+	bash := summary.Tools[0]
+	if bash.Tool != "Bash" {
+		t.Fatalf("slowest tool = %q, want Bash", bash.Tool)
+	}
+	if bash.AvgMs() != 2000 || bash.MaxMs != 3000 || bash.FailureRate() != 0.5 {
+		t.Errorf("unexpected Bash stats: %+v", bash)
+	}
+	if got := summary.Share("Edit"); got != 0.5 {
+		t.Errorf("Share(Edit) = %v, want 0.5", got)
+	}
+	if got := summary.Share("Read"); got != 0 {
+		t.Errorf("Share(Read) = %v, want 0", got)
+	}
+	if got := summary.FailureRate(); got != 0.25 {
+		t.Errorf("FailureRate() = %v, want 0.25", got)
+	}
+}
+
+func TestSummarize_Empty(t *testing.T) {
+	summary := Summarize(nil)
+	if summary.Calls != 0 || len(summary.Tools) != 0 || summary.FailureRate() != 0 {
+		t.Errorf("unexpected empty summary: %+v", summary)
+	}
+}
+
+func TestFilterProject(t *testing.T) {
+	calls := []Call{
+		{Tool: "Bash", CWD: "/home/u/navi"},
+		{Tool: "Bash", CWD: "/home/u/navi/internal"},
+		{Tool: "Bash", CWD: "/home/u/navigator"},
+		{Tool: "Bash", CWD: ""},
+	}
+
+	got := FilterProject(calls, "/home/u/navi/")
+	if len(got) != 2 {
+		t.Errorf("len(FilterProject) = %d, want 2", len(got))
+	}
+}
+
+func TestGroupByProject(t *testing.T) {
+	calls := []Call{
+		{Tool: "Bash", CWD: "/a"},
+		{Tool: "Read", CWD: "/a/"},
+		{Tool: "Edit", CWD: "/b"},
+		{Tool: "Edit"},
+	}
+
+	groups := GroupByProject(calls)
+	if len(groups) != 2 || len(groups["/a"]) != 2 || len(groups["/b"]) != 1 {
+		t.Errorf("unexpected groups: %v", groups)
+	}
+}
@@ -232,8 +232,8 @@ func TestRemoteSessionsMsgTriggersStatusChangeNotification(t *testing.T) {
 			{TmuxSession: "remote-1", Status: "working", Remote: "devbox"},
 		},
 		lastSessionStates: map[string]string{
-			"local":    "working",
-			"remote-1": "working",
+			"local":           "working",
+			"remote-1@devbox": "working",
 		},
 		audioNotifyFn: func(sessionName, status string) {
 			calls = append(calls, [2]string{sessionName, status})
@@ -246,10 +246,10 @@ func TestRemoteSessionsMsgTriggersStatusChangeNotification(t *testing.T) {
 	if len(calls) != 1 {
 		t.Fatalf("expected one notification from remoteSessionsMsg, got %d", len(calls))
 	}
-	if calls[0] != ([2]string{"remote-1", "error"}) {
+	if calls[0] != ([2]string{"remote-1@devbox", "error"}) {
 		t.Fatalf("unexpected notification payload: %#v", calls[0])
 	}
-	if got := newModel.lastSessionStates["remote-1"]; got != "error" {
+	if got := newModel.lastSessionStates["remote-1@devbox"]; got != "error" {
 		t.Fatalf("expected updated remote state, got %q", got)
 	}
 }
@@ -313,3 +313,29 @@ func TestNotifyHookIsOptional(t *testing.T) {
 }
 
 var _ tea.Model = Model{}
+
+func TestDetectStatusChangesKeepsSameNameOnTwoHostsApart(t *testing.T) {
+	var calls [][2]string
+	m := Model{
+		audioNotifier: &audio.Notifier{},
+		lastSessionStates: map[string]string{
+			"api":        "working",
+			"api@devbox": "working",
+		},
+		audioNotifyFn: func(sessionName, status string) {
+			calls = append(calls, [2]string{sessionName, status})
+		},
+	}
+
+	m.detectStatusChanges([]session.Info{
+		{TmuxSession: "api", Status: "working"},
+		{TmuxSession: "api", Remote: "devbox", Status: "permission"},
+	})
+
+	if len(calls) != 1 || calls[0] != ([2]string{"api@devbox", "permission"}) {
+		t.Fatalf("expected only the remote api to notify, got %#v", calls)
+	}
+	if m.lastSessionStates["api"] != "working" || m.lastSessionStates["api@devbox"] != "permission" {
+		t.Fatalf("states should be tracked per host, got %#v", m.lastSessionStates)
+	}
+}
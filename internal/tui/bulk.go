@@ -0,0 +1,458 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/stwalsh4118/navi/internal/remote"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// bulkAction is an action applied to every session in a selection.
+type bulkAction int
+
+// Bulk actions
+const (
+	bulkKill bulkAction = iota
+	bulkDismiss
+	bulkSendPrompt
+	bulkMoveToGroup
+)
+
+// bulkListMax is the number of sessions listed by name in the bulk
+// confirmation dialog before the rest are summarized.
+const bulkListMax = 10
+
+// bulkRemoteConcurrency bounds the commands a bulk action runs at once on
+// one remote. Each opens a channel on the remote's shared SSH connection, and
+// sshd refuses more than MaxSessions (10 by default) at a time.
+const bulkRemoteConcurrency = 4
+
+// checkedMarker marks a selected session in the row marker column.
+const checkedMarker = "✓"
+
+// bulkResult is the outcome of a bulk action for one session.
+type bulkResult struct {
+	id    string
+	label string
+	err   error
+}
+
+// bulkResultMsg is returned after a bulk action has run on every target.
+// groups holds the updated assignments after a move to a group.
+type bulkResultMsg struct {
+	action  bulkAction
+	results []bulkResult
+	groups  session.Groups
+}
+
+// verb returns the confirmation prompt verb for the action.
+func (a bulkAction) verb() string {
+	switch a {
+	case bulkKill:
+		return "Kill"
+	case bulkDismiss:
+		return "Dismiss"
+	case bulkSendPrompt:
+		return "Send prompt to"
+	default:
+		return "Move to group"
+	}
+}
+
+// pastTense returns how a successful action is reported in the summary.
+func (a bulkAction) pastTense() string {
+	switch a {
+	case bulkKill:
+		return "Killed"
+	case bulkDismiss:
+		return "Dismissed"
+	case bulkSendPrompt:
+		return "Sent prompt to"
+	default:
+		return "Moved"
+	}
+}
+
+// needsInput reports whether the action asks for a prompt or group name.
+func (a bulkAction) needsInput() bool {
+	return a == bulkSendPrompt || a == bulkMoveToGroup
+}
+
+// pluralSessions formats a session count.
+func pluralSessions(n int) string {
+	if n == 1 {
+		return "1 session"
+	}
+	return fmt.Sprintf("%d sessions", n)
+}
+
+// toggleSelection adds or removes the session under the cursor from the selection.
+func (m *Model) toggleSelection() {
+	filtered := m.getFilteredSessions()
+	if m.cursor >= len(filtered) {
+		return
+	}
+	id := filtered[m.cursor].ID()
+	if m.selectedSessions[id] {
+		delete(m.selectedSessions, id)
+		return
+	}
+	if m.selectedSessions == nil {
+		m.selectedSessions = make(map[string]bool)
+	}
+	m.selectedSessions[id] = true
+}
+
+// selectMatches selects the search matches, or every listed session when no
+// search is active.
+func (m *Model) selectMatches() {
+	filtered := m.getFilteredSessions()
+	if m.selectedSessions == nil {
+		m.selectedSessions = make(map[string]bool)
+	}
+	if m.searchQuery != "" {
+		for _, i := range m.searchMatches {
+			if i < len(filtered) {
+				m.selectedSessions[filtered[i].ID()] = true
+			}
+		}
+		return
+	}
+	for _, s := range filtered {
+		m.selectedSessions[s.ID()] = true
+	}
+}
+
+// selectSameStatus selects every listed session with the status of the
+// session under the cursor.
+func (m *Model) selectSameStatus() {
+	filtered := m.getFilteredSessions()
+	if m.cursor >= len(filtered) {
+		return
+	}
+	if m.selectedSessions == nil {
+		m.selectedSessions = make(map[string]bool)
+	}
+	status := filtered[m.cursor].Status
+	for _, s := range filtered {
+		if s.Status == status {
+			m.selectedSessions[s.ID()] = true
+		}
+	}
+}
+
+// pruneSelection drops selected sessions that no longer exist.
+func (m *Model) pruneSelection() {
+	if len(m.selectedSessions) == 0 {
+		return
+	}
+	present := make(map[string]bool, len(m.sessions))
+	for _, s := range m.sessions {
+		present[s.ID()] = true
+	}
+	for id := range m.selectedSessions {
+		if !present[id] {
+			delete(m.selectedSessions, id)
+		}
+	}
+}
+
+// selectedSessionList returns the selected sessions in list order, or the
+// session under the cursor when nothing is selected.
+func (m Model) selectedSessionList() []session.Info {
+	filtered := m.getFilteredSessions()
+	if len(m.selectedSessions) == 0 {
+		if m.cursor < len(filtered) {
+			return []session.Info{filtered[m.cursor]}
+		}
+		return nil
+	}
+	var targets []session.Info
+	for _, s := range m.sessions {
+		if m.selectedSessions[s.ID()] {
+			targets = append(targets, s)
+		}
+	}
+	return targets
+}
+
+// openBulkConfirm opens the confirmation dialog for action on the selection.
+func (m Model) openBulkConfirm(action bulkAction) (tea.Model, tea.Cmd) {
+	targets := m.selectedSessionList()
+	if len(targets) == 0 {
+		return m, nil
+	}
+	m.bulkAction = action
+	m.bulkTargets = targets
+	m.bulkResults = nil
+	m.dialogMode = DialogBulkConfirm
+	m.dialogError = ""
+	if action.needsInput() {
+		m.bulkInput = initBulkInput(action)
+		if action == bulkMoveToGroup && len(targets) == 1 {
+			m.bulkInput.SetValue(targets[0].Group)
+			m.bulkInput.CursorEnd()
+		}
+	}
+	return m, nil
+}
+
+// updateBulkConfirm handles keys in the bulk confirmation dialog.
+func (m Model) updateBulkConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.dialogMode = DialogNone
+		m.dialogError = ""
+		m.bulkTargets = nil
+		return m, nil
+	case "n":
+		if !m.bulkAction.needsInput() {
+			m.dialogMode = DialogNone
+			m.bulkTargets = nil
+			return m, nil
+		}
+	case "enter", "y":
+		if msg.String() == "y" && m.bulkAction.needsInput() {
+			break
+		}
+		text := ""
+		if m.bulkAction.needsInput() {
+			text = strings.TrimSpace(m.bulkInput.Value())
+			if text == "" && m.bulkAction == bulkSendPrompt {
+				m.dialogError = "Enter a prompt"
+				return m, nil
+			}
+		}
+		m.dialogMode = DialogBulkResult
+		m.dialogError = ""
+		m.bulkRunning = true
+		return m, bulkActionCmd(m.SSHPool, m.bulkAction, m.bulkTargets, text, m.sessionGroups, m.groupsPath)
+	}
+
+	if !m.bulkAction.needsInput() {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.bulkInput, cmd = m.bulkInput.Update(msg)
+	return m, cmd
+}
+
+// updateBulkResult handles keys in the bulk result summary.
+func (m Model) updateBulkResult(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "enter", "q":
+		if m.bulkRunning {
+			return m, nil
+		}
+		m.dialogMode = DialogNone
+		m.bulkTargets = nil
+		m.bulkResults = nil
+	}
+	return m, nil
+}
+
+// applyBulkResult records the outcome of a bulk action. Sessions the action
+// succeeded on leave the selection; failed ones stay selected for a retry.
+func (m *Model) applyBulkResult(msg bulkResultMsg) {
+	m.bulkRunning = false
+	m.bulkResults = msg.results
+	if msg.groups != nil {
+		m.sessionGroups = msg.groups
+		m.sessionGroups.Apply(m.sessions)
+	}
+	for _, r := range msg.results {
+		if r.err == nil {
+			delete(m.selectedSessions, r.id)
+		}
+	}
+}
+
+// bulkActionCmd runs action on every target concurrently, at most
+// bulkRemoteConcurrency at a time per remote. Moving to a group only updates
+// the local group file, so it is done in one write.
+func bulkActionCmd(pool *remote.SSHPool, action bulkAction, targets []session.Info, text string, groups session.Groups, groupsPath string) tea.Cmd {
+	return func() tea.Msg {
+		results := make([]bulkResult, len(targets))
+		for i, s := range targets {
+			results[i] = bulkResult{id: s.ID(), label: s.Label()}
+		}
+
+		if action == bulkMoveToGroup {
+			updated := make(session.Groups, len(groups)+len(targets))
+			for label, group := range groups {
+				updated[label] = group
+			}
+			for _, s := range targets {
+				if text == "" {
+					delete(updated, s.Label())
+				} else {
+					updated[s.Label()] = text
+				}
+			}
+			err := session.SaveGroups(groupsPath, updated)
+			for i := range results {
+				results[i].err = err
+			}
+			if err != nil {
+				return bulkResultMsg{action: action, results: results}
+			}
+			return bulkResultMsg{action: action, results: results, groups: updated}
+		}
+
+		semaphores := make(map[string]chan struct{})
+		for _, s := range targets {
+			if s.Remote != "" && semaphores[s.Remote] == nil {
+				semaphores[s.Remote] = make(chan struct{}, bulkRemoteConcurrency)
+			}
+		}
+
+		var wg sync.WaitGroup
+		for i, s := range targets {
+			wg.Add(1)
+			go func(i int, s session.Info) {
+				defer wg.Done()
+				if semaphore := semaphores[s.Remote]; semaphore != nil {
+					semaphore <- struct{}{}
+					defer func() { <-semaphore }()
+				}
+				results[i].err = runBulkAction(pool, action, s, text)
+			}(i, s)
+		}
+		wg.Wait()
+		return bulkResultMsg{action: action, results: results}
+	}
+}
+
+// runBulkAction applies a kill, dismiss or prompt to one local or remote session.
+func runBulkAction(pool *remote.SSHPool, action bulkAction, s session.Info, text string) error {
+	if s.Remote == "" {
+		switch action {
+		case bulkKill:
+			return killSession(s.TmuxSession)
+		case bulkDismiss:
+			return dismissSession(s)
+		default:
+			return sendPrompt(s.TmuxSession, text)
+		}
+	}
+
+	if pool == nil {
+		return fmt.Errorf("remote %q is not connected", s.Remote)
+	}
+	config := pool.GetRemoteConfig(s.Remote)
+	if config == nil {
+		return fmt.Errorf("remote %q not found", s.Remote)
+	}
+	switch action {
+	case bulkKill:
+		return remote.KillSession(pool, s.Remote, s.TmuxSession, config.SessionsDir)
+	case bulkDismiss:
+		return remote.DismissSession(pool, s.Remote, s.TmuxSession, config.SessionsDir)
+	default:
+		return remote.SendPrompt(pool, s.Remote, s.TmuxSession, text)
+	}
+}
+
+// initBulkInput creates the text input for a prompt or group name.
+func initBulkInput(action bulkAction) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "Prompt to send"
+	ti.CharLimit = 0
+	if action == bulkMoveToGroup {
+		ti.Placeholder = "Group name (empty to ungroup)"
+		ti.CharLimit = inputNameCharLimit
+	}
+	ti.Width = inputWidth
+	ti.PromptStyle = highlightStyle
+	ti.TextStyle = lipgloss.NewStyle()
+	ti.Focus()
+	return ti
+}
+
+// writeBulkTargets lists session labels, summarizing past bulkListMax.
+func writeBulkTargets(b *strings.Builder, targets []session.Info) {
+	for i, s := range targets {
+		if i == bulkListMax {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  … and %d more", len(targets)-bulkListMax)))
+			b.WriteString("\n")
+			break
+		}
+		b.WriteString("  " + s.Label() + "\n")
+	}
+}
+
+// renderBulkConfirm renders the confirmation dialog body for a bulk action.
+func (m Model) renderBulkConfirm(b *strings.Builder) {
+	b.WriteString(fmt.Sprintf("%s %s?\n\n", m.bulkAction.verb(), pluralSessions(len(m.bulkTargets))))
+	writeBulkTargets(b, m.bulkTargets)
+	b.WriteString("\n")
+	if m.bulkAction.needsInput() {
+		if m.bulkAction == bulkSendPrompt {
+			b.WriteString("Prompt: ")
+		} else {
+			b.WriteString("Group: ")
+		}
+		b.WriteString(m.bulkInput.View())
+		b.WriteString("\n\n")
+		b.WriteString(dimStyle.Render("Enter: confirm  Esc: cancel"))
+		return
+	}
+	b.WriteString(dimStyle.Render("y: yes  n: no  Esc: cancel"))
+}
+
+// renderBulkResult renders the per-session outcome of a bulk action.
+func (m Model) renderBulkResult(b *strings.Builder) {
+	if m.bulkRunning {
+		b.WriteString(fmt.Sprintf("%s %s...\n", m.bulkAction.verb(), pluralSessions(len(m.bulkTargets))))
+		return
+	}
+
+	failed := 0
+	for _, r := range m.bulkResults {
+		if r.err != nil {
+			failed++
+		}
+	}
+	b.WriteString(fmt.Sprintf("%s %d of %s", m.bulkAction.pastTense(), len(m.bulkResults)-failed, pluralSessions(len(m.bulkResults))))
+	if failed > 0 {
+		b.WriteString(redStyle.Render(fmt.Sprintf(" (%d failed)", failed)))
+	}
+	b.WriteString("\n\n")
+
+	// Failures first, so they stay visible when the list is cut short
+	ordered := make([]bulkResult, 0, len(m.bulkResults))
+	for _, r := range m.bulkResults {
+		if r.err != nil {
+			ordered = append(ordered, r)
+		}
+	}
+	for _, r := range m.bulkResults {
+		if r.err == nil {
+			ordered = append(ordered, r)
+		}
+	}
+	for i, r := range ordered {
+		if i == bulkListMax {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  … and %d more", len(ordered)-bulkListMax)))
+			b.WriteString("\n")
+			break
+		}
+		if r.err != nil {
+			b.WriteString(redStyle.Render("✗ "+r.label) + dimStyle.Render(": "+r.err.Error()))
+		} else {
+			b.WriteString(greenStyle.Render("✓") + " " + r.label)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	if failed > 0 {
+		b.WriteString(dimStyle.Render("Failed sessions stay selected.  "))
+	}
+	b.WriteString(dimStyle.Render("Enter/Esc: close"))
+}
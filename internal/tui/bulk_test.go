@@ -0,0 +1,254 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/remote"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// writeStatusFile writes a status file for s into dir.
+func writeStatusFile(t *testing.T, dir string, s session.Info) {
+	t.Helper()
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, s.TmuxSession+".json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readStatusFile(t *testing.T, dir, name string) session.Info {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s session.Info
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func newBulkModel(t *testing.T, remoteDir string) Model {
+	t.Helper()
+	pool := remote.NewSSHPool([]remote.Config{{Name: "ctr", Transport: remote.TransportCommand, Command: "sh -c", SessionsDir: remoteDir}})
+	t.Cleanup(pool.Close)
+	return Model{
+		width:   120,
+		height:  40,
+		SSHPool: pool,
+		sessions: []session.Info{
+			{TmuxSession: "api", Status: session.StatusDone, Message: "finished", Timestamp: 100},
+			{TmuxSession: "api", Remote: "ctr", Status: session.StatusDone, Message: "finished", Timestamp: 90},
+			{TmuxSession: "web", Status: session.StatusWorking, Timestamp: 80},
+			{TmuxSession: "db", Remote: "gone", Status: session.StatusDone, Timestamp: 70},
+		},
+		groupsPath: filepath.Join(t.TempDir(), "groups.json"),
+	}
+}
+
+func TestSelectionKeys(t *testing.T) {
+	m := newBulkModel(t, t.TempDir())
+
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeySpace})
+	if !m.selectedSessions["api"] || len(m.selectedSessions) != 1 {
+		t.Fatalf("space should select the session under the cursor, got %v", m.selectedSessions)
+	}
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeySpace})
+	if len(m.selectedSessions) != 0 {
+		t.Fatalf("space again should deselect, got %v", m.selectedSessions)
+	}
+
+	m = typeText(t, m, "*")
+	want := map[string]bool{"api": true, "api@ctr": true, "db@gone": true}
+	if len(m.selectedSessions) != len(want) {
+		t.Fatalf("* should select every done session, got %v", m.selectedSessions)
+	}
+	for id := range want {
+		if !m.selectedSessions[id] {
+			t.Errorf("%s should be selected", id)
+		}
+	}
+	if !strings.Contains(m.View(), "3 selected") {
+		t.Error("footer should show the selection count")
+	}
+
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if len(m.selectedSessions) != 0 {
+		t.Fatal("esc should clear the selection")
+	}
+
+	m.searchQuery = "api"
+	m.computeSearchMatches()
+	m = typeText(t, m, "V")
+	if len(m.selectedSessions) != 2 || !m.selectedSessions["api"] || !m.selectedSessions["api@ctr"] {
+		t.Fatalf("V should select the search matches, got %v", m.selectedSessions)
+	}
+}
+
+func TestBulkDismissMixesLocalAndRemote(t *testing.T) {
+	localDir, remoteDir := t.TempDir(), t.TempDir()
+	origDir := session.StatusDir
+	session.StatusDir = localDir
+	t.Cleanup(func() { session.StatusDir = origDir })
+
+	m := newBulkModel(t, remoteDir)
+	writeStatusFile(t, localDir, m.sessions[0])
+	writeStatusFile(t, remoteDir, m.sessions[1])
+
+	m = typeText(t, m, "*")
+	m = typeText(t, m, "d")
+	if m.dialogMode != DialogBulkConfirm || len(m.bulkTargets) != 3 {
+		t.Fatalf("d with a selection should confirm 3 sessions, got mode %v targets %d", m.dialogMode, len(m.bulkTargets))
+	}
+	view := m.View()
+	for _, label := range []string{"Dismiss 3 sessions?", "api@ctr", "db@gone"} {
+		if !strings.Contains(view, label) {
+			t.Errorf("confirmation should list %q", label)
+		}
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = updated.(Model)
+	if m.dialogMode != DialogBulkResult || !m.bulkRunning {
+		t.Fatalf("confirming should show the running summary, got mode %v", m.dialogMode)
+	}
+	m, _ = runMsg(t, m, cmd)
+
+	if got := readStatusFile(t, localDir, "api"); got.Status != session.StatusWorking || got.Message != "" {
+		t.Errorf("local session not dismissed: %+v", got)
+	}
+	if got := readStatusFile(t, remoteDir, "api"); got.Status != session.StatusWorking || got.Message != "" {
+		t.Errorf("remote session not dismissed: %+v", got)
+	}
+
+	view = m.View()
+	for _, want := range []string{"Dismissed 2 of 3 sessions", "(1 failed)", "✗ db@gone", `remote "gone" not found`} {
+		if !strings.Contains(view, want) {
+			t.Errorf("result summary should contain %q", want)
+		}
+	}
+	if len(m.selectedSessions) != 1 || !m.selectedSessions["db@gone"] {
+		t.Errorf("only the failed session should stay selected, got %v", m.selectedSessions)
+	}
+
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.dialogMode != DialogNone {
+		t.Errorf("enter should close the summary, got %v", m.dialogMode)
+	}
+}
+
+func TestBulkMoveToGroup(t *testing.T) {
+	m := newBulkModel(t, t.TempDir())
+
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeySpace})
+	m.cursor = 1
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeySpace})
+	m = typeText(t, m, "g")
+	if m.dialogMode != DialogBulkConfirm || m.bulkAction != bulkMoveToGroup {
+		t.Fatalf("g should open the group dialog, got mode %v", m.dialogMode)
+	}
+	m = typeText(t, m, "sprint")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = runMsg(t, updated.(Model), cmd)
+
+	groups, err := session.LoadGroups(m.groupsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if groups["api"] != "sprint" || groups["api@ctr"] != "sprint" || len(groups) != 2 {
+		t.Fatalf("saved groups = %v", groups)
+	}
+	if m.sessions[0].Group != "sprint" || m.sessions[2].Group != "" {
+		t.Errorf("groups should be applied to the session list")
+	}
+	if !strings.Contains(m.renderSession(m.sessions[0], false, 100), "{sprint}") {
+		t.Error("row should show the group")
+	}
+	if matches := findMatches(m.sessions, "sprint"); len(matches) != 2 {
+		t.Errorf("search should match the group, got %v", matches)
+	}
+}
+
+func TestBulkSendPromptRequiresText(t *testing.T) {
+	m := newBulkModel(t, t.TempDir())
+	m = typeText(t, m, ">")
+	if m.dialogMode != DialogBulkConfirm || len(m.bulkTargets) != 1 {
+		t.Fatalf("> without a selection should target the cursor session, got mode %v", m.dialogMode)
+	}
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.dialogMode != DialogBulkConfirm || m.dialogError == "" {
+		t.Fatal("an empty prompt should be rejected")
+	}
+	// y types into the prompt rather than confirming
+	m = typeText(t, m, "y")
+	if m.bulkInput.Value() != "y" || m.dialogMode != DialogBulkConfirm {
+		t.Fatalf("y should be typed into the prompt, got %q", m.bulkInput.Value())
+	}
+}
+
+// concurrencyWrapper runs its argument with sh while holding a slot in dir,
+// appending the number of slots in use to log.
+const concurrencyWrapper = `#!/bin/sh
+slot="$SLOT_DIR/$$"
+mkdir "$slot"
+ls "$SLOT_DIR" | wc -l >> "$SLOT_LOG"
+sleep 0.1
+sh -c "$1"
+status=$?
+rmdir "$slot"
+exit $status
+`
+
+func TestBulkActionLimitsRemoteConcurrency(t *testing.T) {
+	remoteDir, slotDir, binDir := t.TempDir(), t.TempDir(), t.TempDir()
+	logPath := filepath.Join(t.TempDir(), "slots.log")
+	wrapper := filepath.Join(binDir, "wrapper")
+	if err := os.WriteFile(wrapper, []byte(concurrencyWrapper), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SLOT_DIR", slotDir)
+	t.Setenv("SLOT_LOG", logPath)
+
+	pool := remote.NewSSHPool([]remote.Config{{Name: "ctr", Transport: remote.TransportCommand, Command: wrapper, SessionsDir: remoteDir}})
+	t.Cleanup(pool.Close)
+
+	var targets []session.Info
+	for i := 0; i < 12; i++ {
+		s := session.Info{TmuxSession: fmt.Sprintf("s%d", i), Remote: "ctr", Status: session.StatusDone}
+		writeStatusFile(t, remoteDir, s)
+		targets = append(targets, s)
+	}
+
+	msg := bulkActionCmd(pool, bulkDismiss, targets, "", nil, "")().(bulkResultMsg)
+	for _, r := range msg.results {
+		if r.err != nil {
+			t.Errorf("%s: %v", r.label, r.err)
+		}
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := strings.Fields(string(data))
+	if len(counts) != len(targets) {
+		t.Fatalf("wrapper ran %d times, want %d", len(counts), len(targets))
+	}
+	for _, c := range counts {
+		if n, _ := strconv.Atoi(c); n > bulkRemoteConcurrency {
+			t.Errorf("%d commands ran at once on one remote, want at most %d", n, bulkRemoteConcurrency)
+		}
+	}
+}
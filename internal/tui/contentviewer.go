@@ -6,6 +6,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/stwalsh4118/navi/internal/keymap"
 )
 
 // ContentMode distinguishes plain text from diff content for rendering.
@@ -38,6 +40,14 @@ func (m *Model) openContentViewer(title, content string, mode ContentMode) {
 	m.dialogError = ""
 }
 
+// setContentViewerContent replaces the content of the open viewer, keeping
+// its title and return dialog.
+func (m *Model) setContentViewerContent(content string, mode ContentMode) {
+	m.contentViewerMode = mode
+	m.contentViewerScroll = 0
+	m.contentViewerLines = strings.Split(content, "\n")
+}
+
 // openContentViewerFrom opens the content viewer with a return-to dialog.
 // When the viewer is closed with Esc, it returns to prevDialog instead of DialogNone.
 func (m *Model) openContentViewerFrom(title, content string, mode ContentMode, prevDialog DialogMode) {
@@ -59,8 +69,8 @@ func (m Model) contentViewerViewportHeight() int {
 func (m Model) updateContentViewer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	maxScroll := m.contentViewerMaxScroll()
 
-	switch msg.String() {
-	case "esc", "q":
+	switch m.keys.Action(keymap.Viewer, msg.String()) {
+	case keymap.Back:
 		prevDialog := m.contentViewerPrevDialog
 		m.dialogMode = prevDialog
 		m.contentViewerLines = nil
@@ -69,37 +79,37 @@ func (m Model) updateContentViewer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.contentViewerPrevDialog = DialogNone
 		return m, nil
 
-	case "down", "j":
+	case keymap.Down:
 		if m.contentViewerScroll < maxScroll {
 			m.contentViewerScroll++
 		}
 		return m, nil
 
-	case "up", "k":
+	case keymap.Up:
 		if m.contentViewerScroll > 0 {
 			m.contentViewerScroll--
 		}
 		return m, nil
 
-	case "pgdown", " ":
+	case keymap.PageDown:
 		m.contentViewerScroll += contentViewerPageScrollAmt
 		if m.contentViewerScroll > maxScroll {
 			m.contentViewerScroll = maxScroll
 		}
 		return m, nil
 
-	case "pgup":
+	case keymap.PageUp:
 		m.contentViewerScroll -= contentViewerPageScrollAmt
 		if m.contentViewerScroll < 0 {
 			m.contentViewerScroll = 0
 		}
 		return m, nil
 
-	case "g", "home":
+	case keymap.Top:
 		m.contentViewerScroll = 0
 		return m, nil
 
-	case "G", "end":
+	case keymap.Bottom:
 		m.contentViewerScroll = maxScroll
 		return m, nil
 	}
@@ -202,15 +212,15 @@ func renderDiffLine(line string, maxWidth int) string {
 
 	switch {
 	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
-		return dimStyle.Render(line)
+		return diffMetaStyle.Render(line)
 	case strings.HasPrefix(line, "+"):
-		return greenStyle.Render(line)
+		return diffAddStyle.Render(line)
 	case strings.HasPrefix(line, "-"):
-		return redStyle.Render(line)
+		return diffRemoveStyle.Render(line)
 	case strings.HasPrefix(line, "@@"):
-		return cyanStyle.Render(line)
+		return diffHunkStyle.Render(line)
 	case strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "):
-		return dimStyle.Render(line)
+		return diffMetaStyle.Render(line)
 	default:
 		return line
 	}
@@ -14,6 +14,15 @@ const (
 	DialogMetricsDetail                   // Metrics detail view dialog
 	DialogContentViewer                   // Content viewer overlay
 	DialogSoundPackPicker                 // Sound pack picker overlay
+	DialogProcessTree                     // Process tree view dialog
+	DialogHostKey                         // Unknown remote host key confirmation
+	DialogRemotes                         // Remote connection health panel
+	DialogRemoteEdit                      // Add/edit remote dialog
+	DialogGitFile                         // File path prompt from the git detail view
+	DialogBulkConfirm                     // Confirmation for an action on the selected sessions
+	DialogBulkResult                      // Per-session outcome of a bulk action
+	DialogPalette                         // Command palette overlay
+	DialogInbox                           // Notification inbox of priority transitions
 )
 
 // DialogTitle returns the title for a given dialog mode.
@@ -35,6 +44,24 @@ func DialogTitle(mode DialogMode) string {
 		return "Content Viewer"
 	case DialogSoundPackPicker:
 		return "Sound Packs"
+	case DialogProcessTree:
+		return "Processes"
+	case DialogHostKey:
+		return "Unknown Host Key"
+	case DialogRemotes:
+		return "Remotes"
+	case DialogRemoteEdit:
+		return "Edit Remote"
+	case DialogGitFile:
+		return "View File"
+	case DialogBulkConfirm:
+		return "Bulk Action"
+	case DialogBulkResult:
+		return "Bulk Action Results"
+	case DialogPalette:
+		return "Command Palette"
+	case DialogInbox:
+		return "Inbox"
 	default:
 		return ""
 	}
@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/stwalsh4118/navi/internal/remote"
 	"github.com/stwalsh4118/navi/internal/session"
 )
 
@@ -734,3 +735,83 @@ func TestFooterShowsRenameKey(t *testing.T) {
 		t.Error("footer should contain 'R rename' keybinding")
 	}
 }
+
+func TestNewSessionDialogHostSelector(t *testing.T) {
+	m := Model{
+		width:        80,
+		height:       24,
+		dialogMode:   DialogNewSession,
+		nameInput:    initNameInput(),
+		dirInput:     initDirInput(),
+		focusedInput: focusSkipPerms,
+		Remotes:      []remote.Config{{Name: "devbox"}, {Name: "gpu"}},
+	}
+	m.dirInput.SetValue(getDefaultDirectory())
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = newModel.(Model)
+	if m.focusedInput != focusHost {
+		t.Fatalf("tab after skip permissions should focus host with remotes, got %d", m.focusedInput)
+	}
+	if !strings.Contains(m.renderDialog(), "◂ local ▸") {
+		t.Error("host selector should start on local")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m = newModel.(Model)
+	if m.newSessionHost != "devbox" {
+		t.Errorf("newSessionHost = %q, want devbox", m.newSessionHost)
+	}
+	if m.dirInput.Value() != "~" {
+		t.Errorf("directory = %q, want ~ for a remote", m.dirInput.Value())
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = newModel.(Model)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = newModel.(Model)
+	if m.newSessionHost != "gpu" {
+		t.Errorf("newSessionHost = %q, want gpu after wrapping", m.newSessionHost)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = newModel.(Model)
+	if m.focusedInput != focusName {
+		t.Errorf("tab after host should wrap to name, got %d", m.focusedInput)
+	}
+}
+
+func TestSubmitNewSessionOnRemote(t *testing.T) {
+	m := Model{
+		width:          80,
+		height:         24,
+		dialogMode:     DialogNewSession,
+		nameInput:      initNameInput(),
+		dirInput:       initDirInput(),
+		Remotes:        []remote.Config{{Name: "devbox", Host: "devbox"}},
+		SSHPool:        remote.NewSSHPool([]remote.Config{{Name: "devbox", Host: "devbox"}}),
+		newSessionHost: "devbox",
+		sessions: []session.Info{
+			{TmuxSession: "api"},
+			{TmuxSession: "web", Remote: "devbox"},
+		},
+	}
+	defer m.SSHPool.Close()
+
+	// The directory only exists on the remote, so it is not checked locally
+	m.dirInput.SetValue("/srv/only-on-remote")
+
+	m.nameInput.SetValue("web")
+	newModel, cmd := m.submitNewSession()
+	updated := newModel.(Model)
+	if updated.dialogError != errNameExists.Error() || cmd != nil {
+		t.Errorf("name taken on the remote should be rejected, got error %q", updated.dialogError)
+	}
+
+	m.nameInput.SetValue("api")
+	newModel, cmd = m.submitNewSession()
+	updated = newModel.(Model)
+	if updated.dialogError != "" || cmd == nil {
+		t.Errorf("local name should not clash with a remote session, got error %q", updated.dialogError)
+	}
+}
@@ -244,8 +244,8 @@ func TestE2E_ResourcePollMerge(t *testing.T) {
 
 		// Simulate a resource poll result
 		pollMsg := resourcePollMsg{
-			"session1": 256 * 1024 * 1024,
-			"session2": 512 * 1024 * 1024,
+			"session1": {RSSBytes: 256 * 1024 * 1024, ProcessCount: 1},
+			"session2": {RSSBytes: 512 * 1024 * 1024, ProcessCount: 1},
 		}
 
 		updatedModel, _ := m.Update(pollMsg)
@@ -274,7 +274,7 @@ func TestE2E_ResourcePollMerge(t *testing.T) {
 			{TmuxSession: "session1", Status: "working", CWD: "/tmp", Metrics: nil},
 		}
 
-		pollMsg := resourcePollMsg{"session1": 100 * 1024 * 1024}
+		pollMsg := resourcePollMsg{"session1": {RSSBytes: 100 * 1024 * 1024, ProcessCount: 1}}
 
 		updatedModel, _ := m.Update(pollMsg)
 		updated := updatedModel.(Model)
@@ -297,7 +297,7 @@ func TestE2E_ResourcePollMerge(t *testing.T) {
 		}
 
 		// Simulate resource poll setting data
-		pollMsg := resourcePollMsg{"session1": 300 * 1024 * 1024}
+		pollMsg := resourcePollMsg{"session1": {RSSBytes: 300 * 1024 * 1024, ProcessCount: 1}}
 		updatedModel, _ := m.Update(pollMsg)
 		m = updatedModel.(Model)
 
@@ -335,7 +335,7 @@ func TestE2E_ResourcePollMerge(t *testing.T) {
 			},
 		}
 
-		pollMsg := resourcePollMsg{"session1": 200 * 1024 * 1024}
+		pollMsg := resourcePollMsg{"session1": {RSSBytes: 200 * 1024 * 1024, ProcessCount: 1}}
 
 		updatedModel, _ := m.Update(pollMsg)
 		updated := updatedModel.(Model)
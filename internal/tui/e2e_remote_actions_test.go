@@ -777,8 +777,8 @@ func TestE2E_PBI27_AC9_SameConfirmationFeedback(t *testing.T) {
 		if updated.dialogMode != DialogNone {
 			t.Errorf("dialog should close on successful rename, got %d", updated.dialogMode)
 		}
-		if updated.lastSelectedSession != "renamed-remote" {
-			t.Errorf("lastSelectedSession should be set to new name, got %q", updated.lastSelectedSession)
+		if updated.lastSelectedSession != "renamed-remote@dev" {
+			t.Errorf("lastSelectedSession should be set to the renamed session ID, got %q", updated.lastSelectedSession)
 		}
 		if cmd == nil {
 			t.Error("successful rename should trigger session refresh")
@@ -269,7 +269,7 @@ func TestE2E_RemoteAttach(t *testing.T) {
 		updated := newModel.(Model)
 
 		// Should store the s name for cursor restoration
-		if updated.lastSelectedSession != "remote-session" {
+		if updated.lastSelectedSession != "remote-session@dev" {
 			t.Errorf("should store lastSelectedSession, got %q", updated.lastSelectedSession)
 		}
 
@@ -4,6 +4,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/stwalsh4118/navi/internal/keymap"
 	"github.com/stwalsh4118/navi/internal/session"
 )
 
@@ -40,13 +41,13 @@ func SortModeLabel(mode SortMode) string {
 	}
 }
 
-// statusFilterKeys maps number key characters to session status values.
-var statusFilterKeys = map[string]string{
-	"1": session.StatusWaiting,
-	"2": session.StatusPermission,
-	"3": session.StatusWorking,
-	"4": "done",
-	"5": "error",
+// statusFilterActions maps the status filter actions to session status values.
+var statusFilterActions = map[keymap.Action]string{
+	keymap.FilterWaiting: session.StatusWaiting,
+	keymap.FilterPerm:    session.StatusPermission,
+	keymap.FilterWorking: session.StatusWorking,
+	keymap.FilterDone:    "done",
+	keymap.FilterError:   "error",
 }
 
 // statusOrder defines a canonical ordering of statuses for SortStatus mode.
@@ -140,7 +141,7 @@ func exactMatch(query, target string) bool {
 	return strings.Contains(strings.ToLower(target), strings.ToLower(query))
 }
 
-// findMatches returns the indices of sessions whose name, CWD, or message
+// findMatches returns the indices of sessions whose label (name@remote), group, CWD, or message
 // contain the query as a case-insensitive substring.
 func findMatches(sessions []session.Info, query string) []int {
 	if query == "" {
@@ -148,7 +149,7 @@ func findMatches(sessions []session.Info, query string) []int {
 	}
 	var matches []int
 	for i, s := range sessions {
-		if exactMatch(query, s.TmuxSession) || exactMatch(query, s.CWD) || exactMatch(query, s.Message) {
+		if exactMatch(query, s.Label()) || (s.Group != "" && exactMatch(query, s.Group)) || exactMatch(query, s.CWD) || exactMatch(query, s.Message) {
 			matches = append(matches, i)
 		}
 	}
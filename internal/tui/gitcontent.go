@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/git"
+	"github.com/stwalsh4118/navi/internal/pathutil"
+	"github.com/stwalsh4118/navi/internal/remote"
+)
+
+// gitContentKind selects what the git detail view opens in the content viewer.
+type gitContentKind int
+
+// Git content kinds
+const (
+	gitContentDiff gitContentKind = iota
+	gitContentDiffStat
+	gitContentLog
+	gitContentFile
+)
+
+// gitLogMaxCommits is the number of commits shown by the git log view.
+const gitLogMaxCommits = 50
+
+// remoteContentMsg carries content fetched from a remote for a content viewer
+// opened with a loading placeholder.
+type remoteContentMsg struct {
+	title   string
+	content string
+	mode    ContentMode
+	err     error
+}
+
+// openGitContent opens diff, diffstat, log or file content for the session in
+// the git detail view. Local content is read at once; remote content is
+// fetched in the background into a viewer showing a loading line.
+func (m Model) openGitContent(kind gitContentKind, filePath string) (tea.Model, tea.Cmd) {
+	s := m.sessionToModify
+	if s == nil {
+		return m, nil
+	}
+
+	branch := ""
+	dirty := false
+	if s.Git != nil {
+		branch, dirty = s.Git.Branch, s.Git.Dirty
+	}
+	mode := ContentModePlain
+	var title string
+	switch kind {
+	case gitContentDiff:
+		title, mode = "Git Diff: "+branch, ContentModeDiff
+	case gitContentDiffStat:
+		title = "Diff Stat: " + branch
+	case gitContentLog:
+		title = "Git Log: " + branch
+	case gitContentFile:
+		title = "File: " + filePath
+	}
+	empty := emptyGitContent(kind, dirty)
+
+	if s.Remote != "" {
+		if m.SSHPool == nil {
+			return m, nil
+		}
+		title += " (" + s.Remote + ")"
+		m.openContentViewerFrom(title, "Loading from "+s.Remote+"...", ContentModePlain, DialogGitDetail)
+		return m, fetchRemoteContentCmd(m.SSHPool, s.Remote, s.CWD, kind, filePath, title, mode, empty)
+	}
+
+	dir := pathutil.ExpandPath(s.CWD)
+	var content string
+	switch kind {
+	case gitContentDiff:
+		content = git.GetDiff(dir)
+	case gitContentDiffStat:
+		content = git.GetDiffStat(dir)
+	case gitContentLog:
+		content = git.GetLog(dir, gitLogMaxCommits)
+	case gitContentFile:
+		data, err := readLocalFile(localFilePath(dir, filePath))
+		if err != nil {
+			content = fmt.Sprintf("Could not read file:\n%s\n\nError: %s", filePath, err.Error())
+		} else {
+			content = string(data)
+		}
+	}
+	if content == "" {
+		content = empty
+	}
+	m.openContentViewerFrom(title, content, mode, DialogGitDetail)
+	return m, nil
+}
+
+// emptyGitContent returns the text shown when a git view has no output.
+func emptyGitContent(kind gitContentKind, dirty bool) string {
+	switch kind {
+	case gitContentDiff, gitContentDiffStat:
+		if dirty {
+			return "No unstaged changes (changes may be staged)"
+		}
+		return "Working tree clean - no changes"
+	case gitContentLog:
+		return "No commits"
+	}
+	return "(empty file)"
+}
+
+// fetchRemoteContentCmd fetches git or file content from a remote.
+func fetchRemoteContentCmd(pool *remote.SSHPool, remoteName, cwd string, kind gitContentKind, filePath, title string, mode ContentMode, empty string) tea.Cmd {
+	return func() tea.Msg {
+		var content string
+		var err error
+		switch kind {
+		case gitContentDiff:
+			content, err = remote.FetchDiff(pool, remoteName, cwd)
+		case gitContentDiffStat:
+			content, err = remote.FetchDiffStat(pool, remoteName, cwd)
+		case gitContentLog:
+			content, err = remote.FetchLog(pool, remoteName, cwd, gitLogMaxCommits)
+		case gitContentFile:
+			var data []byte
+			data, err = remote.ReadFile(pool, remoteName, remoteFilePath(cwd, filePath))
+			content = string(data)
+		}
+		if err == nil && content == "" {
+			content = empty
+		}
+		return remoteContentMsg{title: title, content: content, mode: mode, err: err}
+	}
+}
+
+// applyRemoteContent fills the content viewer opened for msg, if it is still
+// showing. Results for a viewer the user has since closed are dropped.
+func (m *Model) applyRemoteContent(msg remoteContentMsg) {
+	if m.dialogMode != DialogContentViewer || m.contentViewerTitle != msg.title {
+		return
+	}
+	if msg.err != nil {
+		m.setContentViewerContent("Could not load content:\n\nError: "+msg.err.Error(), ContentModePlain)
+		return
+	}
+	m.setContentViewerContent(msg.content, msg.mode)
+}
+
+// localFilePath resolves a path typed in the file prompt against the session
+// directory. Absolute and ~ paths are used as given.
+func localFilePath(dir, filePath string) string {
+	filePath = pathutil.ExpandPath(filePath)
+	if filepath.IsAbs(filePath) {
+		return filePath
+	}
+	return filepath.Join(dir, filePath)
+}
+
+// remoteFilePath resolves a path typed in the file prompt against a remote
+// session directory. Absolute and ~ paths are left for the remote shell.
+func remoteFilePath(cwd, filePath string) string {
+	if path.IsAbs(filePath) || filePath == "~" || strings.HasPrefix(filePath, "~/") {
+		return filePath
+	}
+	return path.Join(cwd, filePath)
+}
+
+// readLocalFile reads a file for the content viewer, cut at
+// remote.MaxContentBytes like remote files.
+func readLocalFile(filePath string) ([]byte, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, errors.New("is a directory")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(f, remote.MaxContentBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > remote.MaxContentBytes {
+		data = append(data[:remote.MaxContentBytes], remote.ContentTruncatedMsg...)
+	}
+	return data, nil
+}
+
+// updateGitFileDialog handles keys in the file path prompt.
+func (m Model) updateGitFileDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.dialogMode = DialogGitDetail
+		m.dialogError = ""
+		return m, nil
+	case "enter":
+		filePath := strings.TrimSpace(m.fileInput.Value())
+		if filePath == "" {
+			m.dialogError = "Enter a file path"
+			return m, nil
+		}
+		return m.openGitContent(gitContentFile, filePath)
+	}
+
+	var cmd tea.Cmd
+	m.fileInput, cmd = m.fileInput.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/git"
+	"github.com/stwalsh4118/navi/internal/remote"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+func newGitContentModel(t *testing.T, s session.Info) Model {
+	t.Helper()
+	pool := remote.NewSSHPool([]remote.Config{{Name: "ctr", Transport: remote.TransportCommand, Command: "sh -c"}})
+	t.Cleanup(pool.Close)
+	return Model{
+		width:           120,
+		height:          40,
+		SSHPool:         pool,
+		dialogMode:      DialogGitDetail,
+		sessionToModify: &s,
+	}
+}
+
+func TestRemoteGitLogLoadsIntoContentViewer(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.email=navi@example.com", "-c", "user.name=navi", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	m := newGitContentModel(t, session.Info{TmuxSession: "api", CWD: repo, Remote: "ctr", Git: &git.Info{Branch: "main"}})
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	m = updated.(Model)
+	if m.dialogMode != DialogContentViewer || m.contentViewerTitle != "Git Log: main (ctr)" {
+		t.Fatalf("expected log viewer to open, got mode %v title %q", m.dialogMode, m.contentViewerTitle)
+	}
+	if !strings.Contains(strings.Join(m.contentViewerLines, "\n"), "Loading from ctr") {
+		t.Fatalf("expected loading placeholder, got %q", m.contentViewerLines)
+	}
+
+	m, _ = runMsg(t, m, cmd)
+	if !strings.Contains(strings.Join(m.contentViewerLines, "\n"), "init") {
+		t.Fatalf("expected remote log in viewer, got %q", m.contentViewerLines)
+	}
+
+	// Content for a viewer the user has closed is dropped.
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.dialogMode != DialogGitDetail {
+		t.Fatalf("expected esc to return to git detail, got %v", m.dialogMode)
+	}
+	m.applyRemoteContent(remoteContentMsg{title: "Git Log: main (ctr)", content: "late"})
+	if m.dialogMode != DialogGitDetail {
+		t.Fatalf("late content must not reopen the viewer")
+	}
+}
+
+func TestGitFilePromptOpensLocalFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("hello from notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := newGitContentModel(t, session.Info{TmuxSession: "api", CWD: dir})
+
+	m = typeText(t, m, "f")
+	if m.dialogMode != DialogGitFile {
+		t.Fatalf("expected file prompt, got %v", m.dialogMode)
+	}
+	m = typeText(t, m, "notes.md")
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.dialogMode != DialogContentViewer || m.contentViewerTitle != "File: notes.md" {
+		t.Fatalf("expected file viewer, got mode %v title %q", m.dialogMode, m.contentViewerTitle)
+	}
+	if got := strings.Join(m.contentViewerLines, "\n"); got != "hello from notes" {
+		t.Fatalf("file content = %q", got)
+	}
+}
+
+func TestRemoteFilePath(t *testing.T) {
+	tests := []struct{ cwd, path, want string }{
+		{"/srv/app", "README.md", "/srv/app/README.md"},
+		{"/srv/app", "../other/x", "/srv/other/x"},
+		{"/srv/app", "/etc/hosts", "/etc/hosts"},
+		{"/srv/app", "~/notes.md", "~/notes.md"},
+	}
+	for _, tt := range tests {
+		if got := remoteFilePath(tt.cwd, tt.path); got != tt.want {
+			t.Errorf("remoteFilePath(%q, %q) = %q, want %q", tt.cwd, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/stwalsh4118/navi/internal/remote"
+)
+
+// newHostKeyTestPool returns a pool whose only remote has been dialed once,
+// leaving its unknown host key pending. HOME is isolated so known_hosts and
+// the default identity live in a temp directory.
+func newHostKeyTestPool(t *testing.T) (*remote.SSHPool, string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	// Default identity so the client gets as far as the host key check
+	_, clientPriv, _ := ed25519.GenerateKey(rand.Reader)
+	block, err := ssh.MarshalPrivateKey(clientPriv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(home, ".ssh"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".ssh", "id_ed25519"), pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Handshake-only server; the client aborts at host key verification
+	_, hostPriv, _ := ed25519.GenerateKey(rand.Reader)
+	hostKey, _ := ssh.NewSignerFromKey(hostPriv)
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(hostKey)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				ssh.NewServerConn(conn, config)
+				conn.Close()
+			}()
+		}
+	}()
+
+	host, portStr, _ := net.SplitHostPort(ln.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	pool := remote.NewSSHPool([]remote.Config{{Name: "devbox", Host: host, Port: port}})
+	t.Cleanup(pool.Close)
+
+	if _, err := pool.Execute("devbox", "true"); err == nil {
+		t.Fatal("expected unknown host key error")
+	}
+	return pool, home
+}
+
+func TestHostKeyPromptTrust(t *testing.T) {
+	pool, home := newHostKeyTestPool(t)
+	m := Model{width: 120, height: 40, SSHPool: pool}
+
+	updated, _ := m.Update(remoteSessionsMsg{})
+	m = updated.(Model)
+	if m.dialogMode != DialogHostKey || m.hostKeyPrompt == nil {
+		t.Fatalf("dialogMode = %v, want DialogHostKey", m.dialogMode)
+	}
+
+	view := m.renderDialog()
+	for _, want := range []string{"Unknown Host Key", "devbox", m.hostKeyPrompt.Fingerprint(), "y: trust"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("dialog missing %q:\n%s", want, view)
+		}
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected trust command")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	if m.dialogMode != DialogNone || m.hostKeyPrompt != nil {
+		t.Errorf("dialog should close after trusting, mode = %v", m.dialogMode)
+	}
+	if pool.PendingHostKey() != nil {
+		t.Error("prompt should be cleared from the pool")
+	}
+	if _, err := os.Stat(filepath.Join(home, ".ssh", "known_hosts")); err != nil {
+		t.Errorf("known_hosts not written: %v", err)
+	}
+}
+
+func TestHostKeyPromptReject(t *testing.T) {
+	pool, _ := newHostKeyTestPool(t)
+	m := Model{width: 120, height: 40, SSHPool: pool}
+
+	updated, _ := m.Update(remoteSessionsMsg{})
+	m = updated.(Model)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.dialogMode != DialogNone {
+		t.Errorf("dialogMode = %v, want closed after reject", m.dialogMode)
+	}
+
+	// The rejected key is refused without prompting again
+	_, err := pool.Execute("devbox", "true")
+	if !errors.Is(err, remote.ErrHostKeyRejected) {
+		t.Errorf("error = %v, want rejected host key", err)
+	}
+	updated, _ = m.Update(remoteSessionsMsg{})
+	m = updated.(Model)
+	if m.dialogMode != DialogNone {
+		t.Error("rejected key should not reopen the dialog")
+	}
+}
+
+func TestHostKeyPromptWaitsForOpenDialog(t *testing.T) {
+	pool, _ := newHostKeyTestPool(t)
+	m := Model{width: 120, height: 40, SSHPool: pool, dialogMode: DialogKillConfirm}
+
+	updated, _ := m.Update(remoteSessionsMsg{})
+	m = updated.(Model)
+	if m.dialogMode != DialogKillConfirm {
+		t.Errorf("dialogMode = %v, host key prompt must not replace an open dialog", m.dialogMode)
+	}
+}
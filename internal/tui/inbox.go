@@ -0,0 +1,270 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	ansi "github.com/charmbracelet/x/ansi"
+
+	"github.com/stwalsh4118/navi/internal/inbox"
+	"github.com/stwalsh4118/navi/internal/keymap"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// Inbox dialog layout constants
+const (
+	inboxPanelWidth   = 96 // Width of the inbox dialog
+	inboxMaxVisible   = 15 // Items shown at once
+	inboxUnreadMark   = "●"
+	inboxTimeLayout   = "15:04"
+	inboxDateLayout   = "Jan 2 15:04"
+	inboxStatusWidth  = 10
+	inboxSourceWidth  = 10
+	inboxSessionWidth = 20
+)
+
+// saveInboxCmd returns a command that persists the inbox.
+// Errors are ignored; the next change triggers another save.
+func saveInboxCmd(store *inbox.Store) tea.Cmd {
+	return func() tea.Msg {
+		_ = store.Save()
+		return nil
+	}
+}
+
+// recordInboxTransitions adds the priority transitions since the last poll to
+// the inbox. It returns a save command when anything was recorded.
+func (m *Model) recordInboxTransitions(now time.Time) tea.Cmd {
+	if m.inbox == nil {
+		return nil
+	}
+	items := m.inboxTracker.Observe(m.sessions, now)
+	for _, item := range items {
+		m.inbox.Add(item)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	if m.dialogMode == DialogInbox {
+		m.refreshInboxItems()
+	}
+	return saveInboxCmd(m.inbox)
+}
+
+// openInbox opens the inbox dialog at the newest item.
+func (m *Model) openInbox() {
+	m.dialogMode = DialogInbox
+	m.dialogError = ""
+	m.inboxCursor = 0
+	m.inboxScroll = 0
+	m.refreshInboxItems()
+}
+
+// refreshInboxItems reloads the listed items from the store, keeping the
+// cursor on the same item when it is still listed.
+func (m *Model) refreshInboxItems() {
+	var current int64
+	if m.inboxCursor < len(m.inboxItems) {
+		current = m.inboxItems[m.inboxCursor].ID
+	}
+
+	m.inboxItems = nil
+	for _, item := range m.inbox.Items() {
+		if !m.inboxUnreadOnly || !item.Acked {
+			m.inboxItems = append(m.inboxItems, item)
+		}
+	}
+
+	for i, item := range m.inboxItems {
+		if item.ID == current {
+			m.inboxCursor = i
+			break
+		}
+	}
+	m.moveInboxCursor(0)
+}
+
+// moveInboxCursor moves the inbox cursor by delta, keeping it visible.
+func (m *Model) moveInboxCursor(delta int) {
+	m.inboxCursor = max(0, min(m.inboxCursor+delta, len(m.inboxItems)-1))
+	if m.inboxCursor < m.inboxScroll {
+		m.inboxScroll = m.inboxCursor
+	}
+	if m.inboxCursor >= m.inboxScroll+inboxMaxVisible {
+		m.inboxScroll = m.inboxCursor - inboxMaxVisible + 1
+	}
+}
+
+// inboxSession returns the live session an inbox item refers to: the same
+// claude session when it is still running, otherwise the tmux session with
+// the same label.
+func (m Model) inboxSession(item inbox.Item) (session.Info, bool) {
+	for _, s := range m.sessions {
+		if s.ID() == item.SessionID {
+			return s, true
+		}
+	}
+	for _, s := range m.sessions {
+		if s.Label() == item.Session {
+			return s, true
+		}
+	}
+	return session.Info{}, false
+}
+
+// updateInbox handles key input for the inbox dialog.
+func (m Model) updateInbox(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.keys.Action(keymap.Inbox, msg.String()) {
+	case keymap.Back:
+		m.dialogMode = DialogNone
+		m.dialogError = ""
+		return m, nil
+
+	case keymap.Up:
+		m.moveInboxCursor(-1)
+	case keymap.Down:
+		m.moveInboxCursor(1)
+	case keymap.PageUp:
+		m.moveInboxCursor(-inboxMaxVisible)
+	case keymap.PageDown:
+		m.moveInboxCursor(inboxMaxVisible)
+	case keymap.Top:
+		m.moveInboxCursor(-len(m.inboxItems))
+	case keymap.Bottom:
+		m.moveInboxCursor(len(m.inboxItems))
+
+	case keymap.Filter:
+		m.inboxUnreadOnly = !m.inboxUnreadOnly
+		m.refreshInboxItems()
+
+	case keymap.Ack:
+		if m.inboxCursor < len(m.inboxItems) && m.inbox.Ack(m.inboxItems[m.inboxCursor].ID) {
+			m.refreshInboxItems()
+			return m, saveInboxCmd(m.inbox)
+		}
+
+	case keymap.AckAll:
+		if m.inbox.AckAll() > 0 {
+			m.refreshInboxItems()
+			return m, saveInboxCmd(m.inbox)
+		}
+
+	case keymap.Attach:
+		if m.inboxCursor >= len(m.inboxItems) {
+			return m, nil
+		}
+		item := m.inboxItems[m.inboxCursor]
+		s, ok := m.inboxSession(item)
+		if !ok {
+			m.dialogError = fmt.Sprintf("%s is no longer running", item.Session)
+			return m, nil
+		}
+		m.inbox.Ack(item.ID)
+		m.dialogMode = DialogNone
+		m.dialogError = ""
+		m.lastSelectedSession = s.ID()
+		return m, tea.Batch(saveInboxCmd(m.inbox), m.attachTo(s))
+
+	case keymap.Help:
+		m.openKeyHelp(keymap.Inbox)
+	}
+	return m, nil
+}
+
+// formatInboxTime formats an item's time: the clock time for today,
+// otherwise the date as well.
+func formatInboxTime(ts int64, now time.Time) string {
+	t := time.Unix(ts, 0)
+	if y, m, d := t.Date(); y == now.Year() && m == now.Month() && d == now.Day() {
+		return t.Format(inboxTimeLayout)
+	}
+	return t.Format(inboxDateLayout)
+}
+
+// inboxStatusStyle picks the color for a transition's status.
+func inboxStatusStyle(status string) lipgloss.Style {
+	if status == inbox.StatusStalled {
+		return yellowStyle
+	}
+	return statusTimelineStyle(status)
+}
+
+// renderInbox renders the recorded transitions, newest first, with unread
+// items marked and acknowledged ones dimmed.
+func (m Model) renderInbox() string {
+	var b strings.Builder
+	innerWidth := inboxPanelWidth - 6 // Border and padding
+
+	title := DialogTitle(DialogInbox)
+	if n := m.inbox.Unread(); n > 0 {
+		title += fmt.Sprintf(" (%d unread)", n)
+	}
+	b.WriteString(dialogTitleStyle.Render(title))
+	b.WriteString("\n\n")
+
+	if len(m.inboxItems) == 0 {
+		if m.inboxUnreadOnly {
+			b.WriteString(dimStyle.Render("No unread notifications"))
+		} else {
+			b.WriteString(dimStyle.Render("No notifications yet"))
+		}
+		b.WriteString("\n")
+	} else {
+		now := time.Now()
+		end := min(m.inboxScroll+inboxMaxVisible, len(m.inboxItems))
+		if m.inboxScroll > 0 {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  ↑ %d more", m.inboxScroll)))
+			b.WriteString("\n")
+		}
+		for i := m.inboxScroll; i < end; i++ {
+			b.WriteString(m.renderInboxItem(m.inboxItems[i], innerWidth, i == m.inboxCursor, now))
+			b.WriteString("\n")
+		}
+		if end < len(m.inboxItems) {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  ↓ %d more", len(m.inboxItems)-end)))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	show := "u: unread only"
+	if m.inboxUnreadOnly {
+		show = "u: show all"
+	}
+	b.WriteString(dimStyle.Render("↑↓: move  Enter: jump  a: ack  A: ack all  " + show + "  Esc: close"))
+
+	if m.dialogError != "" {
+		b.WriteString("\n")
+		b.WriteString(dialogErrorStyle.Render(m.dialogError))
+	}
+
+	dialog := dialogBoxStyle.Width(inboxPanelWidth).Render(b.String())
+	return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, dialog)
+}
+
+// renderInboxItem renders one row: unread mark, time, status, session,
+// source and message.
+func (m Model) renderInboxItem(item inbox.Item, width int, selected bool, now time.Time) string {
+	mark := " "
+	if !item.Acked {
+		mark = inboxUnreadMark
+	}
+	status := fmt.Sprintf("%-*s", inboxStatusWidth, item.Status)
+	source := fmt.Sprintf("%-*s", inboxSourceWidth, truncate(item.Source, inboxSourceWidth))
+	name := fmt.Sprintf("%-*s", inboxSessionWidth, truncate(item.Session, inboxSessionWidth))
+	line := fmt.Sprintf("%s %-11s %s %s %s %s", mark, formatInboxTime(item.Timestamp, now), status, name, source, strings.Join(strings.Fields(item.Message), " "))
+	line = ansi.Truncate(line, width, "...")
+
+	switch {
+	case selected:
+		return selectedStyle.Render(line)
+	case item.Acked:
+		return dimStyle.Render(line)
+	default:
+		line = strings.Replace(line, inboxUnreadMark, highlightStyle.Render(inboxUnreadMark), 1)
+		return strings.Replace(line, status, inboxStatusStyle(item.Status).Render(status), 1)
+	}
+}
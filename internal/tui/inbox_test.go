@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/inbox"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// newInboxTestModel returns a session list whose "test" session has just
+// asked for permission, recorded in an inbox backed by a temp file.
+func newInboxTestModel(t *testing.T) Model {
+	t.Helper()
+	m := newPreviewScrollTestModel()
+	m.previewVisible = false
+	m.inbox = inbox.NewStore(filepath.Join(t.TempDir(), "inbox.json"))
+
+	now := time.Now()
+	m.recordInboxTransitions(now)
+	m.sessions[0].Status = session.StatusPermission
+	m.sessions[0].Message = "Bash: make deploy"
+	if cmd := m.recordInboxTransitions(now); cmd == nil {
+		t.Fatal("expected a save command after a transition")
+	}
+	return m
+}
+
+func TestInboxRecordsTransitions(t *testing.T) {
+	m := newPreviewScrollTestModel()
+	m.inbox = inbox.NewStore(filepath.Join(t.TempDir(), "inbox.json"))
+	now := time.Now().Unix()
+
+	updated, _ := m.Update(sessionsMsg{{TmuxSession: "test", Status: session.StatusWorking, Timestamp: now}})
+	m = updated.(Model)
+	updated, _ = m.Update(sessionsMsg{{TmuxSession: "test", Status: session.StatusWaiting, Message: "Done?", Timestamp: now}})
+	m = updated.(Model)
+
+	items := m.inbox.Items()
+	if len(items) != 1 || items[0].Status != session.StatusWaiting || items[0].Message != "Done?" {
+		t.Fatalf("inbox = %+v, want one waiting item", items)
+	}
+	if !strings.Contains(m.renderFooter(), "Inbox: 1 unread") {
+		t.Error("expected the unread count in the footer")
+	}
+}
+
+func TestInboxDialog(t *testing.T) {
+	t.Run("I opens the inbox listing the item", func(t *testing.T) {
+		m := sendKeys(t, newInboxTestModel(t), tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}})
+		if m.dialogMode != DialogInbox || len(m.inboxItems) != 1 {
+			t.Fatalf("dialog = %v with %d items, want the inbox with 1", m.dialogMode, len(m.inboxItems))
+		}
+		view := m.renderInbox()
+		for _, want := range []string{"Inbox (1 unread)", "permission", "test", "claude", "Bash: make deploy"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("inbox view missing %q", want)
+			}
+		}
+	})
+
+	t.Run("a acknowledges and u hides acknowledged items", func(t *testing.T) {
+		m := sendKeys(t, newInboxTestModel(t), tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}})
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+		m = updated.(Model)
+		if cmd == nil || m.inbox.Unread() != 0 {
+			t.Fatal("expected the item acknowledged and saved")
+		}
+		if strings.Contains(m.renderFooter(), "unread") {
+			t.Error("footer should drop the counter once everything is read")
+		}
+
+		m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+		if len(m.inboxItems) != 0 || !strings.Contains(m.renderInbox(), "No unread notifications") {
+			t.Error("unread-only view should hide the acknowledged item")
+		}
+	})
+
+	t.Run("A acknowledges everything", func(t *testing.T) {
+		m := newInboxTestModel(t)
+		m.inbox.Add(inbox.Item{Timestamp: time.Now().Unix(), Session: "other", Status: session.StatusDone})
+		m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+		if m.inbox.Unread() != 0 {
+			t.Errorf("unread = %d, want 0", m.inbox.Unread())
+		}
+	})
+
+	t.Run("enter jumps to the session and acknowledges", func(t *testing.T) {
+		m := sendKeys(t, newInboxTestModel(t), tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}})
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(Model)
+		defer m.stopAttachMonitor()
+		if cmd == nil || m.dialogMode != DialogNone || m.inbox.Unread() != 0 {
+			t.Errorf("dialog = %v, unread = %d; want an attach with the item acknowledged", m.dialogMode, m.inbox.Unread())
+		}
+	})
+
+	t.Run("enter on a gone session reports it", func(t *testing.T) {
+		m := newInboxTestModel(t)
+		m.sessions = nil
+		m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}}, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.dialogMode != DialogInbox || !strings.Contains(m.dialogError, "no longer running") {
+			t.Errorf("dialog = %v, error = %q", m.dialogMode, m.dialogError)
+		}
+	})
+}
@@ -25,12 +25,13 @@ const (
 	focusName = iota
 	focusDir
 	focusSkipPerms
+	focusHost // Only reachable when remotes are configured
 )
 
 // Validation error messages
 var (
 	errEmptyName    = errors.New("session name cannot be empty")
-	errInvalidChars = errors.New("session name cannot contain '.' or ':'")
+	errInvalidChars = errors.New("session name cannot contain '.', ':' or shell metacharacters")
 	errNameExists   = errors.New("session name already exists")
 	errInvalidDir   = errors.New("directory does not exist")
 )
@@ -41,7 +42,18 @@ func initTaskSearchInput() textinput.Model {
 	ti.Placeholder = "Search tasks..."
 	ti.CharLimit = inputSearchCharLimit
 	ti.Width = inputWidth
-	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("99"))
+	ti.PromptStyle = highlightStyle
+	ti.TextStyle = lipgloss.NewStyle()
+	return ti
+}
+
+// initPreviewSearchInput creates and configures a text input for preview scrollback search.
+func initPreviewSearchInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "Search scrollback..."
+	ti.CharLimit = inputSearchCharLimit
+	ti.Width = inputWidth
+	ti.PromptStyle = highlightStyle
 	ti.TextStyle = lipgloss.NewStyle()
 	return ti
 }
@@ -52,7 +64,7 @@ func initSearchInput() textinput.Model {
 	ti.Placeholder = "Search sessions..."
 	ti.CharLimit = inputSearchCharLimit
 	ti.Width = inputWidth
-	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("99"))
+	ti.PromptStyle = highlightStyle
 	ti.TextStyle = lipgloss.NewStyle()
 	return ti
 }
@@ -63,7 +75,7 @@ func initNameInput() textinput.Model {
 	ti.Placeholder = "Session name"
 	ti.CharLimit = inputNameCharLimit
 	ti.Width = inputWidth
-	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("99"))
+	ti.PromptStyle = highlightStyle
 	ti.TextStyle = lipgloss.NewStyle()
 	ti.Focus()
 	return ti
@@ -75,11 +87,23 @@ func initDirInput() textinput.Model {
 	ti.Placeholder = "Working directory"
 	ti.CharLimit = inputDirCharLimit
 	ti.Width = inputWidth
-	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("99"))
+	ti.PromptStyle = highlightStyle
 	ti.TextStyle = lipgloss.NewStyle()
 	return ti
 }
 
+// initFileInput creates and configures a text input for file paths to view.
+func initFileInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "Path relative to the session directory"
+	ti.CharLimit = inputDirCharLimit
+	ti.Width = inputWidth
+	ti.PromptStyle = highlightStyle
+	ti.TextStyle = lipgloss.NewStyle()
+	ti.Focus()
+	return ti
+}
+
 // validateSessionName validates a session name for tmux compatibility.
 // Returns an error if the name is invalid.
 func validateSessionName(name string, existingSessions []session.Info) error {
@@ -89,8 +113,8 @@ func validateSessionName(name string, existingSessions []session.Info) error {
 		return errEmptyName
 	}
 
-	// Check for invalid characters (tmux restrictions)
-	if strings.ContainsAny(name, ".:") {
+	// Check for invalid characters (tmux targets and shell metacharacters)
+	if !session.ValidName(name) {
 		return errInvalidChars
 	}
 
@@ -54,6 +54,24 @@ func TestValidateSessionName(t *testing.T) {
 			sessions: existingSessions,
 			wantErr:  errInvalidChars,
 		},
+		{
+			name:     "contains command substitution",
+			input:    "x$(rm -rf ~)",
+			sessions: existingSessions,
+			wantErr:  errInvalidChars,
+		},
+		{
+			name:     "contains backticks",
+			input:    "x`id`",
+			sessions: existingSessions,
+			wantErr:  errInvalidChars,
+		},
+		{
+			name:     "contains command separator",
+			input:    "x;reboot",
+			sessions: existingSessions,
+			wantErr:  errInvalidChars,
+		},
 		{
 			name:     "duplicate name",
 			input:    "existing-session",
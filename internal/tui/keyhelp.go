@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stwalsh4118/navi/internal/keymap"
+)
+
+// keyHelpKeyWidth is the column width reserved for keys in the help overlay.
+const keyHelpKeyWidth = 16
+
+// openKeyHelp shows the effective key bindings in the content viewer, with
+// the current context first. Closing the viewer returns to the open dialog.
+func (m *Model) openKeyHelp(ctx keymap.Context) {
+	m.openContentViewerFrom("Keys: "+keymap.Title(ctx), m.keyHelpText(ctx), ContentModePlain, m.dialogMode)
+}
+
+// keyHelpText lists the bindings of every context, starting with ctx. The
+// search bindings follow the session list since they only refine it.
+func (m Model) keyHelpText(ctx keymap.Context) string {
+	order := []keymap.Context{ctx}
+	if ctx == keymap.Sessions {
+		order = append(order, keymap.Search)
+	}
+	for _, c := range keymap.Contexts() {
+		if c != ctx && !(ctx == keymap.Sessions && c == keymap.Search) {
+			order = append(order, c)
+		}
+	}
+
+	var b strings.Builder
+	for i, c := range order {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(keymap.Title(c) + "\n")
+		for _, binding := range m.keys.Bindings(c) {
+			fmt.Fprintf(&b, "  %-*s %s\n", keyHelpKeyWidth, keymap.FormatKeys(binding.Keys), binding.Help)
+		}
+	}
+	b.WriteString("\nOverride keys in " + keymap.DefaultConfigPath)
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/git"
+	"github.com/stwalsh4118/navi/internal/keymap"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+func newKeymapModel(t *testing.T, cfg keymap.Config) Model {
+	t.Helper()
+	keys := keymap.Default()
+	if err := keys.Override(cfg); err != nil {
+		t.Fatalf("Override error: %v", err)
+	}
+	return Model{
+		width:  120,
+		height: 40,
+		keys:   keys,
+		sessions: []session.Info{
+			{TmuxSession: "api", Status: session.StatusWorking, Timestamp: 100},
+			{TmuxSession: "web", Status: session.StatusDone, Timestamp: 90},
+		},
+	}
+}
+
+func TestRemappedSessionKey(t *testing.T) {
+	m := newKeymapModel(t, keymap.Config{keymap.Sessions: {keymap.Kill: {"X"}}})
+
+	m = typeText(t, m, "x")
+	if m.dialogMode != DialogNone {
+		t.Fatalf("x opened dialog %v after remapping kill", m.dialogMode)
+	}
+
+	m = typeText(t, m, "X")
+	if m.dialogMode != DialogKillConfirm {
+		t.Fatalf("dialogMode = %v, want DialogKillConfirm", m.dialogMode)
+	}
+}
+
+func TestSearchBindingsShadowSessionBindings(t *testing.T) {
+	m := newKeymapModel(t, nil)
+	m.searchQuery = "e"
+	m.searchMatches = []int{0, 1}
+
+	m = typeText(t, m, "n")
+	if m.dialogMode != DialogNone {
+		t.Fatalf("n opened dialog %v while searching", m.dialogMode)
+	}
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d, want next match 1", m.cursor)
+	}
+
+	m.searchQuery = ""
+	m = typeText(t, m, "n")
+	if m.dialogMode != DialogNewSession {
+		t.Errorf("dialogMode = %v, want DialogNewSession without a search", m.dialogMode)
+	}
+}
+
+func TestKeyHelpListsEffectiveBindings(t *testing.T) {
+	m := newKeymapModel(t, keymap.Config{keymap.Sessions: {keymap.Kill: {"X"}}})
+
+	m = typeText(t, m, "?")
+	if m.dialogMode != DialogContentViewer {
+		t.Fatalf("dialogMode = %v, want DialogContentViewer", m.dialogMode)
+	}
+	if m.contentViewerTitle != "Keys: Session list" {
+		t.Errorf("title = %q", m.contentViewerTitle)
+	}
+	text := strings.Join(m.contentViewerLines, "\n")
+	if !strings.Contains(text, "X ") || !strings.Contains(text, "Kill session") {
+		t.Errorf("help does not list the remapped kill key:\n%s", text)
+	}
+	if strings.Index(text, "Session list while searching") > strings.Index(text, "Preview pane") {
+		t.Error("search bindings should follow the session list")
+	}
+
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.dialogMode != DialogNone {
+		t.Errorf("dialogMode = %v after closing help, want DialogNone", m.dialogMode)
+	}
+}
+
+func TestKeyHelpReturnsToGitDetail(t *testing.T) {
+	m := newKeymapModel(t, nil)
+	s := m.sessions[0]
+	s.Git = &git.Info{Branch: "main"}
+	m.sessionToModify = &s
+	m.dialogMode = DialogGitDetail
+
+	m = typeText(t, m, "?")
+	if m.dialogMode != DialogContentViewer || m.contentViewerTitle != "Keys: Git detail" {
+		t.Fatalf("dialogMode = %v, title = %q", m.dialogMode, m.contentViewerTitle)
+	}
+
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.dialogMode != DialogGitDetail {
+		t.Errorf("dialogMode = %v after closing help, want DialogGitDetail", m.dialogMode)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -12,15 +13,22 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/stwalsh4118/navi/internal/alert"
 	"github.com/stwalsh4118/navi/internal/audio"
 	"github.com/stwalsh4118/navi/internal/git"
+	"github.com/stwalsh4118/navi/internal/history"
+	"github.com/stwalsh4118/navi/internal/inbox"
+	"github.com/stwalsh4118/navi/internal/keymap"
 	"github.com/stwalsh4118/navi/internal/metrics"
 	"github.com/stwalsh4118/navi/internal/monitor"
 	"github.com/stwalsh4118/navi/internal/pathutil"
 	"github.com/stwalsh4118/navi/internal/pm"
 	"github.com/stwalsh4118/navi/internal/remote"
+	"github.com/stwalsh4118/navi/internal/resource"
 	"github.com/stwalsh4118/navi/internal/session"
 	"github.com/stwalsh4118/navi/internal/task"
+	"github.com/stwalsh4118/navi/internal/toolstats"
+	"github.com/stwalsh4118/navi/internal/uistate"
 )
 
 // Model is the Bubble Tea application state for navi.
@@ -40,8 +48,10 @@ type Model struct {
 	// Text inputs for dialogs
 	nameInput       textinput.Model // Session name input
 	dirInput        textinput.Model // Working directory input
+	fileInput       textinput.Model // File path input in the git detail view
 	focusedInput    int             // Which input is focused (0 = name, 1 = dir, 2 = skipPerms)
 	skipPermissions bool            // Whether to start claude with --dangerously-skip-permissions
+	newSessionHost  string          // Remote to create the new session on, empty for local
 	sessionToModify *session.Info   // Session being killed or renamed
 
 	// Preview pane state
@@ -52,24 +62,111 @@ type Model struct {
 	previewWidth        int           // Width of preview pane in columns (side layout)
 	previewHeight       int           // Height of preview pane in rows (bottom layout)
 	previewWrap         bool          // Whether to wrap long lines (true) or truncate (false)
+	previewPlain        bool          // Whether to strip ANSI colors from previews
 	previewScrollOffset int           // First visible line in preview pane
 	previewAutoScroll   bool          // Auto-scroll to bottom on new content (default true)
 	previewFocused      bool          // Whether keyboard focus is in preview pane
 	previewLastCapture  time.Time     // Last capture timestamp for debouncing
 	previewLastCursor   int           // Last cursor position for detecting cursor changes
 
+	// Persisted UI state (see uistate.go)
+	uiStateWriter         *uistate.Writer // Saves view settings in order; nil disables saving
+	savedUIState          uistate.State   // Last saved or restored view settings
+	previewRestorePending bool            // Preview was restored and awaits its first capture
+
+	// Preview scrollback state. Indices count from the oldest history line.
+	previewTailStart   int             // Scrollback index of the first line of previewContent
+	previewHistorySize int             // Pane history lines at the last capture
+	previewHistory     []string        // Older lines loaded above previewContent, oldest first
+	previewDeep        bool            // Whether scrollback is being browsed (live refresh paused)
+	previewLoading     bool            // Whether a scrollback capture is in flight
+	previewLoadGen     int             // Generation of the latest scrollback capture
+	previewSearchMode  bool            // Whether preview search input is active
+	previewSearchInput textinput.Model // Text input for preview search
+	previewSearchQuery string          // Current preview search text
+	previewMatches     []int           // Scrollback indices of lines matching the query
+	previewMatchIdx    int             // Position within previewMatches
+
+	// Command palette state
+	paletteInput   textinput.Model // Query input
+	paletteEntries []paletteEntry  // Entries gathered when the palette opened
+	paletteMatches []paletteMatch  // Entries matching the query, best first
+	paletteCursor  int             // Selected position within paletteMatches
+	paletteScroll  int             // First visible position within paletteMatches
+
 	// Git info cache
 	gitCache map[string]*git.Info // Cache of git info by session working directory
 
-	// Resource metrics cache (RSS bytes by session name)
-	resourceCache map[string]int64
+	// Resource usage cache (process tree snapshot by session ID)
+	resourceCache   map[string]resource.Usage
+	resourceSampler *resource.Sampler // CPU% deltas between resource polls
+
+	// Remote resource and token metrics (by remoteMetricsKey)
+	remoteMetricsCache map[string]*metrics.Metrics
+
+	// Resource threshold alerts (active breach labels by session ID)
+	alertEvaluator *alert.Evaluator
+	resourceAlerts map[string][]string
+
+	// Process tree dialog state
+	processCursor       int // Selected row in the process tree
+	processScrollOffset int // Viewport scroll offset
+
+	// Remote host key awaiting a trust decision (DialogHostKey)
+	hostKeyPrompt *remote.HostKeyPrompt
+
+	// Remotes panel state
+	remotesCursor     int                   // Selected row in the remotes panel
+	remotesConfigPath string                // remotes.yaml written by the remote editor
+	remotesWatcher    *remote.ConfigWatcher // Detects edits to remotes.yaml for hot reload
+	remotesInvalid    []remote.ConfigError  // remotes.yaml entries that failed validation
+	remotesLoadErr    error                 // Last failure to read or parse remotes.yaml
+	remoteDeleting    string                // Remote awaiting delete confirmation
+	remoteForm        remoteForm            // Add/edit remote dialog state
+
+	// Multi-select and bulk action state
+	selectedSessions map[string]bool // Selected session IDs
+	bulkAction       bulkAction      // Action awaiting confirmation or running
+	bulkTargets      []session.Info  // Sessions the bulk action applies to
+	bulkInput        textinput.Model // Prompt or group name for the bulk action
+	bulkRunning      bool            // Whether the bulk action is still running
+	bulkResults      []bulkResult    // Per-session outcome of the last bulk action
+
+	// Session groups (assignments by session label)
+	sessionGroups session.Groups
+	groupsPath    string
+
+	// Mosaic view state
+	mosaicVisible bool
+	mosaicZoomed  bool                  // Whether one tile fills the view
+	mosaicCursor  int                   // Selected tile index
+	mosaicTiles   map[string]mosaicTile // Last capture by session ID
+	mosaicPending map[string]bool       // Captures in flight by session ID
+	mosaicGen     int                   // Tick chain generation
+
+	// Key bindings (defaults merged with ~/.config/navi/keys.yaml)
+	keys *keymap.Keymap
+
+	// Metrics history store (sampled time series by session ID)
+	metricsHistory *history.Store
+
+	// Notification inbox of priority transitions
+	inbox           *inbox.Store
+	inboxTracker    inbox.Tracker
+	inboxItems      []inbox.Item // Items listed in the inbox dialog, newest first
+	inboxCursor     int
+	inboxScroll     int
+	inboxUnreadOnly bool // Whether acknowledged items are hidden
+
+	// Tool timing stats for the metrics detail view (lazily loaded)
+	toolStats *toolStatsMsg
 
 	// Remote session support
 	Remotes             []remote.Config    // Configured remote machines
 	SSHPool             *remote.SSHPool    // SSH connection pool for remotes
 	filterMode          session.FilterMode // Current session filter mode
 	audioNotifier       *audio.Notifier    // Audio notification manager
-	lastSessionStates   map[string]string  // Last known status by session name
+	lastSessionStates   map[string]string  // Last known status by session ID
 	lastAgentStates     map[string]map[string]string
 	attachMonitor       *monitor.AttachMonitor
 	attachMonitorCancel context.CancelFunc
@@ -132,6 +229,7 @@ type Model struct {
 	pmEventScrollOffset   int
 	pmExpandedProjects    map[string]bool
 	pmProjectFilterDir    string
+	pmProjectFilterRemote string
 	pmLastError           string
 
 	// Sound pack picker state
@@ -155,7 +253,8 @@ type attachDoneMsg struct{}
 
 // createSessionResultMsg is returned after attempting to create a new session.
 type createSessionResultMsg struct {
-	err error
+	err    error
+	remote string // Remote the session was created on, empty for local
 }
 
 // killSessionResultMsg is returned after attempting to kill a session.
@@ -171,8 +270,10 @@ type renameSessionResultMsg struct {
 
 // previewContentMsg is returned after capturing preview content.
 type previewContentMsg struct {
-	content string
-	err     error
+	content     string
+	start       int // Scrollback index of the first line of content
+	historySize int // Pane history lines at capture time
+	err         error
 }
 
 // previewTickMsg is sent to trigger periodic preview refresh.
@@ -213,8 +314,23 @@ type soundPacksMsg struct {
 // resourceTickMsg is sent to trigger periodic resource usage polling.
 type resourceTickMsg time.Time
 
-// resourcePollMsg carries polled RSS data keyed by session name.
-type resourcePollMsg map[string]int64
+// resourcePollMsg carries polled process tree usage keyed by session ID.
+type resourcePollMsg map[string]resource.Usage
+
+// processTreeRefreshMsg carries usage re-polled for the session whose process
+// tree is open after a kill. It covers only that session, so alerts and the
+// metrics history are left to the next full resourcePollMsg.
+type processTreeRefreshMsg resourcePollMsg
+
+// remoteMetricsMsg carries metrics collected from remotes, keyed by remote name
+// and then tmux session name. Remotes whose poll failed are absent.
+type remoteMetricsMsg map[string]map[string]*metrics.Metrics
+
+// processKillResultMsg is returned after sending SIGTERM to a child process.
+type processKillResultMsg struct {
+	pid int
+	err error
+}
 
 // gitTickMsg is sent to trigger periodic git info refresh.
 type gitTickMsg time.Time
@@ -243,6 +359,13 @@ type gitPRCommentsMsg struct {
 	err      error
 }
 
+// toolStatsMsg is returned after aggregating tool call timing for a session and its project.
+type toolStatsMsg struct {
+	sessionName string
+	session     toolstats.Summary
+	project     toolstats.Summary
+}
+
 // remoteSessionsMsg is the Bubble Tea message for remote session polling results.
 type remoteSessionsMsg struct {
 	sessions []session.Info
@@ -255,6 +378,11 @@ type remoteGitInfoMsg struct {
 	err  error
 }
 
+// hostKeyTrustedMsg is returned after writing a trusted host key to known_hosts.
+type hostKeyTrustedMsg struct {
+	err error
+}
+
 // remoteDismissResultMsg is returned after dismissing a remote session via SSH.
 type remoteDismissResultMsg struct {
 	err error
@@ -280,8 +408,22 @@ func (m Model) Init() tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
-// Update implements tea.Model.
+// Update implements tea.Model. View settings changed while handling msg are
+// saved so they survive a restart.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	updated, ok := next.(Model)
+	if !ok {
+		return next, cmd
+	}
+	if save := updated.persistUIState(); save != nil {
+		return updated, tea.Batch(cmd, save)
+	}
+	return updated, cmd
+}
+
+// update handles msg for Update.
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		// Handle dialog mode first - block main keybindings when dialog is open
@@ -289,11 +431,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateDialog(msg)
 		}
 
+		// The command palette opens from every view except while typing
+		if ctx := m.keyContext(); ctx != "" && m.keys.Action(ctx, msg.String()) == keymap.Palette {
+			return m.openPalette()
+		}
+
 		// Handle PM view mode before any other focused panel handlers.
 		if m.pmViewVisible {
 			return m.updatePMView(msg)
 		}
 
+		// Handle mosaic view mode
+		if m.mosaicVisible {
+			return m.updateMosaicView(msg)
+		}
+
 		// Handle task panel focus mode - route to task panel keybindings
 		if m.taskPanelFocused {
 			return m.updateTaskPanelFocus(msg)
@@ -310,8 +462,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Main keybindings (only when no dialog is open and not in search mode)
-		switch msg.String() {
-		case "up", "k":
+		switch action := m.sessionAction(msg.String()); action {
+		case keymap.Up:
 			filteredSessions := m.getFilteredSessions()
 			if len(filteredSessions) > 0 {
 				oldCursor := m.cursor
@@ -335,7 +487,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "down", "j":
+		case keymap.Down:
 			filteredSessions := m.getFilteredSessions()
 			if len(filteredSessions) > 0 {
 				oldCursor := m.cursor
@@ -359,29 +511,37 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "enter":
+		case keymap.Attach:
 			filteredSessions := m.getFilteredSessions()
 			if len(filteredSessions) > 0 && m.cursor < len(filteredSessions) {
 				s := filteredSessions[m.cursor]
-				m.lastSelectedSession = s.TmuxSession
+				m.lastSelectedSession = s.ID()
+				return m, m.attachTo(s)
+			}
+			return m, nil
 
-				// Check if this is a remote session
-				if s.Remote != "" && m.SSHPool != nil {
-					// Get the remote config for this session
-					r := m.SSHPool.GetRemoteConfig(s.Remote)
-					if r != nil {
-						m.startAttachMonitor()
-						return m, attachRemoteSession(r, s.TmuxSession)
-					}
-					// Fallback to local attach if remote config not found
-				}
+		case keymap.Select:
+			m.toggleSelection()
+			return m, nil
 
-				m.startAttachMonitor()
-				return m, attachSession(s.TmuxSession)
-			}
+		case keymap.SelectMatches:
+			m.selectMatches()
 			return m, nil
 
-		case "d":
+		case keymap.SelectStatus:
+			m.selectSameStatus()
+			return m, nil
+
+		case keymap.SendPrompt:
+			return m.openBulkConfirm(bulkSendPrompt)
+
+		case keymap.Group:
+			return m.openBulkConfirm(bulkMoveToGroup)
+
+		case keymap.Dismiss:
+			if len(m.selectedSessions) > 0 {
+				return m.openBulkConfirm(bulkDismiss)
+			}
 			filteredSessions := m.getFilteredSessions()
 			if len(filteredSessions) > 0 && m.cursor < len(filteredSessions) {
 				s := filteredSessions[m.cursor]
@@ -394,15 +554,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "r":
+		case keymap.Refresh:
 			return m, pollSessions
 
-		case "n":
-			// When search query is active, jump to next match
-			if m.searchQuery != "" {
-				m.nextMatch()
-				return m, nil
-			}
+		case keymap.NextMatch:
+			// Only bound while a search query is active
+			m.nextMatch()
+			return m, nil
+
+		case keymap.NewSession:
 			// Open new session dialog
 			m.dialogMode = DialogNewSession
 			m.dialogError = ""
@@ -411,17 +571,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.dirInput.SetValue(getDefaultDirectory())
 			m.focusedInput = focusName
 			m.skipPermissions = false
+			m.newSessionHost = ""
 			return m, nil
 
-		case "N":
-			// When search query is active, jump to previous match
-			if m.searchQuery != "" {
-				m.prevMatch()
-				return m, nil
-			}
+		case keymap.PrevMatch:
+			// Only bound while a search query is active
+			m.prevMatch()
 			return m, nil
 
-		case "x":
+		case keymap.Kill:
+			if len(m.selectedSessions) > 0 {
+				return m.openBulkConfirm(bulkKill)
+			}
 			// Open kill confirmation dialog
 			filteredSessions := m.getFilteredSessions()
 			if len(filteredSessions) > 0 && m.cursor < len(filteredSessions) {
@@ -433,7 +594,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "R":
+		case keymap.Rename:
 			// Open rename dialog
 			filteredSessions := m.getFilteredSessions()
 			if len(filteredSessions) > 0 && m.cursor < len(filteredSessions) {
@@ -448,7 +609,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "tab":
+		case keymap.Focus:
 			// Tab enters task panel focus when task panel is visible
 			if m.taskPanelVisible {
 				m.taskPanelFocused = true
@@ -464,11 +625,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Otherwise toggle preview (same as p)
 			return m.togglePreview()
 
-		case "p":
+		case keymap.TogglePreview:
 			// Toggle preview pane visibility (mutually exclusive with task panel)
 			return m.togglePreview()
 
-		case "[":
+		case keymap.Shrink:
 			// Shrink active panel
 			if m.taskPanelVisible {
 				currentHeight := m.getTaskPanelHeight()
@@ -496,7 +657,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "]":
+		case keymap.Grow:
 			// Expand active panel
 			if m.taskPanelVisible {
 				contentHeight := m.height - 8
@@ -529,7 +690,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "L":
+		case keymap.Layout:
 			// Toggle preview layout between side and bottom
 			if m.previewVisible {
 				if m.previewLayout == PreviewLayoutSide {
@@ -540,14 +701,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "W":
+		case keymap.Wrap:
 			// Toggle preview wrap mode
 			if m.previewVisible {
 				m.previewWrap = !m.previewWrap
 			}
 			return m, nil
 
-		case "G":
+		case keymap.Color:
+			// Toggle between colored and stripped preview output
+			if m.previewVisible {
+				m.previewPlain = !m.previewPlain
+			}
+			return m, nil
+
+		case keymap.GitDetail:
 			// Open git detail view for selected session
 			filteredSessions := m.getFilteredSessions()
 			if len(filteredSessions) > 0 && m.cursor < len(filteredSessions) {
@@ -573,7 +741,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "i":
+		case keymap.Metrics:
 			// Open metrics detail view for selected session
 			filteredSessions := m.getFilteredSessions()
 			if len(filteredSessions) > 0 && m.cursor < len(filteredSessions) {
@@ -581,25 +749,67 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.sessionToModify = &s
 				m.dialogMode = DialogMetricsDetail
 				m.dialogError = ""
+				m.toolStats = nil
+
+				// Tool call logs are only written locally by the tool-tracker hook
+				if s.Remote == "" {
+					return m, fetchToolStatsCmd(s.TmuxSession, s.CWD)
+				}
+				return m, nil
+			}
+			return m, nil
+
+		case keymap.InboxView:
+			m.openInbox()
+			return m, nil
+
+		case keymap.Remotes:
+			// Open remote connection health panel, where remotes can also be added
+			m.dialogMode = DialogRemotes
+			m.dialogError = ""
+			m.remoteDeleting = ""
+			if m.remotesCursor >= m.remotesRowCount() {
+				m.remotesCursor = 0
+			}
+			return m, nil
+
+		case keymap.Processes:
+			// Open process tree view for selected local session
+			filteredSessions := m.getFilteredSessions()
+			if len(filteredSessions) > 0 && m.cursor < len(filteredSessions) {
+				s := filteredSessions[m.cursor]
+				if s.Remote != "" {
+					return m, nil
+				}
+				m.sessionToModify = &s
+				m.dialogMode = DialogProcessTree
+				m.dialogError = ""
+				m.processCursor = 0
+				m.processScrollOffset = 0
 				return m, nil
 			}
 			return m, nil
 
-		case "/":
+		case keymap.Find:
 			// Enter search mode
 			m.searchMode = true
 			m.searchInput.SetValue("")
 			m.searchInput.Focus()
 			return m, nil
 
-		case "esc":
-			// Clear search state first if search is active (persisted after Enter)
+		case keymap.Back:
+			// Clear the selection first, then search state (persisted after Enter)
+			if len(m.selectedSessions) > 0 {
+				m.selectedSessions = nil
+				return m, nil
+			}
 			if m.searchQuery != "" {
 				m.clearSearchState()
 				return m, nil
 			}
 			if m.pmProjectFilterDir != "" {
 				m.pmProjectFilterDir = ""
+				m.pmProjectFilterRemote = ""
 				m.cursor = 0
 				m.sessionScrollOffset = 0
 				return m, nil
@@ -613,7 +823,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "s":
+		case keymap.Sort:
 			// Cycle sort mode: Priority -> Name -> Age -> Status -> Directory -> Priority
 			m.sortMode = (m.sortMode + 1) % SortMode(sortModeCount)
 			if m.searchQuery != "" {
@@ -621,9 +831,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "o":
+		case keymap.Offline:
 			// Toggle offline session visibility
-			selectedSession := m.selectedSessionName()
+			selectedSession := m.selectedSessionID()
 			m.hideOffline = !m.hideOffline
 			m.preserveCursor(selectedSession)
 			if m.searchQuery != "" {
@@ -631,21 +841,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "0":
+		case keymap.ClearFilters:
 			// Clear status and project filters
-			selectedSession := m.selectedSessionName()
+			selectedSession := m.selectedSessionID()
 			m.statusFilter = ""
 			m.pmProjectFilterDir = ""
+			m.pmProjectFilterRemote = ""
 			m.preserveCursor(selectedSession)
 			if m.searchQuery != "" {
 				m.computeSearchMatches()
 			}
 			return m, nil
 
-		case "1", "2", "3", "4", "5":
-			// Toggle status filter by number key
-			selectedSession := m.selectedSessionName()
-			targetStatus := statusFilterKeys[msg.String()]
+		case keymap.FilterWaiting, keymap.FilterPerm, keymap.FilterWorking, keymap.FilterDone, keymap.FilterError:
+			// Toggle status filter (number keys by default)
+			selectedSession := m.selectedSessionID()
+			targetStatus := statusFilterActions[action]
 			if m.statusFilter == targetStatus {
 				m.statusFilter = "" // Toggle off if same key pressed
 			} else {
@@ -657,10 +868,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "f":
+		case keymap.Filter:
 			// Cycle filter mode: All -> Local -> Remote -> All
 			if len(m.Remotes) > 0 {
-				selectedSession := m.selectedSessionName()
+				selectedSession := m.selectedSessionID()
 				switch m.filterMode {
 				case session.FilterAll:
 					m.filterMode = session.FilterLocal
@@ -676,7 +887,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "T":
+		case keymap.TaskPanel:
 			// Toggle task panel (mutually exclusive with preview)
 			m.taskPanelVisible = !m.taskPanelVisible
 			m.taskPanelUserEnabled = m.taskPanelVisible
@@ -691,7 +902,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				// If we have a config for this project but no data yet, trigger a refresh
 				if len(m.taskGroups) == 0 && m.taskFocusedProject != "" {
-					return m, taskRefreshCmd(m.taskProjectConfigs, m.taskCache, m.taskGlobalConfig, task.DefaultProviderTimeout)
+					return m, taskRefreshCmd(m.taskProjectConfigs, m.taskCache, m.taskGlobalConfig, task.DefaultProviderTimeout, m.SSHPool)
 				}
 			} else {
 				// Clear focus and search when hiding
@@ -700,16 +911,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "P":
+		case keymap.PMView:
 			return m, m.togglePMView()
 
-		case "m":
+		case keymap.MosaicView:
+			return m, m.toggleMosaicView()
+
+		case keymap.Mute:
 			if m.audioNotifier != nil {
 				m.audioNotifier.SetMuted(!m.audioNotifier.IsMuted())
 			}
 			return m, nil
 
-		case "S":
+		case keymap.Sounds:
 			// Open sound pack picker dialog (only when audio is configured)
 			if m.audioNotifier != nil {
 				m.dialogMode = DialogSoundPackPicker
@@ -720,7 +934,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "q", "ctrl+c":
+		case keymap.Help:
+			m.openKeyHelp(keymap.Sessions)
+			return m, nil
+
+		case keymap.Quit:
 			return m, tea.Quit
 		}
 
@@ -732,7 +950,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		if len(m.taskProjectConfigs) > 0 {
 			return m, tea.Batch(
-				taskRefreshCmd(m.taskProjectConfigs, m.taskCache, m.taskGlobalConfig, task.DefaultProviderTimeout),
+				taskRefreshCmd(m.taskProjectConfigs, m.taskCache, m.taskGlobalConfig, task.DefaultProviderTimeout, m.SSHPool),
 				taskTickCmd(interval),
 			)
 		}
@@ -768,7 +986,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, pmTickCmd()
 		}
 		m.pmRunInFlight = true
-		return m, tea.Batch(pmRunCmd(m.pmEngine, m.sessions, m.pmTaskResults), pmTickCmd())
+		return m, tea.Batch(pmRunCmd(m.pmEngine, m.sessions, m.pmTaskResults, m.SSHPool), pmTickCmd())
 
 	case pmOutputMsg:
 		m.pmRunInFlight = false
@@ -803,11 +1021,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.pmLastError = msg.err.Error()
 		return m, nil
 
+	case remoteContentMsg:
+		m.applyRemoteContent(msg)
+		return m, nil
+
+	case remoteTaskFileMsg:
+		if msg.err != nil {
+			m.openContentViewer("Error", fmt.Sprintf("Could not read file:\n%s\n\nError: %s", msg.path, msg.err.Error()), ContentModePlain)
+			return m, nil
+		}
+		m.openContentViewer(msg.title, string(msg.content), ContentModePlain)
+		return m, nil
+
 	case taskConfigsMsg:
 		m.taskProjectConfigs = msg.configs
 		// Trigger task refresh if we have new configs
 		if len(msg.configs) > 0 {
-			return m, taskRefreshCmd(msg.configs, m.taskCache, m.taskGlobalConfig, task.DefaultProviderTimeout)
+			return m, taskRefreshCmd(msg.configs, m.taskCache, m.taskGlobalConfig, task.DefaultProviderTimeout, m.SSHPool)
 		}
 		return m, nil
 
@@ -816,9 +1046,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Also poll remote sessions if configured
 		cmds := []tea.Cmd{pollSessions, tickCmd()}
 		if m.SSHPool != nil && len(m.Remotes) > 0 {
-			cmds = append(cmds, func() tea.Msg {
-				return remoteSessionsMsg{sessions: remote.PollSessions(m.SSHPool, m.Remotes)}
-			})
+			cmds = append(cmds, m.pollRemoteSessionsCmd())
+		}
+		if m.remotesWatcher != nil {
+			cmds = append(cmds, checkRemotesConfigCmd(m.remotesWatcher))
 		}
 		return m, tea.Batch(cmds...)
 
@@ -836,6 +1067,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		allSessions = append(allSessions, remoteSessions...)
 		session.SortSessions(allSessions)
 		m.sessions = allSessions
+		m.sessionGroups.Apply(m.sessions)
+		m.pruneSelection()
 
 		// Merge cached git info into sessions
 		if m.gitCache != nil {
@@ -848,6 +1081,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Merge cached resource metrics into sessions
 		m.mergeResourceCache()
 		m.detectStatusChanges(m.sessions)
+		saveInbox := m.recordInboxTransitions(time.Now())
 
 		// Trigger immediate git poll if cache is empty and we have sessions
 		// This makes git info appear quickly on startup instead of waiting for gitPollInterval
@@ -857,7 +1091,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var currentCWDs []sessionCWD
 		for _, s := range m.sessions {
 			if s.CWD != "" {
-				currentCWDs = append(currentCWDs, sessionCWD{cwd: s.CWD})
+				currentCWDs = append(currentCWDs, sessionCWD{cwd: s.CWD, remote: s.Remote})
 			}
 		}
 		cwdStrings := extractSessionCWDs(currentCWDs)
@@ -867,12 +1101,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		var cmds []tea.Cmd
+		if saveInbox != nil {
+			cmds = append(cmds, saveInbox)
+		}
 
 		// Try to restore cursor to last selected session if set
 		filteredSessions := m.getFilteredSessions()
 		if m.lastSelectedSession != "" {
 			for i, s := range filteredSessions {
-				if s.TmuxSession == m.lastSelectedSession {
+				if s.ID() == m.lastSelectedSession {
 					m.cursor = i
 					m.lastSelectedSession = "" // Clear after restoring
 					break
@@ -899,9 +1136,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, m.pollAllGitInfoCmd())
 		}
 
+		// Start the preview restored from the saved UI state once there is
+		// a session to show
+		if m.previewRestorePending && m.previewVisible && m.cursor < len(filteredSessions) {
+			m.previewRestorePending = false
+			m.previewLastCursor = m.cursor
+			cmds = append(cmds, m.capturePreviewForSession(filteredSessions[m.cursor]), previewTickCmd())
+		}
+
 		// Trigger task config discovery if CWDs changed
 		if cwdsChanged && len(currentCWDs) > 0 {
-			cmds = append(cmds, discoverTaskConfigsCmd(currentCWDs, m.taskGlobalConfig))
+			cmds = append(cmds, discoverTaskConfigsCmd(currentCWDs, m.taskGlobalConfig, m.SSHPool))
 		}
 
 		if len(cmds) > 0 {
@@ -909,6 +1154,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case remoteSessionsMsg:
+		// Ask the user about any unknown host keys found while polling
+		m.promptPendingHostKey()
+
 		// Merge remote sessions with existing local sessions
 		if len(msg.sessions) > 0 {
 			// Keep local sessions (Remote == ""), add remote sessions
@@ -923,6 +1171,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Re-sort the combined list
 			session.SortSessions(allSessions)
 			m.sessions = allSessions
+			m.sessionGroups.Apply(m.sessions)
+			m.pruneSelection()
 
 			// Merge cached git info into sessions
 			if m.gitCache != nil {
@@ -935,6 +1185,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Merge cached resource metrics into sessions
 			m.mergeResourceCache()
 			m.detectStatusChanges(m.sessions)
+			saveInbox := m.recordInboxTransitions(time.Now())
 
 			// Clamp cursor for filtered sessions
 			filteredSessions := m.getFilteredSessions()
@@ -946,9 +1197,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// This runs after remote session polling completes, avoiding SSH mutex contention.
 			if m.SSHPool != nil {
 				if cmd := pollRemoteGitInfoCmd(m.SSHPool, m.sessions); cmd != nil {
-					return m, cmd
+					return m, tea.Batch(cmd, saveInbox)
 				}
 			}
+			if saveInbox != nil {
+				return m, saveInbox
+			}
 		}
 
 	case tea.WindowSizeMsg:
@@ -991,6 +1245,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Success - close dialog and refresh
 		m.dialogMode = DialogNone
 		m.dialogError = ""
+		if msg.remote != "" && m.SSHPool != nil {
+			return m, m.pollRemoteSessionsCmd()
+		}
 		return m, pollSessions
 
 	case killSessionResultMsg:
@@ -1012,15 +1269,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		// Success - close dialog and set lastSelectedSession for cursor preservation
+		if m.sessionToModify != nil {
+			renamed := *m.sessionToModify
+			renamed.TmuxSession = msg.newName
+			m.lastSelectedSession = renamed.ID() // Preserve cursor position on renamed session
+		}
 		m.dialogMode = DialogNone
 		m.dialogError = ""
 		m.sessionToModify = nil
-		m.lastSelectedSession = msg.newName // Preserve cursor position on renamed session
 		return m, pollSessions
 
 	case previewContentMsg:
-		if msg.err == nil {
+		if msg.err == nil && !m.previewDeep {
 			m.previewContent = msg.content
+			m.previewTailStart = msg.start
+			m.previewHistorySize = msg.historySize
 			m.previewLastCapture = time.Now()
 			// Auto-scroll is handled in renderPreview - when previewAutoScroll is true,
 			// the render function always shows the bottom of content
@@ -1028,6 +1291,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Silently ignore errors - preview just won't update
 		return m, nil
 
+	case previewHistoryMsg:
+		return m.handlePreviewHistory(msg)
+
 	case previewTickMsg:
 		// Periodic preview refresh
 		filteredSessions := m.getFilteredSessions()
@@ -1035,8 +1301,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Don't continue polling if preview hidden or no sessions
 			return m, nil
 		}
-		// Capture current session and schedule next tick
-		if m.cursor < len(filteredSessions) {
+		// Capture current session and schedule next tick; browsing
+		// scrollback pauses live refresh
+		if m.cursor < len(filteredSessions) && !m.previewDeep {
 			return m, tea.Batch(
 				m.capturePreviewForSession(filteredSessions[m.cursor]),
 				previewTickCmd(),
@@ -1044,11 +1311,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, previewTickCmd()
 
+	case mosaicTickMsg:
+		if !m.mosaicVisible || msg.gen != m.mosaicGen {
+			// Stop the tick chain when the mosaic closes or is reopened
+			return m, nil
+		}
+		return m, m.mosaicCaptureCmd(time.Now(), mosaicTickCmd(m.mosaicGen))
+
+	case mosaicContentMsg:
+		if !m.mosaicVisible {
+			return m, nil
+		}
+		delete(m.mosaicPending, msg.id)
+		m.mosaicTiles[msg.id] = mosaicTile{content: msg.content, err: msg.err, captured: time.Now()}
+		return m, nil
+
 	case previewDebounceMsg:
 		// Debounced capture after cursor movement - reset preview scroll state
 		m.previewScrollOffset = 0
 		m.previewAutoScroll = true
 		m.previewFocused = false
+		m.resetPreviewScrollback()
 		filteredSessions := m.getFilteredSessions()
 		if !m.previewVisible || len(filteredSessions) == 0 {
 			return m, nil
@@ -1086,18 +1369,63 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Copy sessions for the poll goroutine
 		sessionsCopy := make([]session.Info, len(m.sessions))
 		copy(sessionsCopy, m.sessions)
-		return m, tea.Batch(pollResourceMetricsCmd(sessionsCopy), resourceTickCmd())
+		cmds := []tea.Cmd{pollResourceMetricsCmd(m.resourceSampler, sessionsCopy), resourceTickCmd()}
+		if m.SSHPool != nil {
+			if cmd := pollRemoteMetricsCmd(m.SSHPool, sessionsCopy); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+		return m, tea.Batch(cmds...)
+
+	case remoteMetricsMsg:
+		// Replace cached metrics for each remote that answered
+		if m.remoteMetricsCache == nil {
+			m.remoteMetricsCache = make(map[string]*metrics.Metrics)
+		}
+		for remoteName, bySession := range msg {
+			prefix := remoteMetricsKey(remoteName, "")
+			for key := range m.remoteMetricsCache {
+				if strings.HasPrefix(key, prefix) {
+					delete(m.remoteMetricsCache, key)
+				}
+			}
+			for name, met := range bySession {
+				m.remoteMetricsCache[remoteMetricsKey(remoteName, name)] = met
+			}
+		}
+		m.mergeResourceCache()
+		return m, nil
 
 	case resourcePollMsg:
 		// Update resource cache with new poll data
 		if m.resourceCache == nil {
-			m.resourceCache = make(map[string]int64)
+			m.resourceCache = make(map[string]resource.Usage)
 		}
-		for name, rss := range msg {
-			m.resourceCache[name] = rss
+		for name, usage := range msg {
+			m.resourceCache[name] = usage
 		}
+		m.clampProcessCursor()
+		alertCmds := m.evaluateResourceAlerts(msg, time.Now())
 		// Merge cached resource data onto sessions
 		m.mergeResourceCache()
+		// Sample the time-series store and persist when anything was recorded
+		if m.recordMetricsHistory(time.Now()) {
+			alertCmds = append(alertCmds, saveMetricsHistoryCmd(m.metricsHistory))
+		}
+		if len(alertCmds) == 0 {
+			return m, nil
+		}
+		return m, tea.Batch(alertCmds...)
+
+	case processTreeRefreshMsg:
+		if m.resourceCache == nil {
+			m.resourceCache = make(map[string]resource.Usage)
+		}
+		for name, usage := range msg {
+			m.resourceCache[name] = usage
+		}
+		m.clampProcessCursor()
+		m.mergeResourceCache()
 		return m, nil
 
 	case gitTickMsg:
@@ -1126,6 +1454,78 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case hostKeyTrustedMsg:
+		if msg.err != nil {
+			m.dialogError = fmt.Sprintf("Failed to trust host key: %v", msg.err)
+			return m, nil
+		}
+		m.dialogMode = DialogNone
+		m.dialogError = ""
+		m.hostKeyPrompt = nil
+		m.promptPendingHostKey()
+		return m, nil
+
+	case remoteActionResultMsg:
+		if m.dialogMode != DialogRemotes {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.dialogError = fmt.Sprintf("Failed to connect to %s: %v", msg.remote, msg.err)
+			m.promptPendingHostKey()
+			return m, nil
+		}
+		m.dialogError = ""
+		return m, m.pollRemoteSessionsCmd()
+
+	case remotesConfigMsg:
+		return m, m.applyRemotesConfig(msg)
+
+	case remoteConfigSavedMsg:
+		if msg.err != nil {
+			m.dialogError = fmt.Sprintf("Failed to update remotes config: %v", msg.err)
+			return m, nil
+		}
+		if m.dialogMode == DialogRemoteEdit {
+			m.dialogMode = DialogRemotes
+		}
+		m.dialogError = ""
+		return m, reloadRemotesConfigCmd(m.remotesConfigPath, m.remotesWatcher)
+
+	case remoteCheckResultMsg:
+		if m.dialogMode != DialogRemoteEdit {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.dialogError = remoteCheckMessage(msg.remote, msg.err)
+			return m, nil
+		}
+		m.dialogError = ""
+		m.remoteForm.status = fmt.Sprintf("✓ %s is reachable", msg.remote)
+		return m, nil
+
+	case processKillResultMsg:
+		// Kills issued by the alert policy have no dialog to report into
+		if m.dialogMode != DialogProcessTree {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.dialogError = fmt.Sprintf("Failed to kill %d: %v", msg.pid, msg.err)
+			return m, nil
+		}
+		m.dialogError = ""
+		// Refresh the tree right away instead of waiting for the next tick
+		if m.sessionToModify != nil {
+			return m, refreshProcessTreeCmd(m.resourceSampler, *m.sessionToModify)
+		}
+		return m, nil
+
+	case toolStatsMsg:
+		// Only keep stats for the session still being viewed
+		if m.sessionToModify != nil && m.sessionToModify.Remote == "" && m.sessionToModify.TmuxSession == msg.sessionName {
+			m.toolStats = &msg
+		}
+		return m, nil
+
 	case gitPRMsg:
 		// Update PR number and detail for the session being viewed (lazy-loaded)
 		if m.sessionToModify != nil && m.sessionToModify.CWD == msg.cwd && m.sessionToModify.Git != nil {
@@ -1173,6 +1573,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.openContentViewerFrom(title, commentContent.String(), ContentModePlain, DialogGitDetail)
 		return m, nil
 
+	case bulkResultMsg:
+		m.applyBulkResult(msg)
+		cmds := []tea.Cmd{pollSessions}
+		if m.SSHPool != nil && len(m.Remotes) > 0 {
+			cmds = append(cmds, m.pollRemoteSessionsCmd())
+		}
+		return m, tea.Batch(cmds...)
+
 	case remoteDismissResultMsg:
 		// Remote dismiss completed - refresh sessions regardless of error
 		// (errors are silent, same as local dismiss behavior)
@@ -1235,7 +1643,7 @@ func (m *Model) updateTaskPanelForCursor() {
 	filteredSessions := m.getFilteredSessions()
 	oldProject := m.taskFocusedProject
 	if m.cursor < len(filteredSessions) {
-		m.taskFocusedProject = findProjectForCWD(filteredSessions[m.cursor].CWD, m.taskProjectConfigs)
+		m.taskFocusedProject = findProjectForCWD(filteredSessions[m.cursor].Remote, filteredSessions[m.cursor].CWD, m.taskProjectConfigs)
 	} else {
 		m.taskFocusedProject = ""
 	}
@@ -1280,6 +1688,7 @@ func (m Model) togglePreview() (tea.Model, tea.Cmd) {
 	} else {
 		m.previewFocused = false
 	}
+	m.resetPreviewScrollback()
 	filteredSessions := m.getFilteredSessions()
 	if m.previewVisible && len(filteredSessions) > 0 && m.cursor < len(filteredSessions) {
 		m.previewWrap = true
@@ -1294,49 +1703,100 @@ func (m Model) togglePreview() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// sessionAction resolves a key in the session list. While a search query is
+// active the search bindings take precedence.
+func (m Model) sessionAction(key string) keymap.Action {
+	if m.searchQuery != "" {
+		if action := m.keys.Action(keymap.Search, key); action != "" {
+			return action
+		}
+	}
+	return m.keys.Action(keymap.Sessions, key)
+}
+
 // updatePreviewFocus handles key messages when the preview pane has focus.
+// Scrolling near the first loaded line fetches older scrollback.
 func (m Model) updatePreviewFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "tab", "esc":
+	// Route to preview search mode if active
+	if m.previewSearchMode {
+		return m.updatePreviewSearchMode(msg)
+	}
+
+	switch m.keys.Action(keymap.Preview, msg.String()) {
+	case keymap.Back:
+		// Clear preview search first if active, then return focus
+		if m.previewSearchQuery != "" {
+			m.clearPreviewSearchState()
+			return m, nil
+		}
 		m.previewFocused = false
 		return m, nil
 
-	case "down", "j":
+	case keymap.Down:
 		m.previewScrollOffset++
 		m.previewAutoScroll = false
 		return m, nil
 
-	case "up", "k":
+	case keymap.Up:
 		if m.previewScrollOffset > 0 {
 			m.previewScrollOffset--
 		}
 		m.previewAutoScroll = false
-		return m, nil
+		return m, m.loadPreviewHistoryNearTop()
 
-	case "pgdown":
+	case keymap.PageDown:
 		m.previewScrollOffset += previewPageScrollAmt
 		m.previewAutoScroll = false
 		return m, nil
 
-	case "pgup":
+	case keymap.PageUp:
 		m.previewScrollOffset -= previewPageScrollAmt
 		if m.previewScrollOffset < 0 {
 			m.previewScrollOffset = 0
 		}
 		m.previewAutoScroll = false
-		return m, nil
+		return m, m.loadPreviewHistoryNearTop()
 
-	case "g":
+	case keymap.Top:
 		m.previewScrollOffset = 0
 		m.previewAutoScroll = false
-		return m, nil
+		return m, m.loadPreviewHistory()
 
-	case "G":
-		// Jump to bottom and re-enable auto-scroll
+	case keymap.Bottom:
+		// Jump to bottom and re-enable auto-scroll, dropping loaded scrollback
 		m.previewAutoScroll = true
+		if m.previewDeep {
+			m.resetPreviewScrollback()
+			if s, ok := m.previewSession(); ok {
+				return m, m.capturePreviewForSession(s)
+			}
+		}
+		return m, nil
+
+	case keymap.Find:
+		// Enter preview search mode; live refresh pauses so matches stay put
+		m.previewSearchMode = true
+		m.previewSearchInput.SetValue("")
+		m.previewSearchQuery = ""
+		m.previewMatches = nil
+		m.previewMatchIdx = 0
+		m.previewSearchInput.Focus()
+		m.previewDeep = true
+		return m, nil
+
+	case keymap.NextMatch:
+		m.cyclePreviewMatch(1)
+		return m, nil
+
+	case keymap.PrevMatch:
+		m.cyclePreviewMatch(-1)
+		return m, nil
+
+	case keymap.Help:
+		m.openKeyHelp(keymap.Preview)
 		return m, nil
 
-	case "q", "ctrl+c":
+	case keymap.Quit:
 		return m, tea.Quit
 	}
 
@@ -1350,13 +1810,13 @@ func (m Model) updateTaskPanelFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.updateTaskSearchMode(msg)
 	}
 
-	switch msg.String() {
-	case "tab":
+	switch m.keys.Action(keymap.Tasks, msg.String()) {
+	case keymap.Focus:
 		// Return focus to session list
 		m.taskPanelFocused = false
 		return m, nil
 
-	case "esc":
+	case keymap.Back:
 		// Clear task search first if active, then return focus
 		if m.taskSearchQuery != "" {
 			m.clearTaskSearchState()
@@ -1366,14 +1826,14 @@ func (m Model) updateTaskPanelFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.taskPanelFocused = false
 		return m, nil
 
-	case "T":
+	case keymap.ClosePanel:
 		// Close task panel entirely
 		m.taskPanelVisible = false
 		m.taskPanelUserEnabled = false
 		m.taskPanelFocused = false
 		return m, nil
 
-	case "n":
+	case keymap.NextMatch:
 		// Jump to next task match when search query is active
 		if m.taskSearchQuery != "" {
 			m.nextTaskMatch()
@@ -1382,7 +1842,7 @@ func (m Model) updateTaskPanelFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "N":
+	case keymap.PrevMatch:
 		// Jump to previous task match when search query is active
 		if m.taskSearchQuery != "" {
 			m.prevTaskMatch()
@@ -1391,17 +1851,17 @@ func (m Model) updateTaskPanelFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "up", "k":
+	case keymap.Up:
 		m.moveTaskCursor(-1)
 		m.ensureTaskCursorVisible(m.taskPanelViewportLines())
 		return m, nil
 
-	case "down", "j":
+	case keymap.Down:
 		m.moveTaskCursor(1)
 		m.ensureTaskCursorVisible(m.taskPanelViewportLines())
 		return m, nil
 
-	case "pgup":
+	case keymap.PageUp:
 		maxLines := m.taskPanelViewportLines()
 		m.taskScrollOffset -= taskPanelPageScrollAmt
 		if m.taskScrollOffset < 0 {
@@ -1411,7 +1871,7 @@ func (m Model) updateTaskPanelFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.ensureTaskCursorVisible(maxLines)
 		return m, nil
 
-	case "pgdown":
+	case keymap.PageDown:
 		maxLines := m.taskPanelViewportLines()
 		items := m.getVisibleTaskItems()
 		m.taskScrollOffset += taskPanelPageScrollAmt
@@ -1423,13 +1883,13 @@ func (m Model) updateTaskPanelFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.ensureTaskCursorVisible(maxLines)
 		return m, nil
 
-	case "g":
+	case keymap.Top:
 		// Jump to top
 		m.taskCursor = 0
 		m.taskScrollOffset = 0
 		return m, nil
 
-	case "G":
+	case keymap.Bottom:
 		// Jump to bottom
 		items := m.getVisibleTaskItems()
 		if len(items) > 0 {
@@ -1439,7 +1899,7 @@ func (m Model) updateTaskPanelFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "enter":
+	case keymap.Open:
 		// Open task detail or toggle group
 		item := m.getSelectedTaskItem()
 		if item == nil {
@@ -1451,9 +1911,9 @@ func (m Model) updateTaskPanelFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		// Task item: open URL externally or file in content viewer
-		return m.openTaskDetail(item)
+		return m.openTaskDetail(m.taskFocusedProject, item)
 
-	case " ":
+	case keymap.Toggle:
 		// Toggle group expansion
 		item := m.getSelectedTaskItem()
 		if item != nil && item.isGroup {
@@ -1461,7 +1921,7 @@ func (m Model) updateTaskPanelFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "s":
+	case keymap.Sort:
 		// Cycle sort mode: source → status → name → progress → source
 		currentItem := m.getSelectedTaskItem()
 		m.taskSortMode = nextTaskSortMode(m.taskSortMode)
@@ -1469,12 +1929,12 @@ func (m Model) updateTaskPanelFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.preserveTaskCursor(currentItem)
 		return m, nil
 
-	case "S":
+	case keymap.Reverse:
 		// Toggle sort direction (ascending/descending)
 		m.taskSortReversed = !m.taskSortReversed
 		return m, nil
 
-	case "f":
+	case keymap.Filter:
 		// Cycle filter mode: all → active → incomplete → all
 		currentItem := m.getSelectedTaskItem()
 		m.taskFilterMode = nextTaskFilterMode(m.taskFilterMode)
@@ -1486,31 +1946,31 @@ func (m Model) updateTaskPanelFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case "J":
+	case keymap.NextGroup:
 		// Jump to next group header
 		m.jumpToNextGroup()
 		m.ensureTaskCursorVisible(m.taskPanelViewportLines())
 		return m, nil
 
-	case "K":
+	case keymap.PrevGroup:
 		// Jump to previous group header
 		m.jumpToPrevGroup()
 		m.ensureTaskCursorVisible(m.taskPanelViewportLines())
 		return m, nil
 
-	case "e":
+	case keymap.ExpandAll:
 		// Toggle expand/collapse all groups
 		m.toggleExpandCollapseAll()
 		m.clampTaskScrollOffset(m.taskPanelViewportLines())
 		m.ensureTaskCursorVisible(m.taskPanelViewportLines())
 		return m, nil
 
-	case "a":
+	case keymap.Accordion:
 		// Toggle accordion mode
 		m.taskAccordionMode = !m.taskAccordionMode
 		return m, nil
 
-	case "r":
+	case keymap.Refresh:
 		// Manual refresh: invalidate cache and re-execute provider
 		if m.taskRefreshing {
 			return m, nil // Ignore if already refreshing
@@ -1520,9 +1980,9 @@ func (m Model) updateTaskPanelFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.taskFocusedProject != "" && m.taskCache != nil {
 			m.taskCache.Invalidate(m.taskFocusedProject)
 		}
-		return m, taskRefreshCmd(m.taskProjectConfigs, m.taskCache, m.taskGlobalConfig, task.DefaultProviderTimeout)
+		return m, taskRefreshCmd(m.taskProjectConfigs, m.taskCache, m.taskGlobalConfig, task.DefaultProviderTimeout, m.SSHPool)
 
-	case "/":
+	case keymap.Find:
 		// Enter task search mode
 		m.taskSearchMode = true
 		m.taskSearchInput.SetValue("")
@@ -1530,15 +1990,19 @@ func (m Model) updateTaskPanelFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.taskSearchInput.Focus()
 		return m, nil
 
-	case "q", "ctrl+c":
+	case keymap.Help:
+		m.openKeyHelp(keymap.Tasks)
+		return m, nil
+
+	case keymap.Quit:
 		return m, tea.Quit
 	}
 
 	return m, nil
 }
 
-// openTaskDetail handles Enter on a task item: opens URL externally or file in content viewer.
-func (m Model) openTaskDetail(item *taskItem) (tea.Model, tea.Cmd) {
+// openTaskDetail handles Enter on a task item of project: opens URL externally or file in content viewer.
+func (m Model) openTaskDetail(project string, item *taskItem) (tea.Model, tea.Cmd) {
 	// Tasks with a URL: open externally
 	if item.url != "" {
 		if err := git.OpenURL(item.url); err != nil {
@@ -1548,14 +2012,21 @@ func (m Model) openTaskDetail(item *taskItem) (tea.Model, tea.Cmd) {
 	}
 
 	// Local markdown tasks: derive file path from project dir and task ID
-	if m.taskFocusedProject == "" {
+	if project == "" {
 		return m, nil
 	}
 
 	// Task file path: <projectDir>/docs/delivery/<pbi-num>/<taskID>.md
 	// Extract PBI number from group ID (e.g. "PBI-29" -> "29")
 	pbiNum := strings.TrimPrefix(item.groupID, "PBI-")
-	taskFilePath := filepath.Join(m.taskFocusedProject, "docs", "delivery", pbiNum, item.taskID+".md")
+
+	// Remote projects read the file over the pool without blocking the UI
+	if cfg := m.taskProjectConfig(project); cfg != nil && cfg.Remote != "" {
+		taskFilePath := path.Join(cfg.ProjectDir, "docs", "delivery", pbiNum, item.taskID+".md")
+		return m, readRemoteTaskFileCmd(m.SSHPool, cfg.Remote, item.title, taskFilePath)
+	}
+
+	taskFilePath := filepath.Join(project, "docs", "delivery", pbiNum, item.taskID+".md")
 
 	content, err := os.ReadFile(taskFilePath)
 	if err != nil {
@@ -2054,6 +2525,12 @@ func (m *Model) startAttachMonitor() {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	mon := monitor.New(m.audioNotifier, pathutil.ExpandPath(session.StatusDir), session.PollInterval)
+	if m.SSHPool != nil && len(m.Remotes) > 0 {
+		pool, remotes := m.SSHPool, m.Remotes
+		mon.SetRemoteSource(func() []session.Info {
+			return remote.PollSessions(pool, remotes)
+		})
+	}
 	mon.Start(ctx, m.lastSessionStates, m.lastAgentStates)
 
 	m.attachMonitor = mon
@@ -2142,6 +2619,118 @@ func (m Model) updateSoundPackPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// processTreeMaxVisible is the maximum number of process rows visible in the process tree viewport.
+const processTreeMaxVisible = 15
+
+// selectedSessionProcesses returns the cached process tree for the session being viewed.
+func (m Model) selectedSessionProcesses() []resource.Process {
+	if m.sessionToModify == nil {
+		return nil
+	}
+	return m.resourceCache[m.sessionToModify.ID()].Processes
+}
+
+// clampProcessCursor keeps the process tree cursor within bounds after the tree changes.
+func (m *Model) clampProcessCursor() {
+	n := len(m.selectedSessionProcesses())
+	if m.processCursor >= n {
+		m.processCursor = n - 1
+	}
+	if m.processCursor < 0 {
+		m.processCursor = 0
+	}
+	if m.processScrollOffset > m.processCursor {
+		m.processScrollOffset = m.processCursor
+	}
+}
+
+// promptPendingHostKey opens the host key dialog for the next unknown remote
+// host key, unless another dialog is already open.
+func (m *Model) promptPendingHostKey() {
+	if m.SSHPool == nil || m.dialogMode != DialogNone {
+		return
+	}
+	prompt := m.SSHPool.PendingHostKey()
+	if prompt == nil {
+		return
+	}
+	m.hostKeyPrompt = prompt
+	m.dialogMode = DialogHostKey
+	m.dialogError = ""
+}
+
+// updateHostKeyPrompt handles key input for the unknown host key dialog.
+// y trusts the key and records it in known_hosts; n or esc rejects it for this run.
+func (m Model) updateHostKeyPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.hostKeyPrompt == nil || m.SSHPool == nil {
+		m.dialogMode = DialogNone
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "y":
+		return m, trustHostKeyCmd(m.SSHPool, *m.hostKeyPrompt)
+
+	case "n", "esc":
+		m.SSHPool.RejectHostKey(*m.hostKeyPrompt)
+		m.dialogMode = DialogNone
+		m.dialogError = ""
+		m.hostKeyPrompt = nil
+		m.promptPendingHostKey()
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// updateProcessTree handles keyboard input for the process tree dialog.
+func (m Model) updateProcessTree(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	procs := m.selectedSessionProcesses()
+
+	switch m.keys.Action(keymap.ProcessTree, msg.String()) {
+	case keymap.Back:
+		m.dialogMode = DialogNone
+		m.dialogError = ""
+		m.sessionToModify = nil
+		return m, nil
+
+	case keymap.Up:
+		if m.processCursor > 0 {
+			m.processCursor--
+			if m.processCursor < m.processScrollOffset {
+				m.processScrollOffset = m.processCursor
+			}
+		}
+		return m, nil
+
+	case keymap.Down:
+		if m.processCursor < len(procs)-1 {
+			m.processCursor++
+			if m.processCursor >= m.processScrollOffset+processTreeMaxVisible {
+				m.processScrollOffset = m.processCursor - processTreeMaxVisible + 1
+			}
+		}
+		return m, nil
+
+	case keymap.Kill:
+		// Kill the selected child process (pane shells are protected)
+		if m.sessionToModify == nil || m.processCursor >= len(procs) {
+			return m, nil
+		}
+		selected := procs[m.processCursor]
+		if selected.Depth == 0 {
+			m.dialogError = "Cannot kill a pane shell; kill the session instead"
+			return m, nil
+		}
+		return m, killProcessCmd(m.sessionToModify.TmuxSession, selected.PID)
+
+	case keymap.Help:
+		m.openKeyHelp(keymap.ProcessTree)
+	}
+
+	return m, nil
+}
+
 // previewSoundPackCmd returns a tea.Cmd that plays a sample sound from the given pack.
 func previewSoundPackCmd(packName string) tea.Cmd {
 	return func() tea.Msg {
@@ -2163,6 +2752,20 @@ func previewSoundPackCmd(packName string) tea.Cmd {
 	}
 }
 
+// attachTo starts the attach monitor and returns the command that attaches
+// to s, through its remote's transport for remote sessions.
+func (m *Model) attachTo(s session.Info) tea.Cmd {
+	if s.Remote != "" && m.SSHPool != nil {
+		// Fall back to a local attach if the remote config is gone
+		if r := m.SSHPool.GetRemoteConfig(s.Remote); r != nil {
+			m.startAttachMonitor()
+			return attachRemoteSession(r, s.TmuxSession)
+		}
+	}
+	m.startAttachMonitor()
+	return attachSession(s.TmuxSession)
+}
+
 // attachSession returns a command that attaches to a local tmux session.
 // Uses tea.ExecProcess to hand off terminal control to tmux.
 func attachSession(name string) tea.Cmd {
@@ -2172,11 +2775,12 @@ func attachSession(name string) tea.Cmd {
 	})
 }
 
-// attachRemoteSession returns a command that attaches to a remote tmux session via SSH.
-// Uses tea.ExecProcess to hand off terminal control to SSH with tmux.
+// attachRemoteSession returns a command that attaches to a remote tmux session
+// through the remote's transport (ssh, or its attach command prefix).
+// Uses tea.ExecProcess to hand off terminal control to the attach command.
 func attachRemoteSession(r *remote.Config, sessionName string) tea.Cmd {
-	args := remote.BuildSSHAttachCommand(r, sessionName)
-	// First arg is "ssh", rest are arguments
+	args := remote.BuildAttachCommand(r, sessionName)
+	// First arg is the program, rest are arguments
 	c := exec.Command(args[0], args[1:]...)
 	return tea.ExecProcess(c, func(err error) tea.Msg {
 		return attachDoneMsg{}
@@ -2186,8 +2790,11 @@ func attachRemoteSession(r *remote.Config, sessionName string) tea.Cmd {
 // capturePreviewCmd returns a command that captures preview content from a tmux session.
 func capturePreviewCmd(sessionName string) tea.Cmd {
 	return func() tea.Msg {
-		content, err := capturePane(sessionName, previewDefaultLines)
-		return previewContentMsg{content: content, err: err}
+		r, err := capturePaneRange(sessionName, -previewDefaultLines, remote.PaneBottom)
+		if err != nil {
+			return previewContentMsg{err: err}
+		}
+		return previewTailMsg(r)
 	}
 }
 
@@ -2203,6 +2810,54 @@ func (m Model) updateDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.updateSoundPackPicker(msg)
 	}
 
+	// Route process tree keys to its own handler
+	if m.dialogMode == DialogProcessTree {
+		return m.updateProcessTree(msg)
+	}
+
+	// Route host key confirmation keys to its own handler
+	if m.dialogMode == DialogHostKey {
+		return m.updateHostKeyPrompt(msg)
+	}
+
+	// Route remotes panel keys to its own handler
+	if m.dialogMode == DialogRemotes {
+		return m.updateRemotesPanel(msg)
+	}
+
+	// Route remote editor keys to its own handler
+	if m.dialogMode == DialogRemoteEdit {
+		return m.updateRemoteEditor(msg)
+	}
+
+	// Route file path prompt keys to its own handler
+	if m.dialogMode == DialogGitFile {
+		return m.updateGitFileDialog(msg)
+	}
+
+	// Route git detail keys to their own handler
+	if m.dialogMode == DialogGitDetail {
+		return m.updateGitDetail(msg)
+	}
+
+	// Route command palette keys to its own handler
+	if m.dialogMode == DialogPalette {
+		return m.updatePalette(msg)
+	}
+
+	// Route inbox keys to its own handler
+	if m.dialogMode == DialogInbox {
+		return m.updateInbox(msg)
+	}
+
+	// Route bulk action dialogs to their own handlers
+	if m.dialogMode == DialogBulkConfirm {
+		return m.updateBulkConfirm(msg)
+	}
+	if m.dialogMode == DialogBulkResult {
+		return m.updateBulkResult(msg)
+	}
+
 	switch msg.String() {
 	case "esc":
 		// Close any dialog and reset state
@@ -2215,7 +2870,11 @@ func (m Model) updateDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "tab":
 		// Switch focus between inputs in new session dialog
 		if m.dialogMode == DialogNewSession {
-			m.focusedInput = (m.focusedInput + 1) % 3
+			fields := 3
+			if len(m.Remotes) > 0 {
+				fields = 4 // Host selector
+			}
+			m.focusedInput = (m.focusedInput + 1) % fields
 			m.nameInput.Blur()
 			m.dirInput.Blur()
 			switch m.focusedInput {
@@ -2234,21 +2893,29 @@ func (m Model) updateDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.skipPermissions = !m.skipPermissions
 			return m, nil
 		}
+		// Cycle target host
+		if m.dialogMode == DialogNewSession && m.focusedInput == focusHost {
+			m.cycleNewSessionHost(1)
+			return m, nil
+		}
+
+	case "left", "right":
+		if m.dialogMode == DialogNewSession && m.focusedInput == focusHost {
+			delta := 1
+			if msg.String() == "left" {
+				delta = -1
+			}
+			m.cycleNewSessionHost(delta)
+			return m, nil
+		}
 
-	case "enter", "o":
+	case "enter":
 		// Handle submission based on dialog type
 		switch m.dialogMode {
 		case DialogNewSession:
-			if msg.String() == "enter" {
-				return m.submitNewSession()
-			}
+			return m.submitNewSession()
 		case DialogRename:
-			if msg.String() == "enter" {
-				return m.submitRename()
-			}
-		case DialogGitDetail:
-			// Open PR/issue link if available
-			return m.openGitLink()
+			return m.submitRename()
 		}
 
 	case "y":
@@ -2269,59 +2936,16 @@ func (m Model) updateDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.sessionToModify = nil
 			return m, nil
 		}
-
-	case "d":
-		// Show diff in content viewer from git detail view
-		if m.dialogMode == DialogGitDetail && m.sessionToModify != nil && m.sessionToModify.Git != nil {
-			dir := pathutil.ExpandPath(m.sessionToModify.CWD)
-			diff := git.GetDiff(dir)
-			if diff == "" {
-				if m.sessionToModify.Git.Dirty {
-					diff = "No unstaged changes (changes may be staged)"
-				} else {
-					diff = "Working tree clean - no changes"
-				}
-			}
-			title := fmt.Sprintf("Git Diff: %s", m.sessionToModify.Git.Branch)
-			m.openContentViewerFrom(title, diff, ContentModeDiff, DialogGitDetail)
-			return m, nil
-		}
-
-	case "r":
-		// Refresh PR data from git detail view
-		if m.dialogMode == DialogGitDetail && m.sessionToModify != nil && m.sessionToModify.Git != nil {
-			// Reset auto-refresh timer (next auto-tick will be after full interval)
-			m.prAutoRefreshActive = false
-			if m.sessionToModify.Remote != "" {
-				return m, fetchRemotePRCmd(m.sessionToModify.CWD, m.sessionToModify.Git.Branch, m.sessionToModify.Git.Remote)
-			}
-			if m.sessionToModify.CWD != "" {
-				return m, fetchPRCmd(m.sessionToModify.CWD)
-			}
-		}
-
-	case "c":
-		// Fetch and show PR comments
-		if m.dialogMode == DialogGitDetail && m.sessionToModify != nil && m.sessionToModify.Git != nil && m.sessionToModify.Git.PRNum > 0 {
-			m.dialogError = "Loading comments..."
-			if m.sessionToModify.Remote != "" {
-				ghInfo := git.ParseGitHubRemote(m.sessionToModify.Git.Remote)
-				if ghInfo != nil {
-					return m, fetchRemotePRCommentsCmd(ghInfo.Owner, ghInfo.Repo, m.sessionToModify.Git.PRNum)
-				}
-			} else {
-				return m, fetchPRCommentsCmd(m.sessionToModify.CWD, m.sessionToModify.Git.PRNum)
-			}
-		}
 	}
 
 	// Update the focused text input for dialogs that use text input
 	var cmd tea.Cmd
 	switch m.dialogMode {
 	case DialogNewSession:
-		if m.focusedInput == focusName {
+		switch m.focusedInput {
+		case focusName:
 			m.nameInput, cmd = m.nameInput.Update(msg)
-		} else {
+		case focusDir:
 			m.dirInput, cmd = m.dirInput.Update(msg)
 		}
 	case DialogRename:
@@ -2341,6 +2965,26 @@ func (m Model) submitNewSession() (tea.Model, tea.Cmd) {
 		name = getDefaultSessionName()
 	}
 
+	// Remote sessions only clash with names on the same remote; the
+	// directory is validated on the remote itself
+	if m.newSessionHost != "" {
+		if m.SSHPool == nil {
+			m.dialogError = fmt.Sprintf("remote %q is not connected", m.newSessionHost)
+			return m, nil
+		}
+		var remoteSessions []session.Info
+		for _, s := range m.sessions {
+			if s.Remote == m.newSessionHost {
+				remoteSessions = append(remoteSessions, s)
+			}
+		}
+		if err := validateSessionName(name, remoteSessions); err != nil {
+			m.dialogError = err.Error()
+			return m, nil
+		}
+		return m, createRemoteSessionCmd(m.SSHPool, m.newSessionHost, name, dir, m.skipPermissions)
+	}
+
 	// Validate session name
 	if err := validateSessionName(name, m.sessions); err != nil {
 		m.dialogError = err.Error()
@@ -2365,6 +3009,39 @@ func (m Model) submitNewSession() (tea.Model, tea.Cmd) {
 	return m, createSessionCmd(name, dir, m.skipPermissions)
 }
 
+// cycleNewSessionHost moves the new session target by delta through local and
+// the configured remotes. The directory follows the host while it still holds
+// the other host's default.
+func (m *Model) cycleNewSessionHost(delta int) {
+	hosts := make([]string, 0, len(m.Remotes)+1)
+	hosts = append(hosts, "")
+	current := 0
+	for _, r := range m.Remotes {
+		if r.Name == m.newSessionHost {
+			current = len(hosts)
+		}
+		hosts = append(hosts, r.Name)
+	}
+	next := ((current+delta)%len(hosts) + len(hosts)) % len(hosts)
+	m.newSessionHost = hosts[next]
+
+	dir := strings.TrimSpace(m.dirInput.Value())
+	switch {
+	case m.newSessionHost != "" && (dir == "" || dir == getDefaultDirectory()):
+		m.dirInput.SetValue("~")
+	case m.newSessionHost == "" && (dir == "" || dir == "~"):
+		m.dirInput.SetValue(getDefaultDirectory())
+	}
+}
+
+// pollRemoteSessionsCmd returns a command that polls all configured remotes.
+func (m Model) pollRemoteSessionsCmd() tea.Cmd {
+	pool, remotes := m.SSHPool, m.Remotes
+	return func() tea.Msg {
+		return remoteSessionsMsg{sessions: remote.PollSessions(pool, remotes)}
+	}
+}
+
 // submitRename validates and renames a tmux session.
 func (m Model) submitRename() (tea.Model, tea.Cmd) {
 	if m.sessionToModify == nil {
@@ -2383,10 +3060,12 @@ func (m Model) submitRename() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Validate session name (exclude current session from duplicate check)
+	// Validate session name against other sessions on the same host
+	// (exclude current session from duplicate check)
+	currentID := m.sessionToModify.ID()
 	sessionsWithoutCurrent := make([]session.Info, 0, len(m.sessions)-1)
 	for _, s := range m.sessions {
-		if s.TmuxSession != oldName {
+		if s.Remote == m.sessionToModify.Remote && s.ID() != currentID {
 			sessionsWithoutCurrent = append(sessionsWithoutCurrent, s)
 		}
 	}
@@ -2405,6 +3084,84 @@ func (m Model) submitRename() (tea.Model, tea.Cmd) {
 	return m, renameSessionCmd(oldName, newName)
 }
 
+// updateGitDetail handles keyboard input in the git detail view.
+func (m Model) updateGitDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "esc" {
+		m.dialogMode = DialogNone
+		m.dialogError = ""
+		m.sessionToModify = nil
+		m.prAutoRefreshActive = false // Stop auto-refresh when closing dialog
+		return m, nil
+	}
+	if m.sessionToModify == nil {
+		return m, nil
+	}
+	gitInfo := m.sessionToModify.Git
+
+	switch m.keys.Action(keymap.Git, msg.String()) {
+	case keymap.Open:
+		// Open PR/issue link if available
+		return m.openGitLink()
+
+	case keymap.Diff:
+		// Show diff in content viewer
+		if gitInfo != nil {
+			return m.openGitContent(gitContentDiff, "")
+		}
+
+	case keymap.DiffStat:
+		// Show diffstat in content viewer
+		if gitInfo != nil {
+			return m.openGitContent(gitContentDiffStat, "")
+		}
+
+	case keymap.Log:
+		// Show recent commits in content viewer
+		if gitInfo != nil {
+			return m.openGitContent(gitContentLog, "")
+		}
+
+	case keymap.File:
+		// Prompt for a file to view
+		m.dialogMode = DialogGitFile
+		m.dialogError = ""
+		m.fileInput = initFileInput()
+		return m, nil
+
+	case keymap.Refresh:
+		// Refresh PR data
+		if gitInfo != nil {
+			// Reset auto-refresh timer (next auto-tick will be after full interval)
+			m.prAutoRefreshActive = false
+			if m.sessionToModify.Remote != "" {
+				return m, fetchRemotePRCmd(m.sessionToModify.CWD, gitInfo.Branch, gitInfo.Remote)
+			}
+			if m.sessionToModify.CWD != "" {
+				return m, fetchPRCmd(m.sessionToModify.CWD)
+			}
+		}
+
+	case keymap.Comments:
+		// Fetch and show PR comments
+		if gitInfo != nil && gitInfo.PRNum > 0 {
+			m.dialogError = "Loading comments..."
+			if m.sessionToModify.Remote != "" {
+				ghInfo := git.ParseGitHubRemote(gitInfo.Remote)
+				if ghInfo != nil {
+					return m, fetchRemotePRCommentsCmd(ghInfo.Owner, ghInfo.Repo, gitInfo.PRNum)
+				}
+			} else {
+				return m, fetchPRCommentsCmd(m.sessionToModify.CWD, gitInfo.PRNum)
+			}
+		}
+
+	case keymap.Help:
+		m.openKeyHelp(keymap.Git)
+	}
+
+	return m, nil
+}
+
 // openGitLink opens the GitHub PR link in the system browser.
 func (m Model) openGitLink() (tea.Model, tea.Cmd) {
 	if m.sessionToModify == nil || m.sessionToModify.Git == nil {
@@ -2537,7 +3294,7 @@ func (m Model) updateSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		filteredSessions := m.getFilteredSessions()
 		if len(filteredSessions) > 0 && m.cursor < len(filteredSessions) {
 			s := filteredSessions[m.cursor]
-			m.lastSelectedSession = s.TmuxSession
+			m.lastSelectedSession = s.ID()
 
 			if s.Remote != "" && m.SSHPool != nil {
 				r := m.SSHPool.GetRemoteConfig(s.Remote)
@@ -2637,26 +3394,26 @@ func (m Model) sessionListMaxVisible() int {
 	return maxSessions
 }
 
-// selectedSessionName returns the name of the currently selected session, or empty string.
-func (m Model) selectedSessionName() string {
+// selectedSessionID returns the ID of the currently selected session, or empty string.
+func (m Model) selectedSessionID() string {
 	filtered := m.getFilteredSessions()
 	if m.cursor < len(filtered) {
-		return filtered[m.cursor].TmuxSession
+		return filtered[m.cursor].ID()
 	}
 	return ""
 }
 
 // preserveCursor attempts to keep the cursor on the same session after a filter change.
 // If the session is no longer in the filtered list, cursor resets to 0.
-func (m *Model) preserveCursor(sessionName string) {
-	if sessionName == "" {
+func (m *Model) preserveCursor(sessionID string) {
+	if sessionID == "" {
 		m.cursor = 0
 		m.sessionScrollOffset = 0
 		return
 	}
 	filtered := m.getFilteredSessions()
 	for i, s := range filtered {
-		if s.TmuxSession == sessionName {
+		if s.ID() == sessionID {
 			m.cursor = i
 			m.ensureSessionCursorVisible(m.sessionListMaxVisible())
 			return
@@ -2715,7 +3472,14 @@ func (m Model) getFilteredSessions() []session.Info {
 	if m.pmProjectFilterDir != "" {
 		var filtered []session.Info
 		for _, s := range result {
-			if strings.TrimSpace(s.CWD) == "" {
+			if strings.TrimSpace(s.CWD) == "" || s.Remote != m.pmProjectFilterRemote {
+				continue
+			}
+			if s.Remote != "" {
+				// Remote paths are matched as written; they can't be resolved locally.
+				if s.CWD == m.pmProjectFilterDir || strings.HasPrefix(s.CWD, m.pmProjectFilterDir+"/") {
+					filtered = append(filtered, s)
+				}
 				continue
 			}
 			expanded := pathutil.ExpandPath(s.CWD)
@@ -2753,13 +3517,19 @@ func (m Model) filterModeString() string {
 
 // InitialModel creates the initial Model for the application.
 func InitialModel() Model {
-	// Load remote configuration (errors are logged but not fatal)
-	remotes, err := remote.LoadConfig()
-	if err != nil {
+	// Load remote configuration (errors are logged but not fatal). Invalid
+	// entries are skipped and listed in the remotes panel.
+	remotesConfigPath := remote.ExpandConfigPath()
+	remotesWatcher := remote.NewConfigWatcher(remotesConfigPath)
+	remotes, remotesInvalid, remotesLoadErr := remote.LoadConfigFile(remotesConfigPath)
+	if remotesLoadErr != nil {
 		// Log error but continue - remotes are optional
-		fmt.Fprintf(os.Stderr, "Warning: failed to load remotes config: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: failed to load remotes config: %v\n", remotesLoadErr)
 		remotes = []remote.Config{}
 	}
+	for _, ce := range remotesInvalid {
+		fmt.Fprintf(os.Stderr, "Warning: skipping remote %v\n", ce)
+	}
 
 	// Initialize SSH pool if remotes are configured
 	var sshPool *remote.SSHPool
@@ -2781,6 +3551,45 @@ func InitialModel() Model {
 	}
 	audioNotifier := audio.NewNotifier(audioConfig)
 
+	alertConfig, err := alert.LoadConfig(alert.DefaultConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load alert config: %v\n", err)
+		alertConfig = alert.DefaultConfig()
+	}
+
+	// Load metrics history (errors are logged but not fatal)
+	metricsHistory, err := history.Load(history.DefaultPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load metrics history: %v\n", err)
+	}
+
+	// Load the notification inbox (errors are logged but not fatal)
+	inboxStore, err := inbox.Load(inbox.DefaultPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load inbox: %v\n", err)
+	}
+
+	// Load the saved view settings (errors are logged and the defaults used)
+	uiState, err := uistate.Load(uistate.DefaultPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load UI state: %v\n", err)
+	}
+
+	// Load session groups (errors are logged but not fatal)
+	sessionGroups, err := session.LoadGroups(session.DefaultGroupsPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load session groups: %v\n", err)
+	}
+
+	applyTheme(loadTheme(), detectColorMode())
+
+	// Load key bindings (errors are logged and the defaults used)
+	keys, err := keymap.Load(keymap.DefaultConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load key config: %v\n", err)
+		keys = keymap.Default()
+	}
+
 	m := Model{
 		sessions:            []session.Info{},
 		cursor:              0,
@@ -2788,9 +3597,14 @@ func InitialModel() Model {
 		height:              24,
 		Remotes:             remotes,
 		SSHPool:             sshPool,
+		remotesConfigPath:   remotesConfigPath,
+		remotesWatcher:      remotesWatcher,
+		remotesInvalid:      remotesInvalid,
+		remotesLoadErr:      remotesLoadErr,
 		sortMode:            SortPriority,
 		searchInput:         initSearchInput(),
 		taskSearchInput:     initTaskSearchInput(),
+		previewSearchInput:  initPreviewSearchInput(),
 		taskExpandedGroups:  make(map[string]bool),
 		taskGroupsByProject: make(map[string][]task.TaskGroup),
 		taskCache:           task.NewResultCache(),
@@ -2800,6 +3614,14 @@ func InitialModel() Model {
 		previewAutoScroll:   true,
 		audioNotifier:       audioNotifier,
 		activeSoundPack:     audioConfig.Pack,
+		metricsHistory:      metricsHistory,
+		inbox:               inboxStore,
+		sessionGroups:       sessionGroups,
+		groupsPath:          session.DefaultGroupsPath,
+		uiStateWriter:       uistate.NewWriter(uistate.DefaultPath),
+		keys:                keys,
+		resourceSampler:     resource.NewSampler(),
+		alertEvaluator:      alert.NewEvaluator(alertConfig),
 		lastSessionStates:   make(map[string]string),
 		lastAgentStates:     make(map[string]map[string]string),
 		pmEngine:            pm.NewEngine(),
@@ -2807,6 +3629,7 @@ func InitialModel() Model {
 		pmTaskResults:       make(map[string]*task.ProviderResult),
 		pmExpandedProjects:  make(map[string]bool),
 	}
+	m.applyUIState(uiState)
 
 	// Load cached briefing from last session if available.
 	if cached := initPMCachedBriefing(); cached != nil {
@@ -2836,18 +3659,131 @@ func initPMCachedBriefing() *pm.PMBriefing {
 
 // mergeResourceCache re-applies cached resource metrics onto the current sessions list.
 // Called after sessionsMsg replaces the sessions slice to preserve resource data.
+// Local sessions use the process tree cache; remote sessions use the remote
+// metrics cache, which also carries their transcript token totals.
 func (m *Model) mergeResourceCache() {
-	if len(m.resourceCache) == 0 {
+	if len(m.resourceCache) == 0 && len(m.remoteMetricsCache) == 0 {
 		return
 	}
 	for i := range m.sessions {
-		if rss, ok := m.resourceCache[m.sessions[i].TmuxSession]; ok {
+		if m.sessions[i].Remote != "" {
+			m.mergeRemoteMetrics(&m.sessions[i])
+			continue
+		}
+		if usage, ok := m.resourceCache[m.sessions[i].ID()]; ok {
 			if m.sessions[i].Metrics == nil {
 				m.sessions[i].Metrics = &metrics.Metrics{}
 			}
-			m.sessions[i].Metrics.Resource = &metrics.ResourceMetrics{RSSBytes: rss}
+			m.sessions[i].Metrics.Resource = &metrics.ResourceMetrics{
+				RSSBytes:     usage.RSSBytes,
+				CPUPercent:   usage.CPUPercent,
+				ProcessCount: usage.ProcessCount,
+				Ports:        usage.Ports,
+				Alerts:       m.resourceAlerts[m.sessions[i].ID()],
+			}
+		}
+	}
+}
+
+// mergeRemoteMetrics applies cached remote metrics onto a remote session.
+// Token totals reported by the remote status file take precedence.
+func (m *Model) mergeRemoteMetrics(s *session.Info) {
+	cached, ok := m.remoteMetricsCache[remoteMetricsKey(s.Remote, s.TmuxSession)]
+	if !ok {
+		return
+	}
+	if s.Metrics == nil {
+		s.Metrics = &metrics.Metrics{}
+	}
+	if cached.Resource != nil {
+		res := *cached.Resource
+		s.Metrics.Resource = &res
+	}
+	if cached.Tokens != nil && s.Metrics.Tokens == nil {
+		toks := *cached.Tokens
+		s.Metrics.Tokens = &toks
+	}
+}
+
+// remoteMetricsKey returns the remote metrics cache key for a session on a remote.
+func remoteMetricsKey(remoteName, sessionName string) string {
+	return remoteName + "/" + sessionName
+}
+
+// evaluateResourceAlerts checks polled usage against the alert thresholds,
+// records active breach labels, notifies on newly crossed thresholds, and
+// returns kill commands when the SIGTERM policy is enabled.
+// Must run before mergeResourceCache so badges reflect the latest breaches.
+func (m *Model) evaluateResourceAlerts(usage resourcePollMsg, now time.Time) []tea.Cmd {
+	if m.alertEvaluator == nil {
+		return nil
+	}
+
+	cfg := m.alertEvaluator.Config()
+	alerts := make(map[string][]string)
+	polled := make(map[string]bool, len(usage))
+	var cmds []tea.Cmd
+	for id, u := range usage {
+		polled[id] = true
+		result := m.alertEvaluator.Evaluate(id, u, now)
+		if labels := alert.Labels(result.Active); labels != nil {
+			alerts[id] = labels
+		}
+		if len(result.New) == 0 {
+			continue
+		}
+		s, ok := m.sessionByID(id)
+		if !ok {
+			continue
+		}
+		m.notifyStatusChange(s.Label(), alert.NotifyEvent)
+		if cfg.Action != alert.ActionSIGTERM {
+			continue
+		}
+		for _, b := range result.New {
+			if b.Offender.PID > 0 {
+				cmds = append(cmds, killProcessCmd(s.TmuxSession, b.Offender.PID))
+			}
+		}
+	}
+	m.alertEvaluator.Retain(polled)
+	m.resourceAlerts = alerts
+	return cmds
+}
+
+// sessionByID returns the session with the given ID.
+func (m Model) sessionByID(id string) (session.Info, bool) {
+	for _, s := range m.sessions {
+		if s.ID() == id {
+			return s, true
+		}
+	}
+	return session.Info{}, false
+}
+
+// recordMetricsHistory samples tokens, RSS, tool count and status for every session
+// into the metrics history store. Returns true if at least one sample was recorded.
+func (m *Model) recordMetricsHistory(now time.Time) bool {
+	if m.metricsHistory == nil {
+		return false
+	}
+	recorded := false
+	for _, s := range m.sessions {
+		sample := history.Sample{Timestamp: now.Unix(), Status: s.Status}
+		if s.Metrics != nil {
+			if s.Metrics.Tokens != nil {
+				sample.Tokens = s.Metrics.Tokens.Total
+			}
+			if s.Metrics.Resource != nil {
+				sample.RSSBytes = s.Metrics.Resource.RSSBytes
+			}
+			sample.ToolCount = metrics.FormatToolCount(s.Metrics.Tools)
+		}
+		if m.metricsHistory.Record(s.ID(), sample) {
+			recorded = true
 		}
 	}
+	return recorded
 }
 
 func (m *Model) detectStatusChanges(current []session.Info) {
@@ -2863,8 +3799,11 @@ func (m *Model) detectStatusChanges(current []session.Info) {
 
 	currentStates := make(map[string]string, len(current))
 	currentAgentStates := make(map[string]map[string]string)
+	labels := make(map[string]string, len(current))
 	for _, s := range current {
-		currentStates[s.TmuxSession] = s.Status
+		key := s.ID()
+		labels[key] = s.Label()
+		currentStates[key] = s.Status
 		if len(s.Agents) == 0 {
 			continue
 		}
@@ -2873,7 +3812,7 @@ func (m *Model) detectStatusChanges(current []session.Info) {
 		for agentType, agent := range s.Agents {
 			agentStates[agentType] = agent.Status
 		}
-		currentAgentStates[s.TmuxSession] = agentStates
+		currentAgentStates[key] = agentStates
 	}
 
 	// First poll should only initialize state to avoid startup noise.
@@ -2883,16 +3822,16 @@ func (m *Model) detectStatusChanges(current []session.Info) {
 		return
 	}
 
-	for sessionName, newStatus := range currentStates {
-		if oldStatus, ok := m.lastSessionStates[sessionName]; !ok {
+	for id, newStatus := range currentStates {
+		if oldStatus, ok := m.lastSessionStates[id]; !ok {
 			continue
 		} else if oldStatus != newStatus {
-			m.notifyStatusChange(sessionName, newStatus)
+			m.notifyStatusChange(labels[id], newStatus)
 		}
 	}
 
-	for sessionName, agentStates := range currentAgentStates {
-		lastSessionAgents, ok := m.lastAgentStates[sessionName]
+	for id, agentStates := range currentAgentStates {
+		lastSessionAgents, ok := m.lastAgentStates[id]
 		if !ok {
 			continue
 		}
@@ -2903,7 +3842,7 @@ func (m *Model) detectStatusChanges(current []session.Info) {
 				continue
 			}
 			if oldStatus != newStatus {
-				m.notifyAgentStatusChange(sessionName, agentType, newStatus)
+				m.notifyAgentStatusChange(labels[id], agentType, newStatus)
 			}
 		}
 	}
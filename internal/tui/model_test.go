@@ -1296,8 +1296,8 @@ func TestFilteredSessionOperations(t *testing.T) {
 		updated := newModel.(Model)
 
 		// Should select the remote s, not the first overall s
-		if updated.lastSelectedSession != "remote-1" {
-			t.Errorf("enter should select 'remote-1', got '%s'", updated.lastSelectedSession)
+		if updated.lastSelectedSession != "remote-1@dev" {
+			t.Errorf("enter should select 'remote-1@dev', got '%s'", updated.lastSelectedSession)
 		}
 	})
 
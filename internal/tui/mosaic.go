@@ -0,0 +1,383 @@
+package tui
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	ansi "github.com/charmbracelet/x/ansi"
+
+	"github.com/stwalsh4118/navi/internal/keymap"
+	"github.com/stwalsh4118/navi/internal/remote"
+	"github.com/stwalsh4118/navi/internal/session"
+	"github.com/stwalsh4118/navi/internal/sgr"
+)
+
+// Mosaic layout constants
+const (
+	mosaicMinTileWidth  = 40 // Narrowest tile before the grid drops a column
+	mosaicMinTileHeight = 8  // Shortest tile before the grid drops a row
+	mosaicTileChrome    = 3  // Top border + title line + bottom border
+)
+
+// Mosaic refresh constants. Captures are spread over ticks so a large grid
+// never forks a burst of tmux or SSH processes.
+const (
+	// mosaicTickInterval is how often due tiles are checked for a capture.
+	mosaicTickInterval = 500 * time.Millisecond
+
+	// mosaicLocalRefresh is the minimum age of a local tile before it is recaptured.
+	mosaicLocalRefresh = previewPollInterval
+
+	// mosaicRemoteRefresh is the minimum age of a remote tile before it is recaptured.
+	mosaicRemoteRefresh = 5 * time.Second
+
+	// mosaicMaxCapturesPerTick caps the captures started on one tick.
+	mosaicMaxCapturesPerTick = 4
+)
+
+// mosaicTile is the last capture of one session's pane.
+type mosaicTile struct {
+	content  string
+	err      error
+	captured time.Time
+}
+
+// mosaicContentMsg is returned after capturing a mosaic tile.
+type mosaicContentMsg struct {
+	id      string
+	content string
+	err     error
+}
+
+// mosaicTickMsg triggers the next round of tile captures. gen ties the tick
+// to the mosaic session that started it, so reopening the view never runs
+// two tick chains.
+type mosaicTickMsg struct {
+	gen int
+}
+
+// mosaicTickCmd schedules the next mosaic tick.
+func mosaicTickCmd(gen int) tea.Cmd {
+	return tea.Tick(mosaicTickInterval, func(time.Time) tea.Msg {
+		return mosaicTickMsg{gen: gen}
+	})
+}
+
+// toggleMosaicView opens or closes the mosaic view. Opening starts on the
+// session under the cursor; closing moves the cursor to the chosen tile.
+func (m *Model) toggleMosaicView() tea.Cmd {
+	if m.mosaicVisible {
+		m.closeMosaicView()
+		return nil
+	}
+
+	m.mosaicVisible = true
+	m.mosaicZoomed = false
+	m.mosaicCursor = 0
+	m.mosaicTiles = make(map[string]mosaicTile)
+	m.mosaicPending = make(map[string]bool)
+	m.mosaicGen++
+
+	filteredSessions := m.getFilteredSessions()
+	if m.cursor < len(filteredSessions) {
+		id := filteredSessions[m.cursor].ID()
+		for i, s := range m.mosaicSessions() {
+			if s.ID() == id {
+				m.mosaicCursor = i
+				break
+			}
+		}
+	}
+
+	// The mosaic replaces the preview and task panel while open.
+	m.previewFocused = false
+	m.taskPanelFocused = false
+
+	return m.mosaicCaptureCmd(time.Now(), mosaicTickCmd(m.mosaicGen))
+}
+
+// closeMosaicView hides the mosaic view and drops its captures.
+func (m *Model) closeMosaicView() {
+	if tiles := m.mosaicSessions(); m.mosaicCursor < len(tiles) {
+		id := tiles[m.mosaicCursor].ID()
+		for i, s := range m.getFilteredSessions() {
+			if s.ID() == id {
+				m.cursor = i
+				m.lastSelectedSession = id
+				break
+			}
+		}
+	}
+	m.mosaicVisible = false
+	m.mosaicZoomed = false
+	m.mosaicTiles = nil
+	m.mosaicPending = nil
+}
+
+// mosaicSessions returns the sessions shown as tiles: the filtered list,
+// narrowed to search matches while a search query is active.
+func (m Model) mosaicSessions() []session.Info {
+	filteredSessions := m.getFilteredSessions()
+	if m.searchQuery == "" {
+		return filteredSessions
+	}
+	matches := make([]session.Info, 0, len(m.searchMatches))
+	for _, i := range m.searchMatches {
+		if i < len(filteredSessions) {
+			matches = append(matches, filteredSessions[i])
+		}
+	}
+	return matches
+}
+
+// mosaicGrid returns the columns and rows for n tiles in the given area.
+func mosaicGrid(width, height, n int) (cols, rows int) {
+	maxCols := max(1, width/mosaicMinTileWidth)
+	maxRows := max(1, height/mosaicMinTileHeight)
+	if n <= 0 {
+		return 1, 1
+	}
+
+	perPage := min(n, maxCols*maxRows)
+	cols = min(maxCols, int(math.Ceil(math.Sqrt(float64(perPage)*float64(width)/float64(height*2)))))
+	cols = max(1, cols)
+	rows = min(maxRows, (perPage+cols-1)/cols)
+	// Use fewer columns when the rows are full, so the last row isn't sparse
+	cols = max(1, (perPage+rows-1)/rows)
+	return cols, rows
+}
+
+// mosaicPage returns the index of the first tile on the cursor's page and
+// the number of tiles per page.
+func (m Model) mosaicPage(width, height, n int) (start, perPage int) {
+	cols, rows := mosaicGrid(width, height, n)
+	perPage = cols * rows
+	cursor := max(0, min(m.mosaicCursor, n-1))
+	start = (cursor / perPage) * perPage
+	return start, perPage
+}
+
+// mosaicContentSize returns the area available to the grid.
+func (m Model) mosaicContentSize() (width, height int) {
+	height = m.height - 8
+	if m.searchMode || m.searchQuery != "" {
+		height -= 3
+	}
+	return m.width, max(height, mosaicMinTileHeight)
+}
+
+// mosaicVisibleSessions returns the sessions whose tiles are on screen.
+func (m Model) mosaicVisibleSessions() []session.Info {
+	tiles := m.mosaicSessions()
+	if len(tiles) == 0 {
+		return nil
+	}
+	if m.mosaicZoomed {
+		cursor := min(m.mosaicCursor, len(tiles)-1)
+		return tiles[cursor : cursor+1]
+	}
+	width, height := m.mosaicContentSize()
+	start, perPage := m.mosaicPage(width, height, len(tiles))
+	return tiles[start:min(start+perPage, len(tiles))]
+}
+
+// mosaicCaptureCmd starts captures for the visible tiles that are due, oldest
+// first and at most mosaicMaxCapturesPerTick, and batches them with next.
+func (m *Model) mosaicCaptureCmd(now time.Time, next tea.Cmd) tea.Cmd {
+	var due []session.Info
+	for _, s := range m.mosaicVisibleSessions() {
+		id := s.ID()
+		if m.mosaicPending[id] {
+			continue
+		}
+		refresh := mosaicLocalRefresh
+		if s.Remote != "" {
+			refresh = mosaicRemoteRefresh
+		}
+		if tile, ok := m.mosaicTiles[id]; ok && now.Sub(tile.captured) < refresh {
+			continue
+		}
+		due = append(due, s)
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return m.mosaicTiles[due[i].ID()].captured.Before(m.mosaicTiles[due[j].ID()].captured)
+	})
+	if len(due) > mosaicMaxCapturesPerTick {
+		due = due[:mosaicMaxCapturesPerTick]
+	}
+
+	cmds := []tea.Cmd{next}
+	for _, s := range due {
+		m.mosaicPending[s.ID()] = true
+		cmds = append(cmds, captureMosaicTileCmd(m.SSHPool, s))
+	}
+	return tea.Batch(cmds...)
+}
+
+// captureMosaicTileCmd captures one session's pane for its tile.
+func captureMosaicTileCmd(pool *remote.SSHPool, s session.Info) tea.Cmd {
+	id := s.ID()
+	return func() tea.Msg {
+		var content string
+		var err error
+		if s.Remote != "" && pool != nil {
+			content, err = remote.CapturePane(pool, s.Remote, s.TmuxSession, previewDefaultLines)
+		} else {
+			content, err = capturePane(s.TmuxSession, previewDefaultLines)
+		}
+		return mosaicContentMsg{id: id, content: content, err: err}
+	}
+}
+
+// updateMosaicView handles keyboard input while the mosaic view is open.
+func (m Model) updateMosaicView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tiles := m.mosaicSessions()
+	width, height := m.mosaicContentSize()
+	cols, _ := mosaicGrid(width, height, len(tiles))
+	_, perPage := m.mosaicPage(width, height, len(tiles))
+
+	move := func(delta int) (tea.Model, tea.Cmd) {
+		if len(tiles) == 0 {
+			return m, nil
+		}
+		m.mosaicCursor = max(0, min(len(tiles)-1, m.mosaicCursor+delta))
+		return m, m.mosaicCaptureCmd(time.Now(), nil)
+	}
+
+	switch m.keys.Action(keymap.Mosaic, msg.String()) {
+	case keymap.Quit:
+		return m, tea.Quit
+
+	case keymap.Help:
+		m.openKeyHelp(keymap.Mosaic)
+		return m, nil
+
+	case keymap.Back:
+		if m.mosaicZoomed {
+			m.mosaicZoomed = false
+			return m, m.mosaicCaptureCmd(time.Now(), nil)
+		}
+		m.closeMosaicView()
+		return m, nil
+
+	case keymap.Zoom:
+		if len(tiles) > 0 {
+			m.mosaicZoomed = !m.mosaicZoomed
+		}
+		return m, m.mosaicCaptureCmd(time.Now(), nil)
+
+	case keymap.Left:
+		return move(-1)
+	case keymap.Right:
+		return move(1)
+	case keymap.Up:
+		if m.mosaicZoomed {
+			return move(-1)
+		}
+		return move(-cols)
+	case keymap.Down:
+		if m.mosaicZoomed {
+			return move(1)
+		}
+		return move(cols)
+	case keymap.PageUp:
+		return move(-perPage)
+	case keymap.PageDown:
+		return move(perPage)
+
+	case keymap.Attach:
+		if m.mosaicCursor < len(tiles) {
+			s := tiles[m.mosaicCursor]
+			m.lastSelectedSession = s.ID()
+			return m, m.attachTo(s)
+		}
+	}
+
+	return m, nil
+}
+
+// renderMosaicView renders the visible tiles as a grid, or the zoomed tile.
+func (m Model) renderMosaicView(width, height int) string {
+	tiles := m.mosaicSessions()
+	if len(tiles) == 0 {
+		return previewEmptyStyle.Width(width).Align(lipgloss.Center).Render("No sessions to show")
+	}
+
+	cursor := min(m.mosaicCursor, len(tiles)-1)
+	if m.mosaicZoomed {
+		return m.renderMosaicTile(tiles[cursor], true, width, height)
+	}
+
+	cols, rows := mosaicGrid(width, height, len(tiles))
+	start, perPage := m.mosaicPage(width, height, len(tiles))
+	visible := tiles[start:min(start+perPage, len(tiles))]
+
+	tileWidth := width / cols
+	tileHeight := height / rows
+	var gridRows []string
+	for r := 0; r*cols < len(visible); r++ {
+		var row []string
+		for c := 0; c < cols && r*cols+c < len(visible); c++ {
+			i := r*cols + c
+			row = append(row, m.renderMosaicTile(visible[i], start+i == cursor, tileWidth, tileHeight))
+		}
+		gridRows = append(gridRows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, gridRows...)
+}
+
+// renderMosaicTile renders one session's tile: a border in the session's
+// status color, a title line, and the tail of its pane.
+func (m Model) renderMosaicTile(s session.Info, selected bool, width, height int) string {
+	innerWidth := max(1, width-2)
+	bodyLines := max(1, height-mosaicTileChrome)
+
+	status, _ := session.CompositeStatus(s)
+	title := StatusIcon(status) + " " + boldStyle.Render(s.Label())
+	if selected {
+		title = StatusIcon(status) + " " + highlightStyle.Bold(true).Render(s.Label())
+	}
+
+	var body []string
+	tile, captured := m.mosaicTiles[s.ID()]
+	switch {
+	case !captured:
+		body = []string{previewEmptyStyle.Render("Loading...")}
+	case tile.err != nil:
+		body = []string{dialogErrorStyle.Render(ansi.Truncate("Capture failed: "+tile.err.Error(), innerWidth, "…"))}
+	case strings.TrimSpace(tile.content) == "":
+		body = []string{previewEmptyStyle.Render("No output")}
+	default:
+		content := tile.content
+		if m.previewPlain {
+			content = StripANSI(content)
+		}
+		body = sgr.Lines(content)
+		if len(body) > bodyLines {
+			body = body[len(body)-bodyLines:]
+		}
+		for i, line := range body {
+			body[i] = ansi.Truncate(line, innerWidth, "…")
+		}
+	}
+	for len(body) < bodyLines {
+		body = append(body, "")
+	}
+
+	border := boxStyle.GetBorderStyle()
+	if selected {
+		border = lipgloss.ThickBorder()
+	}
+	style := lipgloss.NewStyle().
+		Border(border).
+		BorderForeground(statusTimelineStyle(status).GetForeground()).
+		Width(innerWidth).
+		Height(height - 2)
+
+	return style.Render(ansi.Truncate(title, innerWidth, "…") + "\n" + strings.Join(body, "\n"))
+}
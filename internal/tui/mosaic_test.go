@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+func newMosaicModel(n int) Model {
+	sessions := make([]session.Info, n)
+	for i := range sessions {
+		sessions[i] = session.Info{
+			TmuxSession: fmt.Sprintf("s%02d", i),
+			Status:      session.StatusWorking,
+			Timestamp:   int64(1000 - i),
+		}
+	}
+	return Model{width: 120, height: 40, sessions: sessions}
+}
+
+func TestMosaicGrid(t *testing.T) {
+	tests := []struct {
+		width, height, n int
+		cols, rows       int
+	}{
+		{120, 32, 1, 1, 1},
+		{120, 32, 4, 2, 2},
+		{120, 32, 20, 3, 4},
+		{60, 10, 5, 1, 1},
+		{120, 32, 0, 1, 1},
+	}
+	for _, tt := range tests {
+		cols, rows := mosaicGrid(tt.width, tt.height, tt.n)
+		if cols != tt.cols || rows != tt.rows {
+			t.Errorf("mosaicGrid(%d, %d, %d) = %dx%d, want %dx%d", tt.width, tt.height, tt.n, cols, rows, tt.cols, tt.rows)
+		}
+	}
+}
+
+func TestMosaicOpensOnCursorAndThrottlesCaptures(t *testing.T) {
+	m := newMosaicModel(12)
+	m.cursor = 2
+
+	m = typeText(t, m, "M")
+	if !m.mosaicVisible {
+		t.Fatal("M did not open the mosaic view")
+	}
+	if m.mosaicCursor != 2 {
+		t.Errorf("mosaicCursor = %d, want 2", m.mosaicCursor)
+	}
+	if len(m.mosaicPending) != mosaicMaxCapturesPerTick {
+		t.Fatalf("started %d captures, want %d", len(m.mosaicPending), mosaicMaxCapturesPerTick)
+	}
+
+	// A tick starts the next batch without repeating in-flight captures.
+	updated, _ := m.Update(mosaicTickMsg{gen: m.mosaicGen})
+	m = updated.(Model)
+	if len(m.mosaicPending) != 2*mosaicMaxCapturesPerTick {
+		t.Errorf("pending = %d after a tick, want %d", len(m.mosaicPending), 2*mosaicMaxCapturesPerTick)
+	}
+
+	// Ticks from an earlier mosaic session are dropped.
+	updated, cmd := m.Update(mosaicTickMsg{gen: m.mosaicGen - 1})
+	if cmd != nil || len(updated.(Model).mosaicPending) != len(m.mosaicPending) {
+		t.Error("stale tick should neither capture nor reschedule")
+	}
+}
+
+func TestMosaicRemoteTilesRefreshSlower(t *testing.T) {
+	m := newMosaicModel(2)
+	m.sessions[1].Remote = "dev"
+	m.toggleMosaicView()
+
+	now := time.Now()
+	for _, s := range m.mosaicSessions() {
+		delete(m.mosaicPending, s.ID())
+		m.mosaicTiles[s.ID()] = mosaicTile{content: "out", captured: now.Add(-2 * time.Second)}
+	}
+
+	m.mosaicCaptureCmd(now, nil)
+	if !m.mosaicPending[m.sessions[0].ID()] {
+		t.Error("local tile older than the local refresh should be recaptured")
+	}
+	if m.mosaicPending[m.sessions[1].ID()] {
+		t.Error("remote tile younger than the remote refresh should not be recaptured")
+	}
+}
+
+func TestMosaicNavigationAndZoom(t *testing.T) {
+	m := newMosaicModel(4)
+	m = typeText(t, m, "M")
+
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyRight}, tea.KeyMsg{Type: tea.KeyDown})
+	if m.mosaicCursor != 3 {
+		t.Fatalf("mosaicCursor = %d, want 3 in a 2x2 grid", m.mosaicCursor)
+	}
+
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.mosaicZoomed {
+		t.Fatal("enter did not zoom")
+	}
+	if got := m.mosaicVisibleSessions(); len(got) != 1 || got[0].TmuxSession != "s03" {
+		t.Errorf("zoomed tiles = %v, want only s03", got)
+	}
+
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mosaicZoomed || !m.mosaicVisible {
+		t.Fatal("esc should leave zoom first")
+	}
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mosaicVisible {
+		t.Fatal("esc should close the mosaic view")
+	}
+	if m.cursor != 3 {
+		t.Errorf("cursor = %d, want the chosen tile 3", m.cursor)
+	}
+}
+
+func TestMosaicFollowsSearch(t *testing.T) {
+	m := newMosaicModel(4)
+	m.searchQuery = "s0"
+	m.searchMatches = []int{1, 3}
+
+	tiles := m.mosaicSessions()
+	if len(tiles) != 2 || tiles[0].TmuxSession != "s01" || tiles[1].TmuxSession != "s03" {
+		t.Errorf("tiles = %v, want the search matches", tiles)
+	}
+}
+
+func TestRenderMosaicView(t *testing.T) {
+	m := newMosaicModel(2)
+	m.toggleMosaicView()
+	m.mosaicTiles[m.sessions[0].ID()] = mosaicTile{content: "first line\nlatest output", captured: time.Now()}
+	m.mosaicTiles[m.sessions[1].ID()] = mosaicTile{err: errors.New("no server"), captured: time.Now()}
+
+	out := m.renderMosaicView(m.mosaicContentSize())
+	for _, want := range []string{"s00", "latest output", "s01", "Capture failed: no server"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("mosaic missing %q:\n%s", want, out)
+		}
+	}
+
+	m.mosaicZoomed = true
+	out = m.renderMosaicView(m.mosaicContentSize())
+	if !strings.Contains(out, "s00") || strings.Contains(out, "s01") {
+		t.Errorf("zoomed mosaic should show only the selected tile:\n%s", out)
+	}
+
+	m.closeMosaicView()
+	m.sessions = nil
+	m.mosaicVisible = true
+	if out := m.renderMosaicView(m.mosaicContentSize()); !strings.Contains(out, "No sessions") {
+		t.Errorf("empty mosaic = %q", out)
+	}
+}
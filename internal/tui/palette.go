@@ -0,0 +1,460 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	ansi "github.com/charmbracelet/x/ansi"
+
+	"github.com/stwalsh4118/navi/internal/keymap"
+	"github.com/stwalsh4118/navi/internal/task"
+)
+
+// Command palette layout constants
+const (
+	paletteWidth      = 72 // Width of the palette dialog
+	paletteMaxVisible = 12 // Entries shown at once
+	paletteKindWidth  = 8  // Column reserved for the entry kind
+)
+
+// Fuzzy match scoring
+const (
+	fuzzyMatchScore       = 1  // Each matched rune
+	fuzzyConsecutiveBonus = 4  // Matched rune directly after the previous match
+	fuzzyWordStartBonus   = 6  // Matched rune at the start of a word
+	fuzzyPrefixBonus      = 8  // Match starting at the first rune
+	fuzzyDetailPenalty    = 10 // Match found only in an entry's detail
+)
+
+// paletteKind is the type of thing a palette entry refers to.
+type paletteKind int
+
+const (
+	paletteSession paletteKind = iota
+	paletteAction
+	paletteTask
+	paletteAttention
+)
+
+// String returns the label shown in the palette's kind column.
+func (k paletteKind) String() string {
+	switch k {
+	case paletteSession:
+		return "session"
+	case paletteAction:
+		return "action"
+	case paletteTask:
+		return "task"
+	case paletteAttention:
+		return "pm"
+	default:
+		return ""
+	}
+}
+
+// paletteEntry is one runnable item in the command palette.
+type paletteEntry struct {
+	kind   paletteKind
+	title  string   // Matched first and shown in full
+	detail []string // Also matched; shown dimmed after the title
+	hint   string   // Shown at the right edge, e.g. the action's key
+	run    func(m Model) (tea.Model, tea.Cmd)
+}
+
+// paletteMatch is an entry that matches the current query.
+type paletteMatch struct {
+	entry     int   // Index into paletteEntries, or the popup's sessions
+	score     int   // Higher is better
+	positions []int // Matched rune positions in the title
+}
+
+// initPaletteInput creates and configures the command palette's text input.
+func initPaletteInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "Type to search sessions, actions, tasks..."
+	ti.CharLimit = inputSearchCharLimit
+	ti.Width = paletteWidth - 10
+	ti.PromptStyle = highlightStyle
+	ti.TextStyle = lipgloss.NewStyle()
+	return ti
+}
+
+// keyContext returns the key binding context of the current view, or "" while
+// text is being typed into a search input.
+func (m Model) keyContext() keymap.Context {
+	switch {
+	case m.pmViewVisible:
+		return keymap.PM
+	case m.mosaicVisible:
+		return keymap.Mosaic
+	case m.taskPanelFocused:
+		if m.taskSearchMode {
+			return ""
+		}
+		return keymap.Tasks
+	case m.previewFocused:
+		if m.previewSearchMode {
+			return ""
+		}
+		return keymap.Preview
+	case m.searchMode:
+		return ""
+	default:
+		return keymap.Sessions
+	}
+}
+
+// openPalette opens the command palette over the current view.
+func (m Model) openPalette() (tea.Model, tea.Cmd) {
+	m.paletteEntries = m.paletteItems(m.keyContext())
+	m.paletteInput = initPaletteInput()
+	m.paletteInput.Focus()
+	m.paletteCursor = 0
+	m.paletteScroll = 0
+	m.filterPalette()
+	m.dialogMode = DialogPalette
+	m.dialogError = ""
+	return m, textinput.Blink
+}
+
+// paletteItems gathers the palette's entries: sessions, the actions bound in
+// ctx, tasks and PM attention items.
+func (m Model) paletteItems(ctx keymap.Context) []paletteEntry {
+	var entries []paletteEntry
+
+	for _, s := range m.getFilteredSessions() {
+		detail := []string{s.CWD}
+		if s.Git != nil && s.Git.Branch != "" {
+			detail = append(detail, s.Git.Branch)
+		}
+		if s.CurrentPBI != "" {
+			detail = append(detail, strings.TrimSpace(s.CurrentPBI+" "+s.CurrentPBITitle))
+		}
+		if s.Message != "" {
+			detail = append(detail, s.Message)
+		}
+		entries = append(entries, paletteEntry{
+			kind:   paletteSession,
+			title:  s.Label(),
+			detail: detail,
+			hint:   s.Status,
+			run: func(m Model) (tea.Model, tea.Cmd) {
+				m.lastSelectedSession = s.ID()
+				return m, m.attachTo(s)
+			},
+		})
+	}
+
+	for _, b := range m.keys.Bindings(ctx) {
+		if b.Action == keymap.Palette || len(b.Keys) == 0 {
+			continue
+		}
+		key, ok := keyMsgFor(b.Keys[0])
+		if !ok {
+			continue
+		}
+		entries = append(entries, paletteEntry{
+			kind:  paletteAction,
+			title: b.Help,
+			hint:  keymap.FormatKeys(b.Keys),
+			run: func(m Model) (tea.Model, tea.Cmd) {
+				// Replay the key in the view the palette was opened from
+				return m.Update(key)
+			},
+		})
+	}
+
+	projects := make([]string, 0, len(m.taskGroupsByProject))
+	for project := range m.taskGroupsByProject {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+	for _, project := range projects {
+		for _, g := range m.taskGroupsByProject[project] {
+			for _, t := range g.Tasks {
+				item := paletteTaskItem(g, t)
+				entries = append(entries, paletteEntry{
+					kind:   paletteTask,
+					title:  strings.TrimSpace(t.ID + " " + t.Title),
+					detail: []string{filepath.Base(project), strings.TrimSpace(g.ID + " " + g.Title)},
+					hint:   t.Status,
+					run: func(m Model) (tea.Model, tea.Cmd) {
+						return m.openTaskDetail(project, &item)
+					},
+				})
+			}
+		}
+	}
+
+	if m.pmBriefing != nil {
+		for _, a := range m.pmBriefing.AttentionItems {
+			entries = append(entries, paletteEntry{
+				kind:   paletteAttention,
+				title:  a.Title,
+				detail: []string{a.ProjectName, a.Description},
+				hint:   a.Priority,
+				run: func(m Model) (tea.Model, tea.Cmd) {
+					text := a.Description
+					if a.ProjectName != "" {
+						text = "Project: " + a.ProjectName + "\n\n" + text
+					}
+					m.openContentViewer(a.Title, text, ContentModePlain)
+					return m, nil
+				},
+			})
+		}
+	}
+
+	return entries
+}
+
+// paletteTaskItem builds the task panel item for a task so it opens the same way.
+func paletteTaskItem(g task.TaskGroup, t task.Task) taskItem {
+	return taskItem{groupID: g.ID, title: t.Title, status: t.Status, taskID: t.ID, url: t.URL}
+}
+
+// filterPalette recomputes the matching entries for the current query, best
+// match first. Without a query every entry matches in gathering order.
+func (m *Model) filterPalette() {
+	query := strings.TrimSpace(m.paletteInput.Value())
+	m.paletteMatches = nil
+	for i, e := range m.paletteEntries {
+		if query == "" {
+			m.paletteMatches = append(m.paletteMatches, paletteMatch{entry: i})
+			continue
+		}
+		if score, positions, ok := fuzzyMatch(query, e.title); ok {
+			m.paletteMatches = append(m.paletteMatches, paletteMatch{entry: i, score: score, positions: positions})
+			continue
+		}
+		if score, _, ok := fuzzyMatch(query, strings.Join(e.detail, " ")); ok {
+			m.paletteMatches = append(m.paletteMatches, paletteMatch{entry: i, score: score - fuzzyDetailPenalty})
+		}
+	}
+	sort.SliceStable(m.paletteMatches, func(i, j int) bool {
+		return m.paletteMatches[i].score > m.paletteMatches[j].score
+	})
+	m.paletteCursor = 0
+	m.paletteScroll = 0
+}
+
+// fuzzyMatch reports whether the runes of query appear in order in target,
+// ignoring case, and scores the match: consecutive runes, word starts and a
+// match at the very start score higher. positions are the matched rune
+// indices in target.
+func fuzzyMatch(query, target string) (score int, positions []int, ok bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(target)
+	if len(q) == 0 {
+		return 0, nil, false
+	}
+
+	qi := 0
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if unicode.ToLower(t[ti]) != q[qi] {
+			continue
+		}
+		score += fuzzyMatchScore
+		if len(positions) > 0 && positions[len(positions)-1] == ti-1 {
+			score += fuzzyConsecutiveBonus
+		}
+		if ti == 0 {
+			score += fuzzyPrefixBonus
+		} else if isWordStart(t[ti-1], t[ti]) {
+			score += fuzzyWordStartBonus
+		}
+		positions = append(positions, ti)
+		qi++
+	}
+	if qi < len(q) {
+		return 0, nil, false
+	}
+
+	// Prefer tighter matches
+	score -= positions[len(positions)-1] - positions[0] - (len(positions) - 1)
+	return score, positions, true
+}
+
+// isWordStart reports whether cur begins a word, given the rune before it.
+func isWordStart(prev, cur rune) bool {
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+// keyMsgFor converts a key as written in the keymap back into the key
+// message that produces it.
+func keyMsgFor(key string) (tea.KeyMsg, bool) {
+	alt := false
+	if rest, found := strings.CutPrefix(key, "alt+"); found && rest != "" {
+		alt, key = true, rest
+	}
+	for t := tea.KeyF20; t <= tea.KeyType(127); t++ {
+		if t != tea.KeyRunes && (tea.Key{Type: t}).String() == key {
+			return tea.KeyMsg{Type: t, Alt: alt}, true
+		}
+	}
+	if runes := []rune(key); len(runes) == 1 {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: runes, Alt: alt}, true
+	}
+	return tea.KeyMsg{}, false
+}
+
+// updatePalette handles key messages while the command palette is open.
+func (m Model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.dialogMode = DialogNone
+		return m, nil
+
+	case "up", "ctrl+k":
+		m.movePaletteCursor(-1)
+		return m, nil
+
+	case "down", "ctrl+j":
+		m.movePaletteCursor(1)
+		return m, nil
+
+	case "pgup":
+		m.movePaletteCursor(-paletteMaxVisible)
+		return m, nil
+
+	case "pgdown":
+		m.movePaletteCursor(paletteMaxVisible)
+		return m, nil
+
+	case "enter":
+		if m.paletteCursor >= len(m.paletteMatches) {
+			return m, nil
+		}
+		entry := m.paletteEntries[m.paletteMatches[m.paletteCursor].entry]
+		m.dialogMode = DialogNone
+		m.paletteEntries = nil
+		m.paletteMatches = nil
+		return entry.run(m)
+	}
+
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	m.filterPalette()
+	return m, cmd
+}
+
+// movePaletteCursor moves the palette cursor by delta, keeping it visible.
+func (m *Model) movePaletteCursor(delta int) {
+	if len(m.paletteMatches) == 0 {
+		return
+	}
+	m.paletteCursor = max(0, min(m.paletteCursor+delta, len(m.paletteMatches)-1))
+	if m.paletteCursor < m.paletteScroll {
+		m.paletteScroll = m.paletteCursor
+	}
+	if m.paletteCursor >= m.paletteScroll+paletteMaxVisible {
+		m.paletteScroll = m.paletteCursor - paletteMaxVisible + 1
+	}
+}
+
+// renderPalette renders the command palette dialog overlay.
+func (m Model) renderPalette() string {
+	var b strings.Builder
+	innerWidth := paletteWidth - 6 // Border and padding
+
+	b.WriteString(dialogTitleStyle.Render(DialogTitle(DialogPalette)))
+	b.WriteString("\n\n")
+	b.WriteString(m.paletteInput.View())
+	b.WriteString("\n\n")
+
+	if len(m.paletteMatches) == 0 {
+		b.WriteString(dimStyle.Render("No matches"))
+	} else {
+		end := min(m.paletteScroll+paletteMaxVisible, len(m.paletteMatches))
+		if m.paletteScroll > 0 {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  ↑ %d more", m.paletteScroll)))
+			b.WriteString("\n")
+		}
+		for i := m.paletteScroll; i < end; i++ {
+			b.WriteString(m.renderPaletteEntry(m.paletteMatches[i], innerWidth, i == m.paletteCursor))
+			if i < end-1 {
+				b.WriteString("\n")
+			}
+		}
+		if end < len(m.paletteMatches) {
+			b.WriteString("\n")
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  ↓ %d more", len(m.paletteMatches)-end)))
+		}
+	}
+
+	b.WriteString("\n\n")
+	b.WriteString(dimStyle.Render("↑↓: navigate  Enter: run  Esc: close"))
+
+	dialog := dialogBoxStyle.Width(paletteWidth).Render(b.String())
+	return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, dialog)
+}
+
+// renderPaletteEntry renders one palette row: kind, title with the matched
+// runes highlighted, dimmed detail, and the hint at the right edge.
+func (m Model) renderPaletteEntry(match paletteMatch, width int, selected bool) string {
+	e := m.paletteEntries[match.entry]
+
+	hint := e.hint
+	titleWidth := width - paletteKindWidth - lipgloss.Width(hint) - 1
+	title := ansi.Truncate(e.title, titleWidth, "...")
+	detail := ""
+	if room := titleWidth - lipgloss.Width(title) - 2; room > 4 && len(e.detail) > 0 {
+		detail = ansi.Truncate(strings.Join(nonEmpty(e.detail), " · "), room, "...")
+	}
+
+	if selected {
+		line := fmt.Sprintf("%-*s%s", paletteKindWidth, e.kind, title)
+		if detail != "" {
+			line += "  " + detail
+		}
+		pad := max(width-lipgloss.Width(line)-lipgloss.Width(hint), 1)
+		return selectedStyle.Render(line + strings.Repeat(" ", pad) + hint)
+	}
+
+	line := dimStyle.Render(fmt.Sprintf("%-*s", paletteKindWidth, e.kind)) + highlightRunes(title, match.positions)
+	if detail != "" {
+		line += "  " + dimStyle.Render(detail)
+	}
+	pad := max(width-lipgloss.Width(line)-lipgloss.Width(hint), 1)
+	return line + strings.Repeat(" ", pad) + dimStyle.Render(hint)
+}
+
+// highlightRunes renders the runes of s at positions with highlightStyle.
+func highlightRunes(s string, positions []int) string {
+	if len(positions) == 0 {
+		return s
+	}
+	marked := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		marked[p] = true
+	}
+	var b strings.Builder
+	for i, r := range []rune(s) {
+		if marked[i] {
+			b.WriteString(highlightStyle.Render(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// nonEmpty returns the non-empty strings of ss.
+func nonEmpty(ss []string) []string {
+	var out []string
+	for _, s := range ss {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/pm"
+	"github.com/stwalsh4118/navi/internal/task"
+)
+
+// newPaletteTestModel returns a session list with one task and one PM
+// attention item, so every kind of palette entry is present.
+func newPaletteTestModel(t *testing.T) Model {
+	t.Helper()
+	m := newPreviewScrollTestModel()
+	m.previewVisible = false
+	m.previewUserEnabled = false
+
+	project := t.TempDir()
+	taskFile := filepath.Join(project, "docs", "delivery", "29", "29-1.md")
+	if err := os.MkdirAll(filepath.Dir(taskFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(taskFile, []byte("# Wire up the widget"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m.taskGroupsByProject[project] = []task.TaskGroup{{
+		ID:    "PBI-29",
+		Title: "Widgets",
+		Tasks: []task.Task{{ID: "29-1", Title: "Wire up the widget", Status: "InProgress"}},
+	}}
+	m.pmBriefing = &pm.PMBriefing{AttentionItems: []pm.AttentionItem{
+		{Priority: "high", Title: "Release is blocked", Description: "CI is red", ProjectName: "navi"},
+	}}
+	return m
+}
+
+// paletteTitles returns the titles of the matching palette entries in order.
+func paletteTitles(m Model) []string {
+	titles := make([]string, len(m.paletteMatches))
+	for i, match := range m.paletteMatches {
+		titles[i] = m.paletteEntries[match.entry].title
+	}
+	return titles
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	t.Run("matches runes in order ignoring case", func(t *testing.T) {
+		_, positions, ok := fuzzyMatch("TgP", "Toggle preview pane")
+		if !ok {
+			t.Fatal("expected a match")
+		}
+		if len(positions) != 3 || positions[0] != 0 {
+			t.Errorf("positions = %v, want three starting at 0", positions)
+		}
+	})
+
+	t.Run("rejects runes out of order", func(t *testing.T) {
+		if _, _, ok := fuzzyMatch("pt", "Toggle"); ok {
+			t.Error("expected no match")
+		}
+	})
+
+	t.Run("prefers prefixes, word starts and runs", func(t *testing.T) {
+		prefix, _, _ := fuzzyMatch("pre", "preview")
+		word, _, _ := fuzzyMatch("pre", "toggle preview")
+		scattered, _, _ := fuzzyMatch("pre", "spare")
+		if !(prefix > word && word > scattered) {
+			t.Errorf("scores prefix=%d word=%d scattered=%d, want descending", prefix, word, scattered)
+		}
+	})
+}
+
+func TestKeyMsgFor(t *testing.T) {
+	for _, key := range []string{"enter", "esc", "tab", " ", "ctrl+p", "pgdown", "up", "G", "/", "alt+x"} {
+		msg, ok := keyMsgFor(key)
+		if !ok {
+			t.Errorf("keyMsgFor(%q) failed", key)
+			continue
+		}
+		if msg.String() != key {
+			t.Errorf("keyMsgFor(%q).String() = %q", key, msg.String())
+		}
+	}
+	if _, ok := keyMsgFor("nonsense"); ok {
+		t.Error("expected an unknown key to fail")
+	}
+}
+
+func TestCommandPalette(t *testing.T) {
+	ctrlP := tea.KeyMsg{Type: tea.KeyCtrlP}
+
+	t.Run("ctrl+p lists sessions, actions, tasks and attention items", func(t *testing.T) {
+		m := sendKeys(t, newPaletteTestModel(t), ctrlP)
+		if m.dialogMode != DialogPalette {
+			t.Fatalf("dialog = %v, want the palette", m.dialogMode)
+		}
+		kinds := make(map[paletteKind]int)
+		for _, e := range m.paletteEntries {
+			kinds[e.kind]++
+			if e.kind == paletteAction && e.title == "Command palette" {
+				t.Error("the palette should not list itself")
+			}
+		}
+		if kinds[paletteSession] != 1 || kinds[paletteTask] != 1 || kinds[paletteAttention] != 1 || kinds[paletteAction] == 0 {
+			t.Errorf("entry kinds = %v", kinds)
+		}
+		if !strings.Contains(m.View(), "Command Palette") {
+			t.Error("expected the palette to render")
+		}
+	})
+
+	t.Run("typing filters the entries", func(t *testing.T) {
+		m := sendKeys(t, newPaletteTestModel(t), ctrlP)
+		m = typeText(t, m, "widget")
+		titles := paletteTitles(m)
+		if len(titles) == 0 || titles[0] != "29-1 Wire up the widget" {
+			t.Errorf("matches = %v, want the task first", titles)
+		}
+
+		m = typeText(t, m, "zzz")
+		if len(m.paletteMatches) != 0 || !strings.Contains(m.renderPalette(), "No matches") {
+			t.Error("expected no matches")
+		}
+	})
+
+	t.Run("enter runs an action in the current view", func(t *testing.T) {
+		m := sendKeys(t, newPaletteTestModel(t), ctrlP)
+		m = typeText(t, m, "toggle preview")
+		m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.dialogMode != DialogNone || !m.previewVisible {
+			t.Errorf("dialog = %v, preview = %v; want the preview toggled on", m.dialogMode, m.previewVisible)
+		}
+	})
+
+	t.Run("enter opens a task", func(t *testing.T) {
+		m := sendKeys(t, newPaletteTestModel(t), ctrlP)
+		m = typeText(t, m, "29-1")
+		m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.dialogMode != DialogContentViewer || m.contentViewerTitle != "Wire up the widget" {
+			t.Errorf("dialog = %v titled %q, want the task file", m.dialogMode, m.contentViewerTitle)
+		}
+	})
+
+	t.Run("enter opens an attention item", func(t *testing.T) {
+		m := sendKeys(t, newPaletteTestModel(t), ctrlP)
+		m = typeText(t, m, "blocked")
+		m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.dialogMode != DialogContentViewer || m.contentViewerTitle != "Release is blocked" {
+			t.Errorf("dialog = %v titled %q, want the attention item", m.dialogMode, m.contentViewerTitle)
+		}
+	})
+
+	t.Run("esc closes without running anything", func(t *testing.T) {
+		m := sendKeys(t, newPaletteTestModel(t), ctrlP, tea.KeyMsg{Type: tea.KeyEscape})
+		if m.dialogMode != DialogNone || m.previewVisible {
+			t.Error("esc should only close the palette")
+		}
+	})
+
+	t.Run("does not open while typing a search", func(t *testing.T) {
+		m := newPaletteTestModel(t)
+		m.searchMode = true
+		m = sendKeys(t, m, ctrlP)
+		if m.dialogMode == DialogPalette {
+			t.Error("ctrl+p should not open the palette in search mode")
+		}
+	})
+}
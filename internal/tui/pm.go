@@ -7,7 +7,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/stwalsh4118/navi/internal/debug"
+	"github.com/stwalsh4118/navi/internal/git"
 	"github.com/stwalsh4118/navi/internal/pm"
+	"github.com/stwalsh4118/navi/internal/remote"
 	"github.com/stwalsh4118/navi/internal/session"
 	"github.com/stwalsh4118/navi/internal/task"
 )
@@ -26,16 +28,34 @@ func pmTickCmd() tea.Cmd {
 	})
 }
 
-func pmRunCmd(engine *pm.Engine, sessions []session.Info, taskResults map[string]*task.ProviderResult) tea.Cmd {
+func pmRunCmd(engine *pm.Engine, sessions []session.Info, taskResults map[string]*task.ProviderResult, pool *remote.SSHPool) tea.Cmd {
 	sessionCopy := append([]session.Info(nil), sessions...)
 	resultsCopy := maps.Clone(taskResults)
 
 	return func() tea.Msg {
+		if pool != nil {
+			engine.SetRemoteGit(remoteGitFunc(pool))
+		}
 		output, err := engine.Run(sessionCopy, resultsCopy)
 		return pmOutputMsg{output: output, err: err}
 	}
 }
 
+// remoteGitFunc looks up git state for remote projects through the pool.
+func remoteGitFunc(pool *remote.SSHPool) pm.RemoteGitFunc {
+	return func(remoteName, dir string) (*git.Info, string) {
+		info, err := remote.FetchGitInfo(pool, remoteName, dir)
+		if err != nil || info == nil {
+			return nil, ""
+		}
+		sha, err := remote.FetchHeadSHA(pool, remoteName, dir)
+		if err != nil {
+			debug.Log("tui: pm remote head sha failed for %s:%s: %v", remoteName, dir, err)
+		}
+		return info, sha
+	}
+}
+
 // pmInvokeCmd runs InvokeWithRecoveryStream in a goroutine, sending streaming
 // status updates through a channel consumed by pmStreamReadCmd.
 func pmInvokeCmd(invoker *pm.Invoker, trigger pm.TriggerType, snapshots []pm.ProjectSnapshot, events []pm.Event) tea.Cmd {
@@ -12,6 +12,7 @@ import (
 	ansi "github.com/charmbracelet/x/ansi"
 	"github.com/muesli/reflow/wordwrap"
 
+	"github.com/stwalsh4118/navi/internal/keymap"
 	"github.com/stwalsh4118/navi/internal/pm"
 	"github.com/stwalsh4118/navi/internal/task"
 )
@@ -58,13 +59,17 @@ func (m *Model) togglePMView() tea.Cmd {
 }
 
 func (m Model) updatePMView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "q", "ctrl+c":
+	action := m.keys.Action(keymap.PM, msg.String())
+	switch action {
+	case keymap.Quit:
 		return m, tea.Quit
-	case "P", "esc":
+	case keymap.Help:
+		m.openKeyHelp(keymap.PM)
+		return m, nil
+	case keymap.Back:
 		m.pmViewVisible = false
 		return m, nil
-	case "r":
+	case keymap.Refresh:
 		if m.pmRunInFlight || m.pmEngine == nil {
 			return m, nil
 		}
@@ -73,10 +78,10 @@ func (m Model) updatePMView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.pmRunInFlight = true
 		return m, tea.Batch(
-			taskRefreshCmd(m.taskProjectConfigs, m.taskCache, m.taskGlobalConfig, task.DefaultProviderTimeout),
-			pmRunCmd(m.pmEngine, m.sessions, m.pmTaskResults),
+			taskRefreshCmd(m.taskProjectConfigs, m.taskCache, m.taskGlobalConfig, task.DefaultProviderTimeout, m.SSHPool),
+			pmRunCmd(m.pmEngine, m.sessions, m.pmTaskResults, m.SSHPool),
 		)
-	case "i":
+	case keymap.Invoke:
 		if m.pmInvoker == nil || m.pmInvokeInFlight {
 			return m, nil
 		}
@@ -88,7 +93,7 @@ func (m Model) updatePMView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.pmInvokeInFlight = true
 		return m, pmInvokeCmd(m.pmInvoker, pm.TriggerOnDemand, snapshots, events)
-	case "tab":
+	case keymap.Focus:
 		if m.pmZoneFocus == pmZoneProjects {
 			m.pmZoneFocus = pmZoneEvents
 		} else {
@@ -98,36 +103,32 @@ func (m Model) updatePMView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	if m.pmZoneFocus == pmZoneProjects {
-		switch msg.String() {
-		case "up", "k":
+		switch action {
+		case keymap.Up:
 			m.movePMProjectCursor(-1)
-		case "down", "j":
+		case keymap.Down:
 			m.movePMProjectCursor(1)
-		case "enter":
+		case keymap.Open:
 			m.pmSelectCurrentProject()
-		case " ":
+		case keymap.Toggle:
 			m.togglePMProjectExpansion()
 		}
 		return m, nil
 	}
 
 	maxScroll := m.pmMaxEventScroll()
-	switch msg.String() {
-	case "up", "k", "j":
-		if msg.String() == "j" {
-			m.pmEventScrollOffset++
-		} else {
-			m.pmEventScrollOffset--
-		}
-	case "down":
+	switch action {
+	case keymap.Up:
+		m.pmEventScrollOffset--
+	case keymap.Down:
 		m.pmEventScrollOffset++
-	case "pgup":
+	case keymap.PageUp:
 		m.pmEventScrollOffset -= pmEventPageScrollAmt
-	case "pgdown":
+	case keymap.PageDown:
 		m.pmEventScrollOffset += pmEventPageScrollAmt
-	case "g":
+	case keymap.Top:
 		m.pmEventScrollOffset = 0
-	case "G":
+	case keymap.Bottom:
 		m.pmEventScrollOffset = maxScroll
 	}
 
@@ -186,11 +187,12 @@ func (m *Model) togglePMProjectExpansion() {
 	if m.pmExpandedProjects == nil {
 		m.pmExpandedProjects = make(map[string]bool)
 	}
-	projectDir := snapshots[m.pmProjectCursor].ProjectDir
-	if projectDir == "" {
+	snapshot := snapshots[m.pmProjectCursor]
+	if snapshot.ProjectDir == "" {
 		return
 	}
-	m.pmExpandedProjects[projectDir] = !m.pmExpandedProjects[projectDir]
+	key := snapshot.Key()
+	m.pmExpandedProjects[key] = !m.pmExpandedProjects[key]
 }
 
 func (m *Model) pmSelectCurrentProject() {
@@ -198,13 +200,14 @@ func (m *Model) pmSelectCurrentProject() {
 	if len(snapshots) == 0 || m.pmProjectCursor >= len(snapshots) {
 		return
 	}
-	projectDir := snapshots[m.pmProjectCursor].ProjectDir
-	if projectDir == "" {
+	snapshot := snapshots[m.pmProjectCursor]
+	if snapshot.ProjectDir == "" {
 		return
 	}
 
 	m.pmViewVisible = false
-	m.pmProjectFilterDir = projectDir
+	m.pmProjectFilterDir = snapshot.ProjectDir
+	m.pmProjectFilterRemote = snapshot.Remote
 	m.cursor = 0
 	m.sessionScrollOffset = 0
 }
@@ -508,7 +511,7 @@ func (m Model) renderPMProjects(width, height int) string {
 		lines = append(lines, row)
 		slotsRemaining--
 
-		if isSelected && slotsRemaining > 0 && m.pmExpandedProjects[projectRows[i].ProjectDir] {
+		if isSelected && slotsRemaining > 0 && m.pmExpandedProjects[projectRows[i].Key()] {
 			details := m.renderPMProjectExpansion(projectRows[i], width-4)
 			for _, detail := range details {
 				if slotsRemaining == 0 {
@@ -588,7 +591,7 @@ func (m Model) renderPMProjectExpansion(snapshot pm.ProjectSnapshot, width int)
 		lines = append(lines, dimStyle.Render("  pr: #"+strconv.Itoa(snapshot.PRNumber)))
 	}
 
-	if result, ok := m.pmTaskResults[snapshot.ProjectDir]; ok && result != nil {
+	if result, ok := m.pmTaskResults[snapshot.Key()]; ok && result != nil {
 		tasks := result.AllTasks()
 		if len(tasks) > 0 {
 			lines = append(lines, dimStyle.Render("  tasks:"))
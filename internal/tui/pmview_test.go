@@ -279,6 +279,35 @@ func TestPMProjectsSortAndSelectionJump(t *testing.T) {
 	}
 }
 
+func TestPMSelectionJumpToRemoteProject(t *testing.T) {
+	now := time.Now().UTC()
+	m := Model{
+		width:              120,
+		height:             40,
+		pmViewVisible:      true,
+		pmZoneFocus:        pmZoneProjects,
+		pmExpandedProjects: make(map[string]bool),
+		sessions: []session.Info{
+			{TmuxSession: "local", CWD: "/srv/app", Timestamp: now.Unix()},
+			{TmuxSession: "remote", CWD: "/srv/app/sub", Remote: "devbox", Timestamp: now.Unix()},
+		},
+		pmOutput: &pm.PMOutput{Snapshots: []pm.ProjectSnapshot{
+			{ProjectName: "remote:app", ProjectDir: "/srv/app", Remote: "devbox", SessionStatus: "working", LastActivity: now},
+		}},
+	}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated := updatedModel.(Model)
+	if updated.pmProjectFilterRemote != "devbox" {
+		t.Fatalf("expected remote project filter, got %q", updated.pmProjectFilterRemote)
+	}
+
+	filtered := updated.getFilteredSessions()
+	if len(filtered) != 1 || filtered[0].TmuxSession != "remote" {
+		t.Fatalf("expected only the remote session, got %+v", filtered)
+	}
+}
+
 func TestPMEventsOrderAndScroll(t *testing.T) {
 	now := time.Now().UTC()
 	events := []pm.Event{
@@ -0,0 +1,251 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	ansi "github.com/charmbracelet/x/ansi"
+
+	"github.com/stwalsh4118/navi/internal/pathutil"
+	"github.com/stwalsh4118/navi/internal/remote"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// popupChromeLines is the number of popup lines that are not session rows:
+// title, input, blank line and key hints.
+const popupChromeLines = 4
+
+// popupSessionsMsg carries the local sessions read for the popup.
+type popupSessionsMsg []session.Info
+
+// PopupModel is the compact session switcher shown by `navi popup`. It lists
+// sessions sorted by attention, filters them as the user types, and quits
+// once one is chosen; the caller then switches to Chosen.
+type PopupModel struct {
+	pool    *remote.SSHPool
+	remotes []remote.Config
+
+	localSessions  []session.Info
+	remoteSessions []session.Info
+	sessions       []session.Info // local and remote, sorted by attention
+	remoteLoading  bool
+	input          textinput.Model
+	matches        []paletteMatch // Entries index into sessions
+	cursor         int
+	width, height  int
+	chosen         *session.Info
+}
+
+// NewPopupModel returns the popup switcher. Remote sessions are polled
+// through pool when remotes are configured.
+func NewPopupModel(pool *remote.SSHPool, remotes []remote.Config) PopupModel {
+	applyTheme(loadTheme(), detectColorMode())
+
+	ti := textinput.New()
+	ti.Placeholder = "Filter sessions..."
+	ti.CharLimit = inputSearchCharLimit
+	ti.PromptStyle = highlightStyle
+	ti.TextStyle = lipgloss.NewStyle()
+	ti.Focus()
+
+	return PopupModel{
+		pool:          pool,
+		remotes:       remotes,
+		remoteLoading: pool != nil && len(remotes) > 0,
+		input:         ti,
+	}
+}
+
+// Chosen returns the session picked with Enter, if any.
+func (m PopupModel) Chosen() (session.Info, bool) {
+	if m.chosen == nil {
+		return session.Info{}, false
+	}
+	return *m.chosen, true
+}
+
+// Init starts reading local sessions and polling remotes.
+func (m PopupModel) Init() tea.Cmd {
+	cmds := []tea.Cmd{loadPopupSessions, textinput.Blink}
+	if m.remoteLoading {
+		pool, remotes := m.pool, m.remotes
+		cmds = append(cmds, func() tea.Msg {
+			return remoteSessionsMsg{sessions: remote.PollSessions(pool, remotes)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// loadPopupSessions reads the status files of live tmux sessions. Unlike
+// pollSessions it leaves stale status files alone and skips token parsing,
+// so the popup opens quickly.
+func loadPopupSessions() tea.Msg {
+	live, _ := listTmuxSessions()
+	liveSet := make(map[string]bool, len(live))
+	for _, name := range live {
+		liveSet[name] = true
+	}
+
+	all, err := session.ReadStatusFiles(pathutil.ExpandPath(session.StatusDir))
+	if err != nil {
+		return popupSessionsMsg(nil)
+	}
+	var sessions []session.Info
+	for _, s := range all {
+		if liveSet[s.TmuxSession] {
+			sessions = append(sessions, s)
+		}
+	}
+	return popupSessionsMsg(sessions)
+}
+
+// Update handles key presses and session loading.
+func (m PopupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.input.Width = max(m.width-4, 10)
+		return m, nil
+
+	case popupSessionsMsg:
+		m.localSessions = msg
+		m.setSessions()
+		return m, nil
+
+	case remoteSessionsMsg:
+		m.remoteSessions = msg.sessions
+		m.remoteLoading = false
+		m.setSessions()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			return m, tea.Quit
+
+		case "up", "ctrl+k", "ctrl+p":
+			m.cursor = max(m.cursor-1, 0)
+			return m, nil
+
+		case "down", "ctrl+j", "ctrl+n":
+			m.cursor = max(min(m.cursor+1, len(m.matches)-1), 0)
+			return m, nil
+
+		case "enter":
+			if m.cursor < len(m.matches) {
+				s := m.sessions[m.matches[m.cursor].entry]
+				m.chosen = &s
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		m.filter()
+		m.cursor = 0
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// setSessions merges local and remote sessions in attention order and
+// refilters, keeping the cursor on the same session when it is still listed.
+func (m *PopupModel) setSessions() {
+	current := ""
+	if m.cursor < len(m.matches) {
+		current = m.sessions[m.matches[m.cursor].entry].ID()
+	}
+
+	m.sessions = append(append([]session.Info(nil), m.localSessions...), m.remoteSessions...)
+	session.SortSessions(m.sessions)
+	m.filter()
+
+	m.cursor = 0
+	for i, match := range m.matches {
+		if m.sessions[match.entry].ID() == current {
+			m.cursor = i
+			break
+		}
+	}
+}
+
+// filter recomputes the sessions matching the query. The label is matched
+// first, then the directory and message; ties keep attention order.
+func (m *PopupModel) filter() {
+	query := strings.TrimSpace(m.input.Value())
+	m.matches = nil
+	for i, s := range m.sessions {
+		if query == "" {
+			m.matches = append(m.matches, paletteMatch{entry: i})
+			continue
+		}
+		if score, positions, ok := fuzzyMatch(query, s.Label()); ok {
+			m.matches = append(m.matches, paletteMatch{entry: i, score: score, positions: positions})
+			continue
+		}
+		if score, _, ok := fuzzyMatch(query, s.CWD+" "+s.Message); ok {
+			m.matches = append(m.matches, paletteMatch{entry: i, score: score - fuzzyDetailPenalty})
+		}
+	}
+	sort.SliceStable(m.matches, func(i, j int) bool {
+		return m.matches[i].score > m.matches[j].score
+	})
+}
+
+// View renders the title, filter input and as many session rows as fit.
+func (m PopupModel) View() string {
+	width := max(m.width, 20)
+	var b strings.Builder
+
+	title := boldStyle.Render("navi") + " " + dimStyle.Render(fmt.Sprintf("%d/%d sessions", len(m.matches), len(m.sessions)))
+	if m.remoteLoading {
+		title += " " + dimStyle.Render("· loading remotes...")
+	}
+	b.WriteString(title)
+	b.WriteString("\n")
+	b.WriteString(m.input.View())
+	b.WriteString("\n\n")
+
+	visible := len(m.matches)
+	if m.height > 0 {
+		visible = min(visible, max(m.height-popupChromeLines, 1))
+	}
+	start := max(m.cursor-visible+1, 0)
+	if len(m.matches) == 0 {
+		b.WriteString(dimStyle.Render("No sessions"))
+		b.WriteString("\n")
+	}
+	for i := start; i < start+visible && i < len(m.matches); i++ {
+		b.WriteString(m.renderRow(m.matches[i], width, i == m.cursor))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(dimStyle.Render("↑↓: navigate  Enter: switch  Esc: close"))
+	return b.String()
+}
+
+// renderRow renders one session: status icon, label with the matched runes
+// highlighted, dimmed directory, and the age at the right edge.
+func (m PopupModel) renderRow(match paletteMatch, width int, selected bool) string {
+	s := m.sessions[match.entry]
+	status, _ := session.CompositeStatus(s)
+	age := formatAge(s.Timestamp)
+
+	marker, label := unselectedMarker, highlightRunes(s.Label(), match.positions)
+	if selected {
+		marker, label = selectedMarker, selectedStyle.Render(s.Label())
+	}
+	cwd := dimStyle.Render(pathutil.ShortenPath(s.CWD))
+
+	line := ansi.Truncate(marker+StatusIcon(status)+" "+label+"  "+cwd, width-len(age)-1, "...")
+	pad := max(width-lipgloss.Width(line)-len(age), 1)
+	return line + strings.Repeat(" ", pad) + dimStyle.Render(age)
+}
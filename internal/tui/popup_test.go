@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// newPopupTestModel returns a popup with two local sessions loaded.
+func newPopupTestModel() PopupModel {
+	m := NewPopupModel(nil, nil)
+	now := time.Now().Unix()
+	updated, _ := m.Update(popupSessionsMsg{
+		{TmuxSession: "api", Status: session.StatusWorking, CWD: "/src/api", Timestamp: now},
+		{TmuxSession: "docs", Status: session.StatusWaiting, CWD: "/src/docs", Timestamp: now - 60},
+	})
+	return updated.(PopupModel)
+}
+
+// popupLabels returns the labels of the matching sessions in order.
+func popupLabels(m PopupModel) []string {
+	labels := make([]string, len(m.matches))
+	for i, match := range m.matches {
+		labels[i] = m.sessions[match.entry].Label()
+	}
+	return labels
+}
+
+func popupKeys(m PopupModel, keys ...tea.KeyMsg) PopupModel {
+	for _, k := range keys {
+		updated, _ := m.Update(k)
+		m = updated.(PopupModel)
+	}
+	return m
+}
+
+func TestPopupSortsByAttention(t *testing.T) {
+	m := newPopupTestModel()
+	if got := popupLabels(m); strings.Join(got, ",") != "docs,api" {
+		t.Errorf("order = %v, want the waiting session first", got)
+	}
+	if !strings.Contains(m.View(), "2/2 sessions") {
+		t.Error("expected the session count in the title")
+	}
+}
+
+func TestPopupFilter(t *testing.T) {
+	m := newPopupTestModel()
+	m = popupKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ap")})
+	if got := popupLabels(m); len(got) != 1 || got[0] != "api" {
+		t.Errorf("matches = %v, want [api]", got)
+	}
+
+	// Directories match too
+	m = popupKeys(m, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("src/doc")})
+	if got := popupLabels(m); len(got) != 1 || got[0] != "docs" {
+		t.Errorf("matches = %v, want [docs]", got)
+	}
+}
+
+func TestPopupChoose(t *testing.T) {
+	m := popupKeys(newPopupTestModel(), tea.KeyMsg{Type: tea.KeyDown})
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(PopupModel)
+	s, ok := m.Chosen()
+	if !ok || s.TmuxSession != "api" {
+		t.Errorf("chosen = %q, %v; want api", s.TmuxSession, ok)
+	}
+	if cmd == nil {
+		t.Error("expected the popup to quit")
+	}
+
+	m = popupKeys(newPopupTestModel(), tea.KeyMsg{Type: tea.KeyEscape})
+	if _, ok := m.Chosen(); ok {
+		t.Error("esc should close without choosing")
+	}
+}
+
+func TestPopupRemoteSessionsKeepCursor(t *testing.T) {
+	m := popupKeys(newPopupTestModel(), tea.KeyMsg{Type: tea.KeyDown})
+	m.remoteLoading = true
+
+	updated, _ := m.Update(remoteSessionsMsg{sessions: []session.Info{
+		{TmuxSession: "web", Remote: "devbox", Status: session.StatusPermission, Timestamp: time.Now().Unix()},
+	}})
+	m = updated.(PopupModel)
+	if got := popupLabels(m); len(got) != 3 || got[m.cursor] != "api" {
+		t.Errorf("matches = %v with cursor %d, want the cursor kept on api", got, m.cursor)
+	}
+	if m.remoteLoading {
+		t.Error("remote loading should clear")
+	}
+}
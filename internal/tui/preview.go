@@ -1,8 +1,13 @@
 package tui
 
 import (
-	"regexp"
+	"strings"
 	"time"
+
+	ansi "github.com/charmbracelet/x/ansi"
+	"github.com/muesli/reflow/wordwrap"
+
+	"github.com/stwalsh4118/navi/internal/sgr"
 )
 
 // PreviewLayout represents the layout mode for the preview pane.
@@ -66,29 +71,32 @@ const (
 	previewDebounceDelay = 100 * time.Millisecond
 )
 
-// ANSI escape sequence patterns
-var (
-	// ansiEscapeRegex matches standard ANSI escape sequences (colors, formatting, cursor)
-	ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
-
-	// oscSequenceRegex matches OSC (Operating System Command) sequences like title setting
-	oscSequenceRegex = regexp.MustCompile(`\x1b\][^\x07]*\x07`)
-
-	// controlCharRegex matches other control characters (except newline, tab)
-	controlCharRegex = regexp.MustCompile(`[\x00-\x08\x0b\x0c\x0e-\x1a\x1c-\x1f]`)
-)
-
-// StripANSI removes ANSI escape sequences and other control characters from input.
-// This ensures captured tmux output displays cleanly in the preview pane.
+// StripANSI removes ANSI escape sequences and other control characters from input,
+// leaving the visible text of a capture.
 func StripANSI(input string) string {
-	// Remove standard ANSI escape sequences (colors, cursor movement, etc.)
-	result := ansiEscapeRegex.ReplaceAllString(input, "")
-
-	// Remove OSC sequences (title setting, hyperlinks, etc.)
-	result = oscSequenceRegex.ReplaceAllString(result, "")
-
-	// Remove other control characters (except newline \n and tab \t)
-	result = controlCharRegex.ReplaceAllString(result, "")
+	return sgr.Strip(input)
+}
 
-	return result
+// previewLines splits captured content into display lines of at most width
+// cells, wrapped or truncated. Each line carries its own SGR state, so
+// colors survive scrolling and never bleed into borders. Widths are measured
+// on the visible text. starts holds, for each content line, the index of its
+// first display line.
+func previewLines(content string, width int, wrap bool) (lines []string, starts []int) {
+	raw := sgr.Lines(content)
+	starts = make([]int, len(raw))
+	for i, line := range raw {
+		starts[i] = len(lines)
+		switch {
+		case wrap:
+			lines = append(lines, sgr.Lines(wordwrap.String(line, width))...)
+		case ansi.StringWidth(line) <= width:
+			lines = append(lines, line)
+		case strings.Contains(line, "\x1b"):
+			lines = append(lines, ansi.Truncate(line, width, "..."))
+		default:
+			lines = append(lines, truncate(line, width))
+		}
+	}
+	return lines, starts
 }
@@ -1,6 +1,11 @@
 package tui
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	ansi "github.com/charmbracelet/x/ansi"
+)
 
 func TestStripANSI(t *testing.T) {
 	t.Run("removes color codes", func(t *testing.T) {
@@ -81,3 +86,71 @@ func TestStripANSI(t *testing.T) {
 		}
 	})
 }
+
+func TestPreviewLines(t *testing.T) {
+	t.Run("carries color across lines", func(t *testing.T) {
+		lines, _ := previewLines("\x1b[31mred\nstill red\x1b[0m\nplain", 40, false)
+		if len(lines) != 3 {
+			t.Fatalf("expected 3 lines, got %d: %q", len(lines), lines)
+		}
+		if !strings.HasPrefix(lines[1], "\x1b[31m") {
+			t.Errorf("second line should reopen the color, got %q", lines[1])
+		}
+		for i, line := range lines[:2] {
+			if !strings.HasSuffix(line, "\x1b[0m") {
+				t.Errorf("line %d should end with a reset, got %q", i, line)
+			}
+		}
+		if lines[2] != "plain" {
+			t.Errorf("line after reset should be plain, got %q", lines[2])
+		}
+	})
+
+	t.Run("truncates on visible width", func(t *testing.T) {
+		lines, _ := previewLines("\x1b[32m"+strings.Repeat("x", 30)+"\x1b[0m", 10, false)
+		if got := ansi.StringWidth(lines[0]); got != 10 {
+			t.Errorf("expected width 10, got %d (%q)", got, lines[0])
+		}
+		if !strings.HasSuffix(lines[0], "\x1b[0m") {
+			t.Errorf("truncated line should keep its reset, got %q", lines[0])
+		}
+	})
+
+	t.Run("truncates plain lines", func(t *testing.T) {
+		lines, _ := previewLines(strings.Repeat("y", 30), 10, false)
+		if lines[0] != "yyyyyyy..." {
+			t.Errorf("unexpected truncation %q", lines[0])
+		}
+	})
+
+	t.Run("wraps on visible width", func(t *testing.T) {
+		lines, _ := previewLines("\x1b[34maaaa bbbb cccc\x1b[0m", 9, true)
+		if len(lines) < 2 {
+			t.Fatalf("expected wrapped lines, got %q", lines)
+		}
+		for i, line := range lines {
+			if w := ansi.StringWidth(line); w > 9 {
+				t.Errorf("line %d too wide (%d): %q", i, w, line)
+			}
+			if !strings.HasPrefix(line, "\x1b[34m") {
+				t.Errorf("line %d should carry the color, got %q", i, line)
+			}
+		}
+	})
+}
+
+func TestPreviewLinesStarts(t *testing.T) {
+	lines, starts := previewLines("aaaa bbbb cccc\nshort\n\nlast", 9, true)
+	want := []int{0, 2, 3, 4}
+	if len(starts) != len(want) {
+		t.Fatalf("starts = %v, want %v (lines %q)", starts, want, lines)
+	}
+	for i := range want {
+		if starts[i] != want[i] {
+			t.Fatalf("starts = %v, want %v (lines %q)", starts, want, lines)
+		}
+	}
+	if lines[starts[3]] != "last" {
+		t.Errorf("line at start 3 = %q, want %q", lines[starts[3]], "last")
+	}
+}
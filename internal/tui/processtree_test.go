@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/keymap"
+	"github.com/stwalsh4118/navi/internal/metrics"
+	"github.com/stwalsh4118/navi/internal/resource"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+func newProcessTreeTestModel() Model {
+	return Model{
+		width:  120,
+		height: 40,
+		sessions: []session.Info{
+			{TmuxSession: "api", Status: session.StatusWorking},
+			{TmuxSession: "remote-api", Status: session.StatusWorking, Remote: "dev"},
+		},
+		resourceCache: map[string]resource.Usage{
+			"api": {
+				RSSBytes:     300 * 1024 * 1024,
+				CPUPercent:   42,
+				ProcessCount: 3,
+				Ports:        []int{3000},
+				Processes: []resource.Process{
+					{PID: 100, Depth: 0, Command: "-bash"},
+					{PID: 200, PPID: 100, Depth: 1, Command: "npm run dev", Ports: []int{3000}, CPUPercent: 40},
+					{PID: 300, PPID: 200, Depth: 2, Command: "node server.js"},
+				},
+			},
+		},
+	}
+}
+
+func pressKey(t *testing.T, m Model, key string) (Model, tea.Cmd) {
+	t.Helper()
+	var msg tea.KeyMsg
+	switch key {
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	updated, cmd := m.Update(msg)
+	return updated.(Model), cmd
+}
+
+func TestProcessTreeOpenAndNavigate(t *testing.T) {
+	m, _ := pressKey(t, newProcessTreeTestModel(), "t")
+	if m.dialogMode != DialogProcessTree {
+		t.Fatalf("dialogMode = %v, want DialogProcessTree", m.dialogMode)
+	}
+
+	m, _ = pressKey(t, m, "j")
+	m, _ = pressKey(t, m, "j")
+	m, _ = pressKey(t, m, "j")
+	if m.processCursor != 2 {
+		t.Errorf("processCursor = %d, want 2 (clamped to last row)", m.processCursor)
+	}
+	m, _ = pressKey(t, m, "k")
+	if m.processCursor != 1 {
+		t.Errorf("processCursor = %d, want 1", m.processCursor)
+	}
+
+	m, _ = pressKey(t, m, "esc")
+	if m.dialogMode != DialogNone {
+		t.Errorf("dialogMode = %v, want DialogNone after esc", m.dialogMode)
+	}
+}
+
+func TestProcessTreeNotOpenedForRemote(t *testing.T) {
+	m := newProcessTreeTestModel()
+	m.cursor = 1
+	m, _ = pressKey(t, m, "t")
+	if m.dialogMode != DialogNone {
+		t.Errorf("dialogMode = %v, want DialogNone for remote session", m.dialogMode)
+	}
+}
+
+func TestProcessTreeKillProtectsPaneShell(t *testing.T) {
+	m, _ := pressKey(t, newProcessTreeTestModel(), "t")
+
+	m, cmd := pressKey(t, m, "x")
+	if cmd != nil {
+		t.Error("did not expect kill command for pane shell")
+	}
+	if m.dialogError == "" {
+		t.Error("expected error message when killing pane shell")
+	}
+
+	m, _ = pressKey(t, m, "j")
+	if _, cmd := pressKey(t, m, "x"); cmd == nil {
+		t.Error("expected kill command for child process")
+	}
+}
+
+func TestProcessTreeRemappedKill(t *testing.T) {
+	m := newProcessTreeTestModel()
+	m.keys = keymap.Default()
+	if err := m.keys.Override(keymap.Config{keymap.ProcessTree: {keymap.Kill: {"K"}}}); err != nil {
+		t.Fatalf("Override error: %v", err)
+	}
+	m, _ = pressKey(t, m, "t")
+	m, _ = pressKey(t, m, "j")
+
+	if _, cmd := pressKey(t, m, "x"); cmd != nil {
+		t.Error("x should not kill after remapping kill")
+	}
+	if _, cmd := pressKey(t, m, "K"); cmd == nil {
+		t.Error("expected kill command from the remapped key")
+	}
+
+	m, _ = pressKey(t, m, "?")
+	if m.dialogMode != DialogContentViewer || m.contentViewerTitle != "Keys: Process tree" {
+		t.Errorf("dialogMode = %v, title = %q", m.dialogMode, m.contentViewerTitle)
+	}
+}
+
+func TestProcessKillResultShowsError(t *testing.T) {
+	m, _ := pressKey(t, newProcessTreeTestModel(), "t")
+
+	updated, _ := m.Update(processKillResultMsg{pid: 200, err: errors.New("permission denied")})
+	m = updated.(Model)
+	if !strings.Contains(m.dialogError, "permission denied") {
+		t.Errorf("dialogError = %q, want kill failure", m.dialogError)
+	}
+
+	updated, cmd := m.Update(processKillResultMsg{pid: 200})
+	m = updated.(Model)
+	if m.dialogError != "" {
+		t.Errorf("dialogError = %q, want cleared on success", m.dialogError)
+	}
+	if cmd == nil {
+		t.Error("expected immediate resource poll after successful kill")
+	}
+}
+
+func TestProcessTreeCursorClampedAfterPoll(t *testing.T) {
+	m, _ := pressKey(t, newProcessTreeTestModel(), "t")
+	m.processCursor = 2
+
+	updated, _ := m.Update(resourcePollMsg{"api": {ProcessCount: 1, Processes: []resource.Process{{PID: 100}}}})
+	m = updated.(Model)
+	if m.processCursor != 0 {
+		t.Errorf("processCursor = %d, want 0 after tree shrank", m.processCursor)
+	}
+}
+
+func TestRenderProcessTree(t *testing.T) {
+	m, _ := pressKey(t, newProcessTreeTestModel(), "t")
+	view := m.renderProcessTree()
+
+	for _, want := range []string{"Processes", "3 processes", "CPU 42%", ":3000", "npm run dev", "    node server.js", "x: kill"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in process tree view:\n%s", want, view)
+		}
+	}
+}
+
+func TestMetricsBadgesCPUAndPorts(t *testing.T) {
+	m := &metrics.Metrics{Resource: &metrics.ResourceMetrics{
+		RSSBytes:     64 * 1024 * 1024,
+		CPUPercent:   12.4,
+		ProcessCount: 2,
+		Ports:        []int{3000, 5173, 8080, 9229},
+	}}
+
+	result := renderMetricsBadges(m)
+	if !strings.Contains(result, "⚡ 12%") {
+		t.Errorf("expected CPU badge, got %q", result)
+	}
+	if !strings.Contains(result, "🔌 :3000 :5173 :8080 +1") {
+		t.Errorf("expected ports badge, got %q", result)
+	}
+
+	remote := renderMetricsBadges(&metrics.Metrics{Resource: &metrics.ResourceMetrics{RSSBytes: 1024 * 1024}})
+	if strings.Contains(remote, "⚡") {
+		t.Errorf("did not expect CPU badge without process data, got %q", remote)
+	}
+}
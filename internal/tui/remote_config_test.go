@@ -57,22 +57,30 @@ func TestValidateRemoteConfig(t *testing.T) {
 			wantErr: remote.ErrRemoteHostRequired,
 		},
 		{
-			name: "missing user",
+			name: "missing user resolves from ssh config",
 			config: remote.Config{
 				Name: "dev-server",
 				Host: "dev.example.com",
 				Key:  "~/.ssh/id_rsa",
 			},
-			wantErr: remote.ErrRemoteUserRequired,
+			wantErr: nil,
 		},
 		{
-			name: "missing key",
+			name: "missing key resolves from ssh config or agent",
 			config: remote.Config{
 				Name: "dev-server",
 				Host: "dev.example.com",
 				User: "sean",
 			},
-			wantErr: remote.ErrRemoteKeyRequired,
+			wantErr: nil,
+		},
+		{
+			name: "host alias only",
+			config: remote.Config{
+				Name: "devbox",
+				Host: "devbox",
+			},
+			wantErr: nil,
 		},
 	}
 
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stwalsh4118/navi/internal/metrics"
+	"github.com/stwalsh4118/navi/internal/resource"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// fakeRemoteExecutor returns canned metrics output per remote.
+type fakeRemoteExecutor struct {
+	mu      sync.Mutex
+	outputs map[string]string
+	errs    map[string]error
+	calls   map[string]int
+}
+
+func (f *fakeRemoteExecutor) Execute(remoteName, _ string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls == nil {
+		f.calls = make(map[string]int)
+	}
+	f.calls[remoteName]++
+	return []byte(f.outputs[remoteName]), f.errs[remoteName]
+}
+
+func TestPollRemoteMetricsCmd(t *testing.T) {
+	executor := &fakeRemoteExecutor{
+		outputs: map[string]string{
+			"dev": "SESSION:api\nPROCS:3\nRSS:1048576\nTOKENS:900 100\nSESSION:web\nPROCS:1\nRSS:2048\n",
+		},
+		errs: map[string]error{"prod": errors.New("connection refused")},
+	}
+	sessions := []session.Info{
+		{TmuxSession: "local", Status: session.StatusWorking},
+		{TmuxSession: "api", Remote: "dev", CWD: "/srv/api"},
+		{TmuxSession: "web", Remote: "dev", CWD: "/srv/web"},
+		{TmuxSession: "api", Remote: "prod", CWD: "/srv/api"},
+	}
+
+	cmd := pollRemoteMetricsCmd(executor, sessions)
+	if cmd == nil {
+		t.Fatal("expected a command for remote sessions")
+	}
+	msg, ok := cmd().(remoteMetricsMsg)
+	if !ok {
+		t.Fatalf("expected remoteMetricsMsg, got %T", cmd())
+	}
+
+	if executor.calls["dev"] != 1 || executor.calls["prod"] != 1 {
+		t.Errorf("calls = %v, want one batched command per remote", executor.calls)
+	}
+	if _, ok := msg["prod"]; ok {
+		t.Error("failed remote should be absent from the result")
+	}
+	if got := msg["dev"]["api"]; got == nil || got.Resource.RSSBytes != 1048576 || got.Tokens.Total != 1000 {
+		t.Errorf("dev/api metrics = %+v", got)
+	}
+}
+
+func TestPollRemoteMetricsCmdNoRemoteSessions(t *testing.T) {
+	cmd := pollRemoteMetricsCmd(&fakeRemoteExecutor{}, []session.Info{{TmuxSession: "local"}})
+	if cmd != nil {
+		t.Error("expected nil command without remote sessions")
+	}
+}
+
+func TestRemoteMetricsMergeOntoRemoteRows(t *testing.T) {
+	m := Model{
+		width:  160,
+		height: 40,
+		sessions: []session.Info{
+			{TmuxSession: "api", Status: session.StatusWorking},
+			{TmuxSession: "api", Status: session.StatusWorking, Remote: "dev"},
+		},
+		// Local poll data must not leak onto a remote session with the same name
+		resourceCache: map[string]resource.Usage{"api": {RSSBytes: 4096, ProcessCount: 1}},
+	}
+
+	updated, _ := m.Update(remoteMetricsMsg{
+		"dev": {"api": {
+			Resource: &metrics.ResourceMetrics{RSSBytes: 2 * 1024 * 1024 * 1024, ProcessCount: 5},
+			Tokens:   &metrics.TokenMetrics{Input: 1500, Output: 500, Total: 2000},
+		}},
+	})
+	m = updated.(Model)
+
+	local, remoteRow := m.sessions[0], m.sessions[1]
+	if local.Metrics == nil || local.Metrics.Resource.RSSBytes != 4096 {
+		t.Errorf("local resource = %+v, want local cache", local.Metrics)
+	}
+	if remoteRow.Metrics == nil || remoteRow.Metrics.Resource.RSSBytes != 2*1024*1024*1024 {
+		t.Fatalf("remote metrics = %+v, want remote cache", remoteRow.Metrics)
+	}
+	if remoteRow.Metrics.Tokens == nil || remoteRow.Metrics.Tokens.Total != 2000 {
+		t.Errorf("remote tokens = %+v, want 2000 total", remoteRow.Metrics.Tokens)
+	}
+
+	row := m.renderSession(remoteRow, false, 160)
+	if !strings.Contains(row, "🧠 2.0G") {
+		t.Errorf("expected RAM badge on remote row, got:\n%s", row)
+	}
+
+	// A fresh remote poll replaces the sessions; cached metrics are re-applied
+	updated, _ = m.Update(remoteSessionsMsg{sessions: []session.Info{
+		{TmuxSession: "api", Status: session.StatusWaiting, Remote: "dev"},
+	}})
+	m = updated.(Model)
+	for _, s := range m.sessions {
+		if s.Remote == "dev" && (s.Metrics == nil || s.Metrics.Resource == nil || s.Metrics.Tokens == nil) {
+			t.Errorf("remote metrics lost after session refresh: %+v", s.Metrics)
+		}
+	}
+}
+
+func TestRemoteMetricsReplacedPerRemote(t *testing.T) {
+	m := Model{
+		sessions: []session.Info{
+			{TmuxSession: "api", Remote: "dev"},
+			{TmuxSession: "web", Remote: "dev"},
+			{TmuxSession: "db", Remote: "prod"},
+		},
+	}
+	res := func(rss int64) *metrics.Metrics {
+		return &metrics.Metrics{Resource: &metrics.ResourceMetrics{RSSBytes: rss}}
+	}
+
+	updated, _ := m.Update(remoteMetricsMsg{
+		"dev":  {"api": res(1), "web": res(2)},
+		"prod": {"db": res(3)},
+	})
+	m = updated.(Model)
+
+	// dev answers without web (session gone); prod fails and keeps its last value
+	updated, _ = m.Update(remoteMetricsMsg{"dev": {"api": res(10)}})
+	m = updated.(Model)
+
+	if _, ok := m.remoteMetricsCache[remoteMetricsKey("dev", "web")]; ok {
+		t.Error("stale dev/web entry should be dropped")
+	}
+	if got := m.remoteMetricsCache[remoteMetricsKey("dev", "api")]; got.Resource.RSSBytes != 10 {
+		t.Errorf("dev/api RSS = %d, want 10", got.Resource.RSSBytes)
+	}
+	if got := m.remoteMetricsCache[remoteMetricsKey("prod", "db")]; got == nil || got.Resource.RSSBytes != 3 {
+		t.Errorf("prod/db = %+v, want last known value", got)
+	}
+}
@@ -0,0 +1,446 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/stwalsh4118/navi/internal/remote"
+)
+
+// remoteEditorWidth is the width of the add/edit remote dialog.
+const remoteEditorWidth = 72
+
+// Remote editor fields, in tab order.
+const (
+	remoteFieldName = iota
+	remoteFieldTransport
+	remoteFieldHost
+	remoteFieldUser
+	remoteFieldPort
+	remoteFieldKey
+	remoteFieldJumpHost
+	remoteFieldCommand
+	remoteFieldAttachCommand
+	remoteFieldSessionsDir
+	remoteFieldCount
+)
+
+// remoteFieldLabels are the editor labels, padded to a common width.
+var remoteFieldLabels = [remoteFieldCount]string{
+	remoteFieldName:          "Name:         ",
+	remoteFieldTransport:     "Transport:    ",
+	remoteFieldHost:          "Host:         ",
+	remoteFieldUser:          "User:         ",
+	remoteFieldPort:          "Port:         ",
+	remoteFieldKey:           "Key:          ",
+	remoteFieldJumpHost:      "Jump host:    ",
+	remoteFieldCommand:       "Command:      ",
+	remoteFieldAttachCommand: "Attach cmd:   ",
+	remoteFieldSessionsDir:   "Sessions dir: ",
+}
+
+// remoteFieldPlaceholders hint at each field's format.
+var remoteFieldPlaceholders = [remoteFieldCount]string{
+	remoteFieldName:          "devbox",
+	remoteFieldHost:          "host or ~/.ssh/config alias",
+	remoteFieldUser:          "from ssh config",
+	remoteFieldPort:          "22",
+	remoteFieldKey:           "~/.ssh/id_ed25519",
+	remoteFieldJumpHost:      "[user@]bastion[:port]",
+	remoteFieldCommand:       "docker exec -i dev sh -c",
+	remoteFieldAttachCommand: "docker exec -it dev sh -c",
+	remoteFieldSessionsDir:   remote.DefaultSessionsDir,
+}
+
+var errRemotePortInvalid = errors.New("port must be a number between 1 and 65535")
+
+// remotesConfigMsg carries a fresh load of remotes.yaml.
+type remotesConfigMsg struct {
+	remotes []remote.Config
+	invalid []remote.ConfigError
+	err     error
+}
+
+// remoteConfigSavedMsg is returned after writing remotes.yaml from the TUI.
+type remoteConfigSavedMsg struct {
+	err error
+}
+
+// remoteCheckResultMsg is returned after testing a remote from the editor.
+type remoteCheckResultMsg struct {
+	remote string
+	err    error
+}
+
+// remoteForm holds the add/edit remote dialog state.
+type remoteForm struct {
+	oldName   string // entry being edited; empty when adding
+	transport string
+	inputs    []textinput.Model // by field; remoteFieldTransport is unused
+	focus     int               // focused field
+	status    string            // result of the last successful test
+}
+
+// newRemoteForm returns an editor prefilled from rc.
+func newRemoteForm(rc remote.Config, oldName string) remoteForm {
+	f := remoteForm{
+		oldName:   oldName,
+		transport: rc.Transport,
+		inputs:    make([]textinput.Model, remoteFieldCount),
+	}
+	if f.transport == "" {
+		f.transport = remote.TransportSSH
+	}
+
+	port := ""
+	if rc.Port != 0 {
+		port = strconv.Itoa(rc.Port)
+	}
+	values := [remoteFieldCount]string{
+		remoteFieldName:          rc.Name,
+		remoteFieldHost:          rc.Host,
+		remoteFieldUser:          rc.User,
+		remoteFieldPort:          port,
+		remoteFieldKey:           rc.Key,
+		remoteFieldJumpHost:      rc.JumpHost,
+		remoteFieldCommand:       rc.Command,
+		remoteFieldAttachCommand: rc.AttachCommand,
+		remoteFieldSessionsDir:   rc.SessionsDir,
+	}
+	for i := range f.inputs {
+		ti := textinput.New()
+		ti.Placeholder = remoteFieldPlaceholders[i]
+		ti.CharLimit = inputDirCharLimit
+		ti.Width = inputWidth
+		ti.Prompt = ""
+		ti.TextStyle = lipgloss.NewStyle()
+		ti.SetValue(values[i])
+		f.inputs[i] = ti
+	}
+	f.setFocus(remoteFieldName)
+	return f
+}
+
+// fields returns the fields shown for the form's transport, in tab order.
+func (f remoteForm) fields() []int {
+	if f.transport == remote.TransportCommand {
+		return []int{remoteFieldName, remoteFieldTransport, remoteFieldCommand, remoteFieldAttachCommand, remoteFieldSessionsDir}
+	}
+	return []int{remoteFieldName, remoteFieldTransport, remoteFieldHost, remoteFieldUser, remoteFieldPort, remoteFieldKey, remoteFieldJumpHost, remoteFieldSessionsDir}
+}
+
+// setFocus focuses field, blurring the others.
+func (f *remoteForm) setFocus(field int) {
+	f.focus = field
+	for i := range f.inputs {
+		if i == field {
+			f.inputs[i].Focus()
+		} else {
+			f.inputs[i].Blur()
+		}
+	}
+}
+
+// moveFocus moves focus by delta through the visible fields, wrapping around.
+func (f *remoteForm) moveFocus(delta int) {
+	fields := f.fields()
+	pos := 0
+	for i, field := range fields {
+		if field == f.focus {
+			pos = i
+		}
+	}
+	pos = (pos + delta + len(fields)) % len(fields)
+	f.setFocus(fields[pos])
+}
+
+// toggleTransport switches between the SSH and command transports.
+func (f *remoteForm) toggleTransport() {
+	if f.transport == remote.TransportCommand {
+		f.transport = remote.TransportSSH
+	} else {
+		f.transport = remote.TransportCommand
+	}
+	f.status = ""
+}
+
+// config builds the remote from the form. Fields hidden for the transport are
+// left out so they are removed from remotes.yaml.
+func (f remoteForm) config() (remote.Config, error) {
+	value := func(field int) string {
+		return strings.TrimSpace(f.inputs[field].Value())
+	}
+
+	rc := remote.Config{
+		Name:        value(remoteFieldName),
+		SessionsDir: value(remoteFieldSessionsDir),
+	}
+	if rc.SessionsDir == remote.DefaultSessionsDir {
+		rc.SessionsDir = ""
+	}
+
+	if f.transport == remote.TransportCommand {
+		rc.Transport = remote.TransportCommand
+		rc.Command = value(remoteFieldCommand)
+		rc.AttachCommand = value(remoteFieldAttachCommand)
+	} else {
+		rc.Host = value(remoteFieldHost)
+		rc.User = value(remoteFieldUser)
+		rc.Key = value(remoteFieldKey)
+		rc.JumpHost = value(remoteFieldJumpHost)
+		if port := value(remoteFieldPort); port != "" {
+			n, err := strconv.Atoi(port)
+			if err != nil || n < 1 || n > 65535 {
+				return rc, errRemotePortInvalid
+			}
+			rc.Port = n
+		}
+	}
+
+	if err := remote.ValidateConfig(&rc); err != nil {
+		return rc, err
+	}
+	return rc, nil
+}
+
+// checkRemotesConfigCmd reloads remotes.yaml when it changed since the last check.
+func checkRemotesConfigCmd(w *remote.ConfigWatcher) tea.Cmd {
+	return func() tea.Msg {
+		if !w.Changed() {
+			return nil
+		}
+		return loadRemotesConfig(w.Path())
+	}
+}
+
+// reloadRemotesConfigCmd reloads remotes.yaml at once, marking the change as
+// seen so the watcher doesn't load it a second time.
+func reloadRemotesConfigCmd(path string, w *remote.ConfigWatcher) tea.Cmd {
+	return func() tea.Msg {
+		if w != nil {
+			w.Changed()
+		}
+		return loadRemotesConfig(path)
+	}
+}
+
+// loadRemotesConfig reads remotes.yaml into a remotesConfigMsg.
+func loadRemotesConfig(path string) tea.Msg {
+	remotes, invalid, err := remote.LoadConfigFile(path)
+	return remotesConfigMsg{remotes: remotes, invalid: invalid, err: err}
+}
+
+// saveRemoteCmd writes a remote to remotes.yaml.
+func saveRemoteCmd(path, oldName string, rc remote.Config) tea.Cmd {
+	return func() tea.Msg {
+		return remoteConfigSavedMsg{err: remote.SaveRemote(path, oldName, rc)}
+	}
+}
+
+// deleteRemoteCmd removes a remote from remotes.yaml.
+func deleteRemoteCmd(path, name string) tea.Cmd {
+	return func() tea.Msg {
+		return remoteConfigSavedMsg{err: remote.DeleteRemote(path, name)}
+	}
+}
+
+// checkRemoteCmd runs a test command on an unsaved remote.
+func checkRemoteCmd(rc remote.Config) tea.Cmd {
+	return func() tea.Msg {
+		return remoteCheckResultMsg{remote: rc.Name, err: remote.CheckRemote(rc)}
+	}
+}
+
+// applyRemotesConfig reconciles the pool and remote list with a reload of
+// remotes.yaml. A file that can't be read or parsed keeps the current remotes.
+func (m *Model) applyRemotesConfig(msg remotesConfigMsg) tea.Cmd {
+	if msg.err != nil {
+		m.remotesLoadErr = msg.err
+		return nil
+	}
+	m.remotesLoadErr = nil
+	m.remotesInvalid = msg.invalid
+
+	if m.SSHPool == nil {
+		if len(msg.remotes) > 0 {
+			m.SSHPool = remote.NewSSHPool(msg.remotes)
+		}
+	} else {
+		_, removed, _ := m.SSHPool.Reconcile(msg.remotes)
+		for _, name := range removed {
+			m.removeRemoteSessions(name)
+		}
+	}
+	m.Remotes = msg.remotes
+
+	if m.newSessionHost != "" && remote.GetByName(m.Remotes, m.newSessionHost) == nil {
+		m.newSessionHost = ""
+	}
+	if rows := m.remotesRowCount(); m.remotesCursor >= rows {
+		m.remotesCursor = rows - 1
+		if m.remotesCursor < 0 {
+			m.remotesCursor = 0
+		}
+	}
+
+	if m.SSHPool != nil && len(m.Remotes) > 0 {
+		return m.pollRemoteSessionsCmd()
+	}
+	return nil
+}
+
+// openRemoteEditor opens the add/edit dialog for the named remotes.yaml entry,
+// or an empty form when name is empty.
+func (m Model) openRemoteEditor(name string) (tea.Model, tea.Cmd) {
+	if m.remotesConfigPath == "" {
+		m.dialogError = "No remotes config file"
+		return m, nil
+	}
+
+	rc := remote.Config{}
+	if name != "" {
+		raw, err := remote.ReadRawConfig(m.remotesConfigPath)
+		if err != nil {
+			m.dialogError = fmt.Sprintf("Failed to read remotes config: %v", err)
+			return m, nil
+		}
+		found := remote.GetByName(raw, name)
+		if found == nil {
+			m.dialogError = fmt.Sprintf("Remote %s is not in %s", name, m.remotesConfigPath)
+			return m, nil
+		}
+		rc = *found
+	}
+
+	m.remoteForm = newRemoteForm(rc, name)
+	m.dialogMode = DialogRemoteEdit
+	m.dialogError = ""
+	return m, nil
+}
+
+// remoteNameTaken reports whether another remotes.yaml entry already uses name.
+func (m Model) remoteNameTaken(name, oldName string) bool {
+	if name == oldName {
+		return false
+	}
+	if remote.GetByName(m.Remotes, name) != nil {
+		return true
+	}
+	for _, ce := range m.remotesInvalid {
+		if ce.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// renderRemoteEditor renders the add/edit remote dialog.
+func (m Model) renderRemoteEditor() string {
+	var b strings.Builder
+	f := m.remoteForm
+
+	title := "Add Remote"
+	if f.oldName != "" {
+		title = "Edit Remote"
+	}
+	b.WriteString(dialogTitleStyle.Render(title))
+	b.WriteString("\n\n")
+
+	for _, field := range f.fields() {
+		if field == remoteFieldTransport {
+			line := remoteFieldLabels[field] + "◂ " + f.transport + " ▸"
+			if f.focus == field {
+				line = highlightStyle.Render(line)
+			}
+			b.WriteString(line)
+		} else {
+			b.WriteString(remoteFieldLabels[field])
+			b.WriteString(f.inputs[field].View())
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("Tab: next  ←→: transport  Ctrl+T: test  Enter: save  Esc: cancel"))
+
+	if f.status != "" {
+		b.WriteString("\n")
+		b.WriteString(greenStyle.Render(f.status))
+	}
+	if m.dialogError != "" {
+		b.WriteString("\n")
+		b.WriteString(dialogErrorStyle.Render(m.dialogError))
+	}
+
+	dialog := dialogBoxStyle.Width(remoteEditorWidth).Render(b.String())
+	return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, dialog)
+}
+
+// updateRemoteEditor handles key input for the add/edit remote dialog.
+func (m Model) updateRemoteEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.dialogMode = DialogRemotes
+		m.dialogError = ""
+		return m, nil
+
+	case "tab", "down":
+		m.remoteForm.moveFocus(1)
+		return m, nil
+
+	case "shift+tab", "up":
+		m.remoteForm.moveFocus(-1)
+		return m, nil
+
+	case "left", "right", " ":
+		if m.remoteForm.focus == remoteFieldTransport {
+			m.remoteForm.toggleTransport()
+			return m, nil
+		}
+
+	case "ctrl+t":
+		rc, err := m.remoteForm.config()
+		if err != nil {
+			m.dialogError = err.Error()
+			return m, nil
+		}
+		m.remoteForm.status = ""
+		m.dialogError = fmt.Sprintf("Testing %s...", rc.Name)
+		return m, checkRemoteCmd(rc)
+
+	case "enter":
+		rc, err := m.remoteForm.config()
+		if err != nil {
+			m.dialogError = err.Error()
+			return m, nil
+		}
+		if m.remoteNameTaken(rc.Name, m.remoteForm.oldName) {
+			m.dialogError = fmt.Sprintf("A remote named %s already exists", rc.Name)
+			return m, nil
+		}
+		m.dialogError = ""
+		return m, saveRemoteCmd(m.remotesConfigPath, m.remoteForm.oldName, rc)
+	}
+
+	if m.remoteForm.focus == remoteFieldTransport {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.remoteForm.inputs[m.remoteForm.focus], cmd = m.remoteForm.inputs[m.remoteForm.focus].Update(msg)
+	return m, cmd
+}
+
+// remoteCheckMessage describes a failed remote test, with a hint for host
+// keys that can only be trusted once the remote is saved.
+func remoteCheckMessage(name string, err error) string {
+	if errors.Is(err, remote.ErrHostKeyUnknown) {
+		return fmt.Sprintf("%s is reachable but its host key is not trusted yet; save to review it", name)
+	}
+	return fmt.Sprintf("Test failed: %v", err)
+}
@@ -0,0 +1,231 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/remote"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+const testRemotesYAML = `# my remotes
+remotes:
+  - name: devbox
+    host: devbox # ssh alias
+  - name: gpu
+    host: gpu
+`
+
+// newRemoteConfigModel returns a remotes panel model backed by a temp remotes.yaml.
+func newRemoteConfigModel(t *testing.T) (Model, string) {
+	t.Helper()
+	m := newRemotesPanelModel(t)
+	path := filepath.Join(t.TempDir(), "remotes.yaml")
+	if err := os.WriteFile(path, []byte(testRemotesYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m.remotesConfigPath = path
+	m.remotesWatcher = remote.NewConfigWatcher(path)
+	m.dialogMode = DialogRemotes
+	return m, path
+}
+
+func sendKeys(t *testing.T, m Model, keys ...tea.KeyMsg) Model {
+	t.Helper()
+	for _, k := range keys {
+		updated, _ := m.Update(k)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func typeText(t *testing.T, m Model, text string) Model {
+	t.Helper()
+	return sendKeys(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+}
+
+// runMsg runs cmd and feeds its message back into the model.
+func runMsg(t *testing.T, m Model, cmd tea.Cmd) (Model, tea.Cmd) {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a command")
+	}
+	updated, next := m.Update(cmd())
+	return updated.(Model), next
+}
+
+func TestApplyRemotesConfigReconciles(t *testing.T) {
+	m := newRemotesPanelModel(t)
+
+	cmd := m.applyRemotesConfig(remotesConfigMsg{
+		remotes: []remote.Config{{Name: "devbox", Host: "devbox.lan"}, {Name: "ctr", Transport: remote.TransportCommand, Command: "sh -c"}},
+		invalid: []remote.ConfigError{{Name: "bad", Err: remote.ErrRemoteHostRequired}},
+	})
+	if cmd == nil {
+		t.Error("reload should poll the remotes")
+	}
+
+	if len(m.Remotes) != 2 || m.SSHPool.GetRemoteConfig("gpu") != nil || m.SSHPool.GetRemoteConfig("ctr") == nil {
+		t.Errorf("remotes = %+v, want gpu removed and ctr added", m.Remotes)
+	}
+	for _, s := range m.sessions {
+		if s.Remote == "gpu" {
+			t.Error("removed remote's sessions should be dropped")
+		}
+	}
+
+	m.dialogMode = DialogRemotes
+	m.remotesCursor = 2
+	view := m.renderDialog()
+	for _, want := range []string{"ctr", "bad", "invalid", "Invalid: remote host is required"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("panel missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestApplyRemotesConfigCreatesPoolAndKeepsRemotesOnError(t *testing.T) {
+	m := Model{width: 120, height: 40, sessions: []session.Info{}}
+
+	m.applyRemotesConfig(remotesConfigMsg{remotes: []remote.Config{{Name: "devbox", Host: "devbox"}}})
+	if m.SSHPool == nil || len(m.Remotes) != 1 {
+		t.Fatalf("first remote should create the pool: %+v", m.Remotes)
+	}
+	t.Cleanup(m.SSHPool.Close)
+
+	m.applyRemotesConfig(remotesConfigMsg{err: errors.New("yaml: line 3: bad indent")})
+	if len(m.Remotes) != 1 {
+		t.Error("a parse error should keep the current remotes")
+	}
+	m.dialogMode = DialogRemotes
+	if view := m.renderDialog(); !strings.Contains(view, "remotes.yaml: yaml: line 3") {
+		t.Errorf("panel should show the load error:\n%s", view)
+	}
+}
+
+func TestRemoteEditorAdd(t *testing.T) {
+	m, path := newRemoteConfigModel(t)
+
+	m = typeText(t, m, "a")
+	if m.dialogMode != DialogRemoteEdit {
+		t.Fatalf("dialogMode = %v, want DialogRemoteEdit", m.dialogMode)
+	}
+
+	// Saving with no name fails validation
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.dialogError != remote.ErrRemoteNameRequired.Error() {
+		t.Errorf("dialogError = %q, want name required", m.dialogError)
+	}
+
+	m = typeText(t, m, "ctr")
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyRight}, tea.KeyMsg{Type: tea.KeyTab})
+	if m.remoteForm.transport != remote.TransportCommand || m.remoteForm.focus != remoteFieldCommand {
+		t.Fatalf("form = transport %q focus %d, want command transport on the command field", m.remoteForm.transport, m.remoteForm.focus)
+	}
+	if view := m.renderDialog(); !strings.Contains(view, "Add Remote") || !strings.Contains(view, "Command:") || strings.Contains(view, "Jump host:") {
+		t.Errorf("editor should show command fields only:\n%s", view)
+	}
+	m = typeText(t, m, "sh -c")
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	m = updated.(Model)
+	m, _ = runMsg(t, m, cmd)
+	if m.remoteForm.status != "✓ ctr is reachable" || m.dialogError != "" {
+		t.Errorf("test result = %q / %q", m.remoteForm.status, m.dialogError)
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	m, cmd = runMsg(t, m, cmd) // remoteConfigSavedMsg
+	if m.dialogMode != DialogRemotes {
+		t.Errorf("dialogMode = %v, want back on the remotes panel", m.dialogMode)
+	}
+	m, _ = runMsg(t, m, cmd) // remotesConfigMsg
+	if remote.GetByName(m.Remotes, "ctr") == nil {
+		t.Errorf("remotes = %+v, want ctr added", m.Remotes)
+	}
+	if m.remotesWatcher.Changed() {
+		t.Error("the editor's own write should not trigger a second reload")
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# my remotes") || !strings.Contains(string(data), "command: sh -c") {
+		t.Errorf("remotes.yaml after add:\n%s", data)
+	}
+}
+
+func TestRemoteEditorEditAndDelete(t *testing.T) {
+	m, path := newRemoteConfigModel(t)
+
+	m = typeText(t, m, "e")
+	if m.dialogMode != DialogRemoteEdit || m.remoteForm.oldName != "devbox" {
+		t.Fatalf("editor not opened for devbox: mode %v form %+v", m.dialogMode, m.remoteForm.oldName)
+	}
+	if got := m.remoteForm.inputs[remoteFieldHost].Value(); got != "devbox" {
+		t.Errorf("host = %q, want prefilled", got)
+	}
+
+	// Port is validated before saving
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab})
+	m = typeText(t, m, "ssh")
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.dialogError != errRemotePortInvalid.Error() {
+		t.Errorf("dialogError = %q, want port error", m.dialogError)
+	}
+	m.remoteForm.inputs[remoteFieldPort].SetValue("2222")
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	m, cmd = runMsg(t, m, cmd)
+	m, _ = runMsg(t, m, cmd)
+	if rc := m.SSHPool.GetRemoteConfig("devbox"); rc == nil || rc.Port != 2222 {
+		t.Errorf("devbox config = %+v, want port 2222", rc)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "host: devbox # ssh alias") {
+		t.Errorf("comment lost on edit:\n%s", data)
+	}
+
+	// Delete asks for confirmation first
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	m = typeText(t, m, "d")
+	if !strings.Contains(m.renderDialog(), "Delete gpu from remotes.yaml?") {
+		t.Error("delete should ask for confirmation")
+	}
+	m = typeText(t, m, "n")
+	if m.remoteDeleting != "" {
+		t.Error("n should cancel the delete")
+	}
+
+	m = typeText(t, m, "d")
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(Model)
+	m, cmd = runMsg(t, m, cmd)
+	m, _ = runMsg(t, m, cmd)
+	if len(m.Remotes) != 1 || m.remotesCursor != 0 {
+		t.Errorf("remotes = %+v cursor %d, want gpu deleted", m.Remotes, m.remotesCursor)
+	}
+}
+
+func TestRemoteEditorRejectsDuplicateName(t *testing.T) {
+	m, _ := newRemoteConfigModel(t)
+
+	m = typeText(t, m, "a")
+	m = typeText(t, m, "gpu")
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab})
+	m = typeText(t, m, "gpu2")
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.dialogError != "A remote named gpu already exists" {
+		t.Errorf("dialogError = %q", m.dialogError)
+	}
+
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.dialogMode != DialogRemotes {
+		t.Errorf("esc should return to the remotes panel, got %v", m.dialogMode)
+	}
+}
@@ -0,0 +1,273 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/stwalsh4118/navi/internal/keymap"
+	"github.com/stwalsh4118/navi/internal/remote"
+)
+
+// remotesPanelWidth is the width of the remotes health dialog.
+const remotesPanelWidth = 84
+
+// remoteActionResultMsg is returned after reconnecting a remote from the remotes panel.
+type remoteActionResultMsg struct {
+	remote string
+	err    error
+}
+
+// reconnectRemoteCmd returns a command that redials a remote, bypassing its backoff.
+func reconnectRemoteCmd(pool *remote.SSHPool, remoteName string) tea.Cmd {
+	return func() tea.Msg {
+		return remoteActionResultMsg{remote: remoteName, err: pool.Reconnect(remoteName)}
+	}
+}
+
+// remoteStateLabel describes a remote's connection state, including the
+// remaining backoff for a failing remote.
+func remoteStateLabel(st *remote.RemoteStatus, now time.Time) string {
+	if st.Status == remote.StatusError && st.NextRetry.After(now) {
+		return fmt.Sprintf("error · retry %s", st.NextRetry.Sub(now).Round(time.Second))
+	}
+	return st.Status.String()
+}
+
+// remoteStateStyle picks the color for a remote's state.
+func remoteStateStyle(st *remote.RemoteStatus) lipgloss.Style {
+	switch st.Status {
+	case remote.StatusConnected:
+		return greenStyle
+	case remote.StatusError:
+		return redStyle
+	default:
+		return dimStyle
+	}
+}
+
+// formatSince formats the time since t, or a dash if t is zero.
+func formatSince(t time.Time) string {
+	if t.IsZero() {
+		return "—"
+	}
+	return formatAge(t.Unix())
+}
+
+// formatLatency formats a round-trip time, or a dash when unknown.
+func formatLatency(d time.Duration) string {
+	if d <= 0 {
+		return "—"
+	}
+	if d < time.Millisecond {
+		return "<1ms"
+	}
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
+
+// remotesRowCount returns the number of rows in the remotes panel: the
+// configured remotes followed by remotes.yaml entries that failed validation.
+func (m Model) remotesRowCount() int {
+	return len(m.Remotes) + len(m.remotesInvalid)
+}
+
+// selectedRemoteName returns the name of the remotes.yaml entry under the
+// panel cursor, and whether it is a configured (valid) remote.
+func (m Model) selectedRemoteName() (string, bool) {
+	if m.remotesCursor < len(m.Remotes) {
+		return m.Remotes[m.remotesCursor].Name, true
+	}
+	if i := m.remotesCursor - len(m.Remotes); i < len(m.remotesInvalid) {
+		return m.remotesInvalid[i].Name, false
+	}
+	return "", false
+}
+
+// renderRemotesPanel renders each remote's connection state, last successful
+// poll, latency, session count and, for the selected remote, its last error.
+// Entries in remotes.yaml that failed validation are listed after them.
+func (m Model) renderRemotesPanel() string {
+	var b strings.Builder
+
+	b.WriteString(dialogTitleStyle.Render(DialogTitle(DialogRemotes)))
+	b.WriteString("\n\n")
+
+	if m.remotesLoadErr != nil {
+		b.WriteString(redStyle.Render(truncate("remotes.yaml: "+m.remotesLoadErr.Error(), remotesPanelWidth-6)))
+		b.WriteString("\n\n")
+	}
+
+	if m.remotesRowCount() == 0 {
+		b.WriteString(dimStyle.Render("No remotes configured"))
+		b.WriteString("\n\n")
+		b.WriteString(dimStyle.Render("a: add  Esc: close"))
+	} else {
+		now := time.Now()
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  %-16s %-20s %-10s %-8s %s", "REMOTE", "STATE", "LAST POLL", "LATENCY", "SESSIONS")))
+		b.WriteString("\n")
+
+		for i, r := range m.Remotes {
+			st := &remote.RemoteStatus{}
+			if m.SSHPool != nil {
+				st = m.SSHPool.GetStatus(r.Name)
+			}
+			state := fmt.Sprintf("%-20s", remoteStateLabel(st, now))
+			line := fmt.Sprintf("  %-16s %s %-10s %-8s %d",
+				truncate(r.Name, 16), state, formatSince(st.LastSuccess), formatLatency(st.Latency), st.Sessions)
+			if i == m.remotesCursor {
+				line = selectedStyle.Render(line)
+			} else {
+				line = strings.Replace(line, state, remoteStateStyle(st).Render(state), 1)
+			}
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+
+		for i, ce := range m.remotesInvalid {
+			name := ce.Name
+			if name == "" {
+				name = "(unnamed)"
+			}
+			state := fmt.Sprintf("%-20s", "invalid")
+			line := fmt.Sprintf("  %-16s %s %-10s %-8s %s", truncate(name, 16), state, "—", "—", "—")
+			if len(m.Remotes)+i == m.remotesCursor {
+				line = selectedStyle.Render(line)
+			} else {
+				line = strings.Replace(line, state, redStyle.Render(state), 1)
+			}
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+
+		var selectedErr string
+		if m.remotesCursor < len(m.Remotes) {
+			if m.SSHPool != nil {
+				if err := m.SSHPool.GetStatus(m.Remotes[m.remotesCursor].Name).LastError; err != nil {
+					selectedErr = "Last error: " + err.Error()
+				}
+			}
+		} else if i := m.remotesCursor - len(m.Remotes); i < len(m.remotesInvalid) {
+			selectedErr = "Invalid: " + m.remotesInvalid[i].Err.Error()
+		}
+		if selectedErr != "" {
+			b.WriteString("\n")
+			b.WriteString(redStyle.Render(truncate(selectedErr, remotesPanelWidth-6)))
+			b.WriteString("\n")
+		}
+
+		b.WriteString("\n")
+		if m.remoteDeleting != "" {
+			b.WriteString(highlightStyle.Render(fmt.Sprintf("Delete %s from remotes.yaml? y: yes  n: no", m.remoteDeleting)))
+		} else {
+			b.WriteString(dimStyle.Render("↑↓: move  a: add  e: edit  d: delete  c: reconnect  x: disconnect  Esc: close"))
+		}
+	}
+
+	if m.dialogError != "" {
+		b.WriteString("\n")
+		b.WriteString(dialogErrorStyle.Render(m.dialogError))
+	}
+
+	dialog := dialogBoxStyle.Width(remotesPanelWidth).Render(b.String())
+	return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, dialog)
+}
+
+// updateRemotesPanel handles key input for the remotes panel.
+func (m Model) updateRemotesPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.remoteDeleting != "" {
+		name := m.remoteDeleting
+		m.remoteDeleting = ""
+		if msg.String() == "y" {
+			return m, deleteRemoteCmd(m.remotesConfigPath, name)
+		}
+		return m, nil
+	}
+
+	action := m.keys.Action(keymap.RemotesPanel, msg.String())
+	switch action {
+	case keymap.Back:
+		m.dialogMode = DialogNone
+		m.dialogError = ""
+		return m, nil
+
+	case keymap.Up:
+		if m.remotesCursor > 0 {
+			m.remotesCursor--
+		}
+		return m, nil
+
+	case keymap.Down:
+		if m.remotesCursor < m.remotesRowCount()-1 {
+			m.remotesCursor++
+		}
+		return m, nil
+
+	case keymap.Add:
+		return m.openRemoteEditor("")
+
+	case keymap.Edit, keymap.Delete:
+		name, _ := m.selectedRemoteName()
+		if m.remotesCursor >= m.remotesRowCount() {
+			return m, nil
+		}
+		if name == "" {
+			m.dialogError = "This entry has no name; fix it in remotes.yaml"
+			return m, nil
+		}
+		if action == keymap.Edit {
+			return m.openRemoteEditor(name)
+		}
+		if m.remotesConfigPath == "" {
+			m.dialogError = "No remotes config file"
+			return m, nil
+		}
+		m.dialogError = ""
+		m.remoteDeleting = name
+		return m, nil
+
+	case keymap.Reconnect:
+		name, ok := m.selectedRemoteName()
+		if m.SSHPool == nil || !ok {
+			return m, nil
+		}
+		m.dialogError = fmt.Sprintf("Connecting to %s...", name)
+		return m, reconnectRemoteCmd(m.SSHPool, name)
+
+	case keymap.Disconnect:
+		name, ok := m.selectedRemoteName()
+		if m.SSHPool == nil || !ok {
+			return m, nil
+		}
+		m.SSHPool.Pause(name)
+		m.dialogError = ""
+		m.removeRemoteSessions(name)
+		return m, nil
+
+	case keymap.Help:
+		m.openKeyHelp(keymap.RemotesPanel)
+	}
+
+	return m, nil
+}
+
+// removeRemoteSessions drops a remote's sessions from the list, used when the
+// remote is disconnected and will no longer be polled.
+func (m *Model) removeRemoteSessions(remoteName string) {
+	kept := m.sessions[:0:0]
+	for _, s := range m.sessions {
+		if s.Remote != remoteName {
+			kept = append(kept, s)
+		}
+	}
+	m.sessions = kept
+	m.pruneSelection()
+	if m.cursor >= len(m.getFilteredSessions()) && m.cursor > 0 {
+		m.cursor = len(m.getFilteredSessions()) - 1
+		if m.cursor < 0 {
+			m.cursor = 0
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/stwalsh4118/navi/internal/keymap"
+	"github.com/stwalsh4118/navi/internal/remote"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+func newRemotesPanelModel(t *testing.T) Model {
+	t.Helper()
+	remotes := []remote.Config{{Name: "devbox", Host: "devbox"}, {Name: "gpu", Host: "gpu"}}
+	pool := remote.NewSSHPool(remotes)
+	pool.SetDialer(func(*remote.Config) (*ssh.Client, error) {
+		return nil, errors.New("connection refused")
+	})
+	t.Cleanup(pool.Close)
+	return Model{
+		width:   120,
+		height:  40,
+		Remotes: remotes,
+		SSHPool: pool,
+		sessions: []session.Info{
+			{TmuxSession: "local"},
+			{TmuxSession: "api", Remote: "devbox"},
+			{TmuxSession: "train", Remote: "gpu"},
+		},
+	}
+}
+
+func TestRemotesPanelShowsHealth(t *testing.T) {
+	m := newRemotesPanelModel(t)
+	m.SSHPool.Connect("gpu")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	m = updated.(Model)
+	if m.dialogMode != DialogRemotes {
+		t.Fatalf("dialogMode = %v, want DialogRemotes", m.dialogMode)
+	}
+
+	view := m.renderDialog()
+	for _, want := range []string{"Remotes", "devbox", "disconnected", "gpu", "error · retry", "c: reconnect"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("panel missing %q:\n%s", want, view)
+		}
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	if !strings.Contains(m.renderDialog(), "Last error: connection refused") {
+		t.Error("selected remote's last error should be shown")
+	}
+}
+
+func TestRemotesPanelDisconnectAndReconnect(t *testing.T) {
+	m := newRemotesPanelModel(t)
+	m.dialogMode = DialogRemotes
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = updated.(Model)
+	if got := m.SSHPool.GetStatus("devbox").Status; got != remote.StatusPaused {
+		t.Errorf("devbox status = %v, want paused", got)
+	}
+	for _, s := range m.sessions {
+		if s.Remote == "devbox" {
+			t.Errorf("session %s from a disconnected remote still listed", s.TmuxSession)
+		}
+	}
+	if len(m.sessions) != 2 {
+		t.Errorf("sessions = %d, want local and gpu kept", len(m.sessions))
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("reconnect should return a command")
+	}
+	msg := cmd()
+	result, ok := msg.(remoteActionResultMsg)
+	if !ok || result.remote != "devbox" || result.err == nil {
+		t.Fatalf("reconnect result = %#v", msg)
+	}
+	updated, _ = m.Update(result)
+	m = updated.(Model)
+	if !strings.Contains(m.dialogError, "connection refused") {
+		t.Errorf("dialogError = %q, want the dial error", m.dialogError)
+	}
+	if got := m.SSHPool.GetStatus("devbox").Status; got != remote.StatusError {
+		t.Errorf("devbox status after failed reconnect = %v, want error", got)
+	}
+}
+
+func TestRemotesPanelRemappedKeys(t *testing.T) {
+	m := newRemotesPanelModel(t)
+	m.keys = keymap.Default()
+	if err := m.keys.Override(keymap.Config{keymap.RemotesPanel: {keymap.Disconnect: {"D"}}}); err != nil {
+		t.Fatalf("Override error: %v", err)
+	}
+	m.dialogMode = DialogRemotes
+
+	m = typeText(t, m, "x")
+	if got := m.SSHPool.GetStatus("devbox").Status; got == remote.StatusPaused {
+		t.Fatal("x disconnected devbox after remapping disconnect")
+	}
+
+	m = typeText(t, m, "D")
+	if got := m.SSHPool.GetStatus("devbox").Status; got != remote.StatusPaused {
+		t.Errorf("devbox status = %v, want paused by the remapped key", got)
+	}
+
+	m = typeText(t, m, "?")
+	if m.dialogMode != DialogContentViewer || m.contentViewerTitle != "Keys: Remotes panel" {
+		t.Fatalf("dialogMode = %v, title = %q", m.dialogMode, m.contentViewerTitle)
+	}
+	m = sendKeys(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.dialogMode != DialogRemotes {
+		t.Errorf("dialogMode = %v after closing help, want DialogRemotes", m.dialogMode)
+	}
+}
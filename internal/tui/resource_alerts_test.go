@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stwalsh4118/navi/internal/alert"
+	"github.com/stwalsh4118/navi/internal/history"
+	"github.com/stwalsh4118/navi/internal/resource"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+func newAlertTestModel(cfg *alert.Config) (Model, *[]string) {
+	var notified []string
+	m := Model{
+		width:          120,
+		height:         40,
+		sessions:       []session.Info{{TmuxSession: "api", Status: session.StatusWorking}},
+		alertEvaluator: alert.NewEvaluator(cfg),
+		audioNotifyFn: func(name, status string) {
+			notified = append(notified, name+":"+status)
+		},
+	}
+	return m, &notified
+}
+
+func runawayUsage() resource.Usage {
+	return resource.Usage{
+		RSSBytes:     8 * 1024 * 1024 * 1024,
+		ProcessCount: 3,
+		Processes: []resource.Process{
+			{PID: 100, Depth: 0, Command: "-bash"},
+			{PID: 150, Depth: 1, Command: "claude", RSSBytes: 512 * 1024 * 1024},
+			{PID: 200, Depth: 2, Command: "jest --watch", RSSBytes: 7 * 1024 * 1024 * 1024},
+		},
+	}
+}
+
+func TestResourceAlertBadgeAndNotification(t *testing.T) {
+	m, notified := newAlertTestModel(&alert.Config{MaxRSSMB: 1024, Action: alert.ActionNotify})
+
+	updated, cmd := m.Update(resourcePollMsg{"api": runawayUsage()})
+	m = updated.(Model)
+
+	if cmd != nil {
+		t.Error("notify-only policy should not issue kill commands")
+	}
+	if len(*notified) != 1 || (*notified)[0] != "api:"+alert.NotifyEvent {
+		t.Errorf("notifications = %v, want one alert for api", *notified)
+	}
+
+	row := m.renderSession(m.sessions[0], false, 160)
+	if !strings.Contains(row, "⚠ RSS 8.0G>1.0G") {
+		t.Errorf("expected alert badge in session row, got:\n%s", row)
+	}
+
+	// Still over the threshold: badge stays, no repeat notification
+	updated, _ = m.Update(resourcePollMsg{"api": runawayUsage()})
+	m = updated.(Model)
+	if len(*notified) != 1 {
+		t.Errorf("notifications = %v, want no repeat while breach is active", *notified)
+	}
+
+	// Back under the threshold: badge clears
+	updated, _ = m.Update(resourcePollMsg{"api": {RSSBytes: 1024, ProcessCount: 1}})
+	m = updated.(Model)
+	if strings.Contains(m.renderSession(m.sessions[0], false, 160), "⚠") {
+		t.Error("alert badge should clear when usage drops below threshold")
+	}
+}
+
+func TestResourceAlertSIGTERMPolicyIssuesKill(t *testing.T) {
+	m, _ := newAlertTestModel(&alert.Config{MaxRSSMB: 1024, Action: alert.ActionSIGTERM})
+
+	cmds := m.evaluateResourceAlerts(resourcePollMsg{"api": runawayUsage()}, time.Now())
+	if len(cmds) != 1 {
+		t.Fatalf("len(cmds) = %d, want 1 kill command for offender", len(cmds))
+	}
+}
+
+func TestResourceAlertSIGTERMPolicySparesAgent(t *testing.T) {
+	m, notified := newAlertTestModel(&alert.Config{MaxRSSMB: 1024, Action: alert.ActionSIGTERM})
+
+	usage := runawayUsage()
+	usage.Processes = usage.Processes[:2] // Only the shell and claude itself
+	cmds := m.evaluateResourceAlerts(resourcePollMsg{"api": usage}, time.Now())
+	if len(cmds) != 0 {
+		t.Errorf("len(cmds) = %d, want no kill when only the agent is running", len(cmds))
+	}
+	if len(*notified) != 1 {
+		t.Errorf("notifications = %v, want the alert still reported", *notified)
+	}
+}
+
+func TestResourceAlertKillResultIgnoredOutsideDialog(t *testing.T) {
+	m, _ := newAlertTestModel(nil)
+	updated, cmd := m.Update(processKillResultMsg{pid: 200})
+	if cmd != nil || updated.(Model).dialogError != "" {
+		t.Error("policy kill results should not touch dialog state")
+	}
+}
+
+func TestProcessTreeKillKeepsOtherAlerts(t *testing.T) {
+	m, notified := newAlertTestModel(&alert.Config{MaxRSSMB: 1024, Action: alert.ActionNotify})
+	m.sessions = append(m.sessions, session.Info{TmuxSession: "web", Status: session.StatusWorking})
+	m.metricsHistory = history.NewStore(filepath.Join(t.TempDir(), "history.json"))
+	m.resourceSampler = resource.NewSampler()
+
+	updated, _ := m.Update(resourcePollMsg{"api": {RSSBytes: 1024, ProcessCount: 1}, "web": runawayUsage()})
+	m = updated.(Model)
+	if len(*notified) != 1 {
+		t.Fatalf("notifications = %v, want one alert for web", *notified)
+	}
+	samples := len(m.metricsHistory.Series("web"))
+
+	// Kill from api's process tree; the refresh covers only api
+	m.dialogMode = DialogProcessTree
+	m.sessionToModify = &m.sessions[0]
+	updated, cmd := m.Update(processKillResultMsg{pid: 200})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected a process tree refresh after the kill")
+	}
+	refresh, ok := cmd().(processTreeRefreshMsg)
+	if !ok {
+		t.Fatalf("refresh message = %T, want processTreeRefreshMsg", refresh)
+	}
+	refresh["api"] = resource.Usage{RSSBytes: 512, ProcessCount: 1}
+	updated, _ = m.Update(refresh)
+	m = updated.(Model)
+
+	if !strings.Contains(m.renderSession(m.sessions[1], false, 160), "⚠ RSS") {
+		t.Error("web's alert badge should survive a refresh of api")
+	}
+	if got := len(m.metricsHistory.Series("web")); got != samples {
+		t.Errorf("refresh recorded %d history samples, want none", got-samples)
+	}
+
+	// web is still tracked as breaching, so the next full poll does not re-alert
+	updated, _ = m.Update(resourcePollMsg{"api": {RSSBytes: 512, ProcessCount: 1}, "web": runawayUsage()})
+	m = updated.(Model)
+	if len(*notified) != 1 {
+		t.Errorf("notifications = %v, want no repeat for web", *notified)
+	}
+}
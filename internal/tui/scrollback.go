@@ -0,0 +1,280 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/remote"
+	"github.com/stwalsh4118/navi/internal/session"
+	"github.com/stwalsh4118/navi/internal/sgr"
+)
+
+// Preview scrollback constants
+const (
+	// previewHistoryChunk is the number of older lines fetched per lazy load
+	previewHistoryChunk = 500
+
+	// previewHistoryMargin is how close to the first loaded line scrolling must
+	// get before the next chunk of older lines is fetched
+	previewHistoryMargin = previewPageScrollAmt
+
+	// previewMatchContext is the number of lines kept above a search match when jumping to it
+	previewMatchContext = 2
+)
+
+// previewHistoryMsg is returned after a ranged scrollback capture.
+type previewHistoryMsg struct {
+	gen  int              // Load generation; stale captures are dropped
+	full bool             // Whether the whole scrollback was captured for a search
+	r    remote.PaneRange // The captured lines
+	err  error
+}
+
+// captureHistoryCmd captures lines start through end of the session's pane,
+// locally or through the session's remote.
+func captureHistoryCmd(pool *remote.SSHPool, s session.Info, start, end, gen int, full bool) tea.Cmd {
+	return func() tea.Msg {
+		var r remote.PaneRange
+		var err error
+		if s.Remote != "" && pool != nil {
+			r, err = remote.CapturePaneRange(pool, s.Remote, s.TmuxSession, start, end)
+		} else {
+			r, err = capturePaneRange(s.TmuxSession, start, end)
+		}
+		return previewHistoryMsg{gen: gen, full: full, r: r, err: err}
+	}
+}
+
+// previewSession returns the session shown in the preview pane.
+func (m Model) previewSession() (session.Info, bool) {
+	filteredSessions := m.getFilteredSessions()
+	if m.cursor < len(filteredSessions) {
+		return filteredSessions[m.cursor], true
+	}
+	return session.Info{}, false
+}
+
+// previewTop returns the scrollback index of the first loaded line.
+func (m Model) previewTop() int {
+	return m.previewTailStart - len(m.previewHistory)
+}
+
+// previewBufferLines returns every loaded line, oldest first.
+func (m Model) previewBufferLines() []string {
+	lines := make([]string, 0, len(m.previewHistory)+previewDefaultLines)
+	lines = append(lines, m.previewHistory...)
+	if m.previewContent != "" {
+		lines = append(lines, strings.Split(m.previewContent, "\n")...)
+	}
+	return lines
+}
+
+// previewContentWidth returns the width available to preview text in the
+// current layout, matching renderPreview.
+func (m Model) previewContentWidth() int {
+	width := m.width
+	if m.previewLayout != PreviewLayoutBottom {
+		width = m.getPreviewWidth()
+	}
+	return max(width-4, 10)
+}
+
+// loadPreviewHistory starts fetching the chunk of scrollback just above the
+// loaded lines and pauses live refresh so the loaded lines stay put. It does
+// nothing while a capture is in flight or once the oldest line is loaded.
+func (m *Model) loadPreviewHistory() tea.Cmd {
+	top := m.previewTop()
+	s, ok := m.previewSession()
+	if m.previewLoading || top <= 0 || !ok {
+		return nil
+	}
+	m.previewDeep = true
+	m.previewLoading = true
+	m.previewLoadGen++
+
+	// Ranges are relative to the bottom of the history when last seen
+	start := max(top-previewHistoryChunk, 0) - m.previewHistorySize
+	end := top - 1 - m.previewHistorySize
+	return captureHistoryCmd(m.SSHPool, s, start, end, m.previewLoadGen, false)
+}
+
+// loadPreviewHistoryNearTop fetches older scrollback once scrolling gets
+// within previewHistoryMargin lines of the first loaded line.
+func (m *Model) loadPreviewHistoryNearTop() tea.Cmd {
+	if m.previewScrollOffset > previewHistoryMargin {
+		return nil
+	}
+	return m.loadPreviewHistory()
+}
+
+// loadPreviewScrollback starts fetching the session's whole scrollback for a
+// search. Any capture in flight is superseded.
+func (m *Model) loadPreviewScrollback() tea.Cmd {
+	s, ok := m.previewSession()
+	if !ok {
+		return nil
+	}
+	m.previewDeep = true
+	m.previewLoading = true
+	m.previewLoadGen++
+	return captureHistoryCmd(m.SSHPool, s, remote.HistoryTop, remote.PaneBottom, m.previewLoadGen, true)
+}
+
+// handlePreviewHistory merges a scrollback capture into the loaded lines.
+func (m Model) handlePreviewHistory(msg previewHistoryMsg) (tea.Model, tea.Cmd) {
+	if !m.previewLoading || msg.gen != m.previewLoadGen {
+		return m, nil
+	}
+	m.previewLoading = false
+	if msg.err != nil {
+		// Keep what is loaded; scrolling up again retries
+		return m, nil
+	}
+	m.previewHistorySize = msg.r.HistorySize
+
+	if msg.full {
+		m.previewHistory = nil
+		m.previewContent = sgr.TrimRight(strings.Join(msg.r.Lines, "\n"))
+		m.previewTailStart = msg.r.Start
+		m.computePreviewMatches()
+		m.jumpToPreviewMatch(len(m.previewMatches) - 1)
+		return m, nil
+	}
+
+	top := m.previewTop()
+	if msg.r.End() < top {
+		// The pane's history was cleared, so the loaded lines no longer line up
+		m.resetPreviewScrollback()
+		m.previewAutoScroll = true
+		if s, ok := m.previewSession(); ok {
+			return m, m.capturePreviewForSession(s)
+		}
+		return m, nil
+	}
+
+	// Output that arrived since the last capture shifts the range down; drop
+	// the lines that are already loaded
+	lines := msg.r.Lines
+	if overlap := msg.r.End() - top; overlap > 0 {
+		lines = lines[:max(len(lines)-overlap, 0)]
+	}
+	if len(lines) == 0 {
+		return m, nil
+	}
+
+	m.previewHistory = append(append([]string(nil), lines...), m.previewHistory...)
+	added, _ := previewLines(strings.Join(lines, "\n"), m.previewContentWidth(), m.previewWrap)
+	m.previewScrollOffset += len(added)
+
+	if m.previewSearchQuery != "" {
+		// Keep the current match; the new lines add matches before it
+		before, idx := len(m.previewMatches), m.previewMatchIdx
+		m.computePreviewMatches()
+		m.previewMatchIdx = idx + len(m.previewMatches) - before
+	}
+	return m, nil
+}
+
+// resetPreviewScrollback drops loaded scrollback and search state, resuming
+// live refresh of the pane's tail.
+func (m *Model) resetPreviewScrollback() {
+	m.previewHistory = nil
+	m.previewDeep = false
+	m.previewLoading = false
+	m.clearPreviewSearchState()
+}
+
+// clearPreviewSearchState resets all preview search-related fields.
+func (m *Model) clearPreviewSearchState() {
+	m.previewSearchMode = false
+	m.previewSearchQuery = ""
+	m.previewSearchInput.SetValue("")
+	m.previewSearchInput.Blur()
+	m.previewMatches = nil
+	m.previewMatchIdx = 0
+}
+
+// computePreviewMatches recomputes the scrollback lines matching the query.
+func (m *Model) computePreviewMatches() {
+	m.previewMatches = nil
+	if m.previewSearchQuery == "" {
+		m.previewMatchIdx = 0
+		return
+	}
+	top := m.previewTop()
+	for i, line := range m.previewBufferLines() {
+		if exactMatch(m.previewSearchQuery, sgr.Strip(line)) {
+			m.previewMatches = append(m.previewMatches, top+i)
+		}
+	}
+	if m.previewMatchIdx >= len(m.previewMatches) || m.previewMatchIdx < 0 {
+		m.previewMatchIdx = 0
+	}
+}
+
+// jumpToPreviewMatch scrolls the preview so match idx is near the top.
+func (m *Model) jumpToPreviewMatch(idx int) {
+	if idx < 0 || idx >= len(m.previewMatches) {
+		return
+	}
+	m.previewMatchIdx = idx
+	_, starts := previewLines(m.previewText(), m.previewContentWidth(), m.previewWrap)
+	if line := m.previewMatches[idx] - m.previewTop(); line < len(starts) {
+		m.previewScrollOffset = max(starts[line]-previewMatchContext, 0)
+		m.previewAutoScroll = false
+	}
+}
+
+// cyclePreviewMatch moves to the next (delta 1) or previous (delta -1) match, wrapping around.
+func (m *Model) cyclePreviewMatch(delta int) {
+	if len(m.previewMatches) == 0 {
+		return
+	}
+	n := len(m.previewMatches)
+	m.jumpToPreviewMatch(((m.previewMatchIdx+delta)%n + n) % n)
+}
+
+// previewText returns the loaded lines as rendered: colors stripped when
+// requested and the current search match highlighted.
+func (m Model) previewText() string {
+	lines := m.previewBufferLines()
+	if m.previewPlain {
+		for i, line := range lines {
+			lines[i] = sgr.Strip(line)
+		}
+	}
+	if m.previewSearchQuery != "" && m.previewMatchIdx < len(m.previewMatches) {
+		if i := m.previewMatches[m.previewMatchIdx] - m.previewTop(); i >= 0 && i < len(lines) {
+			lines[i] = previewMatchStyle.Render(sgr.Strip(lines[i]))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// updatePreviewSearchMode handles key messages while typing a preview search.
+// Matches update as the query changes; Enter loads the whole scrollback and
+// searches it.
+func (m Model) updatePreviewSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.clearPreviewSearchState()
+		return m, nil
+
+	case "enter":
+		// Exit input mode but keep search state (search persists like vim)
+		m.previewSearchMode = false
+		m.previewSearchInput.Blur()
+		if m.previewSearchQuery != "" && m.previewTop() > 0 {
+			return m, m.loadPreviewScrollback()
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.previewSearchInput, cmd = m.previewSearchInput.Update(msg)
+	m.previewSearchQuery = m.previewSearchInput.Value()
+	m.computePreviewMatches()
+	m.jumpToPreviewMatch(len(m.previewMatches) - 1)
+	return m, cmd
+}
@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/remote"
+)
+
+// newScrollbackTestModel returns a focused preview whose tail starts 1000
+// lines into the pane's scrollback.
+func newScrollbackTestModel() Model {
+	m := newPreviewScrollTestModel()
+	m.previewFocused = true
+	m.previewAutoScroll = false
+	m.previewWrap = false
+	m.previewContent = "tail 1\ntail 2\ntail 3"
+	m.previewTailStart = 1000
+	m.previewHistorySize = 1002
+	m.previewSearchInput = initPreviewSearchInput()
+	return m
+}
+
+// numberedLines returns "line <start>" through "line <start+n-1>".
+func numberedLines(start, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", start+i)
+	}
+	return lines
+}
+
+func TestLoadPreviewHistory(t *testing.T) {
+	t.Run("scrolling to the top fetches older lines and pauses refresh", func(t *testing.T) {
+		m := newScrollbackTestModel()
+		updated, cmd := m.updatePreviewFocus(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+		m = updated.(Model)
+		if cmd == nil {
+			t.Fatal("expected a scrollback capture command")
+		}
+		if !m.previewDeep || !m.previewLoading || m.previewLoadGen != 1 {
+			t.Errorf("deep=%v loading=%v gen=%d, want paused and loading gen 1", m.previewDeep, m.previewLoading, m.previewLoadGen)
+		}
+
+		// A second request waits for the first
+		if cmd := m.loadPreviewHistory(); cmd != nil {
+			t.Error("no second capture should start while one is in flight")
+		}
+	})
+
+	t.Run("nothing to load at the oldest line", func(t *testing.T) {
+		m := newScrollbackTestModel()
+		m.previewTailStart = 0
+		if cmd := m.loadPreviewHistory(); cmd != nil || m.previewDeep {
+			t.Error("expected no capture once the oldest line is loaded")
+		}
+	})
+
+	t.Run("scrolling far from the top does not load", func(t *testing.T) {
+		m := newScrollbackTestModel()
+		m.previewScrollOffset = 50
+		_, cmd := m.updatePreviewFocus(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+		if cmd != nil {
+			t.Error("expected no capture away from the top")
+		}
+	})
+}
+
+func TestHandlePreviewHistory(t *testing.T) {
+	loading := func() Model {
+		m := newScrollbackTestModel()
+		m.previewDeep = true
+		m.previewLoading = true
+		m.previewLoadGen = 3
+		return m
+	}
+
+	t.Run("prepends the chunk and keeps the view in place", func(t *testing.T) {
+		m := loading()
+		m.previewScrollOffset = 1
+		updated, _ := m.handlePreviewHistory(previewHistoryMsg{gen: 3, r: remote.PaneRange{
+			Lines: numberedLines(500, 500), Start: 500, HistorySize: 1002,
+		}})
+		m = updated.(Model)
+		if len(m.previewHistory) != 500 || m.previewTop() != 500 {
+			t.Fatalf("history = %d lines from %d, want 500 from 500", len(m.previewHistory), m.previewTop())
+		}
+		if m.previewScrollOffset != 501 {
+			t.Errorf("scroll offset = %d, want 501", m.previewScrollOffset)
+		}
+		if m.previewLoading {
+			t.Error("loading should clear once the capture arrives")
+		}
+	})
+
+	t.Run("drops lines that are already loaded", func(t *testing.T) {
+		m := loading()
+		// Output arrived, so the capture ends 5 lines into the tail
+		updated, _ := m.handlePreviewHistory(previewHistoryMsg{gen: 3, r: remote.PaneRange{
+			Lines: numberedLines(505, 500), Start: 505, HistorySize: 1007,
+		}})
+		m = updated.(Model)
+		if len(m.previewHistory) != 495 || m.previewHistory[494] != "line 999" {
+			t.Errorf("history = %d lines ending %q, want 495 ending line 999", len(m.previewHistory), m.previewHistory[len(m.previewHistory)-1])
+		}
+		if m.previewHistorySize != 1007 {
+			t.Errorf("history size = %d, want 1007", m.previewHistorySize)
+		}
+	})
+
+	t.Run("ignores stale captures", func(t *testing.T) {
+		m := loading()
+		updated, _ := m.handlePreviewHistory(previewHistoryMsg{gen: 2, r: remote.PaneRange{
+			Lines: numberedLines(500, 500), Start: 500,
+		}})
+		if m = updated.(Model); len(m.previewHistory) != 0 || !m.previewLoading {
+			t.Error("a capture from an older generation should be dropped")
+		}
+	})
+
+	t.Run("cleared history resumes the live tail", func(t *testing.T) {
+		m := loading()
+		updated, cmd := m.handlePreviewHistory(previewHistoryMsg{gen: 3, r: remote.PaneRange{
+			Lines: numberedLines(0, 10), Start: 0, HistorySize: 10,
+		}})
+		m = updated.(Model)
+		if m.previewDeep || len(m.previewHistory) != 0 || cmd == nil {
+			t.Error("expected scrollback dropped and a fresh tail capture")
+		}
+	})
+}
+
+func TestPreviewPausedWhileBrowsingScrollback(t *testing.T) {
+	m := newScrollbackTestModel()
+	m.previewDeep = true
+
+	updated, _ := m.Update(previewContentMsg{content: "new output", start: 1001, historySize: 1003})
+	if m = updated.(Model); m.previewContent != "tail 1\ntail 2\ntail 3" {
+		t.Errorf("live capture should not replace paused content, got %q", m.previewContent)
+	}
+
+	updated, _ = m.updatePreviewFocus(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	m = updated.(Model)
+	if m.previewDeep || !m.previewAutoScroll {
+		t.Error("G should resume following live output")
+	}
+}
+
+func TestPreviewSearch(t *testing.T) {
+	m := newScrollbackTestModel()
+	m.previewHistory = []string{"error one", "fine", "\x1b[31merror\x1b[0m two"}
+
+	updated, _ := m.updatePreviewFocus(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = updated.(Model)
+	if !m.previewSearchMode || !m.previewDeep {
+		t.Fatal("/ should start a search and pause live refresh")
+	}
+	m = typeText(t, m, "ERROR")
+
+	if len(m.previewMatches) != 2 || m.previewMatches[0] != 997 || m.previewMatches[1] != 999 {
+		t.Fatalf("matches = %v, want [997 999]", m.previewMatches)
+	}
+	if m.previewMatchIdx != 1 {
+		t.Errorf("typing should jump to the newest match, got %d", m.previewMatchIdx)
+	}
+	if !strings.Contains(m.previewText(), previewMatchStyle.Render("error two")) {
+		t.Error("current match should be highlighted")
+	}
+
+	// Enter searches the whole scrollback
+	updated, cmd := m.updatePreviewSearchMode(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd == nil || !m.previewLoading {
+		t.Fatal("enter should load the full scrollback")
+	}
+	full := append(numberedLines(0, 20), "an error far back")
+	full = append(full, numberedLines(21, 100)...)
+	updated, _ = m.handlePreviewHistory(previewHistoryMsg{gen: m.previewLoadGen, full: true, r: remote.PaneRange{
+		Lines: full, Start: 0, HistorySize: 90,
+	}})
+	m = updated.(Model)
+	if len(m.previewMatches) != 1 || m.previewMatches[0] != 20 {
+		t.Fatalf("matches = %v, want [20]", m.previewMatches)
+	}
+	if m.previewScrollOffset != 20-previewMatchContext {
+		t.Errorf("scroll offset = %d, want %d", m.previewScrollOffset, 20-previewMatchContext)
+	}
+
+	// Esc clears the search before leaving focus
+	updated, _ = m.updatePreviewFocus(tea.KeyMsg{Type: tea.KeyEscape})
+	if m = updated.(Model); m.previewSearchQuery != "" || !m.previewFocused {
+		t.Error("esc should clear the search and keep focus")
+	}
+}
+
+func TestCyclePreviewMatch(t *testing.T) {
+	m := newScrollbackTestModel()
+	m.previewContent = "a\nb\na\nb\na"
+	m.previewSearchQuery = "a"
+	m.computePreviewMatches()
+
+	m.jumpToPreviewMatch(2)
+	m.cyclePreviewMatch(1)
+	if m.previewMatchIdx != 0 {
+		t.Errorf("next should wrap to the first match, got %d", m.previewMatchIdx)
+	}
+	m.cyclePreviewMatch(-1)
+	if m.previewMatchIdx != 2 {
+		t.Errorf("previous should wrap to the last match, got %d", m.previewMatchIdx)
+	}
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+func duplicateNameSessions() []session.Info {
+	return []session.Info{
+		{TmuxSession: "api", Status: session.StatusWorking, CWD: "/home/me/api"},
+		{TmuxSession: "api", Remote: "devbox", Status: session.StatusWaiting, CWD: "/srv/api"},
+		{TmuxSession: "web", Status: session.StatusWorking, CWD: "/home/me/web"},
+	}
+}
+
+func TestPreserveCursorDistinguishesHosts(t *testing.T) {
+	m := Model{width: 120, height: 40, sessions: duplicateNameSessions()}
+	filtered := m.getFilteredSessions()
+	target := -1
+	for i, s := range filtered {
+		if s.Remote == "devbox" {
+			target = i
+		}
+	}
+	if target < 0 {
+		t.Fatal("remote api session missing from the list")
+	}
+
+	m.preserveCursor("api@devbox")
+	if m.cursor != target {
+		t.Fatalf("cursor = %d, want the remote api at %d", m.cursor, target)
+	}
+	if got := m.selectedSessionID(); got != "api@devbox" {
+		t.Fatalf("selectedSessionID() = %q, want api@devbox", got)
+	}
+}
+
+func TestSessionListLabelsDuplicateNames(t *testing.T) {
+	m := Model{width: 120, height: 40, sessions: duplicateNameSessions()}
+
+	local := m.renderSession(m.sessions[0], false, 100)
+	if !strings.Contains(local, "[local]") {
+		t.Errorf("local api sharing its name with a remote should be labelled, got %q", local)
+	}
+	remoteRow := m.renderSession(m.sessions[1], false, 100)
+	if !strings.Contains(remoteRow, "[devbox]") || strings.Contains(remoteRow, "[local]") {
+		t.Errorf("remote api should carry only its remote label, got %q", remoteRow)
+	}
+	unique := m.renderSession(m.sessions[2], false, 100)
+	if strings.Contains(unique, "[local]") {
+		t.Errorf("unique name should not be labelled, got %q", unique)
+	}
+}
+
+func TestFindMatchesUsesHostLabel(t *testing.T) {
+	matches := findMatches(duplicateNameSessions(), "api@dev")
+	if len(matches) != 1 || matches[0] != 1 {
+		t.Fatalf("findMatches(api@dev) = %v, want only the remote api", matches)
+	}
+}
+
+func TestRenameOnlyClashesOnSameHost(t *testing.T) {
+	sessions := append(duplicateNameSessions(), session.Info{TmuxSession: "web", Remote: "devbox"})
+	remoteAPI := sessions[1]
+	m := Model{width: 120, height: 40, sessions: sessions, dialogMode: DialogRename, sessionToModify: &remoteAPI}
+	m.nameInput = initNameInput()
+
+	m.nameInput.SetValue("web")
+	updated, _ := m.submitRename()
+	if got := updated.(Model).dialogError; got == "" {
+		t.Fatal("renaming to a name taken on the same remote should fail")
+	}
+
+	sessions[3].Remote = "other"
+	m.sessions = sessions
+	updated, cmd := m.submitRename()
+	if got := updated.(Model).dialogError; got != "" || cmd == nil {
+		t.Fatalf("a name used only on another host should be accepted, got error %q", got)
+	}
+}
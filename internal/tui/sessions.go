@@ -12,34 +12,49 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/stwalsh4118/navi/internal/git"
+	"github.com/stwalsh4118/navi/internal/history"
 	"github.com/stwalsh4118/navi/internal/metrics"
 	"github.com/stwalsh4118/navi/internal/pathutil"
 	"github.com/stwalsh4118/navi/internal/remote"
 	"github.com/stwalsh4118/navi/internal/resource"
 	"github.com/stwalsh4118/navi/internal/session"
+	"github.com/stwalsh4118/navi/internal/sgr"
 	"github.com/stwalsh4118/navi/internal/tokens"
+	"github.com/stwalsh4118/navi/internal/toolstats"
 )
 
 // capturePane captures the recent output from a tmux session pane.
-// Uses tmux capture-pane to retrieve the last N lines of output.
-// ANSI escape sequences are stripped for clean display.
+// Uses tmux capture-pane -e to retrieve the last N lines of output with
+// their colors. SGR sequences are kept; other escapes and control
+// characters are dropped.
 // Returns an empty string if the session doesn't exist or tmux is not running.
 func capturePane(sessionName string, lines int) (string, error) {
 	// Build the -S argument for number of lines (negative value captures from end)
 	lineArg := fmt.Sprintf("-%d", lines)
 
-	cmd := exec.Command("tmux", "capture-pane", "-t", sessionName, "-p", "-S", lineArg)
+	cmd := exec.Command("tmux", "capture-pane", "-t", sessionName, "-p", "-e", "-S", lineArg)
 	output, err := cmd.Output()
 	if err != nil {
 		// tmux returns error if session doesn't exist or server not running
 		return "", err
 	}
 
-	// Strip ANSI escape sequences for clean display
-	cleaned := StripANSI(string(output))
+	// Keep colors, drop everything else that could disturb the layout
+	cleaned := sgr.Sanitize(string(output))
 
-	// Trim trailing whitespace but preserve internal structure
-	return strings.TrimRight(cleaned, "\n\t "), nil
+	// Drop trailing blank lines but preserve internal structure
+	return sgr.TrimRight(cleaned), nil
+}
+
+// capturePaneRange captures lines start through end of a tmux session pane,
+// including scrollback, along with the pane's history size at capture time.
+// Bounds follow remote.CapturePaneRange.
+func capturePaneRange(sessionName string, start, end int) (remote.PaneRange, error) {
+	output, err := exec.Command("tmux", remote.CaptureRangeArgs(sessionName, start, end)...).Output()
+	if err != nil {
+		return remote.PaneRange{}, err
+	}
+	return remote.ParseCaptureRange(output, start)
 }
 
 // listTmuxSessions queries tmux for all active session names.
@@ -135,23 +150,89 @@ func resourceTickCmd() tea.Cmd {
 	})
 }
 
-// pollResourceMetricsCmd returns a command that polls RSS for local sessions.
-func pollResourceMetricsCmd(sessions []session.Info) tea.Cmd {
+// pollResourceMetricsCmd returns a command that polls process tree usage for local sessions.
+func pollResourceMetricsCmd(sampler *resource.Sampler, sessions []session.Info) tea.Cmd {
 	return func() tea.Msg {
 		result := make(resourcePollMsg)
 		for _, s := range sessions {
 			if s.Remote != "" {
 				continue
 			}
-			rss := resource.SessionRSS(s.TmuxSession)
-			if rss > 0 {
-				result[s.TmuxSession] = rss
+			usage := sampler.SessionUsage(s.TmuxSession)
+			if usage.ProcessCount > 0 {
+				result[s.ID()] = usage
 			}
 		}
 		return result
 	}
 }
 
+// refreshProcessTreeCmd returns a command that re-polls process tree usage for
+// the one session whose tree is open.
+func refreshProcessTreeCmd(sampler *resource.Sampler, s session.Info) tea.Cmd {
+	poll := pollResourceMetricsCmd(sampler, []session.Info{s})
+	return func() tea.Msg {
+		return processTreeRefreshMsg(poll().(resourcePollMsg))
+	}
+}
+
+// pollRemoteMetricsCmd returns a command that collects resource and token metrics
+// for remote sessions, running one batched command per remote concurrently.
+// Returns nil when there are no remote sessions.
+func pollRemoteMetricsCmd(executor remote.Executor, sessions []session.Info) tea.Cmd {
+	byRemote := make(map[string][]session.Info)
+	for _, s := range sessions {
+		if s.Remote != "" {
+			byRemote[s.Remote] = append(byRemote[s.Remote], s)
+		}
+	}
+
+	if len(byRemote) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		type result struct {
+			remote  string
+			metrics map[string]*metrics.Metrics
+			err     error
+		}
+		results := make(chan result, len(byRemote))
+
+		for remoteName, remoteSessions := range byRemote {
+			go func(remoteName string, remoteSessions []session.Info) {
+				met, err := remote.FetchMetrics(executor, remoteName, remoteSessions)
+				results <- result{remote: remoteName, metrics: met, err: err}
+			}(remoteName, remoteSessions)
+		}
+
+		msg := make(remoteMetricsMsg)
+		for i := 0; i < len(byRemote); i++ {
+			r := <-results
+			if r.err == nil {
+				msg[r.remote] = r.metrics
+			}
+		}
+		return msg
+	}
+}
+
+// killProcessCmd returns a command that sends SIGTERM to a child process of a session.
+func killProcessCmd(sessionName string, pid int) tea.Cmd {
+	return func() tea.Msg {
+		return processKillResultMsg{pid: pid, err: resource.KillProcess(sessionName, pid)}
+	}
+}
+
+// saveMetricsHistoryCmd returns a command that persists the metrics history store.
+// Errors are ignored; the next recorded sample triggers another save.
+func saveMetricsHistoryCmd(store *history.Store) tea.Cmd {
+	return func() tea.Msg {
+		_ = store.Save()
+		return nil
+	}
+}
+
 // pollGitInfoCmd returns a command that polls git info for local session working directories.
 // Git info is fetched concurrently for all local sessions to minimize latency.
 // Remote sessions are handled separately by pollRemoteGitInfoCmd.
@@ -247,6 +328,13 @@ func pollRemoteGitInfoCmd(pool *remote.SSHPool, sessions []session.Info) tea.Cmd
 	}
 }
 
+// trustHostKeyCmd returns a command that records a remote host key in known_hosts.
+func trustHostKeyCmd(pool *remote.SSHPool, prompt remote.HostKeyPrompt) tea.Cmd {
+	return func() tea.Msg {
+		return hostKeyTrustedMsg{err: pool.TrustHostKey(prompt)}
+	}
+}
+
 // killRemoteSessionCmd returns a command that kills a remote tmux session via SSH.
 func killRemoteSessionCmd(pool *remote.SSHPool, remoteName, sessionName string) tea.Cmd {
 	return func() tea.Msg {
@@ -286,11 +374,20 @@ func dismissRemoteSessionCmd(pool *remote.SSHPool, remoteName, sessionName strin
 // captureRemotePreviewCmd returns a command that captures preview content from a remote tmux session via SSH.
 func captureRemotePreviewCmd(pool *remote.SSHPool, remoteName, sessionName string) tea.Cmd {
 	return func() tea.Msg {
-		content, err := remote.CapturePane(pool, remoteName, sessionName, previewDefaultLines)
+		r, err := remote.CapturePaneRange(pool, remoteName, sessionName, -previewDefaultLines, remote.PaneBottom)
 		if err != nil {
 			return previewContentMsg{content: "Failed to fetch remote preview: " + err.Error()}
 		}
-		return previewContentMsg{content: content}
+		return previewTailMsg(r)
+	}
+}
+
+// previewTailMsg turns a capture of the pane's tail into a previewContentMsg.
+func previewTailMsg(r remote.PaneRange) previewContentMsg {
+	return previewContentMsg{
+		content:     sgr.TrimRight(strings.Join(r.Lines, "\n")),
+		start:       r.Start,
+		historySize: r.HistorySize,
 	}
 }
 
@@ -325,6 +422,25 @@ func fetchPRCmd(cwd string) tea.Cmd {
 	}
 }
 
+// fetchToolStatsCmd returns a command that aggregates tool call timing for a session
+// and for all sessions working in the same project directory.
+func fetchToolStatsCmd(sessionName, cwd string) tea.Cmd {
+	return func() tea.Msg {
+		statusDir := pathutil.ExpandPath(session.StatusDir)
+		msg := toolStatsMsg{sessionName: sessionName}
+
+		if calls, err := toolstats.ReadSessionCalls(statusDir, sessionName); err == nil {
+			msg.session = toolstats.Summarize(calls)
+		}
+		if cwd != "" {
+			if calls, err := toolstats.ReadAllCalls(statusDir); err == nil {
+				msg.project = toolstats.Summarize(toolstats.FilterProject(calls, pathutil.ExpandPath(cwd)))
+			}
+		}
+		return msg
+	}
+}
+
 // fetchPRCommentsCmd fetches PR comments asynchronously.
 func fetchPRCommentsCmd(cwd string, prNum int) tea.Cmd {
 	return func() tea.Msg {
@@ -428,65 +544,53 @@ func dismissSession(s session.Info) error {
 // If skipPermissions is true, claude is started with --dangerously-skip-permissions.
 func createSessionCmd(name, dir string, skipPermissions bool) tea.Cmd {
 	return func() tea.Msg {
-		// Create tmux session with a shell (no command)
-		cmd := exec.Command("tmux", "new-session", "-d", "-s", name, "-c", dir)
-		err := cmd.Run()
-		if err != nil {
-			return createSessionResultMsg{err: err}
-		}
-
-		// Send keys to start claude - session stays open when claude exits
-		claudeCmd := "claude"
-		if skipPermissions {
-			claudeCmd = "claude --dangerously-skip-permissions"
-		}
-		sendKeys := exec.Command("tmux", "send-keys", "-t", name, claudeCmd, "Enter")
-		sendKeys.Run() // Ignore error - session is created, claude just won't auto-start
-
-		// Create initial status file so session appears immediately in UI
-		statusDirPath := pathutil.ExpandPath(session.StatusDir)
-		// Ensure directory exists
-		os.MkdirAll(statusDirPath, 0755)
-
-		s := session.Info{
-			TmuxSession: name,
-			Status:      session.StatusWorking,
-			Message:     "",
-			CWD:         dir,
-			Timestamp:   time.Now().Unix(),
-		}
+		err := session.Create(name, dir, skipPermissions, pathutil.ExpandPath(session.StatusDir))
+		return createSessionResultMsg{err: err}
+	}
+}
 
-		data, err := json.MarshalIndent(s, "", "  ")
-		if err != nil {
-			// Session was created, but we couldn't marshal the status
-			// Return success anyway - the hook will create it later
-			return createSessionResultMsg{err: nil}
+// createRemoteSessionCmd returns a command that creates a tmux session on a remote via SSH.
+func createRemoteSessionCmd(pool *remote.SSHPool, remoteName, name, dir string, skipPermissions bool) tea.Cmd {
+	return func() tea.Msg {
+		config := pool.GetRemoteConfig(remoteName)
+		if config == nil {
+			return createSessionResultMsg{err: fmt.Errorf("remote %q not found", remoteName)}
 		}
-
-		statusPath := filepath.Join(statusDirPath, name+".json")
-		os.WriteFile(statusPath, data, 0644) // Ignore error - hook will create it later if needed
-
-		return createSessionResultMsg{err: nil}
+		err := remote.CreateSession(pool, remoteName, name, dir, skipPermissions, config.SessionsDir)
+		return createSessionResultMsg{err: err, remote: remoteName}
 	}
 }
 
 // killSessionCmd returns a command that kills a tmux session and cleans up its status file.
 func killSessionCmd(name string) tea.Cmd {
 	return func() tea.Msg {
-		// Kill tmux session: tmux kill-session -t <name>
-		cmd := exec.Command("tmux", "kill-session", "-t", name)
-		err := cmd.Run()
-		if err != nil {
-			return killSessionResultMsg{err: err}
-		}
+		return killSessionResultMsg{err: killSession(name)}
+	}
+}
 
-		// Delete status file
-		dir := pathutil.ExpandPath(session.StatusDir)
-		statusPath := filepath.Join(dir, name+".json")
-		os.Remove(statusPath) // Ignore error - file may not exist
+// killSession kills a local tmux session and removes its status file.
+func killSession(name string) error {
+	// Kill tmux session: tmux kill-session -t <name>
+	cmd := exec.Command("tmux", "kill-session", "-t", name)
+	if err := cmd.Run(); err != nil {
+		return err
+	}
 
-		return killSessionResultMsg{err: nil}
+	// Delete status file
+	dir := pathutil.ExpandPath(session.StatusDir)
+	statusPath := filepath.Join(dir, name+".json")
+	os.Remove(statusPath) // Ignore error - file may not exist
+
+	return nil
+}
+
+// sendPrompt types a prompt into a local tmux session and presses Enter.
+// The prompt is sent literally so words such as "Enter" are not read as keys.
+func sendPrompt(name, prompt string) error {
+	if err := exec.Command("tmux", "send-keys", "-t", name, "-l", prompt).Run(); err != nil {
+		return err
 	}
+	return exec.Command("tmux", "send-keys", "-t", name, "Enter").Run()
 }
 
 // renameSessionCmd returns a command that renames a tmux session and its status file.
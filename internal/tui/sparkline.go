@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/stwalsh4118/navi/internal/history"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+// sparkBlocks are the block characters used for sparklines, lowest to highest.
+var sparkBlocks = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// Sparkline and chart dimension constants
+const (
+	// sessionSparklineWidth is the number of columns for sparklines in the session row.
+	sessionSparklineWidth = 12
+
+	// metricsChartHeight is the number of rows for charts in the metrics detail dialog.
+	metricsChartHeight = 3
+
+	// timelineBlock is the character used for each sample in the status timeline.
+	timelineBlock = "█"
+)
+
+// tailValues returns the last n values.
+func tailValues(values []float64, n int) []float64 {
+	if n <= 0 {
+		return nil
+	}
+	if len(values) > n {
+		return values[len(values)-n:]
+	}
+	return values
+}
+
+// maxValue returns the largest value in the slice (0 for empty).
+func maxValue(values []float64) float64 {
+	max := 0.0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+// renderSparkline renders the last width values as a single-row sparkline.
+// Returns empty string when there are fewer than two values.
+func renderSparkline(values []float64, width int) string {
+	values = tailValues(values, width)
+	if len(values) < 2 {
+		return ""
+	}
+
+	max := maxValue(values)
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if max > 0 && v > 0 {
+			idx = int(v / max * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
+
+// renderChart renders the last width values as a multi-row bar chart.
+// Each row covers an equal slice of the value range, using eighth blocks for partial fills.
+// Rows are returned top to bottom.
+func renderChart(values []float64, width, height int) []string {
+	values = tailValues(values, width)
+	if len(values) == 0 || height <= 0 {
+		return nil
+	}
+
+	max := maxValue(values)
+	levels := len(sparkBlocks)
+	rows := make([]string, height)
+	for row := 0; row < height; row++ {
+		var b strings.Builder
+		// rowFloor is the number of eighth-blocks below this row.
+		rowFloor := (height - 1 - row) * levels
+		for _, v := range values {
+			filled := 0
+			if max > 0 {
+				filled = int(v / max * float64(height*levels))
+			}
+			switch {
+			case filled >= rowFloor+levels:
+				b.WriteRune(sparkBlocks[levels-1])
+			case filled > rowFloor:
+				b.WriteRune(sparkBlocks[filled-rowFloor-1])
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		rows[row] = b.String()
+	}
+	return rows
+}
+
+// renderStatusTimeline renders one colored block per status, keeping the newest width entries.
+func renderStatusTimeline(statuses []string, width int) string {
+	if len(statuses) > width {
+		statuses = statuses[len(statuses)-width:]
+	}
+
+	var b strings.Builder
+	for _, status := range statuses {
+		b.WriteString(statusTimelineStyle(status).Render(timelineBlock))
+	}
+	return b.String()
+}
+
+// renderSessionSparklines returns the compact token-rate and memory sparklines for a session row.
+func (m Model) renderSessionSparklines(s session.Info) string {
+	samples := m.metricsHistory.Series(s.ID())
+	if len(samples) < 2 {
+		return ""
+	}
+
+	var parts []string
+	if rates := history.TokenRate(samples); maxValue(rates) > 0 {
+		if spark := renderSparkline(rates, sessionSparklineWidth); spark != "" {
+			parts = append(parts, "tok/m "+spark)
+		}
+	}
+	if rss := history.RSSValues(samples); maxValue(rss) > 0 {
+		if spark := renderSparkline(rss, sessionSparklineWidth); spark != "" {
+			parts = append(parts, "mem "+spark)
+		}
+	}
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/stwalsh4118/navi/internal/history"
+	"github.com/stwalsh4118/navi/internal/metrics"
+	"github.com/stwalsh4118/navi/internal/session"
+)
+
+func TestRenderSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		want   string
+	}{
+		{"too few values", []float64{1}, 10, ""},
+		{"ascending", []float64{0, 7}, 10, "▁█"},
+		{"all zero", []float64{0, 0, 0}, 10, "▁▁▁"},
+		{"truncated to width keeps newest", []float64{7, 0, 7}, 2, "▁█"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderSparkline(tt.values, tt.width); got != tt.want {
+				t.Errorf("renderSparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderChart(t *testing.T) {
+	rows := renderChart([]float64{0, 5, 10}, 10, 2)
+	if len(rows) != 2 {
+		t.Fatalf("len(rows) = %d, want 2", len(rows))
+	}
+	// Peak value fills both rows, half value fills only the bottom row, zero is blank.
+	if rows[0] != "  █" {
+		t.Errorf("top row = %q, want %q", rows[0], "  █")
+	}
+	if rows[1] != " ██" {
+		t.Errorf("bottom row = %q, want %q", rows[1], " ██")
+	}
+
+	if renderChart(nil, 10, 2) != nil {
+		t.Error("expected nil chart for empty values")
+	}
+}
+
+func TestRenderStatusTimeline_KeepsNewest(t *testing.T) {
+	got := renderStatusTimeline([]string{"working", "waiting", "done"}, 2)
+	if lipgloss.Width(got) != 2 {
+		t.Errorf("timeline width = %d, want 2", lipgloss.Width(got))
+	}
+}
+
+func newHistoryTestModel(t *testing.T) Model {
+	t.Helper()
+	return Model{
+		width:          120,
+		height:         40,
+		metricsHistory: history.NewStore(filepath.Join(t.TempDir(), "history.json")),
+		sessions: []session.Info{{
+			TmuxSession: "api",
+			Status:      session.StatusWorking,
+			Metrics: &metrics.Metrics{
+				Tokens:   &metrics.TokenMetrics{Total: 1000},
+				Resource: &metrics.ResourceMetrics{RSSBytes: 64 * 1024 * 1024},
+			},
+		}},
+	}
+}
+
+func TestRecordMetricsHistory(t *testing.T) {
+	m := newHistoryTestModel(t)
+	now := time.Now()
+
+	if !m.recordMetricsHistory(now) {
+		t.Fatal("expected first sample to be recorded")
+	}
+	if m.recordMetricsHistory(now.Add(time.Second)) {
+		t.Error("expected sample within interval to be skipped")
+	}
+
+	series := m.metricsHistory.Series("api")
+	if len(series) != 1 {
+		t.Fatalf("len(series) = %d, want 1", len(series))
+	}
+	if series[0].Tokens != 1000 || series[0].RSSBytes != 64*1024*1024 || series[0].Status != session.StatusWorking {
+		t.Errorf("unexpected sample: %+v", series[0])
+	}
+}
+
+func TestSessionRowShowsSparklines(t *testing.T) {
+	m := newHistoryTestModel(t)
+	now := time.Now()
+	m.recordMetricsHistory(now)
+	m.sessions[0].Metrics.Tokens.Total = 5000
+	m.sessions[0].Metrics.Resource.RSSBytes = 128 * 1024 * 1024
+	m.recordMetricsHistory(now.Add(history.SampleInterval))
+	m.sessions[0].Metrics.Tokens.Total = 6000
+	m.recordMetricsHistory(now.Add(2 * history.SampleInterval))
+
+	row := m.renderSession(m.sessions[0], false, 120)
+	if !strings.Contains(row, "tok/m") {
+		t.Errorf("expected token rate sparkline in row, got:\n%s", row)
+	}
+	if !strings.Contains(row, "mem") {
+		t.Errorf("expected memory sparkline in row, got:\n%s", row)
+	}
+}
+
+func TestMetricsDetailShowsTrends(t *testing.T) {
+	m := newHistoryTestModel(t)
+	now := time.Now()
+	m.recordMetricsHistory(now)
+	m.sessions[0].Metrics.Tokens.Total = 4000
+	m.recordMetricsHistory(now.Add(history.SampleInterval))
+
+	m.sessionToModify = &m.sessions[0]
+	m.dialogMode = DialogMetricsDetail
+	view := m.renderMetricsDetailView()
+
+	for _, want := range []string{"Trends", "Tokens/min", "Memory", "Status"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in metrics detail view", want)
+		}
+	}
+}
+
+func TestMetricsDetailNoTrendsWithoutHistory(t *testing.T) {
+	m := newHistoryTestModel(t)
+	m.sessionToModify = &m.sessions[0]
+	view := m.renderMetricsDetailView()
+	if strings.Contains(view, "Trends") {
+		t.Error("did not expect Trends section without history samples")
+	}
+}
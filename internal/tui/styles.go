@@ -1,154 +1,223 @@
 package tui
 
 import (
+	"fmt"
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/stwalsh4118/navi/internal/theme"
 )
 
 // Status icon color styles
 var (
-	yellowStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("226")) // waiting
-	greenStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("46"))  // done
-	magentaStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("201")) // permission
-	cyanStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("51"))  // working
-	redStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("196")) // error
-	grayStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("245")) // offline
-	dimStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("241")) // unknown
+	yellowStyle  lipgloss.Style // waiting
+	greenStyle   lipgloss.Style // done
+	magentaStyle lipgloss.Style // permission
+	cyanStyle    lipgloss.Style // working
+	redStyle     lipgloss.Style // error
+	grayStyle    lipgloss.Style // offline
+	dimStyle     lipgloss.Style // unknown
 )
 
 // Text styles
 var (
 	boldStyle      = lipgloss.NewStyle().Bold(true)
 	italicStyle    = lipgloss.NewStyle().Italic(true)
-	highlightStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("99")) // focused input highlight
+	highlightStyle lipgloss.Style // focused input highlight
 )
 
 // Row styles
-var (
-	selectedStyle = lipgloss.NewStyle().
-		Background(lipgloss.Color("236")).
-		Bold(true)
-)
+var selectedStyle lipgloss.Style
 
 // Box styles for header/footer
-var (
-	boxStyle = lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
-		Padding(0, 1)
-)
+var boxStyle lipgloss.Style
 
 // Dialog styles
 var (
-	dialogBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("99")).
-			Padding(1, 2)
-
-	dialogTitleStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("99"))
-
-	dialogErrorStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("196"))
+	dialogBoxStyle   lipgloss.Style
+	dialogTitleStyle lipgloss.Style
+	dialogErrorStyle lipgloss.Style
 )
 
 // Preview pane styles
 var (
-	previewBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("99")).
-			Padding(0, 1)
-
-	previewHeaderStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("99"))
-
-	previewEmptyStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("241")).
-				Italic(true)
-
-	previewFocusedBoxStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("46")).
-				Padding(0, 1)
-
-	pmBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("240")).
-			Padding(0, 1)
-
-	pmFocusedBoxStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("46")).
-				Padding(0, 1)
-
-	pmHeaderStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("46"))
+	previewBoxStyle        lipgloss.Style
+	previewHeaderStyle     lipgloss.Style
+	previewEmptyStyle      lipgloss.Style
+	previewFocusedBoxStyle lipgloss.Style
+	pmBoxStyle             lipgloss.Style
+	pmFocusedBoxStyle      lipgloss.Style
+	pmHeaderStyle          lipgloss.Style
 )
 
 // Search bar style
-var searchBarStyle = lipgloss.NewStyle().
-	Border(lipgloss.RoundedBorder()).
-	BorderForeground(lipgloss.Color("99")).
-	Padding(0, 1)
+var searchBarStyle lipgloss.Style
 
 // Search match styles
 var (
 	// searchMatchIndicatorStyle is used for the left-side gutter indicator on non-current matches.
-	searchMatchIndicatorStyle = lipgloss.NewStyle().
-					Foreground(lipgloss.Color("226")) // Yellow indicator bar
+	searchMatchIndicatorStyle lipgloss.Style
 
 	// searchCurrentMatchIndicatorStyle is used for the left-side gutter indicator on the current match.
-	searchCurrentMatchIndicatorStyle = lipgloss.NewStyle().
-						Foreground(lipgloss.Color("226")). // Bright yellow indicator bar
-						Bold(true)
+	searchCurrentMatchIndicatorStyle lipgloss.Style
 
-	searchMatchCountStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("99")).
-				Bold(true)
+	searchMatchCountStyle lipgloss.Style
+	searchNoMatchStyle    lipgloss.Style
 
-	searchNoMatchStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("196")).
-				Bold(true)
+	// previewMatchStyle highlights the current search match in the preview pane.
+	previewMatchStyle lipgloss.Style
 )
 
 // Filter indicator style
-var filterActiveStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("99")).
-	Bold(true)
+var filterActiveStyle lipgloss.Style
 
 // Task panel styles
 var (
-	taskPanelBoxStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("240")).
-				Padding(0, 1)
+	taskPanelBoxStyle        lipgloss.Style
+	taskPanelFocusedBoxStyle lipgloss.Style
+	taskGroupStyle           = lipgloss.NewStyle().Bold(true)
+	taskGroupChevronStyle    lipgloss.Style
+	taskIDStyle              lipgloss.Style
+	taskEmptyStyle           lipgloss.Style
+	taskErrorStyle           lipgloss.Style
+)
 
-	taskPanelFocusedBoxStyle = lipgloss.NewStyle().
-					Border(lipgloss.RoundedBorder()).
-					BorderForeground(lipgloss.Color("99")).
-					Padding(0, 1)
+// Diff line styles
+var (
+	diffAddStyle    lipgloss.Style
+	diffRemoveStyle lipgloss.Style
+	diffHunkStyle   lipgloss.Style
+	diffMetaStyle   lipgloss.Style
+)
 
-	taskGroupStyle = lipgloss.NewStyle().
-			Bold(true)
+func init() {
+	applyTheme(theme.Default(), theme.ModeFull)
+}
 
-	taskGroupChevronStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("99"))
+// applyTheme rebuilds every style from the theme's palette for mode.
+func applyTheme(t *theme.Theme, mode theme.Mode) {
+	p := t.Palette(mode)
+	fg := func(c string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(themeColor(c))
+	}
+	box := func(c string, vertical, horizontal int) lipgloss.Style {
+		return lipgloss.NewStyle().
+			Border(themeBorder(t.Border)).
+			BorderForeground(themeColor(c)).
+			Padding(vertical, horizontal)
+	}
 
-	taskIDStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241"))
+	yellowStyle = fg(p.Waiting)
+	greenStyle = fg(p.Done)
+	magentaStyle = fg(p.Permission)
+	cyanStyle = fg(p.Working)
+	redStyle = fg(p.Error)
+	grayStyle = fg(p.Offline)
+	dimStyle = fg(p.Muted)
+	highlightStyle = fg(p.Accent)
+
+	selectedStyle = lipgloss.NewStyle().Bold(true)
+	if p.Selection == theme.SelectionReverse {
+		selectedStyle = selectedStyle.Reverse(true)
+	} else {
+		selectedStyle = selectedStyle.Background(themeColor(p.Selection))
+	}
 
-	taskEmptyStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
-			Italic(true)
+	boxStyle = box(p.Border, 0, 1)
+
+	dialogBoxStyle = box(p.Accent, 1, 2)
+	dialogTitleStyle = fg(p.Accent).Bold(true)
+	dialogErrorStyle = fg(p.Error)
+
+	previewBoxStyle = box(p.Accent, 0, 1)
+	previewHeaderStyle = fg(p.Accent).Bold(true)
+	previewEmptyStyle = fg(p.Muted).Italic(true)
+	previewFocusedBoxStyle = box(p.Focus, 0, 1)
+	pmBoxStyle = box(p.Border, 0, 1)
+	pmFocusedBoxStyle = box(p.Focus, 0, 1)
+	pmHeaderStyle = fg(p.Focus).Bold(true)
+
+	searchBarStyle = box(p.Accent, 0, 1)
+	searchMatchIndicatorStyle = fg(p.Match)
+	searchCurrentMatchIndicatorStyle = fg(p.Match).Bold(true)
+	searchMatchCountStyle = fg(p.Accent).Bold(true)
+	searchNoMatchStyle = fg(p.Error).Bold(true)
+	previewMatchStyle = fg(p.Match).Reverse(true)
+
+	filterActiveStyle = fg(p.Accent).Bold(true)
+
+	taskPanelBoxStyle = box(p.Border, 0, 1)
+	taskPanelFocusedBoxStyle = box(p.Accent, 0, 1)
+	taskGroupChevronStyle = fg(p.Accent)
+	taskIDStyle = fg(p.Muted)
+	taskEmptyStyle = fg(p.Muted).Italic(true)
+	taskErrorStyle = fg(p.Error).Italic(true)
+
+	diffAddStyle = fg(p.DiffAdd)
+	diffRemoveStyle = fg(p.DiffRemove)
+	diffHunkStyle = fg(p.DiffHunk)
+	diffMetaStyle = fg(p.DiffMeta)
+}
 
-	taskErrorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")).
-			Italic(true)
-)
+// themeColor converts a palette color; an empty color leaves the terminal default.
+func themeColor(c string) lipgloss.TerminalColor {
+	if c == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(c)
+}
+
+// themeBorder returns the lipgloss border for a theme border name.
+func themeBorder(name string) lipgloss.Border {
+	switch name {
+	case theme.BorderNormal:
+		return lipgloss.NormalBorder()
+	case theme.BorderThick:
+		return lipgloss.ThickBorder()
+	case theme.BorderDouble:
+		return lipgloss.DoubleBorder()
+	case theme.BorderHidden:
+		return lipgloss.HiddenBorder()
+	default:
+		return lipgloss.RoundedBorder()
+	}
+}
+
+// detectColorMode picks the palette for the terminal: none with NO_COLOR,
+// the 16-color fallback on basic terminals, otherwise the full palette.
+// NO_COLOR on a terminal keeps lipgloss at the ANSI profile so bold and
+// reverse video still render; the empty palette keeps colors out.
+func detectColorMode() theme.Mode {
+	if os.Getenv("NO_COLOR") != "" {
+		if termenv.NewOutput(os.Stdout).ColorProfile() != termenv.Ascii {
+			lipgloss.SetColorProfile(termenv.ANSI)
+		}
+		return theme.ModeNoColor
+	}
+
+	switch lipgloss.ColorProfile() {
+	case termenv.Ascii:
+		return theme.ModeNoColor
+	case termenv.ANSI:
+		return theme.Mode16
+	default:
+		return theme.ModeFull
+	}
+}
+
+// loadTheme loads the user's color theme. Errors are logged and the default
+// theme used.
+func loadTheme() *theme.Theme {
+	t, err := theme.Load(theme.DefaultConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load theme: %v\n", err)
+		return theme.Default()
+	}
+	return t
+}
 
 // TaskStatusBadge returns a styled status badge for a task status string.
 func TaskStatusBadge(status string) string {
@@ -211,3 +280,23 @@ func StatusIcon(status string) string {
 		return dimStyle.Render(iconUnknown)
 	}
 }
+
+// statusTimelineStyle returns the color style used for a status in the metrics timeline.
+func statusTimelineStyle(status string) lipgloss.Style {
+	switch status {
+	case "waiting":
+		return yellowStyle
+	case "done":
+		return greenStyle
+	case "permission":
+		return magentaStyle
+	case "working":
+		return cyanStyle
+	case "error":
+		return redStyle
+	case "offline", "idle", "stopped":
+		return grayStyle
+	default:
+		return dimStyle
+	}
+}
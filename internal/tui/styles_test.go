@@ -3,6 +3,10 @@ package tui
 import (
 	"strings"
 	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/stwalsh4118/navi/internal/theme"
 )
 
 func TestStatusIcon(t *testing.T) {
@@ -29,3 +33,39 @@ func TestStatusIcon(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyTheme(t *testing.T) {
+	t.Cleanup(func() { applyTheme(theme.Default(), theme.ModeFull) })
+
+	light, _ := theme.Builtin(theme.Light)
+	applyTheme(light, theme.ModeFull)
+	if got := cyanStyle.GetForeground(); got != lipgloss.Color(light.Colors.Working) {
+		t.Errorf("working color = %v, want %s", got, light.Colors.Working)
+	}
+	if got := diffAddStyle.GetForeground(); got != lipgloss.Color(light.Colors.DiffAdd) {
+		t.Errorf("diff add color = %v, want %s", got, light.Colors.DiffAdd)
+	}
+	if got := selectedStyle.GetBackground(); got != lipgloss.Color(light.Colors.Selection) {
+		t.Errorf("selection background = %v, want %s", got, light.Colors.Selection)
+	}
+
+	applyTheme(light, theme.Mode16)
+	if got := cyanStyle.GetForeground(); got != lipgloss.Color(light.Colors16.Working) {
+		t.Errorf("16-color working color = %v, want %s", got, light.Colors16.Working)
+	}
+	if !selectedStyle.GetReverse() {
+		t.Error("16-color selection should use reverse video")
+	}
+
+	hc, _ := theme.Builtin(theme.HighContrast)
+	applyTheme(hc, theme.ModeNoColor)
+	if _, ok := cyanStyle.GetForeground().(lipgloss.NoColor); !ok {
+		t.Errorf("no-color working color = %v, want none", cyanStyle.GetForeground())
+	}
+	if !selectedStyle.GetReverse() || !selectedStyle.GetBold() {
+		t.Error("no-color selection should be bold reverse video")
+	}
+	if got := boxStyle.GetBorderStyle(); got != lipgloss.ThickBorder() {
+		t.Errorf("border = %+v, want thick", got)
+	}
+}
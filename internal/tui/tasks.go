@@ -1,12 +1,16 @@
 package tui
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/stwalsh4118/navi/internal/debug"
+	"github.com/stwalsh4118/navi/internal/remote"
 	"github.com/stwalsh4118/navi/internal/task"
 )
 
@@ -20,8 +24,8 @@ const (
 
 // tasksMsg carries refreshed task data from provider execution.
 type tasksMsg struct {
-	groupsByProject  map[string][]task.TaskGroup // keyed by project dir
-	errors           map[string]error            // keyed by project dir
+	groupsByProject  map[string][]task.TaskGroup // keyed by project key (see task.ProjectKey)
+	errors           map[string]error            // keyed by project key
 	resultsByProject map[string]*task.ProviderResult
 }
 
@@ -30,11 +34,20 @@ type taskConfigsMsg struct {
 	configs []task.ProjectConfig
 }
 
+// remoteTaskFileMsg carries a task file read from a remote project.
+type remoteTaskFileMsg struct {
+	title   string
+	path    string
+	content []byte
+	err     error
+}
+
 // taskTickMsg triggers periodic task data refresh.
 type taskTickMsg time.Time
 
 // taskRefreshCmd runs all discovered providers and returns results keyed by project.
-func taskRefreshCmd(configs []task.ProjectConfig, cache *task.ResultCache, globalConfig *task.GlobalConfig, timeout time.Duration) tea.Cmd {
+// Providers of remote projects run on their remote through pool.
+func taskRefreshCmd(configs []task.ProjectConfig, cache *task.ResultCache, globalConfig *task.GlobalConfig, timeout time.Duration, pool *remote.SSHPool) tea.Cmd {
 	return func() tea.Msg {
 		groupsByProject := make(map[string][]task.TaskGroup)
 		errors := make(map[string]error)
@@ -51,20 +64,21 @@ func taskRefreshCmd(configs []task.ProjectConfig, cache *task.ResultCache, globa
 		seenProjects := make(map[string]bool, len(configs))
 
 		for _, cfg := range configs {
-			if seenProjects[cfg.ProjectDir] {
+			key := cfg.Key()
+			if seenProjects[key] {
 				continue
 			}
-			seenProjects[cfg.ProjectDir] = true
+			seenProjects[key] = true
 
 			// Check cache first
 			if cache != nil {
-				if cached, ok := cache.Get(cfg.ProjectDir, taskDefaultRefreshInterval); ok {
+				if cached, ok := cache.Get(key, taskDefaultRefreshInterval); ok {
 					if cached.Error != nil {
-						errors[cfg.ProjectDir] = cached.Error
+						errors[key] = cached.Error
 					} else if cached.Result != nil {
 						groups := normalizeGroups(cached.Result, cfg, globalConfig)
-						groupsByProject[cfg.ProjectDir] = groups
-						resultsByProject[cfg.ProjectDir] = cached.Result
+						groupsByProject[key] = groups
+						resultsByProject[key] = cached.Result
 					}
 					continue
 				}
@@ -83,11 +97,11 @@ func taskRefreshCmd(configs []task.ProjectConfig, cache *task.ResultCache, globa
 				go func(index int, projectCfg task.ProjectConfig) {
 					defer wg.Done()
 					semaphore <- struct{}{}
-					result, err := task.ExecuteProvider(projectCfg, timeout)
+					result, err := executeProvider(pool, projectCfg, timeout)
 					<-semaphore
 
 					if cache != nil {
-						cache.Set(projectCfg.ProjectDir, result, err)
+						cache.Set(projectCfg.Key(), result, err)
 					}
 
 					resultCh <- providerExecResult{
@@ -108,14 +122,15 @@ func taskRefreshCmd(configs []task.ProjectConfig, cache *task.ResultCache, globa
 			}
 
 			for _, execResult := range execResults {
+				key := execResult.cfg.Key()
 				if execResult.err != nil {
-					errors[execResult.cfg.ProjectDir] = execResult.err
+					errors[key] = execResult.err
 					continue
 				}
 
 				groups := normalizeGroups(execResult.result, execResult.cfg, globalConfig)
-				groupsByProject[execResult.cfg.ProjectDir] = groups
-				resultsByProject[execResult.cfg.ProjectDir] = execResult.result
+				groupsByProject[key] = groups
+				resultsByProject[key] = execResult.result
 			}
 		}
 
@@ -123,6 +138,39 @@ func taskRefreshCmd(configs []task.ProjectConfig, cache *task.ResultCache, globa
 	}
 }
 
+// executeProvider runs a project's provider locally, or on its remote for
+// remote projects.
+func executeProvider(pool *remote.SSHPool, cfg task.ProjectConfig, timeout time.Duration) (*task.ProviderResult, error) {
+	if cfg.Remote == "" {
+		return task.ExecuteProvider(cfg, timeout)
+	}
+	if pool == nil {
+		return nil, &task.ProviderError{Type: task.ErrExec, Message: "remote " + cfg.Remote + " is not configured"}
+	}
+	return remote.ExecuteTaskProvider(pool, cfg, timeout)
+}
+
+// readRemoteTaskFileCmd reads a task file from a remote project.
+func readRemoteTaskFileCmd(pool *remote.SSHPool, remoteName, title, filePath string) tea.Cmd {
+	return func() tea.Msg {
+		if pool == nil {
+			return remoteTaskFileMsg{title: title, path: filePath, err: fmt.Errorf("remote %s is not configured", remoteName)}
+		}
+		content, err := remote.ReadFile(pool, remoteName, filePath)
+		return remoteTaskFileMsg{title: title, path: remoteName + ":" + filePath, content: content, err: err}
+	}
+}
+
+// taskProjectConfig returns the discovered config for a project key, or nil.
+func (m Model) taskProjectConfig(key string) *task.ProjectConfig {
+	for i := range m.taskProjectConfigs {
+		if m.taskProjectConfigs[i].Key() == key {
+			return &m.taskProjectConfigs[i]
+		}
+	}
+	return nil
+}
+
 // normalizeGroups applies status normalization to a provider result and returns groups.
 // If the result has no groups (flat format), wraps tasks in a single group named after the project.
 func normalizeGroups(result *task.ProviderResult, cfg task.ProjectConfig, globalConfig *task.GlobalConfig) []task.TaskGroup {
@@ -176,47 +224,90 @@ func taskTickCmd(interval time.Duration) tea.Cmd {
 	})
 }
 
-// discoverTaskConfigsCmd discovers project configs from session CWDs.
-func discoverTaskConfigsCmd(sessions []sessionCWD, globalConfig *task.GlobalConfig) tea.Cmd {
+// discoverTaskConfigsCmd discovers project configs from session CWDs. Remote
+// session CWDs are searched on their remote through pool.
+func discoverTaskConfigsCmd(sessions []sessionCWD, globalConfig *task.GlobalConfig, pool *remote.SSHPool) tea.Cmd {
 	return func() tea.Msg {
 		var cwds []string
+		remoteCWDs := make(map[string][]string)
 		for _, s := range sessions {
-			if s.cwd != "" {
-				cwds = append(cwds, s.cwd)
+			if s.cwd == "" {
+				continue
 			}
+			if s.remote != "" {
+				remoteCWDs[s.remote] = append(remoteCWDs[s.remote], s.cwd)
+				continue
+			}
+			cwds = append(cwds, s.cwd)
 		}
 		configs := task.DiscoverProjects(cwds, globalConfig)
+
+		if pool != nil && len(remoteCWDs) > 0 {
+			remoteNames := make([]string, 0, len(remoteCWDs))
+			for name := range remoteCWDs {
+				remoteNames = append(remoteNames, name)
+			}
+			sort.Strings(remoteNames)
+
+			// One discovery round trip per remote, run in parallel
+			results := make([][]task.ProjectConfig, len(remoteNames))
+			var wg sync.WaitGroup
+			for i, name := range remoteNames {
+				wg.Add(1)
+				go func(i int, name string) {
+					defer wg.Done()
+					found, err := remote.DiscoverTaskProjects(pool, name, remoteCWDs[name], globalConfig)
+					if err != nil {
+						debug.Log("remote[%s]: task discovery failed: %v", name, err)
+						return
+					}
+					results[i] = found
+				}(i, name)
+			}
+			wg.Wait()
+			for _, found := range results {
+				configs = append(configs, found...)
+			}
+		}
+
 		return taskConfigsMsg{configs: configs}
 	}
 }
 
 // sessionCWD is a lightweight struct for passing session CWDs to config discovery.
 type sessionCWD struct {
-	cwd string
+	cwd    string
+	remote string // remote host; empty for local sessions
 }
 
-// extractSessionCWDs extracts unique CWDs from the current session list.
+// extractSessionCWDs extracts unique CWDs from the current session list,
+// prefixed with the remote name for remote sessions (see task.ProjectKey).
 func extractSessionCWDs(sessions []sessionCWD) []string {
 	seen := make(map[string]bool)
 	var cwds []string
 	for _, s := range sessions {
-		if s.cwd != "" && !seen[s.cwd] {
-			seen[s.cwd] = true
-			cwds = append(cwds, s.cwd)
+		key := task.ProjectKey(s.remote, s.cwd)
+		if s.cwd != "" && !seen[key] {
+			seen[key] = true
+			cwds = append(cwds, key)
 		}
 	}
 	return cwds
 }
 
-// findProjectForCWD returns the project dir that contains the given CWD,
-// or empty string if no matching project config is found.
-func findProjectForCWD(cwd string, configs []task.ProjectConfig) string {
+// findProjectForCWD returns the key of the project that contains the given
+// CWD on the given remote (empty for local), or empty string if no matching
+// project config is found.
+func findProjectForCWD(remoteName, cwd string, configs []task.ProjectConfig) string {
 	if cwd == "" {
 		return ""
 	}
 	for _, cfg := range configs {
+		if cfg.Remote != remoteName {
+			continue
+		}
 		if cwd == cfg.ProjectDir || strings.HasPrefix(cwd, cfg.ProjectDir+"/") {
-			return cfg.ProjectDir
+			return cfg.Key()
 		}
 	}
 	return ""
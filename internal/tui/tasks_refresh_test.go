@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stwalsh4118/navi/internal/remote"
 	"github.com/stwalsh4118/navi/internal/task"
 )
 
@@ -26,7 +27,7 @@ func TestTaskRefreshCmd_AllCachedSkipsProviderExecution(t *testing.T) {
 		{ProjectDir: projectB, Tasks: task.ProjectTaskConfig{Provider: "missing-provider.sh"}},
 	}
 
-	msg := taskRefreshCmd(configs, cache, globalConfig, time.Second)().(tasksMsg)
+	msg := taskRefreshCmd(configs, cache, globalConfig, time.Second, nil)().(tasksMsg)
 
 	if len(msg.errors) != 0 {
 		t.Fatalf("expected no errors when all projects are cached, got %d", len(msg.errors))
@@ -63,7 +64,7 @@ exit 1
 		{ProjectDir: errorProject, Tasks: task.ProjectTaskConfig{Provider: "provider.sh"}},
 	}
 
-	msg := taskRefreshCmd(configs, cache, globalConfig, 3*time.Second)().(tasksMsg)
+	msg := taskRefreshCmd(configs, cache, globalConfig, 3*time.Second, nil)().(tasksMsg)
 
 	if len(msg.resultsByProject) != 2 {
 		t.Fatalf("expected 2 successful project results, got %d", len(msg.resultsByProject))
@@ -112,7 +113,7 @@ printf '{"tasks":[{"id":"task-` + fmt.Sprintf("%d", i) + `","title":"Task","stat
 	sequentialElapsed := time.Since(sequentialStart)
 
 	start := time.Now()
-	msg := taskRefreshCmd(configs, cache, globalConfig, 3*time.Second)().(tasksMsg)
+	msg := taskRefreshCmd(configs, cache, globalConfig, 3*time.Second, nil)().(tasksMsg)
 	elapsed := time.Since(start)
 
 	if len(msg.errors) != 0 {
@@ -138,7 +139,7 @@ printf '{"tasks":[{"id":"dup-1","title":"Duplicate","status":"todo"}]}'
 	config := task.ProjectConfig{ProjectDir: projectDir, Tasks: task.ProjectTaskConfig{Provider: "provider.sh"}}
 	configs := []task.ProjectConfig{config, config}
 
-	msg := taskRefreshCmd(configs, cache, &task.GlobalConfig{}, 2*time.Second)().(tasksMsg)
+	msg := taskRefreshCmd(configs, cache, &task.GlobalConfig{}, 2*time.Second, nil)().(tasksMsg)
 	if len(msg.errors) != 0 {
 		t.Fatalf("expected duplicate project refresh to succeed, got %d errors", len(msg.errors))
 	}
@@ -153,6 +154,58 @@ printf '{"tasks":[{"id":"dup-1","title":"Duplicate","status":"todo"}]}'
 	}
 }
 
+func TestDiscoverAndRefreshRemoteTaskProjects(t *testing.T) {
+	pool := remote.NewSSHPool([]remote.Config{{Name: "ctr", Transport: remote.TransportCommand, Command: "sh -c"}})
+	t.Cleanup(pool.Close)
+
+	projectDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectDir, task.ProjectConfigFile), []byte("tasks:\n  provider: provider.sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeExecutableScript(t, projectDir, "provider.sh", `#!/bin/sh
+printf '{"tasks":[{"id":"r-1","title":"Remote","status":"todo"}]}'
+`)
+
+	sessions := []sessionCWD{{cwd: projectDir, remote: "ctr"}}
+	configMsg := discoverTaskConfigsCmd(sessions, &task.GlobalConfig{}, pool)().(taskConfigsMsg)
+	if len(configMsg.configs) != 1 {
+		t.Fatalf("expected one remote project, got %d", len(configMsg.configs))
+	}
+	cfg := configMsg.configs[0]
+	key := task.ProjectKey("ctr", projectDir)
+	if cfg.Remote != "ctr" || cfg.Key() != key {
+		t.Fatalf("unexpected remote project config: %+v", cfg)
+	}
+
+	msg := taskRefreshCmd(configMsg.configs, task.NewResultCache(), &task.GlobalConfig{}, 2*time.Second, pool)().(tasksMsg)
+	if len(msg.errors) != 0 {
+		t.Fatalf("expected remote provider to succeed, got errors %v", msg.errors)
+	}
+	if msg.resultsByProject[key] == nil || len(msg.groupsByProject[key]) == 0 {
+		t.Fatalf("expected results keyed by %q, got %v", key, msg.resultsByProject)
+	}
+	if _, ok := msg.resultsByProject[projectDir]; ok {
+		t.Fatalf("remote results must not be keyed by the bare directory")
+	}
+}
+
+func TestFindProjectForCWDKeepsRemotesApart(t *testing.T) {
+	configs := []task.ProjectConfig{
+		{ProjectDir: "/srv/app"},
+		{ProjectDir: "/srv/app", Remote: "devbox"},
+	}
+
+	if got := findProjectForCWD("", "/srv/app/sub", configs); got != "/srv/app" {
+		t.Fatalf("local cwd: got %q", got)
+	}
+	if got := findProjectForCWD("devbox", "/srv/app/sub", configs); got != "devbox:/srv/app" {
+		t.Fatalf("remote cwd: got %q", got)
+	}
+	if got := findProjectForCWD("other", "/srv/app", configs); got != "" {
+		t.Fatalf("unknown remote: got %q", got)
+	}
+}
+
 func writeExecutableScript(t *testing.T, dir string, name string, content string) string {
 	t.Helper()
 	path := filepath.Join(dir, name)
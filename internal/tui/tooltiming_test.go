@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/metrics"
+	"github.com/stwalsh4118/navi/internal/session"
+	"github.com/stwalsh4118/navi/internal/toolstats"
+)
+
+func TestFetchToolStatsCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	logDir := toolstats.LogDir(tmpDir)
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	apiLog := `{"tool":"Bash","session":"api","cwd":"/work/navi","duration_ms":2000,"error":true}
+{"tool":"Edit","session":"api","cwd":"/work/navi","duration_ms":100}
+`
+	webLog := `{"tool":"Read","session":"web","cwd":"/work/navi/web","duration_ms":50}
+{"tool":"Read","session":"web","cwd":"/work/other","duration_ms":50}
+`
+	if err := os.WriteFile(filepath.Join(logDir, "api.jsonl"), []byte(apiLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(logDir, "web.jsonl"), []byte(webLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origStatusDir := session.StatusDir
+	session.StatusDir = tmpDir
+	t.Cleanup(func() { session.StatusDir = origStatusDir })
+
+	msg, ok := fetchToolStatsCmd("api", "/work/navi")().(toolStatsMsg)
+	if !ok {
+		t.Fatal("expected toolStatsMsg")
+	}
+	if msg.session.Calls != 2 || msg.session.Errors != 1 {
+		t.Errorf("session summary = %+v, want 2 calls / 1 error", msg.session)
+	}
+	if msg.project.Calls != 3 {
+		t.Errorf("project calls = %d, want 3", msg.project.Calls)
+	}
+}
+
+func TestMetricsDetailKeyFetchesToolStats(t *testing.T) {
+	m := Model{
+		width:  120,
+		height: 40,
+		sessions: []session.Info{{
+			TmuxSession: "api",
+			Status:      session.StatusWorking,
+			CWD:         "/work/navi",
+		}},
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	m = updated.(Model)
+	if m.dialogMode != DialogMetricsDetail {
+		t.Fatalf("dialogMode = %v, want DialogMetricsDetail", m.dialogMode)
+	}
+	if cmd == nil {
+		t.Fatal("expected tool stats fetch command for local session")
+	}
+
+	m.sessions[0].Remote = "dev"
+	m.dialogMode = DialogNone
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}}); cmd != nil {
+		t.Error("did not expect tool stats fetch for remote session")
+	}
+}
+
+func TestToolStatsMsgIgnoredForOtherSession(t *testing.T) {
+	s := session.Info{TmuxSession: "api"}
+	m := Model{sessionToModify: &s}
+
+	updated, _ := m.Update(toolStatsMsg{sessionName: "web"})
+	if updated.(Model).toolStats != nil {
+		t.Error("stats for a different session should be dropped")
+	}
+
+	updated, _ = m.Update(toolStatsMsg{sessionName: "api"})
+	if updated.(Model).toolStats == nil {
+		t.Error("stats for the viewed session should be kept")
+	}
+}
+
+func TestMetricsDetailShowsToolTiming(t *testing.T) {
+	s := session.Info{
+		TmuxSession: "api",
+		Status:      session.StatusWorking,
+		Metrics:     &metrics.Metrics{Tools: &metrics.ToolMetrics{Counts: map[string]int{"Bash": 2}}},
+	}
+	sessionCalls := []toolstats.Call{
+		{Tool: "Bash", DurationMs: 4000, Error: true},
+		{Tool: "Edit", DurationMs: 120},
+	}
+	projectCalls := append(sessionCalls, toolstats.Call{Tool: "Read", DurationMs: 10})
+
+	m := Model{
+		width:           120,
+		height:          40,
+		sessionToModify: &s,
+		dialogMode:      DialogMetricsDetail,
+		toolStats: &toolStatsMsg{
+			sessionName: "api",
+			session:     toolstats.Summarize(sessionCalls),
+			project:     toolstats.Summarize(projectCalls),
+		},
+	}
+
+	view := m.renderMetricsDetailView()
+	for _, want := range []string{"Tool Timing", "avg 4.0s", "Failure rate", "Mix: Bash 50%", "Project: 3 calls"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in metrics detail view", want)
+		}
+	}
+}
+
+func TestMetricsDetailNoToolTimingWithoutCalls(t *testing.T) {
+	s := session.Info{TmuxSession: "api", Metrics: &metrics.Metrics{}}
+	m := Model{width: 120, height: 40, sessionToModify: &s, toolStats: &toolStatsMsg{sessionName: "api"}}
+
+	if strings.Contains(m.renderMetricsDetailView(), "Tool Timing") {
+		t.Error("did not expect Tool Timing section without timed calls")
+	}
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/session"
+	"github.com/stwalsh4118/navi/internal/uistate"
+)
+
+// Preview layout names used in the persisted UI state
+const (
+	previewLayoutSideName   = "side"
+	previewLayoutBottomName = "bottom"
+)
+
+// saveUIStateCmd returns a command that persists the UI state through w.
+// The sequence number is taken now, so a save that runs after a newer one is
+// skipped. Errors are ignored; the next change triggers another save.
+func saveUIStateCmd(w *uistate.Writer, state uistate.State) tea.Cmd {
+	seq := w.Next()
+	return func() tea.Msg {
+		_ = w.Save(seq, state)
+		return nil
+	}
+}
+
+// uiState captures the persisted view settings from the model.
+func (m Model) uiState() uistate.State {
+	s := uistate.State{
+		Sort:            SortModeLabel(m.sortMode),
+		Filter:          m.filterModeString(),
+		HideOffline:     m.hideOffline,
+		Preview:         m.previewUserEnabled,
+		PreviewLayout:   previewLayoutSideName,
+		PreviewWidth:    m.previewWidth,
+		PreviewHeight:   m.previewHeight,
+		PreviewWrap:     m.previewWrap,
+		PreviewPlain:    m.previewPlain,
+		TaskPanel:       m.taskPanelUserEnabled,
+		TaskPanelHeight: m.taskPanelHeight,
+		TaskSort:        string(m.taskSortMode),
+		TaskSortReverse: m.taskSortReversed,
+		TaskFilter:      string(m.taskFilterMode),
+		Accordion:       m.taskAccordionMode,
+	}
+	if m.previewLayout == PreviewLayoutBottom {
+		s.PreviewLayout = previewLayoutBottomName
+	}
+	for id, expanded := range m.taskExpandedGroups {
+		if expanded {
+			s.ExpandedGroups = append(s.ExpandedGroups, id)
+		}
+	}
+	sort.Strings(s.ExpandedGroups)
+	return s
+}
+
+// applyUIState restores persisted view settings. Unknown mode names keep the
+// defaults, and the local/remote filter only applies when remotes are
+// configured, since it cannot be cycled back otherwise.
+func (m *Model) applyUIState(s uistate.State) {
+	for mode := SortMode(0); mode < sortModeCount; mode++ {
+		if SortModeLabel(mode) == s.Sort {
+			m.sortMode = mode
+		}
+	}
+	if len(m.Remotes) > 0 {
+		switch s.Filter {
+		case "local":
+			m.filterMode = session.FilterLocal
+		case "remote":
+			m.filterMode = session.FilterRemote
+		}
+	}
+	m.hideOffline = s.HideOffline
+
+	if s.PreviewLayout == previewLayoutBottomName {
+		m.previewLayout = PreviewLayoutBottom
+	}
+	m.previewWidth = max(s.PreviewWidth, 0)
+	m.previewHeight = max(s.PreviewHeight, 0)
+	m.previewWrap = s.PreviewWrap
+	m.previewPlain = s.PreviewPlain
+	m.taskPanelHeight = max(s.TaskPanelHeight, 0)
+
+	// The panels are mutually exclusive; the preview wins if both are set.
+	// The first window size message hides them again on a narrow terminal.
+	switch {
+	case s.Preview:
+		m.previewUserEnabled, m.previewVisible = true, true
+		m.previewRestorePending = true
+	case s.TaskPanel:
+		m.taskPanelUserEnabled, m.taskPanelVisible = true, true
+	}
+
+	for _, mode := range taskSortModes {
+		if string(mode) == s.TaskSort {
+			m.taskSortMode = mode
+		}
+	}
+	m.taskSortReversed = s.TaskSortReverse
+	for _, mode := range taskFilterModes {
+		if string(mode) == s.TaskFilter {
+			m.taskFilterMode = mode
+		}
+	}
+	m.taskAccordionMode = s.Accordion
+	for _, id := range s.ExpandedGroups {
+		m.taskExpandedGroups[id] = true
+	}
+
+	m.savedUIState = m.uiState()
+}
+
+// persistUIState returns a command saving the UI state when it differs from
+// the last saved state. Models without a state writer, as in tests, never save.
+func (m *Model) persistUIState() tea.Cmd {
+	if m.uiStateWriter == nil {
+		return nil
+	}
+	s := m.uiState()
+	if s.Equal(m.savedUIState) {
+		return nil
+	}
+	m.savedUIState = s
+	return saveUIStateCmd(m.uiStateWriter, s)
+}
@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stwalsh4118/navi/internal/remote"
+	"github.com/stwalsh4118/navi/internal/session"
+	"github.com/stwalsh4118/navi/internal/uistate"
+)
+
+// runBatch runs cmd and any commands batched with it, discarding messages.
+func runBatch(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		for _, c := range batch {
+			runBatch(c)
+		}
+	}
+}
+
+func TestUIStateRoundTrip(t *testing.T) {
+	m := newPreviewScrollTestModel()
+	m.Remotes = []remote.Config{{Name: "devbox", Host: "devbox.lan"}}
+	m.sortMode = SortAge
+	m.filterMode = session.FilterRemote
+	m.hideOffline = true
+	m.previewWidth = 70
+	m.previewPlain = true
+	m.taskSortMode = taskSortProgress
+	m.taskFilterMode = taskFilterActive
+	m.taskAccordionMode = true
+	m.taskExpandedGroups["PBI-7"] = true
+	m.taskExpandedGroups["PBI-1"] = true
+
+	s := m.uiState()
+	if s.Sort != "age" || s.Filter != "remote" || s.PreviewLayout != "bottom" || !s.Preview || s.TaskPanel {
+		t.Errorf("uiState() = %+v", s)
+	}
+	if len(s.ExpandedGroups) != 2 || s.ExpandedGroups[0] != "PBI-1" {
+		t.Errorf("ExpandedGroups = %v, want sorted IDs", s.ExpandedGroups)
+	}
+
+	restored := Model{Remotes: m.Remotes, taskExpandedGroups: make(map[string]bool)}
+	restored.applyUIState(s)
+	if got := restored.uiState(); !got.Equal(s) {
+		t.Errorf("restored state = %+v, want %+v", got, s)
+	}
+	if !restored.previewVisible || !restored.previewRestorePending || restored.taskPanelVisible {
+		t.Error("restoring an enabled preview should show it and await a capture")
+	}
+	if !restored.savedUIState.Equal(s) {
+		t.Error("the restored state should count as saved")
+	}
+}
+
+func TestApplyUIStateIgnoresUnknownModes(t *testing.T) {
+	m := Model{taskExpandedGroups: make(map[string]bool), taskSortMode: taskSortSource, taskFilterMode: taskFilterAll}
+	m.applyUIState(uistate.State{Sort: "bogus", Filter: "remote", TaskSort: "bogus", TaskPanel: true})
+
+	if m.sortMode != SortPriority || m.taskSortMode != taskSortSource {
+		t.Errorf("unknown sort names should keep the defaults, got %v, %q", m.sortMode, m.taskSortMode)
+	}
+	if m.filterMode != session.FilterAll {
+		t.Error("the remote filter should not be restored without remotes")
+	}
+	if !m.taskPanelVisible || !m.taskPanelUserEnabled {
+		t.Error("the task panel should be restored")
+	}
+}
+
+func TestUIStateSavedOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ui-state.json")
+	m := newPreviewScrollTestModel()
+	m.uiStateWriter = uistate.NewWriter(path)
+	m.savedUIState = m.uiState()
+
+	// Moving the cursor changes nothing persisted
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = updated.(Model)
+	runBatch(cmd)
+	if s, err := uistate.Load(path); err != nil || !s.Equal(uistate.State{}) {
+		t.Fatalf("state saved without a change: %+v, %v", s, err)
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(Model)
+	runBatch(cmd)
+	s, err := uistate.Load(path)
+	if err != nil || s.Sort != "name" || !s.Preview {
+		t.Fatalf("saved state = %+v, %v; want the name sort with the preview", s, err)
+	}
+
+	// Saving again without a change is skipped
+	if cmd := m.persistUIState(); cmd != nil {
+		t.Error("an unchanged state should not be saved again")
+	}
+}
+
+func TestRestoredPreviewCapturesFirstSession(t *testing.T) {
+	m := newPreviewScrollTestModel()
+	m.previewContent = ""
+	m.previewRestorePending = true
+
+	updated, cmd := m.Update(sessionsMsg(m.sessions))
+	m = updated.(Model)
+	if cmd == nil || m.previewRestorePending {
+		t.Fatal("the first sessions should start the restored preview")
+	}
+}
@@ -7,13 +7,14 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/muesli/reflow/wordwrap"
 
 	"github.com/stwalsh4118/navi/internal/git"
+	"github.com/stwalsh4118/navi/internal/history"
 	"github.com/stwalsh4118/navi/internal/metrics"
 	"github.com/stwalsh4118/navi/internal/pathutil"
 	"github.com/stwalsh4118/navi/internal/remote"
 	"github.com/stwalsh4118/navi/internal/session"
+	"github.com/stwalsh4118/navi/internal/task"
 )
 
 // Selection marker constants
@@ -189,6 +190,8 @@ func (m Model) View() string {
 
 	if m.pmViewVisible {
 		b.WriteString(m.renderPMView(m.width, contentHeight))
+	} else if m.mosaicVisible {
+		b.WriteString(m.renderMosaicView(m.mosaicContentSize()))
 	} else if m.taskPanelVisible && m.width >= previewMinTerminalWidth {
 		// Task panel layout: sessions on top, task panel on bottom
 		panelHeight := m.getTaskPanelHeight()
@@ -287,6 +290,17 @@ func (m Model) renderSessionRow(s session.Info, selected, isMatch, isCurrentMatc
 	return rendered
 }
 
+// nameOnOtherHost reports whether another host runs a session with the same
+// tmux name as s.
+func (m Model) nameOnOtherHost(s session.Info) bool {
+	for _, other := range m.sessions {
+		if other.TmuxSession == s.TmuxSession && other.Remote != s.Remote {
+			return true
+		}
+	}
+	return false
+}
+
 // renderSession renders a single session row with icon, name, age, cwd, and message.
 func (m Model) renderSession(s session.Info, selected bool, width int) string {
 	var b strings.Builder
@@ -296,16 +310,29 @@ func (m Model) renderSession(s session.Info, selected bool, width int) string {
 	if selected {
 		marker = selectedMarker
 	}
+	if m.selectedSessions[s.ID()] {
+		// The check takes the marker's blank column, so rows stay aligned
+		marker = greenStyle.Render(checkedMarker) + " "
+		if selected {
+			marker = strings.TrimSuffix(selectedMarker, " ") + greenStyle.Render(checkedMarker)
+		}
+	}
 
 	// First line: marker + icon + name + [remote] + age
 	compositeStatus, compositeSource := session.CompositeStatus(s)
 	icon := StatusIcon(compositeStatus)
 	name := boldStyle.Render(s.TmuxSession)
 
-	// Add remote label if this is a remote session
+	// Add remote label if this is a remote session; a local session that shares
+	// its name with a remote one gets a local label so the two stand apart
 	remoteLabel := ""
 	if s.Remote != "" {
 		remoteLabel = " " + dimStyle.Render(fmt.Sprintf("[%s]", s.Remote))
+	} else if m.nameOnOtherHost(s) {
+		remoteLabel = " " + dimStyle.Render("[local]")
+	}
+	if s.Group != "" {
+		remoteLabel += " " + dimStyle.Render("{"+s.Group+"}")
 	}
 
 	// Add agent count badge if team is active (exclude stopped agents)
@@ -359,6 +386,9 @@ func (m Model) renderSession(s session.Info, selected bool, width int) string {
 	// Metrics badges line if metrics present (indented, dimmed)
 	if s.Metrics != nil {
 		metricsLine := renderMetricsBadges(s.Metrics)
+		if sparklines := m.renderSessionSparklines(s); sparklines != "" && metricsLine != "" {
+			metricsLine += "  " + sparklines
+		}
 		if metricsLine != "" {
 			b.WriteString("\n")
 			b.WriteString(rowIndent)
@@ -469,9 +499,43 @@ func renderMetricsBadges(m *metrics.Metrics) string {
 		parts = append(parts, fmt.Sprintf("🧠 %s", metrics.FormatBytes(m.Resource.RSSBytes)))
 	}
 
+	// CPU badge (only present for locally sampled process trees)
+	if m.Resource != nil && m.Resource.ProcessCount > 0 {
+		parts = append(parts, fmt.Sprintf("⚡ %.0f%%", m.Resource.CPUPercent))
+	}
+
+	// Listening ports badge
+	if m.Resource != nil && len(m.Resource.Ports) > 0 {
+		parts = append(parts, "🔌 "+formatPorts(m.Resource.Ports, portsBadgeMax))
+	}
+
+	// Resource alert badge
+	if m.Resource != nil && len(m.Resource.Alerts) > 0 {
+		parts = append(parts, redStyle.Render("⚠ "+strings.Join(m.Resource.Alerts, ", ")))
+	}
+
 	return strings.Join(parts, "  ")
 }
 
+// portsBadgeMax is the number of ports listed in the session row before collapsing to "+N".
+const portsBadgeMax = 3
+
+// formatPorts renders ports as ":3000 :5173", listing at most max ports.
+func formatPorts(ports []int, max int) string {
+	shown := ports
+	if max > 0 && len(shown) > max {
+		shown = shown[:max]
+	}
+	parts := make([]string, 0, len(shown)+1)
+	for _, port := range shown {
+		parts = append(parts, fmt.Sprintf(":%d", port))
+	}
+	if len(shown) < len(ports) {
+		parts = append(parts, fmt.Sprintf("+%d", len(ports)-len(shown)))
+	}
+	return strings.Join(parts, " ")
+}
+
 // renderGitInfo renders git status info with appropriate coloring.
 // Format: "branch-name ● +3 -1 [PR#42]"
 func renderGitInfo(g *git.Info, maxWidth int) string {
@@ -598,6 +662,8 @@ func (m Model) renderHeader() string {
 				indicator = greenStyle.Render(fmt.Sprintf("[%s:✓]", r.Name))
 			case remote.StatusError:
 				indicator = redStyle.Render(fmt.Sprintf("[%s:✗]", r.Name))
+			case remote.StatusPaused:
+				indicator = dimStyle.Render(fmt.Sprintf("[%s:⏸]", r.Name))
 			default:
 				indicator = dimStyle.Render(fmt.Sprintf("[%s:-]", r.Name))
 			}
@@ -649,19 +715,27 @@ func (m Model) renderFooter() string {
 
 	if m.previewFocused {
 		// Show preview focus keybindings
-		parts = append(parts, "j/k scroll", "PgUp/PgDn page", "g/G top/bottom", "Tab/Esc back", "[/] resize", "q quit")
+		parts = append(parts, "j/k scroll", "PgUp/PgDn page", "g/G top/live", "/ search")
+		if m.previewSearchQuery != "" {
+			parts = append(parts, "n/N match")
+		}
+		parts = append(parts, "Tab/Esc back", "[/] resize", "? keys", "q quit")
+	} else if m.mosaicVisible && m.mosaicZoomed {
+		parts = append(parts, "j/k prev/next", "⏎/z grid", "a attach", "Esc grid", "? keys", "q quit")
+	} else if m.mosaicVisible {
+		parts = append(parts, "hjkl/arrows move", "PgUp/PgDn page", "⏎/z zoom", "a attach", "Esc/M close", "? keys", "q quit")
 	} else if m.pmViewVisible {
-		parts = append(parts, "P close", "Tab focus", "↑/↓ nav", "j/k scroll", "Space expand", "⏎ select", "q quit")
+		parts = append(parts, "P close", "Tab focus", "↑/↓ nav", "j/k scroll", "Space expand", "⏎ select", "? keys", "q quit")
 	} else if m.taskPanelFocused {
 		// Show task panel focus keybindings
-		parts = append(parts, "↑/↓ nav", "J/K groups", "/ search", "Space expand", "e exp/coll", "a accord", "s/S sort", "f filter", "r refresh", "Tab/Esc back", "T close", "[/] resize", "q quit")
+		parts = append(parts, "↑/↓ nav", "J/K groups", "/ search", "Space expand", "e exp/coll", "a accord", "s/S sort", "f filter", "r refresh", "Tab/Esc back", "T close", "[/] resize", "? keys", "q quit")
 	} else {
 		// Normal session keybindings
-		parts = append(parts, "↑/↓ nav", "⏎ attach", "/ search", "p preview", "T tasks")
+		parts = append(parts, "↑/↓ nav", "⏎ attach", "/ search", "p preview", "T tasks", "M mosaic")
 
 		// Show panel-specific keys when a panel is visible
 		if m.previewVisible {
-			parts = append(parts, "Tab focus", "L layout", "W wrap", "[/] resize")
+			parts = append(parts, "Tab focus", "L layout", "W wrap", "C color", "[/] resize")
 		} else if m.taskPanelVisible {
 			parts = append(parts, "Tab focus", "[/] resize")
 		}
@@ -672,10 +746,14 @@ func (m Model) renderFooter() string {
 			parts = append(parts, filterLabel)
 		}
 
-		// Show session action keybindings
-		parts = append(parts, "d dismiss", "n new", "x kill", "R rename", "G git")
+		// Show session action keybindings; with a selection they act on every selected session
+		if len(m.selectedSessions) > 0 {
+			parts = append(parts, "x kill", "d dismiss", "> prompt", "g group", "Esc clear")
+		} else {
+			parts = append(parts, "d dismiss", "n new", "x kill", "R rename", "G git", "Space select")
+		}
 
-		parts = append(parts, "m mute", "S sounds", "r refresh", "q quit")
+		parts = append(parts, "m mute", "S sounds", "r refresh", "? keys", "q quit")
 	}
 
 	footerHelp := strings.Join(parts, "  ")
@@ -687,11 +765,19 @@ func (m Model) renderFooter() string {
 		statusParts = append(statusParts, filterActiveStyle.Render("MUTED"))
 	}
 
+	if n := m.inbox.Unread(); n > 0 {
+		statusParts = append(statusParts, filterActiveStyle.Render(fmt.Sprintf("Inbox: %d unread", n)))
+	}
+
+	if len(m.selectedSessions) > 0 {
+		statusParts = append(statusParts, filterActiveStyle.Render(fmt.Sprintf("%d selected", len(m.selectedSessions))))
+	}
+
 	if m.statusFilter != "" {
 		statusParts = append(statusParts, filterActiveStyle.Render("Filter: "+m.statusFilter))
 	}
 	if m.pmProjectFilterDir != "" {
-		statusParts = append(statusParts, filterActiveStyle.Render("Project: "+m.pmProjectFilterDir))
+		statusParts = append(statusParts, filterActiveStyle.Render("Project: "+task.ProjectKey(m.pmProjectFilterRemote, m.pmProjectFilterDir)))
 	}
 
 	if m.hideOffline {
@@ -710,7 +796,7 @@ func (m Model) renderFooter() string {
 		statusParts = append(statusParts, filterActiveStyle.Render(countStr))
 	}
 
-	if !m.pmViewVisible {
+	if !m.pmViewVisible && !m.mosaicVisible {
 		// Key hints for new features on the status line
 		statusParts = append(statusParts, dimStyle.Render("s:sort  1-5:filter  o:offline  0:clear"))
 	}
@@ -741,15 +827,40 @@ func (m Model) renderPreview(width, height int) string {
 	if len(filteredSessions) > 0 && m.cursor < len(filteredSessions) {
 		selectedSession = filteredSessions[m.cursor]
 		hasSelectedSession = true
-		sessionName = selectedSession.TmuxSession
+		sessionName = selectedSession.Label()
 	}
 
-	// Build header with session name
+	// Build header with session name and scrollback state
 	if sessionName != "" {
 		b.WriteString(previewHeaderStyle.Render("─ " + sessionName + " "))
 	}
+	if m.previewLoading {
+		b.WriteString(dimStyle.Render("loading scrollback..."))
+	} else if m.previewDeep {
+		b.WriteString(dimStyle.Render("paused · G live"))
+	}
 	b.WriteString("\n")
 
+	// Search bar (when actively typing or search persisted after Enter)
+	searchLines := 0
+	if m.previewSearchMode || m.previewSearchQuery != "" {
+		searchContent := "/ " + m.previewSearchQuery
+		if m.previewSearchMode {
+			searchContent = "/ " + m.previewSearchInput.View()
+		}
+		if m.previewSearchQuery != "" {
+			if len(m.previewMatches) > 0 {
+				counter := fmt.Sprintf(" [%d/%d]", m.previewMatchIdx+1, len(m.previewMatches))
+				searchContent += searchMatchCountStyle.Render(counter)
+			} else {
+				searchContent += " " + searchNoMatchStyle.Render("No matches")
+			}
+		}
+		b.WriteString(searchContent)
+		b.WriteString("\n")
+		searchLines = 1
+	}
+
 	agentDetail := ""
 	agentDetailLines := 0
 	if hasSelectedSession {
@@ -767,32 +878,17 @@ func (m Model) renderPreview(width, height int) string {
 		contentWidth = 10
 	}
 
-	if m.previewContent == "" {
+	if m.previewContent == "" && len(m.previewHistory) == 0 {
 		b.WriteString(previewEmptyStyle.Render("No preview available"))
 	} else {
-		var content string
-		if m.previewWrap {
-			// Wrap content to fit width
-			content = wordwrap.String(m.previewContent, contentWidth)
-		} else {
-			// Truncate long lines
-			lines := strings.Split(m.previewContent, "\n")
-			for i, line := range lines {
-				if lipgloss.Width(line) > contentWidth {
-					lines[i] = truncate(line, contentWidth)
-				}
-			}
-			content = strings.Join(lines, "\n")
-		}
-
-		// Calculate max content lines: total height - borders(2) - header(1) - optional agent detail lines
-		maxLines := height - 3 - agentDetailLines
+		// Calculate max content lines: total height - borders(2) - header(1) - optional agent detail and search lines
+		maxLines := height - 3 - agentDetailLines - searchLines
 		if maxLines < 1 {
 			maxLines = 1
 		}
 
-		// Split into lines and apply scroll offset
-		lines := strings.Split(content, "\n")
+		// Wrap or truncate to fit width, then apply scroll offset
+		lines, _ := previewLines(m.previewText(), contentWidth, m.previewWrap)
 
 		// Clamp scroll offset.
 		// When scrolling is active, the top indicator takes 1 line from content
@@ -998,14 +1094,14 @@ func (m Model) renderGitDetailView() string {
 	s := m.sessionToModify
 
 	// Session name
-	b.WriteString(fmt.Sprintf("Session: %s\n", boldStyle.Render(s.TmuxSession)))
+	b.WriteString(fmt.Sprintf("Session: %s\n", boldStyle.Render(s.Label())))
 	b.WriteString(fmt.Sprintf("CWD: %s\n\n", dimStyle.Render(s.CWD)))
 
 	// Check if git info is available
 	if s.Git == nil {
 		b.WriteString(dimStyle.Render("Not a git repository"))
 		b.WriteString("\n\n")
-		b.WriteString(dimStyle.Render("Esc: close"))
+		b.WriteString(dimStyle.Render("f: file  Esc: close"))
 		content := b.String()
 		dialog := dialogBoxStyle.Width(gitDetailWidth).Render(content)
 		return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, dialog)
@@ -1137,8 +1233,9 @@ func (m Model) renderGitDetailView() string {
 	}
 
 	// Keybindings
+	b.WriteString(dimStyle.Render("d: diff  s: stat  l: log  f: file"))
+	b.WriteString("\n")
 	var keys []string
-	keys = append(keys, "d: diff")
 	if g.PRNum > 0 && g.Remote != "" {
 		keys = append(keys, "o: open PR")
 	}
@@ -1176,7 +1273,7 @@ func (m Model) renderMetricsDetailView() string {
 	s := m.sessionToModify
 
 	// Session name
-	b.WriteString(fmt.Sprintf("Session: %s\n", boldStyle.Render(s.TmuxSession)))
+	b.WriteString(fmt.Sprintf("Session: %s\n", boldStyle.Render(s.Label())))
 	b.WriteString(fmt.Sprintf("Status: %s %s\n", StatusIcon(s.Status), s.Status))
 	b.WriteString("\n")
 
@@ -1293,6 +1390,18 @@ func (m Model) renderMetricsDetailView() string {
 		b.WriteString("\n")
 	}
 
+	// Tool timing section from the tool call log
+	if timing := m.renderToolTiming(s.TmuxSession); s.Remote == "" && timing != "" {
+		b.WriteString("\n")
+		b.WriteString(timing)
+	}
+
+	// Trends section from the metrics history store
+	if trends := m.renderMetricsTrends(s.ID()); trends != "" {
+		b.WriteString("\n")
+		b.WriteString(trends)
+	}
+
 	b.WriteString("\n")
 	b.WriteString(dimStyle.Render("Esc: close"))
 
@@ -1301,6 +1410,103 @@ func (m Model) renderMetricsDetailView() string {
 	return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, dialog)
 }
 
+// Tool timing display constants
+const (
+	// toolTimingMaxTools is the number of slowest tools listed in the metrics detail view.
+	toolTimingMaxTools = 5
+
+	// toolTimingNameWidth is the column width for tool names in the timing table.
+	toolTimingNameWidth = 18
+)
+
+// renderToolTiming renders the slowest tools, failure rates and tool mix for the
+// named session, plus a one-line project summary. Returns empty string when no
+// timed calls have been loaded.
+func (m Model) renderToolTiming(sessionName string) string {
+	if m.toolStats == nil || m.toolStats.sessionName != sessionName || m.toolStats.session.Calls == 0 {
+		return ""
+	}
+	summary := m.toolStats.session
+
+	var b strings.Builder
+	b.WriteString(boldStyle.Render("Tool Timing"))
+	b.WriteString(dimStyle.Render(fmt.Sprintf(" (%d calls)", summary.Calls)))
+	b.WriteString("\n")
+
+	limit := len(summary.Tools)
+	if limit > toolTimingMaxTools {
+		limit = toolTimingMaxTools
+	}
+	for _, st := range summary.Tools[:limit] {
+		name := st.Tool
+		if len(name) > toolTimingNameWidth {
+			name = name[:toolTimingNameWidth-1] + "…"
+		}
+		line := fmt.Sprintf("  %-*s avg %-7s max %-7s", toolTimingNameWidth, name,
+			metrics.FormatMillis(st.AvgMs()), metrics.FormatMillis(st.MaxMs))
+		b.WriteString(line)
+		if st.Errors > 0 {
+			b.WriteString(redStyle.Render(fmt.Sprintf(" %.0f%% failed", st.FailureRate()*100)))
+		}
+		b.WriteString("\n")
+	}
+
+	failLine := fmt.Sprintf("%.1f%%", summary.FailureRate()*100)
+	if summary.Errors > 0 {
+		failLine = redStyle.Render(failLine)
+	}
+	b.WriteString(fmt.Sprintf("  Failure rate: %s\n", failLine))
+	b.WriteString(fmt.Sprintf("  Mix: Bash %.0f%% · Edit %.0f%% · Read %.0f%%\n",
+		summary.Share("Bash")*100, summary.Share("Edit")*100, summary.Share("Read")*100))
+
+	if project := m.toolStats.project; project.Calls > summary.Calls {
+		slowest := ""
+		if len(project.Tools) > 0 {
+			slowest = fmt.Sprintf(", slowest %s (%s)", project.Tools[0].Tool, metrics.FormatMillis(project.Tools[0].AvgMs()))
+		}
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  Project: %d calls, %.1f%% failed%s",
+			project.Calls, project.FailureRate()*100, slowest)))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// metricsChartWidth is the number of columns used by charts in the metrics detail dialog.
+const metricsChartWidth = metricsDetailWidth - 8
+
+// renderMetricsTrends renders token rate and memory charts plus a status timeline
+// for the named session. Returns empty string when fewer than two samples exist.
+func (m Model) renderMetricsTrends(sessionID string) string {
+	samples := m.metricsHistory.Series(sessionID)
+	if len(samples) < 2 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(boldStyle.Render("Trends"))
+	span := samples[len(samples)-1].Timestamp - samples[0].Timestamp
+	b.WriteString(dimStyle.Render(fmt.Sprintf(" (last %s)", metrics.FormatDuration(span))))
+	b.WriteString("\n")
+
+	rates := history.TokenRate(samples)
+	b.WriteString(fmt.Sprintf("  Tokens/min (peak %s)\n", metrics.FormatTokenCount(int64(maxValue(tailValues(rates, metricsChartWidth))))))
+	for _, row := range renderChart(rates, metricsChartWidth, metricsChartHeight) {
+		b.WriteString("  " + cyanStyle.Render(row) + "\n")
+	}
+
+	rss := history.RSSValues(samples)
+	b.WriteString(fmt.Sprintf("  Memory (peak %s)\n", metrics.FormatBytes(int64(maxValue(tailValues(rss, metricsChartWidth))))))
+	for _, row := range renderChart(rss, metricsChartWidth, metricsChartHeight) {
+		b.WriteString("  " + magentaStyle.Render(row) + "\n")
+	}
+
+	b.WriteString("  Status\n")
+	b.WriteString("  " + renderStatusTimeline(history.StatusTimeline(samples), metricsChartWidth) + "\n")
+
+	return b.String()
+}
+
 // renderDialog renders the dialog overlay when dialogMode is set.
 // Returns empty string if no dialog is open.
 func (m Model) renderDialog() string {
@@ -1334,21 +1540,42 @@ func (m Model) renderDialog() string {
 			checkboxLine = highlightStyle.Render(checkboxLine)
 		}
 		b.WriteString(checkboxLine)
-		b.WriteString("\n\n")
+		b.WriteString("\n")
+		if len(m.Remotes) > 0 {
+			host := "local"
+			if m.newSessionHost != "" {
+				host = m.newSessionHost
+			}
+			hostLine := "Host:      ◂ " + host + " ▸"
+			if m.focusedInput == focusHost {
+				hostLine = highlightStyle.Render(hostLine)
+			}
+			b.WriteString(hostLine)
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
 		b.WriteString(dimStyle.Render("Tab: switch  Space: toggle  Enter: create  Esc: cancel"))
 	case DialogKillConfirm:
 		if m.sessionToModify != nil {
-			b.WriteString(fmt.Sprintf("Kill session '%s'?\n\n", m.sessionToModify.TmuxSession))
+			b.WriteString(fmt.Sprintf("Kill session '%s'?\n\n", m.sessionToModify.Label()))
 		}
 		b.WriteString(dimStyle.Render("y: yes  n: no  Esc: cancel"))
 	case DialogRename:
 		if m.sessionToModify != nil {
-			b.WriteString(fmt.Sprintf("Current: %s\n\n", dimStyle.Render(m.sessionToModify.TmuxSession)))
+			b.WriteString(fmt.Sprintf("Current: %s\n\n", dimStyle.Render(m.sessionToModify.Label())))
 		}
 		b.WriteString("New name: ")
 		b.WriteString(m.nameInput.View())
 		b.WriteString("\n\n")
 		b.WriteString(dimStyle.Render("Enter: rename  Esc: cancel"))
+	case DialogGitFile:
+		if m.sessionToModify != nil {
+			b.WriteString(fmt.Sprintf("Directory: %s\n\n", dimStyle.Render(m.sessionToModify.CWD)))
+		}
+		b.WriteString("File: ")
+		b.WriteString(m.fileInput.View())
+		b.WriteString("\n\n")
+		b.WriteString(dimStyle.Render("Enter: view  Esc: back"))
 	case DialogGitDetail:
 		b.Reset() // Clear the builder for custom git view
 		return m.renderGitDetailView()
@@ -1361,6 +1588,35 @@ func (m Model) renderDialog() string {
 	case DialogSoundPackPicker:
 		b.Reset()
 		return m.renderSoundPackPicker()
+	case DialogProcessTree:
+		b.Reset()
+		return m.renderProcessTree()
+	case DialogRemotes:
+		b.Reset()
+		return m.renderRemotesPanel()
+	case DialogRemoteEdit:
+		b.Reset()
+		return m.renderRemoteEditor()
+	case DialogPalette:
+		b.Reset()
+		return m.renderPalette()
+	case DialogInbox:
+		b.Reset()
+		return m.renderInbox()
+	case DialogBulkConfirm:
+		m.renderBulkConfirm(&b)
+	case DialogBulkResult:
+		m.renderBulkResult(&b)
+	case DialogHostKey:
+		if p := m.hostKeyPrompt; p != nil {
+			b.WriteString(fmt.Sprintf("Remote '%s' presented a host key that is not in known_hosts.\n\n", p.Remote))
+			b.WriteString(fmt.Sprintf("Host: %s\n", p.Host))
+			b.WriteString(fmt.Sprintf("Type: %s\n", p.Key.Type()))
+			// Fingerprint on its own line so it fits the dialog width unwrapped
+			b.WriteString(fmt.Sprintf("Fingerprint:\n  %s\n\n", p.Fingerprint()))
+			b.WriteString("Trust this key and add it to known_hosts?\n\n")
+		}
+		b.WriteString(dimStyle.Render("y: trust  n/Esc: reject"))
 	}
 
 	// Error message if present
@@ -1447,3 +1703,83 @@ func (m Model) renderSoundPackPicker() string {
 	dialog := dialogBoxStyle.Width(dialogWidth).Render(content)
 	return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, dialog)
 }
+
+// processTreeWidth is the width of the process tree dialog.
+const processTreeWidth = 90
+
+// processTreeIndent is the indentation added per tree depth level.
+const processTreeIndent = "  "
+
+// renderProcessTree renders the selected session's process tree with per-process
+// CPU, memory, and listening ports.
+func (m Model) renderProcessTree() string {
+	var b strings.Builder
+
+	b.WriteString(dialogTitleStyle.Render(DialogTitle(DialogProcessTree)))
+	if m.sessionToModify != nil {
+		b.WriteString(dimStyle.Render(" · " + m.sessionToModify.Label()))
+	}
+	b.WriteString("\n\n")
+
+	procs := m.selectedSessionProcesses()
+	if len(procs) == 0 {
+		b.WriteString(dimStyle.Render("No process data yet"))
+		b.WriteString("\n\n")
+		b.WriteString(dimStyle.Render("Esc: close"))
+	} else {
+		usage := m.resourceCache[m.sessionToModify.ID()]
+		summary := fmt.Sprintf("%d processes  CPU %.0f%%  RAM %s", usage.ProcessCount, usage.CPUPercent, metrics.FormatBytes(usage.RSSBytes))
+		if len(usage.Ports) > 0 {
+			summary += "  ports " + formatPorts(usage.Ports, 0)
+		}
+		b.WriteString(summary)
+		b.WriteString("\n\n")
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  %7s %5s %6s  %s", "PID", "CPU", "RSS", "COMMAND")))
+		b.WriteString("\n")
+
+		end := m.processScrollOffset + processTreeMaxVisible
+		if end > len(procs) {
+			end = len(procs)
+		}
+		if m.processScrollOffset > 0 {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  ↑ %d more", m.processScrollOffset)))
+			b.WriteString("\n")
+		}
+
+		// Command column gets whatever is left after the fixed columns and box padding
+		commandWidth := processTreeWidth - 30
+		for i := m.processScrollOffset; i < end; i++ {
+			p := procs[i]
+			command := strings.Repeat(processTreeIndent, p.Depth) + p.Command
+			ports := ""
+			if len(p.Ports) > 0 {
+				ports = formatPorts(p.Ports, 0)
+				command = truncate(command, commandWidth-len(ports)-1) + " " + cyanStyle.Render(ports)
+			} else {
+				command = truncate(command, commandWidth)
+			}
+			line := fmt.Sprintf("  %7d %4.0f%% %6s  %s", p.PID, p.CPUPercent, metrics.FormatBytes(p.RSSBytes), command)
+			if i == m.processCursor {
+				line = selectedStyle.Render(line)
+			}
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+
+		if end < len(procs) {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  ↓ %d more", len(procs)-end)))
+			b.WriteString("\n")
+		}
+
+		b.WriteString("\n")
+		b.WriteString(dimStyle.Render("↑↓: navigate  x: kill process (SIGTERM)  Esc: close"))
+	}
+
+	if m.dialogError != "" {
+		b.WriteString("\n")
+		b.WriteString(dialogErrorStyle.Render(m.dialogError))
+	}
+
+	dialog := dialogBoxStyle.Width(processTreeWidth).Render(b.String())
+	return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, dialog)
+}
@@ -316,6 +316,31 @@ func TestRenderPreview(t *testing.T) {
 	})
 }
 
+func TestRenderPreviewColors(t *testing.T) {
+	m := Model{
+		width:  80,
+		height: 24,
+		sessions: []session.Info{
+			{TmuxSession: "test-session"},
+		},
+		previewVisible: true,
+		previewContent: "\x1b[31mred output\x1b[0m",
+	}
+
+	if result := m.renderPreview(40, 20); !strings.Contains(result, "\x1b[31mred output") {
+		t.Errorf("preview should keep captured colors, got %q", result)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+	m = updated.(Model)
+	if !m.previewPlain {
+		t.Fatal("C should switch the preview to stripped output")
+	}
+	if result := m.renderPreview(40, 20); strings.Contains(result, "\x1b[31m") {
+		t.Errorf("stripped preview should not contain captured colors, got %q", result)
+	}
+}
+
 func TestViewWithPreview(t *testing.T) {
 	t.Run("view includes preview when visible", func(t *testing.T) {
 		m := Model{
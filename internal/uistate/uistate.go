@@ -0,0 +1,145 @@
+// Package uistate persists the view settings navi restores on start: sort
+// and filter modes, preview and task panel layout, and expanded task groups.
+package uistate
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/stwalsh4118/navi/internal/pathutil"
+)
+
+// DefaultPath is where the UI state is persisted between runs.
+const DefaultPath = "~/.config/navi/ui-state.json"
+
+// State is the persisted subset of the UI. Modes are stored by name so the
+// file stays readable and survives reordering of the enums behind them; the
+// zero value means the defaults.
+type State struct {
+	Sort        string `json:"sort,omitempty"`   // Session sort mode label
+	Filter      string `json:"filter,omitempty"` // all, local or remote
+	HideOffline bool   `json:"hide_offline,omitempty"`
+
+	Preview       bool   `json:"preview,omitempty"`        // Preview pane enabled
+	PreviewLayout string `json:"preview_layout,omitempty"` // side or bottom
+	PreviewWidth  int    `json:"preview_width,omitempty"`  // Side layout width; 0 is the default
+	PreviewHeight int    `json:"preview_height,omitempty"` // Bottom layout height; 0 is the default
+	PreviewWrap   bool   `json:"preview_wrap,omitempty"`
+	PreviewPlain  bool   `json:"preview_plain,omitempty"`
+
+	TaskPanel       bool     `json:"task_panel,omitempty"`        // Task panel enabled
+	TaskPanelHeight int      `json:"task_panel_height,omitempty"` // 0 is the default
+	TaskSort        string   `json:"task_sort,omitempty"`
+	TaskSortReverse bool     `json:"task_sort_reverse,omitempty"`
+	TaskFilter      string   `json:"task_filter,omitempty"`
+	Accordion       bool     `json:"accordion,omitempty"`
+	ExpandedGroups  []string `json:"expanded_groups,omitempty"` // Sorted task group IDs
+}
+
+// Equal reports whether s and o hold the same settings.
+func (s State) Equal(o State) bool {
+	groups, otherGroups := s.ExpandedGroups, o.ExpandedGroups
+	s.ExpandedGroups, o.ExpandedGroups = nil, nil
+	return slices.Equal(groups, otherGroups) && reflect.DeepEqual(s, o)
+}
+
+// Load reads the state from path. A missing file yields the zero State.
+func Load(path string) (State, error) {
+	var s State
+
+	data, err := os.ReadFile(pathutil.ExpandPath(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return s, err
+	}
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return State{}, err
+	}
+	return s, nil
+}
+
+// Save writes the state to path atomically.
+func Save(path string, s State) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path = pathutil.ExpandPath(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "ui-state-*.json")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// Reset deletes the state file at path so the next start uses the defaults.
+// A missing file is not an error.
+func Reset(path string) error {
+	err := os.Remove(pathutil.ExpandPath(path))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Writer serializes saves to one path. A save takes its sequence number from
+// Next when it is queued and is skipped when a later one was already written,
+// so saves that run concurrently still leave the newest state on disk.
+type Writer struct {
+	path   string
+	queued atomic.Uint64
+
+	mu      sync.Mutex
+	written uint64 // Sequence number of the last save; guarded by mu
+}
+
+// NewWriter returns a Writer saving to path.
+func NewWriter(path string) *Writer {
+	return &Writer{path: path}
+}
+
+// Next returns the sequence number for a new save. Call it in the order the
+// states were captured.
+func (w *Writer) Next() uint64 {
+	return w.queued.Add(1)
+}
+
+// Save writes s unless a save with a later sequence number already ran.
+func (w *Writer) Save(seq uint64, s State) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if seq <= w.written {
+		return nil
+	}
+	w.written = seq
+	return Save(w.path, s)
+}
@@ -0,0 +1,116 @@
+package uistate
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "navi", "ui-state.json")
+
+	s, err := Load(path)
+	if err != nil || !s.Equal(State{}) {
+		t.Fatalf("missing file should load the zero state, got %+v, %v", s, err)
+	}
+
+	want := State{
+		Sort:           "name",
+		HideOffline:    true,
+		Preview:        true,
+		PreviewLayout:  "side",
+		PreviewWidth:   70,
+		PreviewWrap:    true,
+		TaskSort:       "status",
+		Accordion:      true,
+		ExpandedGroups: []string{"PBI-1", "PBI-7"},
+	}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil || !loaded.Equal(want) {
+		t.Fatalf("Load() = %+v, %v; want %+v", loaded, err, want)
+	}
+
+	if err := Reset(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Reset() left the file behind: %v", err)
+	}
+	if err := Reset(path); err != nil {
+		t.Errorf("Reset() of a missing file = %v", err)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ui-state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err == nil {
+		t.Error("Load() of invalid JSON should fail")
+	}
+	if !s.Equal(State{}) {
+		t.Errorf("Load() of invalid JSON = %+v, want the zero state", s)
+	}
+}
+
+func TestStateEqual(t *testing.T) {
+	a := State{Sort: "age", ExpandedGroups: []string{"a", "b"}}
+	if !a.Equal(State{Sort: "age", ExpandedGroups: []string{"a", "b"}}) {
+		t.Error("identical states should be equal")
+	}
+	if a.Equal(State{Sort: "age", ExpandedGroups: []string{"a"}}) {
+		t.Error("states with different groups should differ")
+	}
+	if a.Equal(State{Sort: "name", ExpandedGroups: []string{"a", "b"}}) {
+		t.Error("states with different sort modes should differ")
+	}
+}
+
+func TestWriterSkipsStaleSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ui-state.json")
+	w := NewWriter(path)
+
+	older, newer := w.Next(), w.Next()
+	if err := w.Save(newer, State{PreviewWidth: 80}); err != nil {
+		t.Fatal(err)
+	}
+	// The older save runs last, as a slower command would
+	if err := w.Save(older, State{PreviewWidth: 70}); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(path)
+	if err != nil || s.PreviewWidth != 80 {
+		t.Errorf("Load() = %+v, %v; want the newer width 80", s, err)
+	}
+}
+
+func TestWriterConcurrentSavesKeepNewest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ui-state.json")
+	w := NewWriter(path)
+
+	const saves = 50
+	var wg sync.WaitGroup
+	for i := 1; i <= saves; i++ {
+		seq := w.Next()
+		wg.Add(1)
+		go func(width int) {
+			defer wg.Done()
+			if err := w.Save(seq, State{PreviewWidth: width}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	s, err := Load(path)
+	if err != nil || s.PreviewWidth != saves {
+		t.Errorf("Load() = %+v, %v; want the last width %d", s, err, saves)
+	}
+}